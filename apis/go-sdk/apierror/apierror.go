@@ -0,0 +1,158 @@
+// Package apierror turns the errors of the services into gRPC statuses with proper codes and
+// google.rpc details, and writes them to gateway clients in one JSON shape
+package apierror
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+)
+
+// Domain is the ErrorInfo domain of all errors returned by the services
+const Domain = "todolist"
+
+// Reasons of errors that don't have a more specific one
+const (
+	ReasonInvalidArgument = "INVALID_ARGUMENT"
+	ReasonNotFound        = "NOT_FOUND"
+	ReasonAlreadyExists   = "ALREADY_EXISTS"
+	ReasonUnauthenticated = "UNAUTHENTICATED"
+	ReasonUnavailable     = "UNAVAILABLE"
+	ReasonInternal        = "INTERNAL"
+)
+
+// Error is a domain error with the code it is reported with. Reason is a constant in UPPER_SNAKE_CASE
+// clients can switch on, Field names the request field the error is about
+type Error struct {
+	Code   codes.Code
+	Reason string
+	Field  string
+	Err    error
+}
+
+// New returns a domain error, declare them as package variables and compare them with errors.Is
+func New(code codes.Code, reason, msg string) *Error {
+	return &Error{
+		Code:   code,
+		Reason: reason,
+		Err:    errors.New(msg),
+	}
+}
+
+// Wrap reports err with the code and reason
+func Wrap(code codes.Code, reason string, err error) *Error {
+	return &Error{
+		Code:   code,
+		Reason: reason,
+		Err:    err,
+	}
+}
+
+// Field attaches the request field to err, errors without a code become invalid arguments
+func Field(field string, err error) error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return &Error{
+			Code:   apiErr.Code,
+			Reason: apiErr.Reason,
+			Field:  field,
+			Err:    err,
+		}
+	}
+	return &Error{
+		Code:   codes.InvalidArgument,
+		Reason: ReasonInvalidArgument,
+		Field:  field,
+		Err:    err,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Status converts err into a gRPC status error. Domain errors keep their code, errors of Firestore
+// and other gRPC clients are mapped to the codes meaningful to the caller, anything else is internal
+func Status(err error) error {
+	if err == nil {
+		return nil
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return newStatus(apiErr.Code, apiErr.Reason, err.Error(), fieldViolations(apiErr.Field, err.Error()))
+	}
+	if errors.Is(err, context.Canceled) {
+		return newStatus(codes.Canceled, "CANCELED", err.Error(), nil)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return newStatus(codes.DeadlineExceeded, "DEADLINE_EXCEEDED", err.Error(), nil)
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return newStatus(codes.Internal, ReasonInternal, err.Error(), nil)
+	}
+	// statuses built by this package or the interceptors are passed through
+	if len(st.Details()) > 0 {
+		return err
+	}
+	code, reason := clientCode(st.Code())
+	return newStatus(code, reason, st.Message(), nil)
+}
+
+// clientCode maps the code returned by a backend, e.g. Firestore. Failures of the service's own
+// credentials or queries are not the caller's fault and become internal
+func clientCode(code codes.Code) (codes.Code, string) {
+	switch code {
+	case codes.NotFound:
+		return codes.NotFound, ReasonNotFound
+	case codes.AlreadyExists:
+		return codes.AlreadyExists, ReasonAlreadyExists
+	case codes.InvalidArgument:
+		return codes.InvalidArgument, ReasonInvalidArgument
+	case codes.Aborted:
+		return codes.Aborted, "ABORTED"
+	case codes.Canceled:
+		return codes.Canceled, "CANCELED"
+	case codes.DeadlineExceeded:
+		return codes.DeadlineExceeded, "DEADLINE_EXCEEDED"
+	case codes.ResourceExhausted:
+		return codes.ResourceExhausted, "RESOURCE_EXHAUSTED"
+	case codes.Unauthenticated:
+		return codes.Unauthenticated, ReasonUnauthenticated
+	case codes.Unavailable:
+		return codes.Unavailable, ReasonUnavailable
+	}
+	return codes.Internal, ReasonInternal
+}
+
+// BadRequest returns an InvalidArgument status listing all violations of the request
+func BadRequest(msg string, violations []*errdetails.BadRequest_FieldViolation) error {
+	return newStatus(codes.InvalidArgument, ReasonInvalidArgument, msg, violations)
+}
+
+func newStatus(code codes.Code, reason, msg string, violations []*errdetails.BadRequest_FieldViolation) error {
+	st := status.New(code, msg)
+	details := []protoiface.MessageV1{&errdetails.ErrorInfo{Reason: reason, Domain: Domain}}
+	if len(violations) > 0 {
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+func fieldViolations(field, msg string) []*errdetails.BadRequest_FieldViolation {
+	if field == "" {
+		return nil
+	}
+	return []*errdetails.BadRequest_FieldViolation{{Field: field, Description: msg}}
+}
@@ -0,0 +1,86 @@
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var errTaskMissing = New(codes.NotFound, "TASK_MISSING", "task missing")
+
+type APIErrorTestSuite struct {
+	suite.Suite
+}
+
+func (s *APIErrorTestSuite) TestStatus() {
+	candidates := []struct {
+		err            error
+		expectedCode   codes.Code
+		expectedReason string
+		expectedField  string
+	}{
+		{err: errTaskMissing, expectedCode: codes.NotFound, expectedReason: "TASK_MISSING"},
+		// wrapped domain errors keep their code
+		{err: fmt.Errorf("get: %w", errTaskMissing), expectedCode: codes.NotFound, expectedReason: "TASK_MISSING"},
+		{
+			err:            Field("name", errors.New("too long")),
+			expectedCode:   codes.InvalidArgument,
+			expectedReason: ReasonInvalidArgument,
+			expectedField:  "name",
+		},
+		// Firestore errors
+		{err: status.Error(codes.NotFound, "no doc"), expectedCode: codes.NotFound, expectedReason: ReasonNotFound},
+		{err: status.Error(codes.PermissionDenied, "iam"), expectedCode: codes.Internal, expectedReason: ReasonInternal},
+		{err: status.Error(codes.Unavailable, "down"), expectedCode: codes.Unavailable, expectedReason: ReasonUnavailable},
+		{err: context.Canceled, expectedCode: codes.Canceled, expectedReason: "CANCELED"},
+		{err: errors.New("boom"), expectedCode: codes.Internal, expectedReason: ReasonInternal},
+	}
+	for i, c := range candidates {
+		st := status.Convert(Status(c.err))
+		s.Equalf(c.expectedCode, st.Code(), "candidate %d", i+1)
+		body := NewBody(st)
+		s.Equalf(c.expectedReason, body.Error.Reason, "candidate %d", i+1)
+		s.Equalf(Domain, body.Error.Domain, "candidate %d", i+1)
+		if c.expectedField != "" {
+			s.Equalf([]FieldViolation{{Field: c.expectedField, Description: c.err.Error()}},
+				body.Error.FieldViolations, "candidate %d", i+1)
+		}
+	}
+	s.Nil(Status(nil))
+	s.True(errors.Is(Field("name", errTaskMissing), errTaskMissing))
+}
+
+func (s *APIErrorTestSuite) TestStatusPassesDetails() {
+	err := BadRequest("invalid name", []*errdetails.BadRequest_FieldViolation{{Field: "name", Description: "empty"}})
+	s.Equal(err, Status(err))
+}
+
+func (s *APIErrorTestSuite) TestGatewayErrorHandler() {
+	rec := httptest.NewRecorder()
+	GatewayErrorHandler(context.Background(), nil, nil, rec, httptest.NewRequest(http.MethodGet, "/", nil),
+		Status(Field("url", New(codes.InvalidArgument, "INVALID_URL", "invalid url"))))
+	s.Equal(http.StatusBadRequest, rec.Code)
+	s.Equal("application/json", rec.Header().Get("Content-Type"))
+	body := Body{}
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &body))
+	s.Equal(Body{Error: Detail{
+		Code:            http.StatusBadRequest,
+		Status:          "INVALID_ARGUMENT",
+		Message:         "invalid url",
+		Reason:          "INVALID_URL",
+		Domain:          Domain,
+		FieldViolations: []FieldViolation{{Field: "url", Description: "invalid url"}},
+	}}, body)
+}
+
+func TestAPIErrorTestSuite(t *testing.T) {
+	suite.Run(t, new(APIErrorTestSuite))
+}
@@ -0,0 +1,69 @@
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+// Body is the JSON shape of all errors returned by the gateways
+type Body struct {
+	Error Detail `json:"error"`
+}
+
+type Detail struct {
+	// Code is the HTTP status code
+	Code int `json:"code"`
+	// Status is the name of the gRPC code, e.g. NOT_FOUND
+	Status          string            `json:"status"`
+	Message         string            `json:"message"`
+	Reason          string            `json:"reason,omitempty"`
+	Domain          string            `json:"domain,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	FieldViolations []FieldViolation  `json:"fieldViolations,omitempty"`
+}
+
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// GatewayErrorHandler is a runtime.ErrorHandlerFunc writing errors as Body,
+// register it with runtime.WithErrorHandler
+func GatewayErrorHandler(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler,
+	w http.ResponseWriter, _ *http.Request, err error) {
+	st := status.Convert(err)
+	body := NewBody(st)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(body.Error.Code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// NewBody reads the ErrorInfo and BadRequest details of the status
+func NewBody(st *status.Status) Body {
+	detail := Detail{
+		Code:    runtime.HTTPStatusFromCode(st.Code()),
+		Status:  code.Code_name[int32(st.Code())],
+		Message: st.Message(),
+	}
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			detail.Reason = d.Reason
+			detail.Domain = d.Domain
+			detail.Metadata = d.Metadata
+		case *errdetails.BadRequest:
+			for _, violation := range d.FieldViolations {
+				detail.FieldViolations = append(detail.FieldViolations, FieldViolation{
+					Field:       violation.Field,
+					Description: violation.Description,
+				})
+			}
+		}
+	}
+	return Body{Error: detail}
+}
@@ -0,0 +1,5256 @@
+// Code generated by protoc-gen-validate. DO NOT EDIT.
+// source: v1/task.proto
+
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ensure the imports are used
+var (
+	_ = bytes.MinRead
+	_ = errors.New("")
+	_ = fmt.Print
+	_ = utf8.UTFMax
+	_ = (*regexp.Regexp)(nil)
+	_ = (*strings.Reader)(nil)
+	_ = net.IPv4len
+	_ = time.Duration(0)
+	_ = (*url.URL)(nil)
+	_ = (*mail.Address)(nil)
+	_ = anypb.Any{}
+	_ = sort.Sort
+)
+
+// Validate checks the field values on Task with the rules defined in the proto
+// definition for this message. If any rules are violated, the first error
+// encountered is returned, or nil if there are no violations.
+func (m *Task) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Task with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in TaskMultiError, or nil if none found.
+func (m *Task) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Task) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetTaskId()) > 128 {
+		err := TaskValidationError{
+			field:  "TaskId",
+			reason: "value length must be at most 128 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetCreatedAt() < 0 {
+		err := TaskValidationError{
+			field:  "CreatedAt",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if l := utf8.RuneCountInString(m.GetName()); l < 1 || l > 256 {
+		err := TaskValidationError{
+			field:  "Name",
+			reason: "value length must be between 1 and 256 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetDescription()) > 4096 {
+		err := TaskValidationError{
+			field:  "Description",
+			reason: "value length must be at most 4096 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetTime() < 0 {
+		err := TaskValidationError{
+			field:  "Time",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for UserId
+
+	// no validation rules for UserEmail
+
+	// no validation rules for Completed
+
+	if m.GetCompletedAt() < 0 {
+		err := TaskValidationError{
+			field:  "CompletedAt",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetRecurrence()) > 512 {
+		err := TaskValidationError{
+			field:  "Recurrence",
+			reason: "value length must be at most 512 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_Task_Priority_Pattern.MatchString(m.GetPriority()) {
+		err := TaskValidationError{
+			field:  "Priority",
+			reason: "value does not match regex pattern \"^[A-Z]?$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetUpdatedAt() < 0 {
+		err := TaskValidationError{
+			field:  "UpdatedAt",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(m.GetReminders()) > 10 {
+		err := TaskValidationError{
+			field:  "Reminders",
+			reason: "value must contain no more than 10 item(s)",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	_Task_Reminders_Unique := make(map[int64]struct{}, len(m.GetReminders()))
+
+	for idx, item := range m.GetReminders() {
+		_, _ = idx, item
+
+		if _, exists := _Task_Reminders_Unique[item]; exists {
+			err := TaskValidationError{
+				field:  fmt.Sprintf("Reminders[%v]", idx),
+				reason: "repeated value must contain unique items",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+			_Task_Reminders_Unique[item] = struct{}{}
+		}
+
+		if val := item; val < 0 || val > 2592000 {
+			err := TaskValidationError{
+				field:  fmt.Sprintf("Reminders[%v]", idx),
+				reason: "value must be inside range [0, 2592000]",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	// no validation rules for NextReminderAt
+
+	// no validation rules for StopNagging
+
+	// no validation rules for ClearReminders
+
+	if len(errors) > 0 {
+		return TaskMultiError(errors)
+	}
+
+	return nil
+}
+
+// TaskMultiError is an error wrapping multiple validation errors returned by
+// Task.ValidateAll() if the designated constraints aren't met.
+type TaskMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TaskMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TaskMultiError) AllErrors() []error { return m }
+
+// TaskValidationError is the validation error returned by Task.Validate if the
+// designated constraints aren't met.
+type TaskValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TaskValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TaskValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TaskValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TaskValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TaskValidationError) ErrorName() string { return "TaskValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TaskValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTask.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TaskValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TaskValidationError{}
+
+var _Task_Priority_Pattern = regexp.MustCompile("^[A-Z]?$")
+
+// Validate checks the field values on GetTaskRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *GetTaskRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetTaskRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in GetTaskRequestMultiError,
+// or nil if none found.
+func (m *GetTaskRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetTaskRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetTaskId()); l < 1 || l > 128 {
+		err := GetTaskRequestValidationError{
+			field:  "TaskId",
+			reason: "value length must be between 1 and 128 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return GetTaskRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetTaskRequestMultiError is an error wrapping multiple validation errors
+// returned by GetTaskRequest.ValidateAll() if the designated constraints
+// aren't met.
+type GetTaskRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetTaskRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetTaskRequestMultiError) AllErrors() []error { return m }
+
+// GetTaskRequestValidationError is the validation error returned by
+// GetTaskRequest.Validate if the designated constraints aren't met.
+type GetTaskRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetTaskRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetTaskRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetTaskRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetTaskRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetTaskRequestValidationError) ErrorName() string { return "GetTaskRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GetTaskRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetTaskRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetTaskRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetTaskRequestValidationError{}
+
+// Validate checks the field values on DeleteTaskRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *DeleteTaskRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteTaskRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteTaskRequestMultiError, or nil if none found.
+func (m *DeleteTaskRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteTaskRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetTaskId()); l < 1 || l > 128 {
+		err := DeleteTaskRequestValidationError{
+			field:  "TaskId",
+			reason: "value length must be between 1 and 128 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return DeleteTaskRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeleteTaskRequestMultiError is an error wrapping multiple validation errors
+// returned by DeleteTaskRequest.ValidateAll() if the designated constraints
+// aren't met.
+type DeleteTaskRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteTaskRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeleteTaskRequestMultiError) AllErrors() []error { return m }
+
+// DeleteTaskRequestValidationError is the validation error returned by
+// DeleteTaskRequest.Validate if the designated constraints aren't met.
+type DeleteTaskRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeleteTaskRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeleteTaskRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeleteTaskRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeleteTaskRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeleteTaskRequestValidationError) ErrorName() string {
+	return "DeleteTaskRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeleteTaskRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeleteTaskRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeleteTaskRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeleteTaskRequestValidationError{}
+
+// Validate checks the field values on GetLastNRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *GetLastNRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetLastNRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetLastNRequestMultiError, or nil if none found.
+func (m *GetLastNRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetLastNRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetN() < 0 {
+		err := GetLastNRequestValidationError{
+			field:  "N",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return GetLastNRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetLastNRequestMultiError is an error wrapping multiple validation errors
+// returned by GetLastNRequest.ValidateAll() if the designated constraints
+// aren't met.
+type GetLastNRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetLastNRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetLastNRequestMultiError) AllErrors() []error { return m }
+
+// GetLastNRequestValidationError is the validation error returned by
+// GetLastNRequest.Validate if the designated constraints aren't met.
+type GetLastNRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetLastNRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetLastNRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetLastNRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetLastNRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetLastNRequestValidationError) ErrorName() string { return "GetLastNRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GetLastNRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetLastNRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetLastNRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetLastNRequestValidationError{}
+
+// Validate checks the field values on GetExpiredRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *GetExpiredRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetExpiredRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetExpiredRequestMultiError, or nil if none found.
+func (m *GetExpiredRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetExpiredRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return GetExpiredRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetExpiredRequestMultiError is an error wrapping multiple validation errors
+// returned by GetExpiredRequest.ValidateAll() if the designated constraints
+// aren't met.
+type GetExpiredRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetExpiredRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetExpiredRequestMultiError) AllErrors() []error { return m }
+
+// GetExpiredRequestValidationError is the validation error returned by
+// GetExpiredRequest.Validate if the designated constraints aren't met.
+type GetExpiredRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetExpiredRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetExpiredRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetExpiredRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetExpiredRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetExpiredRequestValidationError) ErrorName() string {
+	return "GetExpiredRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GetExpiredRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetExpiredRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetExpiredRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetExpiredRequestValidationError{}
+
+// Validate checks the field values on SyncTasksRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *SyncTasksRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SyncTasksRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SyncTasksRequestMultiError, or nil if none found.
+func (m *SyncTasksRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SyncTasksRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for SyncToken
+
+	if m.GetPageSize() < 0 {
+		err := SyncTasksRequestValidationError{
+			field:  "PageSize",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return SyncTasksRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// SyncTasksRequestMultiError is an error wrapping multiple validation errors
+// returned by SyncTasksRequest.ValidateAll() if the designated constraints
+// aren't met.
+type SyncTasksRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SyncTasksRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SyncTasksRequestMultiError) AllErrors() []error { return m }
+
+// SyncTasksRequestValidationError is the validation error returned by
+// SyncTasksRequest.Validate if the designated constraints aren't met.
+type SyncTasksRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SyncTasksRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SyncTasksRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SyncTasksRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SyncTasksRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SyncTasksRequestValidationError) ErrorName() string { return "SyncTasksRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e SyncTasksRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSyncTasksRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SyncTasksRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SyncTasksRequestValidationError{}
+
+// Validate checks the field values on Tombstone with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Tombstone) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Tombstone with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in TombstoneMultiError, or nil
+// if none found.
+func (m *Tombstone) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Tombstone) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for TaskId
+
+	// no validation rules for DeletedAt
+
+	if len(errors) > 0 {
+		return TombstoneMultiError(errors)
+	}
+
+	return nil
+}
+
+// TombstoneMultiError is an error wrapping multiple validation errors returned
+// by Tombstone.ValidateAll() if the designated constraints aren't met.
+type TombstoneMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TombstoneMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TombstoneMultiError) AllErrors() []error { return m }
+
+// TombstoneValidationError is the validation error returned by
+// Tombstone.Validate if the designated constraints aren't met.
+type TombstoneValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TombstoneValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TombstoneValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TombstoneValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TombstoneValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TombstoneValidationError) ErrorName() string { return "TombstoneValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TombstoneValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTombstone.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TombstoneValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TombstoneValidationError{}
+
+// Validate checks the field values on SyncTasksResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *SyncTasksResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SyncTasksResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SyncTasksResponseMultiError, or nil if none found.
+func (m *SyncTasksResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SyncTasksResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetTasks() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SyncTasksResponseValidationError{
+						field:  fmt.Sprintf("Tasks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SyncTasksResponseValidationError{
+						field:  fmt.Sprintf("Tasks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SyncTasksResponseValidationError{
+					field:  fmt.Sprintf("Tasks[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetTombstones() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SyncTasksResponseValidationError{
+						field:  fmt.Sprintf("Tombstones[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SyncTasksResponseValidationError{
+						field:  fmt.Sprintf("Tombstones[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SyncTasksResponseValidationError{
+					field:  fmt.Sprintf("Tombstones[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for SyncToken
+
+	// no validation rules for HasMore
+
+	// no validation rules for FullSync
+
+	if len(errors) > 0 {
+		return SyncTasksResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// SyncTasksResponseMultiError is an error wrapping multiple validation errors
+// returned by SyncTasksResponse.ValidateAll() if the designated constraints
+// aren't met.
+type SyncTasksResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SyncTasksResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SyncTasksResponseMultiError) AllErrors() []error { return m }
+
+// SyncTasksResponseValidationError is the validation error returned by
+// SyncTasksResponse.Validate if the designated constraints aren't met.
+type SyncTasksResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SyncTasksResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SyncTasksResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SyncTasksResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SyncTasksResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SyncTasksResponseValidationError) ErrorName() string {
+	return "SyncTasksResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SyncTasksResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSyncTasksResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SyncTasksResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SyncTasksResponseValidationError{}
+
+// Validate checks the field values on WatchTasksRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *WatchTasksRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on WatchTasksRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// WatchTasksRequestMultiError, or nil if none found.
+func (m *WatchTasksRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *WatchTasksRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for SyncToken
+
+	if len(errors) > 0 {
+		return WatchTasksRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// WatchTasksRequestMultiError is an error wrapping multiple validation errors
+// returned by WatchTasksRequest.ValidateAll() if the designated constraints
+// aren't met.
+type WatchTasksRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WatchTasksRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WatchTasksRequestMultiError) AllErrors() []error { return m }
+
+// WatchTasksRequestValidationError is the validation error returned by
+// WatchTasksRequest.Validate if the designated constraints aren't met.
+type WatchTasksRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WatchTasksRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WatchTasksRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WatchTasksRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WatchTasksRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WatchTasksRequestValidationError) ErrorName() string {
+	return "WatchTasksRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e WatchTasksRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWatchTasksRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WatchTasksRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WatchTasksRequestValidationError{}
+
+// Validate checks the field values on TaskEvent with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *TaskEvent) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TaskEvent with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in TaskEventMultiError, or nil
+// if none found.
+func (m *TaskEvent) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TaskEvent) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Type
+
+	// no validation rules for TaskId
+
+	if all {
+		switch v := interface{}(m.GetTask()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, TaskEventValidationError{
+					field:  "Task",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, TaskEventValidationError{
+					field:  "Task",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTask()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return TaskEventValidationError{
+				field:  "Task",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Time
+
+	// no validation rules for SyncToken
+
+	if len(errors) > 0 {
+		return TaskEventMultiError(errors)
+	}
+
+	return nil
+}
+
+// TaskEventMultiError is an error wrapping multiple validation errors returned
+// by TaskEvent.ValidateAll() if the designated constraints aren't met.
+type TaskEventMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TaskEventMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TaskEventMultiError) AllErrors() []error { return m }
+
+// TaskEventValidationError is the validation error returned by
+// TaskEvent.Validate if the designated constraints aren't met.
+type TaskEventValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TaskEventValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TaskEventValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TaskEventValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TaskEventValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TaskEventValidationError) ErrorName() string { return "TaskEventValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TaskEventValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTaskEvent.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TaskEventValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TaskEventValidationError{}
+
+// Validate checks the field values on GetTaskStatsRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *GetTaskStatsRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetTaskStatsRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetTaskStatsRequestMultiError, or nil if none found.
+func (m *GetTaskStatsRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetTaskStatsRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetFrom() < 0 {
+		err := GetTaskStatsRequestValidationError{
+			field:  "From",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetTo() < 0 {
+		err := GetTaskStatsRequestValidationError{
+			field:  "To",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Period
+
+	if len(errors) > 0 {
+		return GetTaskStatsRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetTaskStatsRequestMultiError is an error wrapping multiple validation
+// errors returned by GetTaskStatsRequest.ValidateAll() if the designated
+// constraints aren't met.
+type GetTaskStatsRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetTaskStatsRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetTaskStatsRequestMultiError) AllErrors() []error { return m }
+
+// GetTaskStatsRequestValidationError is the validation error returned by
+// GetTaskStatsRequest.Validate if the designated constraints aren't met.
+type GetTaskStatsRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetTaskStatsRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetTaskStatsRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetTaskStatsRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetTaskStatsRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetTaskStatsRequestValidationError) ErrorName() string {
+	return "GetTaskStatsRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GetTaskStatsRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetTaskStatsRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetTaskStatsRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetTaskStatsRequestValidationError{}
+
+// Validate checks the field values on TaskStatsBucket with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *TaskStatsBucket) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TaskStatsBucket with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// TaskStatsBucketMultiError, or nil if none found.
+func (m *TaskStatsBucket) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TaskStatsBucket) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Start
+
+	// no validation rules for Created
+
+	// no validation rules for Completed
+
+	// no validation rules for Overdue
+
+	if len(errors) > 0 {
+		return TaskStatsBucketMultiError(errors)
+	}
+
+	return nil
+}
+
+// TaskStatsBucketMultiError is an error wrapping multiple validation errors
+// returned by TaskStatsBucket.ValidateAll() if the designated constraints
+// aren't met.
+type TaskStatsBucketMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TaskStatsBucketMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TaskStatsBucketMultiError) AllErrors() []error { return m }
+
+// TaskStatsBucketValidationError is the validation error returned by
+// TaskStatsBucket.Validate if the designated constraints aren't met.
+type TaskStatsBucketValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TaskStatsBucketValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TaskStatsBucketValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TaskStatsBucketValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TaskStatsBucketValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TaskStatsBucketValidationError) ErrorName() string { return "TaskStatsBucketValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TaskStatsBucketValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTaskStatsBucket.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TaskStatsBucketValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TaskStatsBucketValidationError{}
+
+// Validate checks the field values on TaskStats with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *TaskStats) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TaskStats with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in TaskStatsMultiError, or nil
+// if none found.
+func (m *TaskStats) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TaskStats) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetBuckets() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, TaskStatsValidationError{
+						field:  fmt.Sprintf("Buckets[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, TaskStatsValidationError{
+						field:  fmt.Sprintf("Buckets[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return TaskStatsValidationError{
+					field:  fmt.Sprintf("Buckets[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for Created
+
+	// no validation rules for Completed
+
+	// no validation rules for Overdue
+
+	// no validation rules for AverageLeadTime
+
+	// no validation rules for CurrentStreak
+
+	// no validation rules for LongestStreak
+
+	if len(errors) > 0 {
+		return TaskStatsMultiError(errors)
+	}
+
+	return nil
+}
+
+// TaskStatsMultiError is an error wrapping multiple validation errors returned
+// by TaskStats.ValidateAll() if the designated constraints aren't met.
+type TaskStatsMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TaskStatsMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TaskStatsMultiError) AllErrors() []error { return m }
+
+// TaskStatsValidationError is the validation error returned by
+// TaskStats.Validate if the designated constraints aren't met.
+type TaskStatsValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TaskStatsValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TaskStatsValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TaskStatsValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TaskStatsValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TaskStatsValidationError) ErrorName() string { return "TaskStatsValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TaskStatsValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTaskStats.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TaskStatsValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TaskStatsValidationError{}
+
+// Validate checks the field values on TaskList with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *TaskList) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TaskList with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in TaskListMultiError, or nil
+// if none found.
+func (m *TaskList) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TaskList) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetTasks() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, TaskListValidationError{
+						field:  fmt.Sprintf("Tasks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, TaskListValidationError{
+						field:  fmt.Sprintf("Tasks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return TaskListValidationError{
+					field:  fmt.Sprintf("Tasks[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return TaskListMultiError(errors)
+	}
+
+	return nil
+}
+
+// TaskListMultiError is an error wrapping multiple validation errors returned
+// by TaskList.ValidateAll() if the designated constraints aren't met.
+type TaskListMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TaskListMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TaskListMultiError) AllErrors() []error { return m }
+
+// TaskListValidationError is the validation error returned by
+// TaskList.Validate if the designated constraints aren't met.
+type TaskListValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TaskListValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TaskListValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TaskListValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TaskListValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TaskListValidationError) ErrorName() string { return "TaskListValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TaskListValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTaskList.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TaskListValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TaskListValidationError{}
+
+// Validate checks the field values on ExportTasksRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ExportTasksRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ExportTasksRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ExportTasksRequestMultiError, or nil if none found.
+func (m *ExportTasksRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ExportTasksRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Format
+
+	if len(errors) > 0 {
+		return ExportTasksRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ExportTasksRequestMultiError is an error wrapping multiple validation errors
+// returned by ExportTasksRequest.ValidateAll() if the designated constraints
+// aren't met.
+type ExportTasksRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ExportTasksRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ExportTasksRequestMultiError) AllErrors() []error { return m }
+
+// ExportTasksRequestValidationError is the validation error returned by
+// ExportTasksRequest.Validate if the designated constraints aren't met.
+type ExportTasksRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ExportTasksRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ExportTasksRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ExportTasksRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ExportTasksRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ExportTasksRequestValidationError) ErrorName() string {
+	return "ExportTasksRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ExportTasksRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sExportTasksRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ExportTasksRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ExportTasksRequestValidationError{}
+
+// Validate checks the field values on GetExportJobRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *GetExportJobRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetExportJobRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetExportJobRequestMultiError, or nil if none found.
+func (m *GetExportJobRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetExportJobRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetJobId()); l < 1 || l > 128 {
+		err := GetExportJobRequestValidationError{
+			field:  "JobId",
+			reason: "value length must be between 1 and 128 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return GetExportJobRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetExportJobRequestMultiError is an error wrapping multiple validation
+// errors returned by GetExportJobRequest.ValidateAll() if the designated
+// constraints aren't met.
+type GetExportJobRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetExportJobRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetExportJobRequestMultiError) AllErrors() []error { return m }
+
+// GetExportJobRequestValidationError is the validation error returned by
+// GetExportJobRequest.Validate if the designated constraints aren't met.
+type GetExportJobRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetExportJobRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetExportJobRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetExportJobRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetExportJobRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetExportJobRequestValidationError) ErrorName() string {
+	return "GetExportJobRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GetExportJobRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetExportJobRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetExportJobRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetExportJobRequestValidationError{}
+
+// Validate checks the field values on ExportJob with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *ExportJob) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ExportJob with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ExportJobMultiError, or nil
+// if none found.
+func (m *ExportJob) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ExportJob) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for JobId
+
+	// no validation rules for Format
+
+	// no validation rules for Status
+
+	// no validation rules for CreatedAt
+
+	// no validation rules for FinishedAt
+
+	// no validation rules for TaskCount
+
+	// no validation rules for DownloadUrl
+
+	// no validation rules for DownloadUrlExpiresAt
+
+	// no validation rules for Error
+
+	if len(errors) > 0 {
+		return ExportJobMultiError(errors)
+	}
+
+	return nil
+}
+
+// ExportJobMultiError is an error wrapping multiple validation errors returned
+// by ExportJob.ValidateAll() if the designated constraints aren't met.
+type ExportJobMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ExportJobMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ExportJobMultiError) AllErrors() []error { return m }
+
+// ExportJobValidationError is the validation error returned by
+// ExportJob.Validate if the designated constraints aren't met.
+type ExportJobValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ExportJobValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ExportJobValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ExportJobValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ExportJobValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ExportJobValidationError) ErrorName() string { return "ExportJobValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ExportJobValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sExportJob.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ExportJobValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ExportJobValidationError{}
+
+// Validate checks the field values on ExportTasksTextRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ExportTasksTextRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ExportTasksTextRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ExportTasksTextRequestMultiError, or nil if none found.
+func (m *ExportTasksTextRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ExportTasksTextRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Format
+
+	if len(errors) > 0 {
+		return ExportTasksTextRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ExportTasksTextRequestMultiError is an error wrapping multiple validation
+// errors returned by ExportTasksTextRequest.ValidateAll() if the designated
+// constraints aren't met.
+type ExportTasksTextRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ExportTasksTextRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ExportTasksTextRequestMultiError) AllErrors() []error { return m }
+
+// ExportTasksTextRequestValidationError is the validation error returned by
+// ExportTasksTextRequest.Validate if the designated constraints aren't met.
+type ExportTasksTextRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ExportTasksTextRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ExportTasksTextRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ExportTasksTextRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ExportTasksTextRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ExportTasksTextRequestValidationError) ErrorName() string {
+	return "ExportTasksTextRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ExportTasksTextRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sExportTasksTextRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ExportTasksTextRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ExportTasksTextRequestValidationError{}
+
+// Validate checks the field values on TasksText with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *TasksText) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TasksText with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in TasksTextMultiError, or nil
+// if none found.
+func (m *TasksText) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TasksText) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Format
+
+	// no validation rules for Content
+
+	if len(errors) > 0 {
+		return TasksTextMultiError(errors)
+	}
+
+	return nil
+}
+
+// TasksTextMultiError is an error wrapping multiple validation errors returned
+// by TasksText.ValidateAll() if the designated constraints aren't met.
+type TasksTextMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TasksTextMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TasksTextMultiError) AllErrors() []error { return m }
+
+// TasksTextValidationError is the validation error returned by
+// TasksText.Validate if the designated constraints aren't met.
+type TasksTextValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TasksTextValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TasksTextValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TasksTextValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TasksTextValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TasksTextValidationError) ErrorName() string { return "TasksTextValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TasksTextValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTasksText.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TasksTextValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TasksTextValidationError{}
+
+// Validate checks the field values on ImportTasksRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ImportTasksRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ImportTasksRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ImportTasksRequestMultiError, or nil if none found.
+func (m *ImportTasksRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ImportTasksRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Format
+
+	if len(m.GetContent()) > 5242880 {
+		err := ImportTasksRequestValidationError{
+			field:  "Content",
+			reason: "value length must be at most 5242880 bytes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for DryRun
+
+	if len(errors) > 0 {
+		return ImportTasksRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ImportTasksRequestMultiError is an error wrapping multiple validation errors
+// returned by ImportTasksRequest.ValidateAll() if the designated constraints
+// aren't met.
+type ImportTasksRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ImportTasksRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ImportTasksRequestMultiError) AllErrors() []error { return m }
+
+// ImportTasksRequestValidationError is the validation error returned by
+// ImportTasksRequest.Validate if the designated constraints aren't met.
+type ImportTasksRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImportTasksRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImportTasksRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImportTasksRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImportTasksRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImportTasksRequestValidationError) ErrorName() string {
+	return "ImportTasksRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ImportTasksRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImportTasksRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImportTasksRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImportTasksRequestValidationError{}
+
+// Validate checks the field values on ImportError with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *ImportError) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ImportError with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ImportErrorMultiError, or
+// nil if none found.
+func (m *ImportError) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ImportError) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Line
+
+	// no validation rules for Message
+
+	// no validation rules for Item
+
+	if len(errors) > 0 {
+		return ImportErrorMultiError(errors)
+	}
+
+	return nil
+}
+
+// ImportErrorMultiError is an error wrapping multiple validation errors
+// returned by ImportError.ValidateAll() if the designated constraints aren't met.
+type ImportErrorMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ImportErrorMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ImportErrorMultiError) AllErrors() []error { return m }
+
+// ImportErrorValidationError is the validation error returned by
+// ImportError.Validate if the designated constraints aren't met.
+type ImportErrorValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImportErrorValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImportErrorValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImportErrorValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImportErrorValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImportErrorValidationError) ErrorName() string { return "ImportErrorValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ImportErrorValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImportError.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImportErrorValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImportErrorValidationError{}
+
+// Validate checks the field values on ImportTasksResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ImportTasksResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ImportTasksResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ImportTasksResponseMultiError, or nil if none found.
+func (m *ImportTasksResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ImportTasksResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetTasks() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ImportTasksResponseValidationError{
+						field:  fmt.Sprintf("Tasks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ImportTasksResponseValidationError{
+						field:  fmt.Sprintf("Tasks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ImportTasksResponseValidationError{
+					field:  fmt.Sprintf("Tasks[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetErrors() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ImportTasksResponseValidationError{
+						field:  fmt.Sprintf("Errors[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ImportTasksResponseValidationError{
+						field:  fmt.Sprintf("Errors[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ImportTasksResponseValidationError{
+					field:  fmt.Sprintf("Errors[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for DryRun
+
+	if len(errors) > 0 {
+		return ImportTasksResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// ImportTasksResponseMultiError is an error wrapping multiple validation
+// errors returned by ImportTasksResponse.ValidateAll() if the designated
+// constraints aren't met.
+type ImportTasksResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ImportTasksResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ImportTasksResponseMultiError) AllErrors() []error { return m }
+
+// ImportTasksResponseValidationError is the validation error returned by
+// ImportTasksResponse.Validate if the designated constraints aren't met.
+type ImportTasksResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImportTasksResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImportTasksResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImportTasksResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImportTasksResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImportTasksResponseValidationError) ErrorName() string {
+	return "ImportTasksResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ImportTasksResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImportTasksResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImportTasksResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImportTasksResponseValidationError{}
+
+// Validate checks the field values on CalendarFeed with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *CalendarFeed) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CalendarFeed with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in CalendarFeedMultiError, or
+// nil if none found.
+func (m *CalendarFeed) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CalendarFeed) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Token
+
+	// no validation rules for Url
+
+	if len(errors) > 0 {
+		return CalendarFeedMultiError(errors)
+	}
+
+	return nil
+}
+
+// CalendarFeedMultiError is an error wrapping multiple validation errors
+// returned by CalendarFeed.ValidateAll() if the designated constraints aren't met.
+type CalendarFeedMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CalendarFeedMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CalendarFeedMultiError) AllErrors() []error { return m }
+
+// CalendarFeedValidationError is the validation error returned by
+// CalendarFeed.Validate if the designated constraints aren't met.
+type CalendarFeedValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CalendarFeedValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CalendarFeedValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CalendarFeedValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CalendarFeedValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CalendarFeedValidationError) ErrorName() string { return "CalendarFeedValidationError" }
+
+// Error satisfies the builtin error interface
+func (e CalendarFeedValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCalendarFeed.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CalendarFeedValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CalendarFeedValidationError{}
+
+// Validate checks the field values on CalDAVPassword with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *CalDAVPassword) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CalDAVPassword with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in CalDAVPasswordMultiError,
+// or nil if none found.
+func (m *CalDAVPassword) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CalDAVPassword) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for PasswordId
+
+	if utf8.RuneCountInString(m.GetName()) > 128 {
+		err := CalDAVPasswordValidationError{
+			field:  "Name",
+			reason: "value length must be at most 128 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Password
+
+	// no validation rules for CreatedAt
+
+	if len(errors) > 0 {
+		return CalDAVPasswordMultiError(errors)
+	}
+
+	return nil
+}
+
+// CalDAVPasswordMultiError is an error wrapping multiple validation errors
+// returned by CalDAVPassword.ValidateAll() if the designated constraints
+// aren't met.
+type CalDAVPasswordMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CalDAVPasswordMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CalDAVPasswordMultiError) AllErrors() []error { return m }
+
+// CalDAVPasswordValidationError is the validation error returned by
+// CalDAVPassword.Validate if the designated constraints aren't met.
+type CalDAVPasswordValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CalDAVPasswordValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CalDAVPasswordValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CalDAVPasswordValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CalDAVPasswordValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CalDAVPasswordValidationError) ErrorName() string { return "CalDAVPasswordValidationError" }
+
+// Error satisfies the builtin error interface
+func (e CalDAVPasswordValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCalDAVPassword.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CalDAVPasswordValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CalDAVPasswordValidationError{}
+
+// Validate checks the field values on CalDAVPasswordList with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *CalDAVPasswordList) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CalDAVPasswordList with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// CalDAVPasswordListMultiError, or nil if none found.
+func (m *CalDAVPasswordList) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CalDAVPasswordList) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetPasswords() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, CalDAVPasswordListValidationError{
+						field:  fmt.Sprintf("Passwords[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, CalDAVPasswordListValidationError{
+						field:  fmt.Sprintf("Passwords[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return CalDAVPasswordListValidationError{
+					field:  fmt.Sprintf("Passwords[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return CalDAVPasswordListMultiError(errors)
+	}
+
+	return nil
+}
+
+// CalDAVPasswordListMultiError is an error wrapping multiple validation errors
+// returned by CalDAVPasswordList.ValidateAll() if the designated constraints
+// aren't met.
+type CalDAVPasswordListMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CalDAVPasswordListMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CalDAVPasswordListMultiError) AllErrors() []error { return m }
+
+// CalDAVPasswordListValidationError is the validation error returned by
+// CalDAVPasswordList.Validate if the designated constraints aren't met.
+type CalDAVPasswordListValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CalDAVPasswordListValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CalDAVPasswordListValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CalDAVPasswordListValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CalDAVPasswordListValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CalDAVPasswordListValidationError) ErrorName() string {
+	return "CalDAVPasswordListValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CalDAVPasswordListValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCalDAVPasswordList.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CalDAVPasswordListValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CalDAVPasswordListValidationError{}
+
+// Validate checks the field values on CalDAVPasswordRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *CalDAVPasswordRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CalDAVPasswordRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// CalDAVPasswordRequestMultiError, or nil if none found.
+func (m *CalDAVPasswordRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CalDAVPasswordRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetPasswordId()); l < 1 || l > 128 {
+		err := CalDAVPasswordRequestValidationError{
+			field:  "PasswordId",
+			reason: "value length must be between 1 and 128 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return CalDAVPasswordRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// CalDAVPasswordRequestMultiError is an error wrapping multiple validation
+// errors returned by CalDAVPasswordRequest.ValidateAll() if the designated
+// constraints aren't met.
+type CalDAVPasswordRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CalDAVPasswordRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CalDAVPasswordRequestMultiError) AllErrors() []error { return m }
+
+// CalDAVPasswordRequestValidationError is the validation error returned by
+// CalDAVPasswordRequest.Validate if the designated constraints aren't met.
+type CalDAVPasswordRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CalDAVPasswordRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CalDAVPasswordRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CalDAVPasswordRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CalDAVPasswordRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CalDAVPasswordRequestValidationError) ErrorName() string {
+	return "CalDAVPasswordRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CalDAVPasswordRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCalDAVPasswordRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CalDAVPasswordRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CalDAVPasswordRequestValidationError{}
+
+// Validate checks the field values on Webhook with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Webhook) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Webhook with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in WebhookMultiError, or nil if none found.
+func (m *Webhook) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Webhook) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetWebhookId()) > 128 {
+		err := WebhookValidationError{
+			field:  "WebhookId",
+			reason: "value length must be at most 128 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetUrl()) > 2048 {
+		err := WebhookValidationError{
+			field:  "Url",
+			reason: "value length must be at most 2048 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if uri, err := url.Parse(m.GetUrl()); err != nil {
+		err = WebhookValidationError{
+			field:  "Url",
+			reason: "value must be a valid URI",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	} else if !uri.IsAbs() {
+		err := WebhookValidationError{
+			field:  "Url",
+			reason: "value must be absolute",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetSecret()) > 256 {
+		err := WebhookValidationError{
+			field:  "Secret",
+			reason: "value length must be at most 256 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Disabled
+
+	// no validation rules for DisabledReason
+
+	// no validation rules for ConsecutiveFailures
+
+	// no validation rules for CreatedAt
+
+	if len(errors) > 0 {
+		return WebhookMultiError(errors)
+	}
+
+	return nil
+}
+
+// WebhookMultiError is an error wrapping multiple validation errors returned
+// by Webhook.ValidateAll() if the designated constraints aren't met.
+type WebhookMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WebhookMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WebhookMultiError) AllErrors() []error { return m }
+
+// WebhookValidationError is the validation error returned by Webhook.Validate
+// if the designated constraints aren't met.
+type WebhookValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WebhookValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WebhookValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WebhookValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WebhookValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WebhookValidationError) ErrorName() string { return "WebhookValidationError" }
+
+// Error satisfies the builtin error interface
+func (e WebhookValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWebhook.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WebhookValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WebhookValidationError{}
+
+// Validate checks the field values on WebhookList with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *WebhookList) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on WebhookList with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in WebhookListMultiError, or
+// nil if none found.
+func (m *WebhookList) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *WebhookList) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetWebhooks() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, WebhookListValidationError{
+						field:  fmt.Sprintf("Webhooks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, WebhookListValidationError{
+						field:  fmt.Sprintf("Webhooks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return WebhookListValidationError{
+					field:  fmt.Sprintf("Webhooks[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return WebhookListMultiError(errors)
+	}
+
+	return nil
+}
+
+// WebhookListMultiError is an error wrapping multiple validation errors
+// returned by WebhookList.ValidateAll() if the designated constraints aren't met.
+type WebhookListMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WebhookListMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WebhookListMultiError) AllErrors() []error { return m }
+
+// WebhookListValidationError is the validation error returned by
+// WebhookList.Validate if the designated constraints aren't met.
+type WebhookListValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WebhookListValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WebhookListValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WebhookListValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WebhookListValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WebhookListValidationError) ErrorName() string { return "WebhookListValidationError" }
+
+// Error satisfies the builtin error interface
+func (e WebhookListValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWebhookList.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WebhookListValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WebhookListValidationError{}
+
+// Validate checks the field values on WebhookRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *WebhookRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on WebhookRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in WebhookRequestMultiError,
+// or nil if none found.
+func (m *WebhookRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *WebhookRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetWebhookId()); l < 1 || l > 128 {
+		err := WebhookRequestValidationError{
+			field:  "WebhookId",
+			reason: "value length must be between 1 and 128 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return WebhookRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// WebhookRequestMultiError is an error wrapping multiple validation errors
+// returned by WebhookRequest.ValidateAll() if the designated constraints
+// aren't met.
+type WebhookRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WebhookRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WebhookRequestMultiError) AllErrors() []error { return m }
+
+// WebhookRequestValidationError is the validation error returned by
+// WebhookRequest.Validate if the designated constraints aren't met.
+type WebhookRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WebhookRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WebhookRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WebhookRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WebhookRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WebhookRequestValidationError) ErrorName() string { return "WebhookRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e WebhookRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWebhookRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WebhookRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WebhookRequestValidationError{}
+
+// Validate checks the field values on ListWebhookDeliveriesRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ListWebhookDeliveriesRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListWebhookDeliveriesRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListWebhookDeliveriesRequestMultiError, or nil if none found.
+func (m *ListWebhookDeliveriesRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListWebhookDeliveriesRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetWebhookId()); l < 1 || l > 128 {
+		err := ListWebhookDeliveriesRequestValidationError{
+			field:  "WebhookId",
+			reason: "value length must be between 1 and 128 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetN() < 0 {
+		err := ListWebhookDeliveriesRequestValidationError{
+			field:  "N",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return ListWebhookDeliveriesRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListWebhookDeliveriesRequestMultiError is an error wrapping multiple
+// validation errors returned by ListWebhookDeliveriesRequest.ValidateAll() if
+// the designated constraints aren't met.
+type ListWebhookDeliveriesRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListWebhookDeliveriesRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListWebhookDeliveriesRequestMultiError) AllErrors() []error { return m }
+
+// ListWebhookDeliveriesRequestValidationError is the validation error returned
+// by ListWebhookDeliveriesRequest.Validate if the designated constraints
+// aren't met.
+type ListWebhookDeliveriesRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListWebhookDeliveriesRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListWebhookDeliveriesRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListWebhookDeliveriesRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListWebhookDeliveriesRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListWebhookDeliveriesRequestValidationError) ErrorName() string {
+	return "ListWebhookDeliveriesRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListWebhookDeliveriesRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListWebhookDeliveriesRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListWebhookDeliveriesRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListWebhookDeliveriesRequestValidationError{}
+
+// Validate checks the field values on WebhookDelivery with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *WebhookDelivery) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on WebhookDelivery with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// WebhookDeliveryMultiError, or nil if none found.
+func (m *WebhookDelivery) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *WebhookDelivery) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for DeliveryId
+
+	// no validation rules for WebhookId
+
+	// no validation rules for EventId
+
+	// no validation rules for Event
+
+	// no validation rules for Attempt
+
+	// no validation rules for StatusCode
+
+	// no validation rules for Error
+
+	// no validation rules for Success
+
+	// no validation rules for DurationMs
+
+	// no validation rules for CreatedAt
+
+	if len(errors) > 0 {
+		return WebhookDeliveryMultiError(errors)
+	}
+
+	return nil
+}
+
+// WebhookDeliveryMultiError is an error wrapping multiple validation errors
+// returned by WebhookDelivery.ValidateAll() if the designated constraints
+// aren't met.
+type WebhookDeliveryMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WebhookDeliveryMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WebhookDeliveryMultiError) AllErrors() []error { return m }
+
+// WebhookDeliveryValidationError is the validation error returned by
+// WebhookDelivery.Validate if the designated constraints aren't met.
+type WebhookDeliveryValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WebhookDeliveryValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WebhookDeliveryValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WebhookDeliveryValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WebhookDeliveryValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WebhookDeliveryValidationError) ErrorName() string { return "WebhookDeliveryValidationError" }
+
+// Error satisfies the builtin error interface
+func (e WebhookDeliveryValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWebhookDelivery.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WebhookDeliveryValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WebhookDeliveryValidationError{}
+
+// Validate checks the field values on WebhookDeliveryList with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *WebhookDeliveryList) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on WebhookDeliveryList with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// WebhookDeliveryListMultiError, or nil if none found.
+func (m *WebhookDeliveryList) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *WebhookDeliveryList) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetDeliveries() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, WebhookDeliveryListValidationError{
+						field:  fmt.Sprintf("Deliveries[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, WebhookDeliveryListValidationError{
+						field:  fmt.Sprintf("Deliveries[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return WebhookDeliveryListValidationError{
+					field:  fmt.Sprintf("Deliveries[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return WebhookDeliveryListMultiError(errors)
+	}
+
+	return nil
+}
+
+// WebhookDeliveryListMultiError is an error wrapping multiple validation
+// errors returned by WebhookDeliveryList.ValidateAll() if the designated
+// constraints aren't met.
+type WebhookDeliveryListMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WebhookDeliveryListMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WebhookDeliveryListMultiError) AllErrors() []error { return m }
+
+// WebhookDeliveryListValidationError is the validation error returned by
+// WebhookDeliveryList.Validate if the designated constraints aren't met.
+type WebhookDeliveryListValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WebhookDeliveryListValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WebhookDeliveryListValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WebhookDeliveryListValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WebhookDeliveryListValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WebhookDeliveryListValidationError) ErrorName() string {
+	return "WebhookDeliveryListValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e WebhookDeliveryListValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWebhookDeliveryList.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WebhookDeliveryListValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WebhookDeliveryListValidationError{}
+
+// Validate checks the field values on AdminListTasksRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *AdminListTasksRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AdminListTasksRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// AdminListTasksRequestMultiError, or nil if none found.
+func (m *AdminListTasksRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AdminListTasksRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetUserId()) > 128 {
+		err := AdminListTasksRequestValidationError{
+			field:  "UserId",
+			reason: "value length must be at most 128 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_AdminListTasksRequest_UserEmail_Pattern.MatchString(m.GetUserEmail()) {
+		err := AdminListTasksRequestValidationError{
+			field:  "UserEmail",
+			reason: "value does not match regex pattern \"^$|^[^@\\\\s]+@[^@\\\\s]+$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetN() < 0 {
+		err := AdminListTasksRequestValidationError{
+			field:  "N",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return AdminListTasksRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// AdminListTasksRequestMultiError is an error wrapping multiple validation
+// errors returned by AdminListTasksRequest.ValidateAll() if the designated
+// constraints aren't met.
+type AdminListTasksRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AdminListTasksRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AdminListTasksRequestMultiError) AllErrors() []error { return m }
+
+// AdminListTasksRequestValidationError is the validation error returned by
+// AdminListTasksRequest.Validate if the designated constraints aren't met.
+type AdminListTasksRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AdminListTasksRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AdminListTasksRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AdminListTasksRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AdminListTasksRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AdminListTasksRequestValidationError) ErrorName() string {
+	return "AdminListTasksRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e AdminListTasksRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAdminListTasksRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AdminListTasksRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AdminListTasksRequestValidationError{}
+
+var _AdminListTasksRequest_UserEmail_Pattern = regexp.MustCompile("^$|^[^@\\s]+@[^@\\s]+$")
+
+// Validate checks the field values on AdminTaskRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *AdminTaskRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AdminTaskRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// AdminTaskRequestMultiError, or nil if none found.
+func (m *AdminTaskRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AdminTaskRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetUserId()); l < 1 || l > 128 {
+		err := AdminTaskRequestValidationError{
+			field:  "UserId",
+			reason: "value length must be between 1 and 128 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if l := utf8.RuneCountInString(m.GetTaskId()); l < 1 || l > 128 {
+		err := AdminTaskRequestValidationError{
+			field:  "TaskId",
+			reason: "value length must be between 1 and 128 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return AdminTaskRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// AdminTaskRequestMultiError is an error wrapping multiple validation errors
+// returned by AdminTaskRequest.ValidateAll() if the designated constraints
+// aren't met.
+type AdminTaskRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AdminTaskRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AdminTaskRequestMultiError) AllErrors() []error { return m }
+
+// AdminTaskRequestValidationError is the validation error returned by
+// AdminTaskRequest.Validate if the designated constraints aren't met.
+type AdminTaskRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AdminTaskRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AdminTaskRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AdminTaskRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AdminTaskRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AdminTaskRequestValidationError) ErrorName() string { return "AdminTaskRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AdminTaskRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAdminTaskRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AdminTaskRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AdminTaskRequestValidationError{}
+
+// Validate checks the field values on ReminderSettings with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ReminderSettings) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ReminderSettings with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ReminderSettingsMultiError, or nil if none found.
+func (m *ReminderSettings) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ReminderSettings) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(m.GetReminders()) > 10 {
+		err := ReminderSettingsValidationError{
+			field:  "Reminders",
+			reason: "value must contain no more than 10 item(s)",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	_ReminderSettings_Reminders_Unique := make(map[int64]struct{}, len(m.GetReminders()))
+
+	for idx, item := range m.GetReminders() {
+		_, _ = idx, item
+
+		if _, exists := _ReminderSettings_Reminders_Unique[item]; exists {
+			err := ReminderSettingsValidationError{
+				field:  fmt.Sprintf("Reminders[%v]", idx),
+				reason: "repeated value must contain unique items",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+			_ReminderSettings_Reminders_Unique[item] = struct{}{}
+		}
+
+		if val := item; val < 0 || val > 2592000 {
+			err := ReminderSettingsValidationError{
+				field:  fmt.Sprintf("Reminders[%v]", idx),
+				reason: "value must be inside range [0, 2592000]",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return ReminderSettingsMultiError(errors)
+	}
+
+	return nil
+}
+
+// ReminderSettingsMultiError is an error wrapping multiple validation errors
+// returned by ReminderSettings.ValidateAll() if the designated constraints
+// aren't met.
+type ReminderSettingsMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ReminderSettingsMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ReminderSettingsMultiError) AllErrors() []error { return m }
+
+// ReminderSettingsValidationError is the validation error returned by
+// ReminderSettings.Validate if the designated constraints aren't met.
+type ReminderSettingsValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ReminderSettingsValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ReminderSettingsValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ReminderSettingsValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ReminderSettingsValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ReminderSettingsValidationError) ErrorName() string { return "ReminderSettingsValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ReminderSettingsValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sReminderSettings.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ReminderSettingsValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ReminderSettingsValidationError{}
+
+// Validate checks the field values on NotificationChannel with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *NotificationChannel) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on NotificationChannel with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// NotificationChannelMultiError, or nil if none found.
+func (m *NotificationChannel) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *NotificationChannel) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if _, ok := NotificationChannelType_name[int32(m.GetType())]; !ok {
+		err := NotificationChannelValidationError{
+			field:  "Type",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Enabled
+
+	if utf8.RuneCountInString(m.GetTarget()) > 2048 {
+		err := NotificationChannelValidationError{
+			field:  "Target",
+			reason: "value length must be at most 2048 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetSecret()) > 256 {
+		err := NotificationChannelValidationError{
+			field:  "Secret",
+			reason: "value length must be at most 256 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for HasSecret
+
+	if len(errors) > 0 {
+		return NotificationChannelMultiError(errors)
+	}
+
+	return nil
+}
+
+// NotificationChannelMultiError is an error wrapping multiple validation
+// errors returned by NotificationChannel.ValidateAll() if the designated
+// constraints aren't met.
+type NotificationChannelMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m NotificationChannelMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m NotificationChannelMultiError) AllErrors() []error { return m }
+
+// NotificationChannelValidationError is the validation error returned by
+// NotificationChannel.Validate if the designated constraints aren't met.
+type NotificationChannelValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e NotificationChannelValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e NotificationChannelValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e NotificationChannelValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e NotificationChannelValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e NotificationChannelValidationError) ErrorName() string {
+	return "NotificationChannelValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e NotificationChannelValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sNotificationChannel.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = NotificationChannelValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = NotificationChannelValidationError{}
+
+// Validate checks the field values on NotificationPreferences with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *NotificationPreferences) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on NotificationPreferences with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// NotificationPreferencesMultiError, or nil if none found.
+func (m *NotificationPreferences) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *NotificationPreferences) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(m.GetChannels()) > 4 {
+		err := NotificationPreferencesValidationError{
+			field:  "Channels",
+			reason: "value must contain no more than 4 item(s)",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	for idx, item := range m.GetChannels() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, NotificationPreferencesValidationError{
+						field:  fmt.Sprintf("Channels[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, NotificationPreferencesValidationError{
+						field:  fmt.Sprintf("Channels[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return NotificationPreferencesValidationError{
+					field:  fmt.Sprintf("Channels[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if utf8.RuneCountInString(m.GetLocale()) > 35 {
+		err := NotificationPreferencesValidationError{
+			field:  "Locale",
+			reason: "value length must be at most 35 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetTimeZone()) > 64 {
+		err := NotificationPreferencesValidationError{
+			field:  "TimeZone",
+			reason: "value length must be at most 64 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetQuietHours()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, NotificationPreferencesValidationError{
+					field:  "QuietHours",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, NotificationPreferencesValidationError{
+					field:  "QuietHours",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetQuietHours()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return NotificationPreferencesValidationError{
+				field:  "QuietHours",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	_NotificationPreferences_Events_Unique := make(map[NotificationEventType]struct{}, len(m.GetEvents()))
+
+	for idx, item := range m.GetEvents() {
+		_, _ = idx, item
+
+		if _, exists := _NotificationPreferences_Events_Unique[item]; exists {
+			err := NotificationPreferencesValidationError{
+				field:  fmt.Sprintf("Events[%v]", idx),
+				reason: "repeated value must contain unique items",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+			_NotificationPreferences_Events_Unique[item] = struct{}{}
+		}
+
+		if _, ok := NotificationEventType_name[int32(item)]; !ok {
+			err := NotificationPreferencesValidationError{
+				field:  fmt.Sprintf("Events[%v]", idx),
+				reason: "value must be one of the defined enum values",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	// no validation rules for NotifyNone
+
+	if len(errors) > 0 {
+		return NotificationPreferencesMultiError(errors)
+	}
+
+	return nil
+}
+
+// NotificationPreferencesMultiError is an error wrapping multiple validation
+// errors returned by NotificationPreferences.ValidateAll() if the designated
+// constraints aren't met.
+type NotificationPreferencesMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m NotificationPreferencesMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m NotificationPreferencesMultiError) AllErrors() []error { return m }
+
+// NotificationPreferencesValidationError is the validation error returned by
+// NotificationPreferences.Validate if the designated constraints aren't met.
+type NotificationPreferencesValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e NotificationPreferencesValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e NotificationPreferencesValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e NotificationPreferencesValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e NotificationPreferencesValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e NotificationPreferencesValidationError) ErrorName() string {
+	return "NotificationPreferencesValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e NotificationPreferencesValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sNotificationPreferences.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = NotificationPreferencesValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = NotificationPreferencesValidationError{}
+
+// Validate checks the field values on QuietHours with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *QuietHours) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on QuietHours with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in QuietHoursMultiError, or
+// nil if none found.
+func (m *QuietHours) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *QuietHours) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if !_QuietHours_Start_Pattern.MatchString(m.GetStart()) {
+		err := QuietHoursValidationError{
+			field:  "Start",
+			reason: "value does not match regex pattern \"^$|^([01][0-9]|2[0-3]):[0-5][0-9]$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_QuietHours_End_Pattern.MatchString(m.GetEnd()) {
+		err := QuietHoursValidationError{
+			field:  "End",
+			reason: "value does not match regex pattern \"^$|^([01][0-9]|2[0-3]):[0-5][0-9]$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return QuietHoursMultiError(errors)
+	}
+
+	return nil
+}
+
+// QuietHoursMultiError is an error wrapping multiple validation errors
+// returned by QuietHours.ValidateAll() if the designated constraints aren't met.
+type QuietHoursMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m QuietHoursMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m QuietHoursMultiError) AllErrors() []error { return m }
+
+// QuietHoursValidationError is the validation error returned by
+// QuietHours.Validate if the designated constraints aren't met.
+type QuietHoursValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e QuietHoursValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e QuietHoursValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e QuietHoursValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e QuietHoursValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e QuietHoursValidationError) ErrorName() string { return "QuietHoursValidationError" }
+
+// Error satisfies the builtin error interface
+func (e QuietHoursValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sQuietHours.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = QuietHoursValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = QuietHoursValidationError{}
+
+var _QuietHours_Start_Pattern = regexp.MustCompile("^$|^([01][0-9]|2[0-3]):[0-5][0-9]$")
+
+var _QuietHours_End_Pattern = regexp.MustCompile("^$|^([01][0-9]|2[0-3]):[0-5][0-9]$")
+
+// Validate checks the field values on DigestSettings with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *DigestSettings) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DigestSettings with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in DigestSettingsMultiError,
+// or nil if none found.
+func (m *DigestSettings) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DigestSettings) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if _, ok := DigestFrequency_name[int32(m.GetFrequency())]; !ok {
+		err := DigestSettingsValidationError{
+			field:  "Frequency",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if val := m.GetHour(); val < 0 || val > 23 {
+		err := DigestSettingsValidationError{
+			field:  "Hour",
+			reason: "value must be inside range [0, 23]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if val := m.GetWeekday(); val < 0 || val > 6 {
+		err := DigestSettingsValidationError{
+			field:  "Weekday",
+			reason: "value must be inside range [0, 6]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for LastPeriod
+
+	if len(errors) > 0 {
+		return DigestSettingsMultiError(errors)
+	}
+
+	return nil
+}
+
+// DigestSettingsMultiError is an error wrapping multiple validation errors
+// returned by DigestSettings.ValidateAll() if the designated constraints
+// aren't met.
+type DigestSettingsMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DigestSettingsMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DigestSettingsMultiError) AllErrors() []error { return m }
+
+// DigestSettingsValidationError is the validation error returned by
+// DigestSettings.Validate if the designated constraints aren't met.
+type DigestSettingsValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DigestSettingsValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DigestSettingsValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DigestSettingsValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DigestSettingsValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DigestSettingsValidationError) ErrorName() string { return "DigestSettingsValidationError" }
+
+// Error satisfies the builtin error interface
+func (e DigestSettingsValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDigestSettings.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DigestSettingsValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DigestSettingsValidationError{}
+
+// Validate checks the field values on EscalationPolicy with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *EscalationPolicy) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on EscalationPolicy with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// EscalationPolicyMultiError, or nil if none found.
+func (m *EscalationPolicy) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *EscalationPolicy) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(m.GetIntervals()) > 10 {
+		err := EscalationPolicyValidationError{
+			field:  "Intervals",
+			reason: "value must contain no more than 10 item(s)",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	_EscalationPolicy_Intervals_Unique := make(map[int64]struct{}, len(m.GetIntervals()))
+
+	for idx, item := range m.GetIntervals() {
+		_, _ = idx, item
+
+		if _, exists := _EscalationPolicy_Intervals_Unique[item]; exists {
+			err := EscalationPolicyValidationError{
+				field:  fmt.Sprintf("Intervals[%v]", idx),
+				reason: "repeated value must contain unique items",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+			_EscalationPolicy_Intervals_Unique[item] = struct{}{}
+		}
+
+		if val := item; val <= 0 || val > 2592000 {
+			err := EscalationPolicyValidationError{
+				field:  fmt.Sprintf("Intervals[%v]", idx),
+				reason: "value must be inside range (0, 2592000]",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if val := m.GetEscalateAfter(); val < 0 || val > 2592000 {
+		err := EscalationPolicyValidationError{
+			field:  "EscalateAfter",
+			reason: "value must be inside range [0, 2592000]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetEscalationEmail()) > 320 {
+		err := EscalationPolicyValidationError{
+			field:  "EscalationEmail",
+			reason: "value length must be at most 320 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for EscalationEmailVerified
+
+	if len(errors) > 0 {
+		return EscalationPolicyMultiError(errors)
+	}
+
+	return nil
+}
+
+// EscalationPolicyMultiError is an error wrapping multiple validation errors
+// returned by EscalationPolicy.ValidateAll() if the designated constraints
+// aren't met.
+type EscalationPolicyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m EscalationPolicyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m EscalationPolicyMultiError) AllErrors() []error { return m }
+
+// EscalationPolicyValidationError is the validation error returned by
+// EscalationPolicy.Validate if the designated constraints aren't met.
+type EscalationPolicyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e EscalationPolicyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e EscalationPolicyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e EscalationPolicyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e EscalationPolicyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e EscalationPolicyValidationError) ErrorName() string { return "EscalationPolicyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e EscalationPolicyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sEscalationPolicy.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = EscalationPolicyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = EscalationPolicyValidationError{}
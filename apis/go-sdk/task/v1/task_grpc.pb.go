@@ -29,6 +29,64 @@ type TaskServiceClient interface {
 	DeleteTask(ctx context.Context, in *DeleteTaskRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 	GetLastN(ctx context.Context, in *GetLastNRequest, opts ...grpc.CallOption) (*TaskList, error)
 	GetExpired(ctx context.Context, in *GetExpiredRequest, opts ...grpc.CallOption) (*TaskList, error)
+	// SyncTasks returns the changes of caller's tasks since the sync token, so that clients can keep
+	// an offline replica. Without a token, or with one older than the tombstone retention, all tasks
+	// are returned with full_sync set and the client replaces its replica
+	SyncTasks(ctx context.Context, in *SyncTasksRequest, opts ...grpc.CallOption) (*SyncTasksResponse, error)
+	// WatchTasks streams changes of caller's tasks as they happen. Streams resumed with a sync token
+	// first catch up on the changes missed since the token
+	WatchTasks(ctx context.Context, in *WatchTasksRequest, opts ...grpc.CallOption) (TaskService_WatchTasksClient, error)
+	GetTaskStats(ctx context.Context, in *GetTaskStatsRequest, opts ...grpc.CallOption) (*TaskStats, error)
+	// ExportTasks starts a background job writing all caller's tasks to the object storage
+	ExportTasks(ctx context.Context, in *ExportTasksRequest, opts ...grpc.CallOption) (*ExportJob, error)
+	GetExportJob(ctx context.Context, in *GetExportJobRequest, opts ...grpc.CallOption) (*ExportJob, error)
+	// ExportTasksText returns all caller's tasks in a plain text format
+	ExportTasksText(ctx context.Context, in *ExportTasksTextRequest, opts ...grpc.CallOption) (*TasksText, error)
+	// ImportTasks creates tasks from a document exported by a task manager, items that can't be
+	// parsed are reported and skipped
+	ImportTasks(ctx context.Context, in *ImportTasksRequest, opts ...grpc.CallOption) (*ImportTasksResponse, error)
+	// RotateCalendarToken replaces the secret token of the caller's iCalendar feed,
+	// links with the previous token stop working
+	RotateCalendarToken(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*CalendarFeed, error)
+	// CreateCalDAVPassword generates an app password CalDAV clients sign in with, it's returned only here.
+	// The calendar feed token is read-only and doesn't work for CalDAV
+	CreateCalDAVPassword(ctx context.Context, in *CalDAVPassword, opts ...grpc.CallOption) (*CalDAVPassword, error)
+	ListCalDAVPasswords(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*CalDAVPasswordList, error)
+	// RevokeCalDAVPassword signs out the CalDAV client using the password
+	RevokeCalDAVPassword(ctx context.Context, in *CalDAVPasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// CreateWebhook subscribes the URL to events of caller's tasks, the secret signing
+	// the deliveries is generated when not given and returned only here
+	CreateWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Webhook, error)
+	GetWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	ListWebhooks(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*WebhookList, error)
+	// UpdateWebhook changes URL, events and the disabled flag, enabling the webhook resets its failures
+	UpdateWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*WebhookDeliveryList, error)
+	// GetReminderSettings returns the reminders new tasks of the caller get when created without any
+	GetReminderSettings(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ReminderSettings, error)
+	// UpdateReminderSettings replaces the reminder defaults of the caller, existing tasks keep their reminders
+	UpdateReminderSettings(ctx context.Context, in *ReminderSettings, opts ...grpc.CallOption) (*ReminderSettings, error)
+	// GetNotificationPreferences returns the channels reminders of the caller are sent through,
+	// email only until the caller sets them
+	GetNotificationPreferences(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*NotificationPreferences, error)
+	// UpdateNotificationPreferences replaces the channels of the caller, secrets left empty are kept
+	UpdateNotificationPreferences(ctx context.Context, in *NotificationPreferences, opts ...grpc.CallOption) (*NotificationPreferences, error)
+	// GetDigestSettings returns the digest schedule of the caller, digests are off until the caller opts in
+	GetDigestSettings(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*DigestSettings, error)
+	// UpdateDigestSettings replaces the digest schedule of the caller, digests go to the email of the caller
+	// in the time zone of the notification preferences
+	UpdateDigestSettings(ctx context.Context, in *DigestSettings, opts ...grpc.CallOption) (*DigestSettings, error)
+	// GetEscalationPolicy returns what happens to overdue tasks of the caller, nothing until the caller sets it
+	GetEscalationPolicy(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*EscalationPolicy, error)
+	// UpdateEscalationPolicy replaces the escalation policy of the caller, the open tasks are checked
+	// with it shortly after, overdue reminders sent already aren't sent again and the ones past already are skipped. A new escalation email is emailed a confirmation link,
+	// another one may be set in 10 minutes
+	UpdateEscalationPolicy(ctx context.Context, in *EscalationPolicy, opts ...grpc.CallOption) (*EscalationPolicy, error)
+	AdminListTasks(ctx context.Context, in *AdminListTasksRequest, opts ...grpc.CallOption) (*TaskList, error)
+	AdminGetTask(ctx context.Context, in *AdminTaskRequest, opts ...grpc.CallOption) (*Task, error)
+	AdminUpdateTask(ctx context.Context, in *Task, opts ...grpc.CallOption) (*Task, error)
+	AdminDeleteTask(ctx context.Context, in *AdminTaskRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 }
 
 type taskServiceClient struct {
@@ -93,6 +151,290 @@ func (c *taskServiceClient) GetExpired(ctx context.Context, in *GetExpiredReques
 	return out, nil
 }
 
+func (c *taskServiceClient) SyncTasks(ctx context.Context, in *SyncTasksRequest, opts ...grpc.CallOption) (*SyncTasksResponse, error) {
+	out := new(SyncTasksResponse)
+	err := c.cc.Invoke(ctx, "/task.TaskService/SyncTasks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) WatchTasks(ctx context.Context, in *WatchTasksRequest, opts ...grpc.CallOption) (TaskService_WatchTasksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaskService_ServiceDesc.Streams[0], "/task.TaskService/WatchTasks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taskServiceWatchTasksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TaskService_WatchTasksClient interface {
+	Recv() (*TaskEvent, error)
+	grpc.ClientStream
+}
+
+type taskServiceWatchTasksClient struct {
+	grpc.ClientStream
+}
+
+func (x *taskServiceWatchTasksClient) Recv() (*TaskEvent, error) {
+	m := new(TaskEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taskServiceClient) GetTaskStats(ctx context.Context, in *GetTaskStatsRequest, opts ...grpc.CallOption) (*TaskStats, error) {
+	out := new(TaskStats)
+	err := c.cc.Invoke(ctx, "/task.TaskService/GetTaskStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ExportTasks(ctx context.Context, in *ExportTasksRequest, opts ...grpc.CallOption) (*ExportJob, error) {
+	out := new(ExportJob)
+	err := c.cc.Invoke(ctx, "/task.TaskService/ExportTasks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetExportJob(ctx context.Context, in *GetExportJobRequest, opts ...grpc.CallOption) (*ExportJob, error) {
+	out := new(ExportJob)
+	err := c.cc.Invoke(ctx, "/task.TaskService/GetExportJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ExportTasksText(ctx context.Context, in *ExportTasksTextRequest, opts ...grpc.CallOption) (*TasksText, error) {
+	out := new(TasksText)
+	err := c.cc.Invoke(ctx, "/task.TaskService/ExportTasksText", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ImportTasks(ctx context.Context, in *ImportTasksRequest, opts ...grpc.CallOption) (*ImportTasksResponse, error) {
+	out := new(ImportTasksResponse)
+	err := c.cc.Invoke(ctx, "/task.TaskService/ImportTasks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) RotateCalendarToken(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*CalendarFeed, error) {
+	out := new(CalendarFeed)
+	err := c.cc.Invoke(ctx, "/task.TaskService/RotateCalendarToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) CreateCalDAVPassword(ctx context.Context, in *CalDAVPassword, opts ...grpc.CallOption) (*CalDAVPassword, error) {
+	out := new(CalDAVPassword)
+	err := c.cc.Invoke(ctx, "/task.TaskService/CreateCalDAVPassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ListCalDAVPasswords(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*CalDAVPasswordList, error) {
+	out := new(CalDAVPasswordList)
+	err := c.cc.Invoke(ctx, "/task.TaskService/ListCalDAVPasswords", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) RevokeCalDAVPassword(ctx context.Context, in *CalDAVPasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/task.TaskService/RevokeCalDAVPassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) CreateWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, "/task.TaskService/CreateWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, "/task.TaskService/GetWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ListWebhooks(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*WebhookList, error) {
+	out := new(WebhookList)
+	err := c.cc.Invoke(ctx, "/task.TaskService/ListWebhooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) UpdateWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, "/task.TaskService/UpdateWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) DeleteWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/task.TaskService/DeleteWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*WebhookDeliveryList, error) {
+	out := new(WebhookDeliveryList)
+	err := c.cc.Invoke(ctx, "/task.TaskService/ListWebhookDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetReminderSettings(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ReminderSettings, error) {
+	out := new(ReminderSettings)
+	err := c.cc.Invoke(ctx, "/task.TaskService/GetReminderSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) UpdateReminderSettings(ctx context.Context, in *ReminderSettings, opts ...grpc.CallOption) (*ReminderSettings, error) {
+	out := new(ReminderSettings)
+	err := c.cc.Invoke(ctx, "/task.TaskService/UpdateReminderSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetNotificationPreferences(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*NotificationPreferences, error) {
+	out := new(NotificationPreferences)
+	err := c.cc.Invoke(ctx, "/task.TaskService/GetNotificationPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) UpdateNotificationPreferences(ctx context.Context, in *NotificationPreferences, opts ...grpc.CallOption) (*NotificationPreferences, error) {
+	out := new(NotificationPreferences)
+	err := c.cc.Invoke(ctx, "/task.TaskService/UpdateNotificationPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetDigestSettings(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*DigestSettings, error) {
+	out := new(DigestSettings)
+	err := c.cc.Invoke(ctx, "/task.TaskService/GetDigestSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) UpdateDigestSettings(ctx context.Context, in *DigestSettings, opts ...grpc.CallOption) (*DigestSettings, error) {
+	out := new(DigestSettings)
+	err := c.cc.Invoke(ctx, "/task.TaskService/UpdateDigestSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetEscalationPolicy(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*EscalationPolicy, error) {
+	out := new(EscalationPolicy)
+	err := c.cc.Invoke(ctx, "/task.TaskService/GetEscalationPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) UpdateEscalationPolicy(ctx context.Context, in *EscalationPolicy, opts ...grpc.CallOption) (*EscalationPolicy, error) {
+	out := new(EscalationPolicy)
+	err := c.cc.Invoke(ctx, "/task.TaskService/UpdateEscalationPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) AdminListTasks(ctx context.Context, in *AdminListTasksRequest, opts ...grpc.CallOption) (*TaskList, error) {
+	out := new(TaskList)
+	err := c.cc.Invoke(ctx, "/task.TaskService/AdminListTasks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) AdminGetTask(ctx context.Context, in *AdminTaskRequest, opts ...grpc.CallOption) (*Task, error) {
+	out := new(Task)
+	err := c.cc.Invoke(ctx, "/task.TaskService/AdminGetTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) AdminUpdateTask(ctx context.Context, in *Task, opts ...grpc.CallOption) (*Task, error) {
+	out := new(Task)
+	err := c.cc.Invoke(ctx, "/task.TaskService/AdminUpdateTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) AdminDeleteTask(ctx context.Context, in *AdminTaskRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/task.TaskService/AdminDeleteTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TaskServiceServer is the server API for TaskService service.
 // All implementations must embed UnimplementedTaskServiceServer
 // for forward compatibility
@@ -103,6 +445,64 @@ type TaskServiceServer interface {
 	DeleteTask(context.Context, *DeleteTaskRequest) (*empty.Empty, error)
 	GetLastN(context.Context, *GetLastNRequest) (*TaskList, error)
 	GetExpired(context.Context, *GetExpiredRequest) (*TaskList, error)
+	// SyncTasks returns the changes of caller's tasks since the sync token, so that clients can keep
+	// an offline replica. Without a token, or with one older than the tombstone retention, all tasks
+	// are returned with full_sync set and the client replaces its replica
+	SyncTasks(context.Context, *SyncTasksRequest) (*SyncTasksResponse, error)
+	// WatchTasks streams changes of caller's tasks as they happen. Streams resumed with a sync token
+	// first catch up on the changes missed since the token
+	WatchTasks(*WatchTasksRequest, TaskService_WatchTasksServer) error
+	GetTaskStats(context.Context, *GetTaskStatsRequest) (*TaskStats, error)
+	// ExportTasks starts a background job writing all caller's tasks to the object storage
+	ExportTasks(context.Context, *ExportTasksRequest) (*ExportJob, error)
+	GetExportJob(context.Context, *GetExportJobRequest) (*ExportJob, error)
+	// ExportTasksText returns all caller's tasks in a plain text format
+	ExportTasksText(context.Context, *ExportTasksTextRequest) (*TasksText, error)
+	// ImportTasks creates tasks from a document exported by a task manager, items that can't be
+	// parsed are reported and skipped
+	ImportTasks(context.Context, *ImportTasksRequest) (*ImportTasksResponse, error)
+	// RotateCalendarToken replaces the secret token of the caller's iCalendar feed,
+	// links with the previous token stop working
+	RotateCalendarToken(context.Context, *empty.Empty) (*CalendarFeed, error)
+	// CreateCalDAVPassword generates an app password CalDAV clients sign in with, it's returned only here.
+	// The calendar feed token is read-only and doesn't work for CalDAV
+	CreateCalDAVPassword(context.Context, *CalDAVPassword) (*CalDAVPassword, error)
+	ListCalDAVPasswords(context.Context, *empty.Empty) (*CalDAVPasswordList, error)
+	// RevokeCalDAVPassword signs out the CalDAV client using the password
+	RevokeCalDAVPassword(context.Context, *CalDAVPasswordRequest) (*empty.Empty, error)
+	// CreateWebhook subscribes the URL to events of caller's tasks, the secret signing
+	// the deliveries is generated when not given and returned only here
+	CreateWebhook(context.Context, *Webhook) (*Webhook, error)
+	GetWebhook(context.Context, *WebhookRequest) (*Webhook, error)
+	ListWebhooks(context.Context, *empty.Empty) (*WebhookList, error)
+	// UpdateWebhook changes URL, events and the disabled flag, enabling the webhook resets its failures
+	UpdateWebhook(context.Context, *Webhook) (*Webhook, error)
+	DeleteWebhook(context.Context, *WebhookRequest) (*empty.Empty, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*WebhookDeliveryList, error)
+	// GetReminderSettings returns the reminders new tasks of the caller get when created without any
+	GetReminderSettings(context.Context, *empty.Empty) (*ReminderSettings, error)
+	// UpdateReminderSettings replaces the reminder defaults of the caller, existing tasks keep their reminders
+	UpdateReminderSettings(context.Context, *ReminderSettings) (*ReminderSettings, error)
+	// GetNotificationPreferences returns the channels reminders of the caller are sent through,
+	// email only until the caller sets them
+	GetNotificationPreferences(context.Context, *empty.Empty) (*NotificationPreferences, error)
+	// UpdateNotificationPreferences replaces the channels of the caller, secrets left empty are kept
+	UpdateNotificationPreferences(context.Context, *NotificationPreferences) (*NotificationPreferences, error)
+	// GetDigestSettings returns the digest schedule of the caller, digests are off until the caller opts in
+	GetDigestSettings(context.Context, *empty.Empty) (*DigestSettings, error)
+	// UpdateDigestSettings replaces the digest schedule of the caller, digests go to the email of the caller
+	// in the time zone of the notification preferences
+	UpdateDigestSettings(context.Context, *DigestSettings) (*DigestSettings, error)
+	// GetEscalationPolicy returns what happens to overdue tasks of the caller, nothing until the caller sets it
+	GetEscalationPolicy(context.Context, *empty.Empty) (*EscalationPolicy, error)
+	// UpdateEscalationPolicy replaces the escalation policy of the caller, the open tasks are checked
+	// with it shortly after, overdue reminders sent already aren't sent again and the ones past already are skipped. A new escalation email is emailed a confirmation link,
+	// another one may be set in 10 minutes
+	UpdateEscalationPolicy(context.Context, *EscalationPolicy) (*EscalationPolicy, error)
+	AdminListTasks(context.Context, *AdminListTasksRequest) (*TaskList, error)
+	AdminGetTask(context.Context, *AdminTaskRequest) (*Task, error)
+	AdminUpdateTask(context.Context, *Task) (*Task, error)
+	AdminDeleteTask(context.Context, *AdminTaskRequest) (*empty.Empty, error)
 	mustEmbedUnimplementedTaskServiceServer()
 }
 
@@ -128,6 +528,93 @@ func (UnimplementedTaskServiceServer) GetLastN(context.Context, *GetLastNRequest
 func (UnimplementedTaskServiceServer) GetExpired(context.Context, *GetExpiredRequest) (*TaskList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetExpired not implemented")
 }
+func (UnimplementedTaskServiceServer) SyncTasks(context.Context, *SyncTasksRequest) (*SyncTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncTasks not implemented")
+}
+func (UnimplementedTaskServiceServer) WatchTasks(*WatchTasksRequest, TaskService_WatchTasksServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTasks not implemented")
+}
+func (UnimplementedTaskServiceServer) GetTaskStats(context.Context, *GetTaskStatsRequest) (*TaskStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTaskStats not implemented")
+}
+func (UnimplementedTaskServiceServer) ExportTasks(context.Context, *ExportTasksRequest) (*ExportJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportTasks not implemented")
+}
+func (UnimplementedTaskServiceServer) GetExportJob(context.Context, *GetExportJobRequest) (*ExportJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExportJob not implemented")
+}
+func (UnimplementedTaskServiceServer) ExportTasksText(context.Context, *ExportTasksTextRequest) (*TasksText, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportTasksText not implemented")
+}
+func (UnimplementedTaskServiceServer) ImportTasks(context.Context, *ImportTasksRequest) (*ImportTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportTasks not implemented")
+}
+func (UnimplementedTaskServiceServer) RotateCalendarToken(context.Context, *empty.Empty) (*CalendarFeed, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateCalendarToken not implemented")
+}
+func (UnimplementedTaskServiceServer) CreateCalDAVPassword(context.Context, *CalDAVPassword) (*CalDAVPassword, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCalDAVPassword not implemented")
+}
+func (UnimplementedTaskServiceServer) ListCalDAVPasswords(context.Context, *empty.Empty) (*CalDAVPasswordList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCalDAVPasswords not implemented")
+}
+func (UnimplementedTaskServiceServer) RevokeCalDAVPassword(context.Context, *CalDAVPasswordRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeCalDAVPassword not implemented")
+}
+func (UnimplementedTaskServiceServer) CreateWebhook(context.Context, *Webhook) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedTaskServiceServer) GetWebhook(context.Context, *WebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhook not implemented")
+}
+func (UnimplementedTaskServiceServer) ListWebhooks(context.Context, *empty.Empty) (*WebhookList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedTaskServiceServer) UpdateWebhook(context.Context, *Webhook) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWebhook not implemented")
+}
+func (UnimplementedTaskServiceServer) DeleteWebhook(context.Context, *WebhookRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedTaskServiceServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*WebhookDeliveryList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedTaskServiceServer) GetReminderSettings(context.Context, *empty.Empty) (*ReminderSettings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReminderSettings not implemented")
+}
+func (UnimplementedTaskServiceServer) UpdateReminderSettings(context.Context, *ReminderSettings) (*ReminderSettings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateReminderSettings not implemented")
+}
+func (UnimplementedTaskServiceServer) GetNotificationPreferences(context.Context, *empty.Empty) (*NotificationPreferences, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotificationPreferences not implemented")
+}
+func (UnimplementedTaskServiceServer) UpdateNotificationPreferences(context.Context, *NotificationPreferences) (*NotificationPreferences, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNotificationPreferences not implemented")
+}
+func (UnimplementedTaskServiceServer) GetDigestSettings(context.Context, *empty.Empty) (*DigestSettings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDigestSettings not implemented")
+}
+func (UnimplementedTaskServiceServer) UpdateDigestSettings(context.Context, *DigestSettings) (*DigestSettings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDigestSettings not implemented")
+}
+func (UnimplementedTaskServiceServer) GetEscalationPolicy(context.Context, *empty.Empty) (*EscalationPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEscalationPolicy not implemented")
+}
+func (UnimplementedTaskServiceServer) UpdateEscalationPolicy(context.Context, *EscalationPolicy) (*EscalationPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateEscalationPolicy not implemented")
+}
+func (UnimplementedTaskServiceServer) AdminListTasks(context.Context, *AdminListTasksRequest) (*TaskList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminListTasks not implemented")
+}
+func (UnimplementedTaskServiceServer) AdminGetTask(context.Context, *AdminTaskRequest) (*Task, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminGetTask not implemented")
+}
+func (UnimplementedTaskServiceServer) AdminUpdateTask(context.Context, *Task) (*Task, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminUpdateTask not implemented")
+}
+func (UnimplementedTaskServiceServer) AdminDeleteTask(context.Context, *AdminTaskRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminDeleteTask not implemented")
+}
 func (UnimplementedTaskServiceServer) mustEmbedUnimplementedTaskServiceServer() {}
 
 // UnsafeTaskServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -249,38 +736,681 @@ func _TaskService_GetExpired_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
-// TaskService_ServiceDesc is the grpc.ServiceDesc for TaskService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var TaskService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "task.TaskService",
-	HandlerType: (*TaskServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "CreateTask",
-			Handler:    _TaskService_CreateTask_Handler,
-		},
-		{
-			MethodName: "GetTask",
-			Handler:    _TaskService_GetTask_Handler,
-		},
-		{
-			MethodName: "UpdateTask",
-			Handler:    _TaskService_UpdateTask_Handler,
-		},
-		{
-			MethodName: "DeleteTask",
-			Handler:    _TaskService_DeleteTask_Handler,
-		},
-		{
-			MethodName: "GetLastN",
-			Handler:    _TaskService_GetLastN_Handler,
-		},
-		{
-			MethodName: "GetExpired",
-			Handler:    _TaskService_GetExpired_Handler,
+func _TaskService_SyncTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).SyncTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/SyncTasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).SyncTasks(ctx, req.(*SyncTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_WatchTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTasksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaskServiceServer).WatchTasks(m, &taskServiceWatchTasksServer{stream})
+}
+
+type TaskService_WatchTasksServer interface {
+	Send(*TaskEvent) error
+	grpc.ServerStream
+}
+
+type taskServiceWatchTasksServer struct {
+	grpc.ServerStream
+}
+
+func (x *taskServiceWatchTasksServer) Send(m *TaskEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TaskService_GetTaskStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaskStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).GetTaskStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/GetTaskStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).GetTaskStats(ctx, req.(*GetTaskStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_ExportTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).ExportTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/ExportTasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).ExportTasks(ctx, req.(*ExportTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_GetExportJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExportJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).GetExportJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/GetExportJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).GetExportJob(ctx, req.(*GetExportJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_ExportTasksText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportTasksTextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).ExportTasksText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/ExportTasksText",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).ExportTasksText(ctx, req.(*ExportTasksTextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_ImportTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).ImportTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/ImportTasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).ImportTasks(ctx, req.(*ImportTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_RotateCalendarToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).RotateCalendarToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/RotateCalendarToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).RotateCalendarToken(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_CreateCalDAVPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalDAVPassword)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).CreateCalDAVPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/CreateCalDAVPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).CreateCalDAVPassword(ctx, req.(*CalDAVPassword))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_ListCalDAVPasswords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).ListCalDAVPasswords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/ListCalDAVPasswords",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).ListCalDAVPasswords(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_RevokeCalDAVPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalDAVPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).RevokeCalDAVPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/RevokeCalDAVPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).RevokeCalDAVPassword(ctx, req.(*CalDAVPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Webhook)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/CreateWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).CreateWebhook(ctx, req.(*Webhook))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_GetWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).GetWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/GetWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).GetWebhook(ctx, req.(*WebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/ListWebhooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).ListWebhooks(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_UpdateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Webhook)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).UpdateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/UpdateWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).UpdateWebhook(ctx, req.(*Webhook))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/DeleteWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).DeleteWebhook(ctx, req.(*WebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/ListWebhookDeliveries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_GetReminderSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).GetReminderSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/GetReminderSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).GetReminderSettings(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_UpdateReminderSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReminderSettings)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).UpdateReminderSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/UpdateReminderSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).UpdateReminderSettings(ctx, req.(*ReminderSettings))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_GetNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).GetNotificationPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/GetNotificationPreferences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).GetNotificationPreferences(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_UpdateNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationPreferences)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).UpdateNotificationPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/UpdateNotificationPreferences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).UpdateNotificationPreferences(ctx, req.(*NotificationPreferences))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_GetDigestSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).GetDigestSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/GetDigestSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).GetDigestSettings(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_UpdateDigestSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DigestSettings)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).UpdateDigestSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/UpdateDigestSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).UpdateDigestSettings(ctx, req.(*DigestSettings))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_GetEscalationPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).GetEscalationPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/GetEscalationPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).GetEscalationPolicy(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_UpdateEscalationPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EscalationPolicy)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).UpdateEscalationPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/UpdateEscalationPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).UpdateEscalationPolicy(ctx, req.(*EscalationPolicy))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_AdminListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminListTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).AdminListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/AdminListTasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).AdminListTasks(ctx, req.(*AdminListTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_AdminGetTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).AdminGetTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/AdminGetTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).AdminGetTask(ctx, req.(*AdminTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_AdminUpdateTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Task)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).AdminUpdateTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/AdminUpdateTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).AdminUpdateTask(ctx, req.(*Task))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskService_AdminDeleteTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).AdminDeleteTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.TaskService/AdminDeleteTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).AdminDeleteTask(ctx, req.(*AdminTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TaskService_ServiceDesc is the grpc.ServiceDesc for TaskService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TaskService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "task.TaskService",
+	HandlerType: (*TaskServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateTask",
+			Handler:    _TaskService_CreateTask_Handler,
+		},
+		{
+			MethodName: "GetTask",
+			Handler:    _TaskService_GetTask_Handler,
+		},
+		{
+			MethodName: "UpdateTask",
+			Handler:    _TaskService_UpdateTask_Handler,
+		},
+		{
+			MethodName: "DeleteTask",
+			Handler:    _TaskService_DeleteTask_Handler,
+		},
+		{
+			MethodName: "GetLastN",
+			Handler:    _TaskService_GetLastN_Handler,
+		},
+		{
+			MethodName: "GetExpired",
+			Handler:    _TaskService_GetExpired_Handler,
+		},
+		{
+			MethodName: "SyncTasks",
+			Handler:    _TaskService_SyncTasks_Handler,
+		},
+		{
+			MethodName: "GetTaskStats",
+			Handler:    _TaskService_GetTaskStats_Handler,
+		},
+		{
+			MethodName: "ExportTasks",
+			Handler:    _TaskService_ExportTasks_Handler,
+		},
+		{
+			MethodName: "GetExportJob",
+			Handler:    _TaskService_GetExportJob_Handler,
+		},
+		{
+			MethodName: "ExportTasksText",
+			Handler:    _TaskService_ExportTasksText_Handler,
+		},
+		{
+			MethodName: "ImportTasks",
+			Handler:    _TaskService_ImportTasks_Handler,
+		},
+		{
+			MethodName: "RotateCalendarToken",
+			Handler:    _TaskService_RotateCalendarToken_Handler,
+		},
+		{
+			MethodName: "CreateCalDAVPassword",
+			Handler:    _TaskService_CreateCalDAVPassword_Handler,
+		},
+		{
+			MethodName: "ListCalDAVPasswords",
+			Handler:    _TaskService_ListCalDAVPasswords_Handler,
+		},
+		{
+			MethodName: "RevokeCalDAVPassword",
+			Handler:    _TaskService_RevokeCalDAVPassword_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _TaskService_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "GetWebhook",
+			Handler:    _TaskService_GetWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _TaskService_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "UpdateWebhook",
+			Handler:    _TaskService_UpdateWebhook_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _TaskService_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _TaskService_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "GetReminderSettings",
+			Handler:    _TaskService_GetReminderSettings_Handler,
+		},
+		{
+			MethodName: "UpdateReminderSettings",
+			Handler:    _TaskService_UpdateReminderSettings_Handler,
+		},
+		{
+			MethodName: "GetNotificationPreferences",
+			Handler:    _TaskService_GetNotificationPreferences_Handler,
+		},
+		{
+			MethodName: "UpdateNotificationPreferences",
+			Handler:    _TaskService_UpdateNotificationPreferences_Handler,
+		},
+		{
+			MethodName: "GetDigestSettings",
+			Handler:    _TaskService_GetDigestSettings_Handler,
+		},
+		{
+			MethodName: "UpdateDigestSettings",
+			Handler:    _TaskService_UpdateDigestSettings_Handler,
+		},
+		{
+			MethodName: "GetEscalationPolicy",
+			Handler:    _TaskService_GetEscalationPolicy_Handler,
+		},
+		{
+			MethodName: "UpdateEscalationPolicy",
+			Handler:    _TaskService_UpdateEscalationPolicy_Handler,
+		},
+		{
+			MethodName: "AdminListTasks",
+			Handler:    _TaskService_AdminListTasks_Handler,
+		},
+		{
+			MethodName: "AdminGetTask",
+			Handler:    _TaskService_AdminGetTask_Handler,
+		},
+		{
+			MethodName: "AdminUpdateTask",
+			Handler:    _TaskService_AdminUpdateTask_Handler,
+		},
+		{
+			MethodName: "AdminDeleteTask",
+			Handler:    _TaskService_AdminDeleteTask_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTasks",
+			Handler:       _TaskService_WatchTasks_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "v1/task.proto",
 }
@@ -0,0 +1,111 @@
+// Package validation checks the requests of the services against the rules of their proto files,
+// violations are reported as apierror bad requests
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+)
+
+// validator is implemented by the messages generated by protoc-gen-validate
+type validator interface {
+	ValidateAll() error
+}
+
+// multiError is returned by ValidateAll, it holds every violation of the message
+type multiError interface {
+	AllErrors() []error
+}
+
+// fieldError is a single violation, the cause is set when the violation is in an embedded message
+type fieldError interface {
+	Field() string
+	Reason() string
+	Cause() error
+}
+
+// UnaryInterceptor rejects requests breaking the rules of the proto file before they reach the handlers
+func UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		err := Validate(req)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor validates every message received on the stream
+func StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		return handler(srv, &validatingStream{ServerStream: stream})
+	}
+}
+
+type validatingStream struct {
+	grpc.ServerStream
+}
+
+func (s *validatingStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err != nil {
+		return err
+	}
+	return Validate(m)
+}
+
+// Validate returns an InvalidArgument status with the field violations of the message,
+// messages without rules are always valid
+func Validate(msg interface{}) error {
+	v, ok := msg.(validator)
+	if !ok {
+		return nil
+	}
+	err := v.ValidateAll()
+	if err == nil {
+		return nil
+	}
+	violations := fieldViolations("", err)
+	return apierror.BadRequest(violationsMessage(violations), violations)
+}
+
+func fieldViolations(prefix string, err error) []*errdetails.BadRequest_FieldViolation {
+	var multi multiError
+	if errors.As(err, &multi) {
+		var violations []*errdetails.BadRequest_FieldViolation
+		for _, e := range multi.AllErrors() {
+			violations = append(violations, fieldViolations(prefix, e)...)
+		}
+		return violations
+	}
+	var field fieldError
+	if !errors.As(err, &field) {
+		return []*errdetails.BadRequest_FieldViolation{{Field: prefix, Description: err.Error()}}
+	}
+	path := field.Field()
+	if prefix != "" {
+		path = prefix + "." + path
+	}
+	// violations in embedded messages are reported with the full path of the field
+	if field.Cause() != nil {
+		return fieldViolations(path, field.Cause())
+	}
+	return []*errdetails.BadRequest_FieldViolation{{Field: path, Description: field.Reason()}}
+}
+
+func violationsMessage(violations []*errdetails.BadRequest_FieldViolation) string {
+	if len(violations) == 0 {
+		return "invalid request"
+	}
+	msg := fmt.Sprintf("invalid %s: %s", violations[0].Field, violations[0].Description)
+	if len(violations) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(violations)-1)
+	}
+	return msg
+}
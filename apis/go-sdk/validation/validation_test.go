@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"context"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"testing"
+)
+
+type ValidationTestSuite struct {
+	suite.Suite
+	interceptor grpc.UnaryServerInterceptor
+}
+
+func (s *ValidationTestSuite) SetupTest() {
+	s.interceptor = UnaryInterceptor()
+}
+
+func (s *ValidationTestSuite) TestUnaryInterceptor() {
+	candidates := []struct {
+		in                 interface{}
+		expectedCode       codes.Code
+		expectedViolations []string
+	}{
+		{
+			in:           &v1.Task{Name: "task1", Time: 1658102400},
+			expectedCode: codes.OK,
+		},
+		{
+			in:                 &v1.Task{Time: -1, Description: strings.Repeat("a", 4097)},
+			expectedCode:       codes.InvalidArgument,
+			expectedViolations: []string{"Time", "Name", "Description"},
+		},
+		{
+			in:                 &v1.GetTaskRequest{},
+			expectedCode:       codes.InvalidArgument,
+			expectedViolations: []string{"TaskId"},
+		},
+		// embedded messages are reported with the path of the field
+		{
+			in:                 &v1.TaskList{Tasks: []*v1.Task{{Name: "task1"}, {}}},
+			expectedCode:       codes.InvalidArgument,
+			expectedViolations: []string{"Tasks[1].Name"},
+		},
+		// messages without rules are passed through
+		{
+			in:           "not a message",
+			expectedCode: codes.OK,
+		},
+	}
+	for _, c := range candidates {
+		handled := false
+		_, err := s.interceptor(context.Background(), c.in, &grpc.UnaryServerInfo{},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				handled = true
+				return req, nil
+			})
+		s.Equal(c.expectedCode, status.Code(err))
+		s.Equal(err == nil, handled)
+		if err == nil {
+			continue
+		}
+		var fields []string
+		for _, detail := range status.Convert(err).Details() {
+			badRequest, ok := detail.(*errdetails.BadRequest)
+			if !ok {
+				continue
+			}
+			for _, violation := range badRequest.FieldViolations {
+				fields = append(fields, violation.Field)
+			}
+		}
+		s.ElementsMatch(c.expectedViolations, fields)
+	}
+}
+
+func TestValidationTestSuite(t *testing.T) {
+	suite.Run(t, new(ValidationTestSuite))
+}
@@ -1,17 +1,28 @@
 package main
 
 import (
+	"cloud.google.com/go/pubsub"
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	gcs "cloud.google.com/go/storage"
 	"context"
+	"crypto/rand"
+	"expvar"
 	firebase "firebase.google.com/go"
 	"fmt"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
 	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	v2 "github.com/jakubjano/todolist/apis/go-sdk/task/v2"
+	"github.com/jakubjano/todolist/apis/go-sdk/validation"
 	"github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/events"
 	"github.com/jakubjano/todolist/task/pkg/service"
+	"github.com/jakubjano/todolist/task/pkg/service/mail"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
 	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/jakubjano/todolist/task/pkg/service/storage"
 	"github.com/robfig/cron/v3"
 	"github.com/spf13/viper"
 	"google.golang.org/api/option"
@@ -20,18 +31,71 @@ import (
 	"google.golang.org/grpc/reflection"
 	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
+// shutdownTimeout is how long requests in flight may take after SIGTERM, Cloud Run kills the instance after 10s
+const shutdownTimeout = time.Second * 5
+
 func main() {
 
 	viper.SetDefault("grpc.port", ":8181")
 	viper.SetDefault("gateway.port", ":8180")
+	// debug.port serves /debug/vars, it must not be exposed, only the gateway port is public
+	viper.SetDefault("debug.port", ":8182")
 	viper.SetDefault("firebase.secret", "projects/todolist-356712/secrets/firebase-key/versions/latest")
 	viper.SetDefault("host", "smtp.mailtrap.io")
 	viper.SetDefault("from", "jakubjanek8@gmail.com")
 	viper.SetDefault("email.credentials", "projects/todolist-356712/secrets/email-credentials/versions/latest")
+	// export.store is either "gcs" or "local", local exports are downloaded through the gateway
+	viper.SetDefault("export.store", "gcs")
+	viper.SetDefault("export.bucket", "todolist-356712-exports")
+	viper.SetDefault("export.dir", "exports")
+	viper.SetDefault("export.url", "http://localhost:8180/task/export/download")
+	viper.SetDefault("export.link.ttl", "15m")
+	viper.SetDefault("calendar.url", "http://localhost:8180/task/calendar")
+	viper.SetDefault("caldav.prefix", "/caldav")
+	viper.SetDefault("webhook.attempts", 5)
+	viper.SetDefault("webhook.backoff", "2s")
+	viper.SetDefault("webhook.timeout", "10s")
+	viper.SetDefault("webhook.max.failures", 10)
+	viper.SetDefault("reminders.attempts", 5)
+	viper.SetDefault("reminders.backoff", "1m")
+	viper.SetDefault("reminders.lease", "2m")
+	viper.SetDefault("reminders.batch", 100)
+	// the scheduler loads the reminders due within reminders.horizon every reminders.reconcile,
+	// which also sends the retried and deferred ones
+	viper.SetDefault("reminders.reconcile", "1m")
+	viper.SetDefault("reminders.horizon", "10m")
+	// actions.url is the public URL of the complete and snooze links in reminder emails, actions.secret signs them
+	viper.SetDefault("actions.url", "http://localhost:8180/task/actions")
+	viper.SetDefault("actions.link.ttl", "168h")
+	viper.SetDefault("actions.secret", "projects/todolist-356712/secrets/action-links-key/versions/latest")
+	// leader.id names the instance in the scheduler lease, it's generated when empty
+	viper.SetDefault("leader.id", "")
+	viper.SetDefault("leader.ttl", "30s")
+	viper.SetDefault("leader.renew", "10s")
+	// notifications.sms.url is the HTTP SMS gateway, SMS reminders are off without it
+	viper.SetDefault("notifications.timeout", "10s")
+	viper.SetDefault("notifications.sms.url", "")
+	viper.SetDefault("notifications.sms.from", "todolist")
+	viper.SetDefault("notifications.sms.key", "")
+	// events.publisher is either "memory", events reach only the instance relaying them, or "pubsub".
+	// Pub/Sub client uses the emulator when PUBSUB_EMULATOR_HOST is set, events.pubsub.create creates
+	// the topic and subscription the emulator starts without
+	viper.SetDefault("events.publisher", "memory")
+	viper.SetDefault("events.pubsub.project", "todolist-356712")
+	viper.SetDefault("events.pubsub.topic", "todolist-events")
+	viper.SetDefault("events.pubsub.subscription", "task-service")
+	viper.SetDefault("events.pubsub.create", false)
+	viper.SetDefault("events.relay.interval", "1s")
 
-	ctx := context.Background()
+	// the context is cancelled on shutdown, so the leader hands over its lease before the instance stops
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
 	logger, err := service.NewLogger()
 	if err != nil {
 		panic(err)
@@ -68,8 +132,133 @@ func main() {
 	}
 
 	taskRepo := repository.NewFSTask(client.Collection(repository.CollectionUsers), client)
-	taskService := service.NewTaskService(taskRepo, logger)
-	tokenClient := auth.NewTokenClient(authClient)
+	exportRepo := repository.NewFSExport(client.Collection(repository.CollectionUsers), client)
+
+	var blobStore storage.BlobStore
+	var localStore *storage.LocalStore
+	switch viper.GetString("export.store") {
+	case "local":
+		// links of the local store are valid only until restart
+		secret := make([]byte, 32)
+		_, err = rand.Read(secret)
+		if err != nil {
+			panic(err)
+		}
+		localStore = storage.NewLocalStore(viper.GetString("export.dir"), viper.GetString("export.url"), secret)
+		blobStore = localStore
+	default:
+		gcsClient, err := gcs.NewClient(ctx)
+		if err != nil {
+			panic(err)
+		}
+		defer gcsClient.Close()
+		blobStore = storage.NewGCSStore(gcsClient, viper.GetString("export.bucket"))
+	}
+	exporter := service.NewExporter(taskRepo, exportRepo, blobStore, logger, viper.GetDuration("export.link.ttl"))
+	calendarRepo := repository.NewFSCalendar(client.Collection(repository.CalendarFeeds), client)
+	calendar := service.NewCalendar(taskRepo, calendarRepo, logger, viper.GetString("calendar.url"))
+	webhookRepo := repository.NewFSWebhook(client.Collection(repository.CollectionUsers), client)
+	webhooks := service.NewWebhooks(webhookRepo, logger, service.WebhookSettings{
+		Attempts:    viper.GetInt("webhook.attempts"),
+		Backoff:     viper.GetDuration("webhook.backoff"),
+		Timeout:     viper.GetDuration("webhook.timeout"),
+		MaxFailures: viper.GetInt32("webhook.max.failures"),
+	})
+
+	bus := events.NewBus(events.NewFSProgress(client.Collection(events.CollectionHandled)), logger)
+	webhooks.Subscribe(bus)
+	service.NewUserCleanup(taskRepo, webhookRepo, logger).Subscribe(bus)
+	var publisher events.Publisher = bus
+	var pubsubClient *pubsub.Client
+	var topic *pubsub.Topic
+	if viper.GetString("events.publisher") == "pubsub" {
+		pubsubClient, err = pubsub.NewClient(ctx, viper.GetString("events.pubsub.project"))
+		if err != nil {
+			panic(err)
+		}
+		defer pubsubClient.Close()
+		topic = pubsubClient.Topic(viper.GetString("events.pubsub.topic"))
+		sub := pubsubClient.Subscription(viper.GetString("events.pubsub.subscription"))
+		if viper.GetBool("events.pubsub.create") {
+			topic, sub, err = events.EnsurePubSub(ctx, pubsubClient, topic.ID(), sub.ID())
+			if err != nil {
+				panic(err)
+			}
+		}
+		defer topic.Stop()
+		publisher = events.NewPubSubPublisher(topic)
+		go func() {
+			err := events.ReceivePubSub(ctx, sub, bus, logger)
+			if err != nil {
+				panic(err)
+			}
+		}()
+	}
+	outbox := events.NewFSOutbox(client.Collection(events.CollectionOutbox), client)
+	go events.NewRelay(outbox, publisher, logger, viper.GetDuration("events.relay.interval")).Run(ctx)
+	credJsonData, err := secretManager.AccessSecret(
+		viper.GetString("email.credentials"))
+	if err != nil {
+		panic(err)
+	}
+	emailCredentials, err := secretManager.MapSecretData(credJsonData)
+	if err != nil {
+		panic(err)
+	}
+	settings := &service.Settings{
+		Host:     viper.GetString("host"),
+		From:     viper.GetString("from"),
+		UserName: emailCredentials.Username,
+		Password: emailCredentials.Password,
+	}
+	templates, err := mail.ParseTemplates()
+	if err != nil {
+		panic(err)
+	}
+	email := notify.NewEmail(service.NewEmailSender(settings), notify.EmailSettings{
+		From:      viper.GetString("from"),
+		Templates: templates,
+	})
+	notifiers := []notify.Notifier{
+		email,
+		notify.NewWebhook(viper.GetDuration("notifications.timeout")),
+		notify.NewSlack(viper.GetDuration("notifications.timeout")),
+	}
+	if viper.GetString("notifications.sms.url") != "" {
+		notifiers = append(notifiers, notify.NewSMS(notify.SMSSettings{
+			URL:     viper.GetString("notifications.sms.url"),
+			APIKey:  viper.GetString("notifications.sms.key"),
+			From:    viper.GetString("notifications.sms.from"),
+			Timeout: viper.GetDuration("notifications.timeout"),
+		}))
+	}
+	notificationRepo := repository.NewFSNotification(client.Collection(repository.CollectionUsers))
+	notifications := service.NewNotifications(notificationRepo, logger, notifiers...)
+	digestRepo := repository.NewFSDigest(client.Collection(repository.CollectionUsers), client)
+	digests := service.NewDigests(digestRepo, taskRepo, notificationRepo, email, logger)
+	reminderQueue := repository.NewFSReminderQueue(client.Collection(repository.CollectionReminderQueue), client)
+	actionSecret, err := secretManager.AccessSecret(viper.GetString("actions.secret"))
+	if err != nil {
+		panic(err)
+	}
+	actions := service.NewActionLinks(taskRepo, reminderQueue, email, logger, service.ActionLinkSettings{
+		BaseURL: viper.GetString("actions.url"),
+		Secret:  actionSecret,
+		TTL:     viper.GetDuration("actions.link.ttl"),
+	})
+	taskService := service.NewTaskService(taskRepo, exporter, calendar, webhooks, notifications, digests, actions,
+		logger)
+	tokenClient := auth.NewTokenClient(authClient, logger)
+	idempotency := service.NewIdempotency(
+		repository.NewFSIdempotency(client.Collection(repository.CollectionUsers), client),
+		logger,
+		"/task.TaskService/CreateTask",
+		"/task.TaskService/UpdateTask",
+		"/task.TaskService/DeleteTask",
+		"/task.v2.TaskService/CreateTask",
+		"/task.v2.TaskService/UpdateTask",
+		"/task.v2.TaskService/DeleteTask",
+	)
 
 	grpcPort := viper.GetString("grpc.port")
 	lis, err := net.Listen("tcp", grpcPort)
@@ -81,9 +270,17 @@ func main() {
 		grpc_middleware.WithUnaryServerChain(
 			grpc_recovery.UnaryServerInterceptor(),
 			tokenClient.CustomUnaryInterceptor(),
+			validation.UnaryInterceptor(),
+			idempotency.UnaryInterceptor(),
+		),
+		grpc_middleware.WithStreamServerChain(
+			grpc_recovery.StreamServerInterceptor(),
+			tokenClient.CustomStreamInterceptor(),
+			validation.StreamInterceptor(),
 		),
 	)
 	v1.RegisterTaskServiceServer(s, taskService)
+	v2.RegisterTaskServiceServer(s, service.NewTaskServiceV2(taskService))
 	reflection.Register(s)
 
 	go func() {
@@ -105,44 +302,140 @@ func main() {
 
 	// Register gRPC server endpoint
 	// Note: Make sure the gRPC server is running properly and accessible
-	mux := runtime.NewServeMux()
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(service.IdempotencyHeaderMatcher),
+		runtime.WithErrorHandler(apierror.GatewayErrorHandler),
+	)
 	err = v1.RegisterTaskServiceHandler(context.Background(), mux, conn)
 	if err != nil {
 		panic(err)
 	}
-
-	// cron reminders
-	credJsonData, err := secretManager.AccessSecret(
-		viper.GetString("email.credentials"))
+	err = v2.RegisterTaskServiceHandler(context.Background(), mux, conn)
 	if err != nil {
 		panic(err)
 	}
-	emailCredentials, err := secretManager.MapSecretData(credJsonData)
+	// iCalendar feed is authorized by the token in path instead of the bearer token
+	err = mux.HandlePath(http.MethodGet, "/task/calendar/{token}", calendar.HandleFeed)
 	if err != nil {
 		panic(err)
 	}
-	settings := &service.Settings{
-		Host:     viper.GetString("host"),
-		From:     viper.GetString("from"),
-		UserName: emailCredentials.Username,
-		Password: emailCredentials.Password,
+	if localStore != nil {
+		err = mux.HandlePath(http.MethodGet, "/task/export/download",
+			func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+				localStore.Download(w, r)
+			})
+		if err != nil {
+			panic(err)
+		}
 	}
-	emailSender := service.NewEmailSender(settings)
-	reminder := service.NewReminder(taskRepo, logger, emailSender, client)
-	c := cron.New()
-	c.AddFunc("@every 30s", func() {
-		err := reminder.RemindUserViaEmail(ctx)
+
+	// reminder email links are authorized by their signature instead of the bearer token
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		err = mux.HandlePath(method, "/task/actions/{action}", actions.HandleAction)
 		if err != nil {
 			panic(err)
 		}
-	},
-	)
+	}
+
+	// CalDAV uses WebDAV methods the gateway can't route, so it gets its own handler
+	caldavPrefix := viper.GetString("caldav.prefix")
+	httpMux := http.NewServeMux()
+	httpMux.Handle(caldavPrefix+"/", service.NewCalDAV(taskRepo, calendarRepo, logger, caldavPrefix))
+	httpMux.Handle("/.well-known/caldav", http.RedirectHandler(caldavPrefix+"/", http.StatusMovedPermanently))
+	httpMux.Handle("/", mux)
+
+	// leader election metrics among the runtime ones, on the internal port only
+	debugMux := http.NewServeMux()
+	debugMux.Handle("/debug/vars", expvar.Handler())
+	go func() {
+		err := http.ListenAndServe(viper.GetString("debug.port"), debugMux)
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	reminder := service.NewReminder(taskRepo, reminderQueue, logger, notifications, actions, service.ReminderQueueSettings{
+		Attempts:  viper.GetInt("reminders.attempts"),
+		Backoff:   viper.GetDuration("reminders.backoff"),
+		Lease:     viper.GetDuration("reminders.lease"),
+		BatchSize: viper.GetInt("reminders.batch"),
+	})
+	// every instance schedules the jobs, only the leader runs them
+	holderID := viper.GetString("leader.id")
+	if holderID == "" {
+		holderID, err = service.NewHolderID()
+		if err != nil {
+			panic(err)
+		}
+	}
+	elector := service.NewElector(repository.NewFSLease(client.Collection(repository.CollectionLeases), client), logger,
+		service.LeaderSettings{
+			Lease:      service.LeaseScheduler,
+			Holder:     holderID,
+			TTL:        viper.GetDuration("leader.ttl"),
+			RenewEvery: viper.GetDuration("leader.renew"),
+		})
+	electorDone := make(chan struct{})
+	go func() {
+		elector.Run(ctx)
+		close(electorDone)
+	}()
+	// reminders fire at their time, the task events reschedule them
+	scheduler, err := service.NewReminderScheduler(reminder, logger, service.SchedulerSettings{
+		ReconcileEvery: viper.GetDuration("reminders.reconcile"),
+		Horizon:        viper.GetDuration("reminders.horizon"),
+	}, elector.IsLeader)
+	if err != nil {
+		panic(err)
+	}
+	// the heap of every instance follows the task events, with Pub/Sub each instance has a subscription of its own
+	if topic != nil {
+		instanceSub, err := events.EnsureInstanceSubscription(ctx, pubsubClient, topic,
+			viper.GetString("events.pubsub.subscription")+"-"+holderID)
+		if err != nil {
+			panic(err)
+		}
+		instanceBus := events.NewBus(events.NewFSProgress(client.Collection(events.CollectionHandled)), logger)
+		scheduler.Observe(instanceBus)
+		go func() {
+			err := events.ReceivePubSub(ctx, instanceSub, instanceBus, logger)
+			if err != nil {
+				panic(err)
+			}
+		}()
+	} else {
+		scheduler.Observe(bus)
+	}
+	go scheduler.Run(ctx)
+	c := cron.New()
+	// digests are due at full hours of the time zones of users, failures are retried by the next run
+	c.AddFunc("@every 5m", elector.Lead(func() {
+		digests.SendDigests(ctx, time.Now())
+	}))
+	// exports of instances stopped while running them
+	c.AddFunc("@every 10m", elector.Lead(func() {
+		exporter.FailStale(ctx, time.Now())
+	}))
 	c.Start()
 
 	// Start HTTP server (and proxy calls to gRPC server endpoint)
+	server := &http.Server{Addr: viper.GetString("gateway.port"), Handler: httpMux}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		err := server.Shutdown(shutdownCtx)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+	}()
 	fmt.Printf("starting http server at '%s'\n", viper.GetString("gateway.port"))
-	err = http.ListenAndServe(viper.GetString("gateway.port"), mux)
-	if err != nil {
+	err = server.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
 		panic(err)
 	}
+	<-c.Stop().Done()
+	s.GracefulStop()
+	<-electorDone
+	logger.Info("stopped")
 }
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const usage = `usage: taskcli <import|export> [flags] [file]
+
+Imports tasks from the file (stdin when omitted) or exports them to the file (stdout when omitted)
+in the todo.txt or Markdown checklist format. Exports of Todoist (CSV), Google Tasks (Takeout JSON)
+and Trello (board JSON) can be imported too. The Firebase ID token is read from -token
+or the TODOLIST_TOKEN environment variable.
+
+flags:
+`
+
+var formats = map[string]v1.TextFormat{
+	"todotxt":     v1.TextFormat_TODO_TXT,
+	"markdown":    v1.TextFormat_MARKDOWN,
+	"todoist":     v1.TextFormat_TODOIST_CSV,
+	"googletasks": v1.TextFormat_GOOGLE_TASKS_JSON,
+	"trello":      v1.TextFormat_TRELLO_JSON,
+}
+
+func main() {
+	flags := flag.NewFlagSet("taskcli", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8181", "Address of the task service gRPC server")
+	token := flags.String("token", os.Getenv("TODOLIST_TOKEN"), "Firebase ID token of the user")
+	format := flags.String("format", "todotxt", "Format, todotxt, markdown, or import only todoist, googletasks, trello")
+	dryRun := flags.Bool("dry-run", false, "Report the tasks import would create without creating them")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	if len(os.Args) < 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	command := os.Args[1]
+	flags.Parse(os.Args[2:])
+	textFormat, ok := formats[strings.ToLower(*format)]
+	if !ok || *token == "" || flags.NArg() > 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+*token)
+	conn, err := grpc.DialContext(ctx, *addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		fail(err)
+	}
+	defer conn.Close()
+	client := v1.NewTaskServiceClient(conn)
+
+	switch command {
+	case "import":
+		err = importTasks(ctx, client, textFormat, *dryRun, flags.Arg(0))
+	case "export":
+		err = exportTasks(ctx, client, textFormat, flags.Arg(0))
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func importTasks(ctx context.Context, client v1.TaskServiceClient, format v1.TextFormat, dryRun bool,
+	path string) error {
+	in := io.Reader(os.Stdin)
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	content, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	resp, err := client.ImportTasks(ctx, &v1.ImportTasksRequest{
+		Format:  format,
+		Content: string(content),
+		DryRun:  dryRun,
+	})
+	if err != nil {
+		return err
+	}
+	for _, itemErr := range resp.Errors {
+		if itemErr.Line > 0 {
+			fmt.Fprintf(os.Stderr, "%s:%d: %s\n", displayName(path), itemErr.Line, itemErr.Message)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %s: %s\n", displayName(path), itemErr.Item, itemErr.Message)
+		}
+	}
+	if resp.DryRun {
+		for _, task := range resp.Tasks {
+			fmt.Println("would create:", task.Name)
+		}
+		fmt.Printf("would import %d tasks, skipped %d items\n", len(resp.Tasks), len(resp.Errors))
+		return nil
+	}
+	fmt.Printf("imported %d tasks, skipped %d items\n", len(resp.Tasks), len(resp.Errors))
+	return nil
+}
+
+func exportTasks(ctx context.Context, client v1.TaskServiceClient, format v1.TextFormat, path string) error {
+	resp, err := client.ExportTasksText(ctx, &v1.ExportTasksTextRequest{Format: format})
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		_, err = io.WriteString(os.Stdout, resp.Content)
+		return err
+	}
+	return os.WriteFile(path, []byte(resp.Content), 0644)
+}
+
+func displayName(path string) string {
+	if path == "" {
+		return "stdin"
+	}
+	return path
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "taskcli:", err)
+	os.Exit(1)
+}
@@ -3,22 +3,30 @@ package auth
 import (
 	"context"
 	"firebase.google.com/go/auth"
-	"fmt"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpcAuth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 const (
-	ContextUser = "user"
+	ContextUser  = "user"
+	ContextAdmin = "admin"
+	// ReasonInvalidToken is reported when the ID token can't be verified
+	ReasonInvalidToken = "INVALID_TOKEN"
 )
 
 type TokenClient struct {
 	authClient *auth.Client
+	logger     *zap.Logger
 }
 
-func NewTokenClient(authClient *auth.Client) *TokenClient {
+func NewTokenClient(authClient *auth.Client, logger *zap.Logger) *TokenClient {
 	return &TokenClient{
 		authClient: authClient,
+		logger:     logger,
 	}
 }
 
@@ -36,26 +44,41 @@ func (t *TokenClient) CustomUnaryInterceptor() grpc.UnaryServerInterceptor {
 			// token can't be parsed without complete/authorized signature
 			// so when error occurs and the request is intercepted there are no data
 			// of the user that tried and failed to authorize
-			fmt.Printf("error authorizing request: %v", err)
+			t.logger.Error(err.Error())
 			return nil, err
 		}
 		return handler(newCtx, req)
 	}
 }
 
+// CustomStreamInterceptor returns a new stream server interceptor that performs per-stream auth,
+// handlers find the user in the context of the stream as in unary calls
+func (t *TokenClient) CustomStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		newCtx, err := t.AuthFunc(stream.Context())
+		if err != nil {
+			t.logger.Error(err.Error(), zap.String("method", info.FullMethod))
+			return err
+		}
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = newCtx
+		return handler(srv, wrapped)
+	}
+}
+
 func (t *TokenClient) AuthFunc(ctx context.Context) (context.Context, error) {
 	// AuthFromMD searches for Authorization header from request that is carried by context
 	jwt, err := grpcAuth.AuthFromMD(ctx, "bearer")
 	if err != nil {
-		fmt.Printf("error obtaining auth header from metadata: %v", err)
-		return nil, err
+		t.logger.Error(err.Error())
+		return nil, apierror.Status(err)
 	}
 	// VerifyIDToken searches for projectID in key automatically when client was initialized with service account
 	// credentials
 	token, err := t.authClient.VerifyIDToken(ctx, jwt)
 	if err != nil {
-		fmt.Printf("error verifying token: %v", err)
-		return nil, err
+		t.logger.Error(err.Error())
+		return nil, apierror.Status(apierror.Wrap(codes.Unauthenticated, ReasonInvalidToken, err))
 	}
 	data := token.Claims
 	ctxUser := &UserContext{
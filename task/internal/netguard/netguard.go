@@ -0,0 +1,112 @@
+// Package netguard keeps requests to URLs given by users away from the internal network,
+// e.g. the metadata server or other services of the project
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrForbiddenAddress = errors.New("address is not public")
+)
+
+// reserved are the special-purpose ranges net.IP has no method for
+var reserved = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("192.0.0.0/24"),
+	mustParseCIDR("198.18.0.0/15"),
+	mustParseCIDR("240.0.0.0/4"),
+	mustParseCIDR("64:ff9b::/96"),
+}
+
+// internalSuffixes are the host names resolving to internal addresses by convention,
+// metadata.google.internal among them
+var internalSuffixes = []string{".localhost", ".internal", ".local"}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return ipNet
+}
+
+// IsPublic tells whether the address is a public unicast address, it's false for loopback,
+// RFC 1918, link-local, unique local and the other special-purpose addresses
+func IsPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() ||
+		ip.IsMulticast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, ipNet := range reserved {
+		if ipNet.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL rejects URLs with a host that is an address which isn't public or an internal host name.
+// Other host names are only known to be public once resolved, which the client of NewClient checks
+// when dialing
+func CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublic(ip) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+		}
+		return nil
+	}
+	if host == "localhost" {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	for _, suffix := range internalSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+		}
+	}
+	return nil
+}
+
+// Control refuses connections to addresses that aren't public. Dialers call it with the resolved
+// address right before connecting, so host names rebinding to internal addresses don't get through
+func Control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// NewClient returns the client for URLs given by users, it only connects to public addresses,
+// redirects included. Proxies from the environment are not used, they would connect instead of it
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   Control,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
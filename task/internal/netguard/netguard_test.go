@@ -0,0 +1,72 @@
+package netguard
+
+import (
+	"errors"
+	"github.com/stretchr/testify/suite"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type NetguardTestSuite struct {
+	suite.Suite
+}
+
+func (s *NetguardTestSuite) TestIsPublic() {
+	candidates := []struct {
+		ip       string
+		expected bool
+	}{
+		{ip: "93.184.216.34", expected: true},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", expected: true},
+		{ip: "127.0.0.1", expected: false},
+		{ip: "10.1.2.3", expected: false},
+		{ip: "172.16.0.1", expected: false},
+		{ip: "192.168.1.1", expected: false},
+		{ip: "169.254.169.254", expected: false},
+		{ip: "100.64.0.1", expected: false},
+		{ip: "0.0.0.0", expected: false},
+		{ip: "255.255.255.255", expected: false},
+		{ip: "::1", expected: false},
+		{ip: "fd00::1", expected: false},
+		{ip: "fe80::1", expected: false},
+		{ip: "::ffff:127.0.0.1", expected: false},
+		{ip: "64:ff9b::a9fe:a9fe", expected: false},
+	}
+	for i, c := range candidates {
+		s.Equalf(c.expected, IsPublic(net.ParseIP(c.ip)), "candidate %d", i+1)
+	}
+}
+
+func (s *NetguardTestSuite) TestCheckURL() {
+	candidates := []struct {
+		url       string
+		forbidden bool
+	}{
+		{url: "https://example.com/hook", forbidden: false},
+		{url: "https://93.184.216.34/hook", forbidden: false},
+		{url: "http://169.254.169.254/computeMetadata/v1/", forbidden: true},
+		{url: "http://metadata.google.internal/computeMetadata/v1/", forbidden: true},
+		{url: "http://METADATA.google.internal./", forbidden: true},
+		{url: "http://localhost:8080/", forbidden: true},
+		{url: "http://[::1]:8080/", forbidden: true},
+		{url: "http://10.0.0.1/", forbidden: true},
+	}
+	for i, c := range candidates {
+		err := CheckURL(c.url)
+		s.Equalf(c.forbidden, errors.Is(err, ErrForbiddenAddress), "candidate %d", i+1)
+	}
+}
+
+func (s *NetguardTestSuite) TestNewClient() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	_, err := NewClient(time.Second).Get(server.URL)
+	s.ErrorIs(err, ErrForbiddenAddress)
+}
+
+func TestNetguardTestSuite(t *testing.T) {
+	suite.Run(t, new(NetguardTestSuite))
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"sync"
+)
+
+type subscription struct {
+	name    string
+	handler Handler
+	// observers keep no progress, they see every delivery of the event to this instance
+	observer bool
+}
+
+// Bus dispatches events to the handlers subscribed in this process. It is the in-memory Publisher too,
+// events published through it reach only this instance
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[Type][]subscription
+	progress FSProgressInterface
+	logger   *zap.Logger
+}
+
+func NewBus(progress FSProgressInterface, logger *zap.Logger) *Bus {
+	return &Bus{
+		handlers: make(map[Type][]subscription),
+		progress: progress,
+		logger:   logger,
+	}
+}
+
+// Subscribe registers the handler of the event type under the subscriber name,
+// the name identifies the progress of the subscriber so it has to stay the same across deployments
+func (b *Bus) Subscribe(name string, eventType Type, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], subscription{name: name, handler: handler})
+}
+
+// Observe registers the handler of the event type for the state of this instance, like the reminders
+// scheduled in memory. Unlike subscribers, observers of all instances get the events, so no progress is
+// recorded for them and they see redelivered events again
+func (b *Bus) Observe(name string, eventType Type, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], subscription{name: name, handler: handler, observer: true})
+}
+
+// Publish calls every handler of the event type, even when some of them fail.
+// The event is published again after an error, handlers that succeeded already skip it then
+func (b *Bus) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	handlers := b.handlers[event.Type]
+	b.mu.RUnlock()
+	var firstErr error
+	for _, sub := range handlers {
+		log := b.logger.With(
+			zap.String("event_id", event.ID),
+			zap.String("event_type", string(event.Type)),
+			zap.String("subscriber", sub.name),
+		)
+		err := b.handle(ctx, sub, event)
+		if err != nil {
+			log.Error(err.Error())
+			if firstErr == nil {
+				firstErr = fmt.Errorf("handling %s %s by %s: %w", event.Type, event.ID, sub.name, err)
+			}
+		}
+	}
+	return firstErr
+}
+
+func (b *Bus) handle(ctx context.Context, sub subscription, event Event) error {
+	if sub.observer {
+		return sub.handler(ctx, event)
+	}
+	handled, err := b.progress.Handled(ctx, event.ID, sub.name)
+	if err != nil {
+		return err
+	}
+	if handled {
+		return nil
+	}
+	err = sub.handler(ctx, event)
+	if err != nil {
+		return err
+	}
+	err = b.progress.MarkHandled(ctx, event.ID, sub.name)
+	if err != nil {
+		// the subscriber may see the event again, which the at least once delivery allows
+		b.logger.Error(err.Error(), zap.String("event_id", event.ID), zap.String("subscriber", sub.name))
+	}
+	return nil
+}
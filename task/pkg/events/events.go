@@ -0,0 +1,89 @@
+// Package events carries domain events between the services. Events are recorded in the Firestore outbox
+// in the same write as the data change they describe, so they are never lost nor published for writes
+// that failed. The Relay publishes the recorded events and subscribers register their handlers on a Bus.
+//
+// Delivery is at least once, handlers have to tolerate events they have seen already, the event ID
+// stays the same for every delivery
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type Type string
+
+const (
+	TaskCreated  Type = "TaskCreated"
+	TaskUpdated  Type = "TaskUpdated"
+	TaskDeleted  Type = "TaskDeleted"
+	UserDeleted  Type = "UserDeleted"
+	ReminderSent Type = "ReminderSent"
+)
+
+// Event is a change of the aggregate, a task or a user, Data holds the JSON payload of the type
+type Event struct {
+	ID          string          `json:"id"`
+	Type        Type            `json:"type"`
+	UserID      string          `json:"user_id"`
+	AggregateID string          `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Data        json.RawMessage `json:"data,omitempty"`
+}
+
+// TaskData is the payload of TaskCreated, TaskUpdated and TaskDeleted
+type TaskData struct {
+	TaskID string `json:"task_id"`
+	// Task is the v1.Task after the write in protojson, left out for deletions
+	Task json.RawMessage `json:"task,omitempty"`
+	// Completion marks writes that completed the task
+	Completion bool `json:"completion,omitempty"`
+}
+
+// UserData is the payload of UserDeleted
+type UserData struct {
+	UserID string `json:"user_id"`
+}
+
+// ReminderData is the payload of ReminderSent
+type ReminderData struct {
+	TaskID string `json:"task_id"`
+	Email  string `json:"email"`
+	// Offset is the number of seconds before the due time the reminder was set for
+	Offset int64 `json:"offset"`
+}
+
+// New returns an event with the data encoded, the ID and time are set by the outbox
+func New(eventType Type, userID, aggregateID string, data interface{}) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		Type:        eventType,
+		UserID:      userID,
+		AggregateID: aggregateID,
+		Data:        raw,
+	}, nil
+}
+
+// Decode reads the data of the event into v
+func (e Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// Publisher hands the event over to the subscribers, returning nil only once they have it
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+type Handler func(ctx context.Context, event Event) error
+
+type Subscriber interface {
+	Subscribe(name string, eventType Type, handler Handler)
+}
+
+type Observer interface {
+	Observe(name string, eventType Type, handler Handler)
+}
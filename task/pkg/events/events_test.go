@@ -0,0 +1,159 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+type EventsTestSuite struct {
+	suite.Suite
+	bus      *Bus
+	progress *FSProgressMock
+	outbox   *FSOutboxMock
+	relay    *Relay
+}
+
+func (s *EventsTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.progress = NewMockProgress()
+	s.bus = NewBus(s.progress, logger)
+	s.outbox = NewMockOutbox()
+	s.relay = NewRelay(s.outbox, s.bus, logger, time.Second)
+}
+
+func (s *EventsTestSuite) TestBusPublish() {
+	ctx := context.Background()
+	s.progress.On("Handled", ctx, mock.Anything, mock.Anything).Return(false, nil)
+	s.progress.On("MarkHandled", ctx, mock.Anything, mock.Anything).Return(nil)
+	var handled []string
+	s.bus.Subscribe("first", TaskCreated, func(ctx context.Context, event Event) error {
+		handled = append(handled, "first "+event.ID)
+		return errors.New("failed")
+	})
+	s.bus.Subscribe("second", TaskCreated, func(ctx context.Context, event Event) error {
+		handled = append(handled, "second "+event.ID)
+		return nil
+	})
+	s.bus.Subscribe("deleted", TaskDeleted, func(ctx context.Context, event Event) error {
+		handled = append(handled, "deleted "+event.ID)
+		return nil
+	})
+
+	// a failing handler doesn't stop the others
+	err := s.bus.Publish(ctx, Event{ID: "ev1", Type: TaskCreated})
+	s.Error(err)
+	s.Equal([]string{"first ev1", "second ev1"}, handled)
+	// only the successful handler is done with the event
+	s.progress.AssertCalled(s.T(), "MarkHandled", ctx, "ev1", "second")
+	s.progress.AssertNotCalled(s.T(), "MarkHandled", ctx, "ev1", "first")
+
+	// events without handlers are dropped
+	err = s.bus.Publish(ctx, Event{ID: "ev2", Type: UserDeleted})
+	s.NoError(err)
+}
+
+func (s *EventsTestSuite) TestRelayPending() {
+	ctx := context.Background()
+	event, err := New(UserDeleted, "1", "1", UserData{UserID: "1"})
+	s.Require().NoError(err)
+	pending := []Event{event, event, event}
+	pending[0].ID = "published"
+	pending[1].ID = "locked"
+	pending[2].ID = "failing"
+	s.outbox.On("Pending", ctx, relayBatchSize).Return(pending, nil)
+	s.outbox.On("Claim", ctx, "published", relayLease).Return(true, nil)
+	s.outbox.On("Claim", ctx, "locked", relayLease).Return(false, nil)
+	s.outbox.On("Claim", ctx, "failing", relayLease).Return(true, nil)
+	s.outbox.On("MarkPublished", ctx, mock.Anything).Return(nil)
+	s.outbox.On("Retry", ctx, "failing", mock.Anything, relayMaxAttempts).Return(false, nil)
+	s.progress.On("Handled", ctx, mock.Anything, "cleanup").Return(false, nil)
+	s.progress.On("MarkHandled", ctx, mock.Anything, "cleanup").Return(nil)
+	var received []UserData
+	s.bus.Subscribe("cleanup", UserDeleted, func(ctx context.Context, event Event) error {
+		if event.ID == "failing" {
+			return errors.New("failed")
+		}
+		data := UserData{}
+		err := event.Decode(&data)
+		received = append(received, data)
+		return err
+	})
+
+	published, err := s.relay.RelayPending(ctx)
+	s.NoError(err)
+	s.Equal(1, published)
+	s.Equal([]UserData{{UserID: "1"}}, received)
+	s.outbox.AssertCalled(s.T(), "MarkPublished", ctx, "published")
+	// failed events stay pending and back off
+	s.outbox.AssertNotCalled(s.T(), "MarkPublished", ctx, "failing")
+	s.outbox.AssertCalled(s.T(), "Retry", ctx, "failing", mock.Anything, relayMaxAttempts)
+	s.outbox.AssertNotCalled(s.T(), "MarkPublished", ctx, "locked")
+}
+
+func (s *EventsTestSuite) TestBusRedelivery() {
+	ctx := context.Background()
+	s.progress.On("Handled", ctx, "ev1", "webhooks").Return(true, nil)
+	s.progress.On("Handled", ctx, "ev1", "scheduler").Return(false, nil)
+	s.progress.On("MarkHandled", ctx, "ev1", "scheduler").Return(nil)
+	var handled []string
+	s.bus.Subscribe("webhooks", TaskUpdated, func(ctx context.Context, event Event) error {
+		handled = append(handled, "webhooks")
+		return nil
+	})
+	s.bus.Subscribe("scheduler", TaskUpdated, func(ctx context.Context, event Event) error {
+		handled = append(handled, "scheduler")
+		return nil
+	})
+
+	// the subscriber done with the event doesn't see it again
+	err := s.bus.Publish(ctx, Event{ID: "ev1", Type: TaskUpdated})
+	s.NoError(err)
+	s.Equal([]string{"scheduler"}, handled)
+}
+
+func (s *EventsTestSuite) TestBusObserve() {
+	ctx := context.Background()
+	s.progress.On("Handled", ctx, "ev1", "webhooks").Return(true, nil)
+	var handled []string
+	s.bus.Subscribe("webhooks", TaskUpdated, func(ctx context.Context, event Event) error {
+		handled = append(handled, "webhooks")
+		return nil
+	})
+	s.bus.Observe("scheduler", TaskUpdated, func(ctx context.Context, event Event) error {
+		handled = append(handled, "scheduler")
+		return nil
+	})
+
+	// observers see every delivery, nothing is recorded for them
+	err := s.bus.Publish(ctx, Event{ID: "ev1", Type: TaskUpdated})
+	s.NoError(err)
+	err = s.bus.Publish(ctx, Event{ID: "ev1", Type: TaskUpdated})
+	s.NoError(err)
+	s.Equal([]string{"scheduler", "scheduler"}, handled)
+	s.progress.AssertNotCalled(s.T(), "Handled", ctx, "ev1", "scheduler")
+	s.progress.AssertNotCalled(s.T(), "MarkHandled", ctx, "ev1", "scheduler")
+}
+
+func (s *EventsTestSuite) TestRetryDelay() {
+	candidates := []struct {
+		Attempts      int
+		ExpectedDelay time.Duration
+	}{
+		{Attempts: 1, ExpectedDelay: relayBackoff},
+		{Attempts: 2, ExpectedDelay: relayBackoff * 2},
+		{Attempts: 4, ExpectedDelay: relayBackoff * 8},
+		{Attempts: 100, ExpectedDelay: relayMaxBackoff},
+	}
+	for i, candidate := range candidates {
+		s.Equal(candidate.ExpectedDelay, retryDelay(candidate.Attempts), "candidate %d", i+1)
+	}
+}
+
+func TestEventsTestSuite(t *testing.T) {
+	suite.Run(t, new(EventsTestSuite))
+}
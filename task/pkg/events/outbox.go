@@ -0,0 +1,222 @@
+package events
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+const (
+	CollectionOutbox = "outbox"
+	// CollectionDeadLetters keeps events the relay gave up on, they are moved there from the outbox
+	CollectionDeadLetters = "outbox_dead_letters"
+	// PublishedTTL is how long published events are kept, they are removed by the Firestore TTL policy on expireAt
+	PublishedTTL = time.Hour * 24 * 7
+)
+
+type FSOutboxInterface interface {
+	Pending(ctx context.Context, n int) ([]Event, error)
+	Claim(ctx context.Context, eventID string, lease time.Duration) (bool, error)
+	MarkPublished(ctx context.Context, eventID string) error
+	Retry(ctx context.Context, eventID, reason string, maxAttempts int) (bool, error)
+	Backfill(ctx context.Context) (int, error)
+}
+
+// outboxEntry is the event as stored in outbox/{id}, other services write the same documents.
+// Pending events are queried by published and nextAttemptAt, which needs a composite index
+type outboxEntry struct {
+	ID            string     `firestore:"id"`
+	Type          string     `firestore:"type"`
+	UserID        string     `firestore:"userID"`
+	AggregateID   string     `firestore:"aggregateID"`
+	OccurredAt    time.Time  `firestore:"occurredAt,serverTimestamp"`
+	Data          []byte     `firestore:"data"`
+	Published     bool       `firestore:"published"`
+	LockedUntil   time.Time  `firestore:"lockedUntil"`
+	NextAttemptAt time.Time  `firestore:"nextAttemptAt,serverTimestamp"`
+	Attempts      int        `firestore:"attempts"`
+	LastError     string     `firestore:"lastError"`
+	PublishedAt   *time.Time `firestore:"publishedAt,omitempty"`
+	ExpireAt      *time.Time `firestore:"expireAt,omitempty"`
+}
+
+func (e outboxEntry) event() Event {
+	return Event{
+		ID:          e.ID,
+		Type:        Type(e.Type),
+		UserID:      e.UserID,
+		AggregateID: e.AggregateID,
+		OccurredAt:  e.OccurredAt,
+		Data:        e.Data,
+	}
+}
+
+type FSOutbox struct {
+	fs     *firestore.CollectionRef
+	client *firestore.Client
+}
+
+func NewFSOutbox(fs *firestore.CollectionRef, client *firestore.Client) *FSOutbox {
+	return &FSOutbox{
+		fs:     fs,
+		client: client,
+	}
+}
+
+// Add records the event in the batch writing the data change, the event is pending until relayed
+func (f *FSOutbox) Add(batch *firestore.WriteBatch, event Event) {
+	docRef := f.fs.NewDoc()
+	batch.Set(docRef, newOutboxEntry(docRef.ID, event))
+}
+
+// AddTx records the event in the transaction writing the data change, like Add
+func (f *FSOutbox) AddTx(tx *firestore.Transaction, event Event) error {
+	docRef := f.fs.NewDoc()
+	return tx.Set(docRef, newOutboxEntry(docRef.ID, event))
+}
+
+func newOutboxEntry(id string, event Event) outboxEntry {
+	return outboxEntry{
+		ID:          id,
+		Type:        string(event.Type),
+		UserID:      event.UserID,
+		AggregateID: event.AggregateID,
+		Data:        event.Data,
+	}
+}
+
+// Pending returns at most n events not published yet and due for an attempt, oldest first.
+// Events backing off after failures don't hold back the newer ones
+func (f *FSOutbox) Pending(ctx context.Context, n int) ([]Event, error) {
+	docs := f.fs.Where("published", "==", false).
+		Where("nextAttemptAt", "<=", time.Now()).
+		OrderBy("nextAttemptAt", firestore.Asc).
+		Limit(n).Documents(ctx)
+	var pending []Event
+	for {
+		doc, err := docs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		entry := outboxEntry{}
+		err = doc.DataTo(&entry)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, entry.event())
+	}
+	return pending, nil
+}
+
+// Claim locks the pending event for the lease, so relays of other instances skip it meanwhile.
+// It returns false when the event is published, locked already or backing off
+func (f *FSOutbox) Claim(ctx context.Context, eventID string, lease time.Duration) (bool, error) {
+	docRef := f.fs.Doc(eventID)
+	claimed := false
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = false
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		entry := outboxEntry{}
+		err = doc.DataTo(&entry)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if entry.Published || entry.LockedUntil.After(now) || entry.NextAttemptAt.After(now) {
+			return nil
+		}
+		claimed = true
+		return tx.Update(docRef, []firestore.Update{{Path: "lockedUntil", Value: now.Add(lease)}})
+	})
+	return claimed, err
+}
+
+func (f *FSOutbox) MarkPublished(ctx context.Context, eventID string) error {
+	now := time.Now()
+	_, err := f.fs.Doc(eventID).Update(ctx, []firestore.Update{
+		{Path: "published", Value: true},
+		{Path: "publishedAt", Value: now},
+		{Path: "expireAt", Value: now.Add(PublishedTTL)},
+	})
+	return err
+}
+
+// Retry counts the failed attempt of the claimed event and schedules the next one after a backoff.
+// After maxAttempts the event is moved to the dead letters and true is returned
+func (f *FSOutbox) Retry(ctx context.Context, eventID, reason string, maxAttempts int) (bool, error) {
+	docRef := f.fs.Doc(eventID)
+	dead := false
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		dead = false
+		doc, err := tx.Get(docRef)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		entry := outboxEntry{}
+		err = doc.DataTo(&entry)
+		if err != nil {
+			return err
+		}
+		if entry.Published {
+			return nil
+		}
+		entry.Attempts++
+		entry.LastError = reason
+		if entry.Attempts >= maxAttempts {
+			dead = true
+			data := doc.Data()
+			data["attempts"] = entry.Attempts
+			data["lastError"] = reason
+			data["deadAt"] = time.Now()
+			err = tx.Set(f.client.Collection(CollectionDeadLetters).Doc(eventID), data)
+			if err != nil {
+				return err
+			}
+			return tx.Delete(docRef)
+		}
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "attempts", Value: entry.Attempts},
+			{Path: "lastError", Value: reason},
+			{Path: "lockedUntil", Value: time.Time{}},
+			{Path: "nextAttemptAt", Value: time.Now().Add(retryDelay(entry.Attempts))},
+		})
+	})
+	return dead, err
+}
+
+// Backfill schedules the pending events written without nextAttemptAt, by older versions of the services,
+// for an immediate attempt. Pending doesn't see them until then. It returns how many were updated
+func (f *FSOutbox) Backfill(ctx context.Context) (int, error) {
+	docs := f.fs.Where("published", "==", false).Documents(ctx)
+	updated := 0
+	for {
+		doc, err := docs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return updated, err
+		}
+		if _, err := doc.DataAt("nextAttemptAt"); err == nil {
+			continue
+		}
+		_, err = doc.Ref.Update(ctx, []firestore.Update{{Path: "nextAttemptAt", Value: firestore.ServerTimestamp}})
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
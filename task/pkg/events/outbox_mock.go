@@ -0,0 +1,40 @@
+package events
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type FSOutboxMock struct {
+	mock.Mock
+}
+
+func NewMockOutbox() *FSOutboxMock {
+	return &FSOutboxMock{}
+}
+
+func (m *FSOutboxMock) Pending(ctx context.Context, n int) ([]Event, error) {
+	args := m.Called(ctx, n)
+	return args.Get(0).([]Event), args.Error(1)
+}
+
+func (m *FSOutboxMock) Claim(ctx context.Context, eventID string, lease time.Duration) (bool, error) {
+	args := m.Called(ctx, eventID, lease)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *FSOutboxMock) MarkPublished(ctx context.Context, eventID string) error {
+	args := m.Called(ctx, eventID)
+	return args.Error(0)
+}
+
+func (m *FSOutboxMock) Retry(ctx context.Context, eventID, reason string, maxAttempts int) (bool, error) {
+	args := m.Called(ctx, eventID, reason, maxAttempts)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *FSOutboxMock) Backfill(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// CollectionHandled records which subscribers handled which events, entries expire with the published events
+const CollectionHandled = "outbox_handled"
+
+// FSProgressInterface keeps track of the subscribers done with an event, so that a failure of one
+// subscriber doesn't make the others handle the event again when it is redelivered
+type FSProgressInterface interface {
+	Handled(ctx context.Context, eventID, subscriber string) (bool, error)
+	MarkHandled(ctx context.Context, eventID, subscriber string) error
+}
+
+type handledEntry struct {
+	EventID    string    `firestore:"eventID"`
+	Subscriber string    `firestore:"subscriber"`
+	HandledAt  time.Time `firestore:"handledAt"`
+	ExpireAt   time.Time `firestore:"expireAt"`
+}
+
+// FSProgress stores the progress in outbox_handled/{eventID}_{subscriber}
+type FSProgress struct {
+	fs *firestore.CollectionRef
+}
+
+func NewFSProgress(fs *firestore.CollectionRef) *FSProgress {
+	return &FSProgress{
+		fs: fs,
+	}
+}
+
+func (f *FSProgress) Handled(ctx context.Context, eventID, subscriber string) (bool, error) {
+	_, err := f.fs.Doc(eventID + "_" + subscriber).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (f *FSProgress) MarkHandled(ctx context.Context, eventID, subscriber string) error {
+	now := time.Now()
+	_, err := f.fs.Doc(eventID+"_"+subscriber).Set(ctx, handledEntry{
+		EventID:    eventID,
+		Subscriber: subscriber,
+		HandledAt:  now,
+		ExpireAt:   now.Add(PublishedTTL),
+	})
+	return err
+}
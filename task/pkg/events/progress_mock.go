@@ -0,0 +1,24 @@
+package events
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type FSProgressMock struct {
+	mock.Mock
+}
+
+func NewMockProgress() *FSProgressMock {
+	return &FSProgressMock{}
+}
+
+func (m *FSProgressMock) Handled(ctx context.Context, eventID, subscriber string) (bool, error) {
+	args := m.Called(ctx, eventID, subscriber)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *FSProgressMock) MarkHandled(ctx context.Context, eventID, subscriber string) error {
+	args := m.Called(ctx, eventID, subscriber)
+	return args.Error(0)
+}
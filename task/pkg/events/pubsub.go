@@ -0,0 +1,108 @@
+package events
+
+import (
+	"cloud.google.com/go/pubsub"
+	"context"
+	"encoding/json"
+	"go.uber.org/zap"
+	"time"
+)
+
+// instanceSubscriptionExpiry deletes the subscriptions of instances that are gone, it is the minimum Pub/Sub allows
+const instanceSubscriptionExpiry = time.Hour * 24
+
+// PubSubPublisher publishes events to a Pub/Sub topic as JSON, with the type and ID in the attributes.
+// The client connects to the emulator when PUBSUB_EMULATOR_HOST is set
+type PubSubPublisher struct {
+	topic *pubsub.Topic
+}
+
+func NewPubSubPublisher(topic *pubsub.Topic) *PubSubPublisher {
+	return &PubSubPublisher{
+		topic: topic,
+	}
+}
+
+func (p *PubSubPublisher) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = p.topic.Publish(ctx, &pubsub.Message{
+		Data: data,
+		Attributes: map[string]string{
+			"id":   event.ID,
+			"type": string(event.Type),
+		},
+	}).Get(ctx)
+	return err
+}
+
+// ReceivePubSub dispatches the events of the subscription to the bus until the context is done.
+// Instances sharing the subscription split the events among themselves
+func ReceivePubSub(ctx context.Context, sub *pubsub.Subscription, bus *Bus, logger *zap.Logger) error {
+	return sub.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
+		event := Event{}
+		err := json.Unmarshal(msg.Data, &event)
+		if err != nil {
+			// redelivery won't fix malformed messages
+			logger.Error(err.Error(), zap.String("message_id", msg.ID))
+			msg.Ack()
+			return
+		}
+		err = bus.Publish(ctx, event)
+		if err != nil {
+			msg.Nack()
+			return
+		}
+		msg.Ack()
+	})
+}
+
+// EnsurePubSub returns the topic and the subscription, creating them when they don't exist yet,
+// which is handy with the emulator starting empty
+func EnsurePubSub(ctx context.Context, client *pubsub.Client, topicID, subID string) (*pubsub.Topic,
+	*pubsub.Subscription, error) {
+	topic := client.Topic(topicID)
+	ok, err := topic.Exists(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		topic, err = client.CreateTopic(ctx, topicID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	sub := client.Subscription(subID)
+	ok, err = sub.Exists(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		sub, err = client.CreateSubscription(ctx, subID, pubsub.SubscriptionConfig{Topic: topic})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return topic, sub, nil
+}
+
+// EnsureInstanceSubscription returns the subscription of a single instance to the topic, creating it when
+// it doesn't exist yet. Instances receive every event through their own subscriptions, which Pub/Sub deletes
+// once the instance stops receiving
+func EnsureInstanceSubscription(ctx context.Context, client *pubsub.Client, topic *pubsub.Topic,
+	subID string) (*pubsub.Subscription, error) {
+	sub := client.Subscription(subID)
+	ok, err := sub.Exists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return sub, nil
+	}
+	return client.CreateSubscription(ctx, subID, pubsub.SubscriptionConfig{
+		Topic:            topic,
+		ExpirationPolicy: instanceSubscriptionExpiry,
+	})
+}
@@ -0,0 +1,112 @@
+package events
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	relayBatchSize = 100
+	// relayLease has to outlast publishing of the event, the event is published again after it runs out
+	relayLease = time.Minute
+	// relayMaxAttempts failed publishes move the event to the dead letters
+	relayMaxAttempts = 10
+	relayBackoff     = time.Second * 10
+	relayMaxBackoff  = time.Hour
+)
+
+// Relay publishes the pending events of the outbox. Relays of all instances share the outbox,
+// each event is claimed by one of them before it is published
+type Relay struct {
+	outbox    FSOutboxInterface
+	publisher Publisher
+	logger    *zap.Logger
+	interval  time.Duration
+}
+
+func NewRelay(outbox FSOutboxInterface, publisher Publisher, logger *zap.Logger, interval time.Duration) *Relay {
+	return &Relay{
+		outbox:    outbox,
+		publisher: publisher,
+		logger:    logger,
+		interval:  interval,
+	}
+}
+
+// Run relays the pending events every interval until the context is done
+func (r *Relay) Run(ctx context.Context) {
+	backfilled, err := r.outbox.Backfill(ctx)
+	if err != nil {
+		r.logger.Error(err.Error())
+	}
+	if backfilled > 0 {
+		r.logger.Info("Scheduled pending events of the outbox", zap.Int("events", backfilled))
+	}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		_, err := r.RelayPending(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.logger.Error(err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes a batch of pending events and returns how many of them were published.
+// Events failing to publish are retried with a backoff, until they run out of attempts
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	pending, err := r.outbox.Pending(ctx, relayBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	published := 0
+	for _, event := range pending {
+		log := r.logger.With(zap.String("event_id", event.ID), zap.String("event_type", string(event.Type)))
+		claimed, err := r.outbox.Claim(ctx, event.ID, relayLease)
+		if err != nil {
+			log.Error(err.Error())
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		err = r.publisher.Publish(ctx, event)
+		if err != nil {
+			log.Error(err.Error())
+			dead, err := r.outbox.Retry(ctx, event.ID, err.Error(), relayMaxAttempts)
+			if err != nil {
+				log.Error(err.Error())
+			}
+			if dead {
+				log.Error("Event moved to the dead letters", zap.Int("attempts", relayMaxAttempts))
+			}
+			continue
+		}
+		err = r.outbox.MarkPublished(ctx, event.ID)
+		if err != nil {
+			// the event will be published again, subscribers drop it by the ID
+			log.Error(err.Error())
+			continue
+		}
+		published++
+	}
+	return published, nil
+}
+
+// retryDelay returns the backoff before the next attempt after the given number of failed ones
+func retryDelay(attempts int) time.Duration {
+	delay := relayBackoff
+	for i := 1; i < attempts && delay < relayMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > relayMaxBackoff {
+		return relayMaxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/jakubjano/todolist/task/pkg/service/mail"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Actions of the links in reminder emails
+const (
+	ActionComplete = "complete"
+	ActionSnooze   = "snooze"
+	// ActionVerifyEscalation confirms the escalation email, its links are sent to the address itself
+	ActionVerifyEscalation = "verify-escalation"
+
+	// SnoozeDuration is how much later a snoozed task is reminded again
+	SnoozeDuration = time.Hour
+)
+
+var (
+	ErrInvalidActionLink = errors.New("the link is invalid or expired")
+)
+
+// actionPage is the page of the action links. Opening a link only asks for a confirmation, the action
+// is performed by the form it posts, so that mail scanners following the links don't perform them
+var actionPage = template.Must(template.New("action").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>todolist</title>
+</head>
+<body style="font-family: sans-serif; color: #222; max-width: 32em; margin: 3em auto;">
+  {{- if .Confirm}}
+  <form method="post">
+    <p>{{.Message}}</p>
+    <button type="submit" style="padding: 8px 16px;">{{.Button}}</button>
+  </form>
+  {{- else}}
+  <p>{{.Message}}</p>
+  {{- end}}
+</body>
+</html>
+`))
+
+type actionPageData struct {
+	Message string
+	Button  string
+	Confirm bool
+}
+
+// ActionLinkSettings sign the links with Secret, they expire TTL after the email was sent.
+// BaseURL is the public URL of the action endpoint, the action is appended to it
+type ActionLinkSettings struct {
+	BaseURL string
+	Secret  []byte
+	TTL     time.Duration
+}
+
+// ActionLinks complete and snooze tasks from reminder emails without logging in. The links carry the task
+// and the expiry signed with HMAC-SHA256, which is the only authorization of the unauthenticated endpoint.
+// The links confirming escalation emails carry the email instead of the task
+type ActionLinks struct {
+	taskRepo      repository.FSTaskInterface
+	reminderQueue repository.FSReminderQueueInterface
+	email         *notify.Email
+	logger        *zap.Logger
+	settings      ActionLinkSettings
+}
+
+func NewActionLinks(taskRepo repository.FSTaskInterface, reminderQueue repository.FSReminderQueueInterface,
+	email *notify.Email, logger *zap.Logger, settings ActionLinkSettings) *ActionLinks {
+	return &ActionLinks{
+		taskRepo:      taskRepo,
+		reminderQueue: reminderQueue,
+		email:         email,
+		logger:        logger,
+		settings:      settings,
+	}
+}
+
+// Link returns the signed link performing the action on the task of the user, valid TTL from now
+func (a *ActionLinks) Link(action, userID, taskID string, now time.Time) string {
+	return a.link(action, "task", userID, taskID, now)
+}
+
+// SendEscalationVerification emails the escalation email of the user the link confirming it
+func (a *ActionLinks) SendEscalationVerification(userID, owner, email string, now time.Time) error {
+	return a.email.Send(email, mail.TemplateEscalationVerification, mail.EscalationVerificationData{
+		Owner:      owner,
+		ConfirmURL: a.link(ActionVerifyEscalation, "email", userID, email, now),
+		Locale:     mail.DefaultLocale,
+	})
+}
+
+// link signs the subject of the action, the task or the email, under the param
+func (a *ActionLinks) link(action, param, userID, subject string, now time.Time) string {
+	expires := strconv.FormatInt(now.Add(a.settings.TTL).Unix(), 10)
+	query := url.Values{}
+	query.Set("user", userID)
+	query.Set(param, subject)
+	query.Set("expires", expires)
+	query.Set("signature", a.sign(action, userID, subject, expires))
+	return a.settings.BaseURL + "/" + action + "?" + query.Encode()
+}
+
+// verify returns the user, subject and expiry of the link, the link has to be signed for the action and unexpired
+func (a *ActionLinks) verify(action, param string, query url.Values, now time.Time) (userID, subject string,
+	expires int64, err error) {
+	userID, subject = query.Get("user"), query.Get(param)
+	expires, err = strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || userID == "" || subject == "" || now.Unix() > expires ||
+		!hmac.Equal([]byte(a.sign(action, userID, subject, query.Get("expires"))), []byte(query.Get("signature"))) {
+		return "", "", 0, ErrInvalidActionLink
+	}
+	return userID, subject, expires, nil
+}
+
+func (a *ActionLinks) sign(action, userID, subject, expires string) string {
+	mac := hmac.New(sha256.New, a.settings.Secret)
+	mac.Write([]byte(action + "\n" + userID + "\n" + subject + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HandleAction serves the action links, GET asks for a confirmation and POST performs the action
+func (a *ActionLinks) HandleAction(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	action := pathParams["action"]
+	if action == ActionVerifyEscalation {
+		a.verifyEscalation(w, r)
+		return
+	}
+	if action != ActionComplete && action != ActionSnooze {
+		http.NotFound(w, r)
+		return
+	}
+	userID, taskID, expires, err := a.verify(action, "task", r.URL.Query(), time.Now())
+	if err != nil {
+		a.render(w, http.StatusForbidden, actionPageData{Message: "Sorry, " + err.Error() + "."})
+		return
+	}
+	log := a.logger.With(
+		zap.String("user_id", userID),
+		zap.String("task_id", taskID),
+		zap.String("action", action),
+	)
+	ctx := r.Context()
+	task, err := a.taskRepo.Get(ctx, userID, taskID)
+	if status.Code(err) == codes.NotFound {
+		a.render(w, http.StatusNotFound, actionPageData{Message: "The task doesn't exist anymore."})
+		return
+	}
+	if err != nil {
+		log.Error(err.Error())
+		a.render(w, http.StatusInternalServerError, actionPageData{Message: "Something went wrong, please try again."})
+		return
+	}
+	if task.Completed {
+		a.render(w, http.StatusOK, actionPageData{Message: task.Name + " is done already."})
+		return
+	}
+	if r.Method != http.MethodPost {
+		page := actionPageData{Message: "Mark " + task.Name + " as done?", Button: "Mark done", Confirm: true}
+		if action == ActionSnooze {
+			page = actionPageData{Message: "Remind " + task.Name + " again in an hour?", Button: "Snooze", Confirm: true}
+		}
+		a.render(w, http.StatusOK, page)
+		return
+	}
+	var page actionPageData
+	switch action {
+	case ActionComplete:
+		page, err = a.complete(ctx, task)
+	case ActionSnooze:
+		page, err = a.snooze(ctx, task, expires, time.Now())
+	}
+	if err != nil {
+		log.Error(err.Error())
+		a.render(w, http.StatusInternalServerError, actionPageData{Message: "Something went wrong, please try again."})
+		return
+	}
+	log.Info("Performed action link")
+	a.render(w, http.StatusOK, page)
+}
+
+// verifyEscalation serves the links confirming escalation emails, GET asks for a confirmation and POST confirms.
+// A link of an email the user replaced meanwhile confirms nothing
+func (a *ActionLinks) verifyEscalation(w http.ResponseWriter, r *http.Request) {
+	userID, email, _, err := a.verify(ActionVerifyEscalation, "email", r.URL.Query(), time.Now())
+	if err != nil {
+		a.render(w, http.StatusForbidden, actionPageData{Message: "Sorry, " + err.Error() + "."})
+		return
+	}
+	if r.Method != http.MethodPost {
+		a.render(w, http.StatusOK, actionPageData{
+			Message: "Get emails about overdue tasks at " + email + "?",
+			Button:  "Confirm",
+			Confirm: true,
+		})
+		return
+	}
+	log := a.logger.With(
+		zap.String("user_id", userID),
+		zap.String("action", ActionVerifyEscalation),
+	)
+	verified, err := a.taskRepo.VerifyEscalationEmail(r.Context(), userID, email)
+	if err != nil {
+		log.Error(err.Error())
+		a.render(w, http.StatusInternalServerError, actionPageData{Message: "Something went wrong, please try again."})
+		return
+	}
+	if !verified {
+		a.render(w, http.StatusGone, actionPageData{Message: "The escalations go to another email by now."})
+		return
+	}
+	log.Info("Verified escalation email")
+	a.render(w, http.StatusOK, actionPageData{Message: email + " will get emails about overdue tasks."})
+}
+
+func (a *ActionLinks) complete(ctx context.Context, task repository.Task) (actionPageData, error) {
+	task.Completed = true
+	_, err := a.taskRepo.Update(ctx, task, task.UserID, task.TaskID)
+	if err != nil {
+		return actionPageData{}, err
+	}
+	return actionPageData{Message: task.Name + " is done."}, nil
+}
+
+// snooze queues a reminder of the task SnoozeDuration from now, a link snoozes the task once
+func (a *ActionLinks) snooze(ctx context.Context, task repository.Task, expires int64, now time.Time) (actionPageData, error) {
+	until := now.Add(SnoozeDuration).Unix()
+	delivery := repository.NewReminderDelivery(task, task.Time-until, until)
+	delivery.DeliveryID = repository.SnoozedDeliveryID(task.TaskID, expires)
+	delivery.CreatedAt = now.Unix()
+	snoozed, err := a.reminderQueue.Snooze(ctx, delivery)
+	if err != nil {
+		return actionPageData{}, err
+	}
+	if !snoozed {
+		return actionPageData{Message: task.Name + " is snoozed already."}, nil
+	}
+	return actionPageData{Message: "You'll be reminded of " + task.Name + " again in an hour."}, nil
+}
+
+func (a *ActionLinks) render(w http.ResponseWriter, statusCode int, page actionPageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// the links carry their signature
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	err := actionPage.Execute(w, page)
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ActionLinksTestSuite struct {
+	suite.Suite
+	actions       *ActionLinks
+	taskRepo      *repository.FSTaskMock
+	reminderQueue *repository.FSReminderQueueMock
+	task          repository.Task
+}
+
+func (s *ActionLinksTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.taskRepo = repository.NewMockRepo()
+	s.reminderQueue = repository.NewMockReminderQueue()
+	s.actions = NewActionLinks(s.taskRepo, s.reminderQueue, nil, logger, ActionLinkSettings{
+		BaseURL: "http://localhost/task/actions",
+		Secret:  []byte("secret"),
+		TTL:     time.Hour,
+	})
+	s.task = repository.Task{
+		TaskID:    "tid1",
+		UserID:    "1",
+		UserEmail: "example1@tst.com",
+		Name:      "task1",
+		Time:      time.Now().Add(time.Hour * 3).Unix(),
+	}
+	s.taskRepo.On("Get", mock.Anything, "1", "tid1").Return(s.task, nil)
+}
+
+func (s *ActionLinksTestSuite) serve(method, link, action string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.actions.HandleAction(rec, httptest.NewRequest(method, link, nil), map[string]string{"action": action})
+	return rec
+}
+
+func (s *ActionLinksTestSuite) TestVerify() {
+	link := s.actions.Link(ActionComplete, "1", "tid1", time.Now())
+	s.True(strings.HasPrefix(link, "http://localhost/task/actions/complete?"))
+	tampered := strings.Replace(link, "task=tid1", "task=tid2", 1)
+	expired := s.actions.Link(ActionComplete, "1", "tid1", time.Now().Add(-time.Hour*2))
+	candidates := []struct {
+		action string
+		link   string
+		status int
+	}{
+		{action: ActionComplete, link: link, status: http.StatusOK},
+		// the link is signed for one action
+		{action: ActionSnooze, link: link, status: http.StatusForbidden},
+		{action: ActionComplete, link: tampered, status: http.StatusForbidden},
+		{action: ActionComplete, link: expired, status: http.StatusForbidden},
+		{action: ActionComplete, link: "/task/actions/complete?user=1&task=tid1", status: http.StatusForbidden},
+		{action: "delete", link: link, status: http.StatusNotFound},
+	}
+	for i, c := range candidates {
+		rec := s.serve(http.MethodGet, c.link, c.action)
+		s.Equalf(c.status, rec.Code, "candidate %d", i+1)
+	}
+	// opening the links only asks for a confirmation
+	s.taskRepo.AssertNotCalled(s.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ActionLinksTestSuite) TestComplete() {
+	completed := s.task
+	completed.Completed = true
+	s.taskRepo.On("Update", mock.Anything, completed, "1", "tid1").Return(completed, nil)
+	rec := s.serve(http.MethodPost, s.actions.Link(ActionComplete, "1", "tid1", time.Now()), ActionComplete)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Contains(rec.Body.String(), "task1 is done.")
+	s.Equal("no-referrer", rec.Header().Get("Referrer-Policy"))
+	s.taskRepo.AssertNumberOfCalls(s.T(), "Update", 1)
+}
+
+func (s *ActionLinksTestSuite) TestSnooze() {
+	link := s.actions.Link(ActionSnooze, "1", "tid1", time.Now())
+	parsed, err := url.Parse(link)
+	s.Require().NoError(err)
+	expires := parsed.Query().Get("expires")
+	var snoozed repository.ReminderDelivery
+	s.reminderQueue.On("Snooze", mock.Anything, mock.Anything).Return(true, nil).Once().
+		Run(func(args mock.Arguments) {
+			snoozed = args.Get(1).(repository.ReminderDelivery)
+		})
+	s.reminderQueue.On("Snooze", mock.Anything, mock.Anything).Return(false, nil).Once()
+
+	now := time.Now()
+	rec := s.serve(http.MethodPost, link, ActionSnooze)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Contains(rec.Body.String(), "again in an hour")
+	s.Equal("tid1_snoozed_"+expires, snoozed.DeliveryID)
+	s.Equal(repository.ReminderPending, snoozed.Status)
+	s.InDelta(now.Add(SnoozeDuration).Unix(), snoozed.NextAttemptAt, 1)
+	s.Equal(s.task.Time-snoozed.NextAttemptAt, snoozed.Offset)
+
+	// a link snoozes the task once
+	rec = s.serve(http.MethodPost, link, ActionSnooze)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Contains(rec.Body.String(), "snoozed already")
+}
+
+func (s *ActionLinksTestSuite) TestVerifyEscalation() {
+	link := s.actions.link(ActionVerifyEscalation, "email", "1", "boss@tst.com", time.Now())
+	// the link is signed for the email
+	tampered := strings.Replace(link, "boss%40tst.com", "spam%40tst.com", 1)
+	s.Equal(http.StatusForbidden, s.serve(http.MethodPost, tampered, ActionVerifyEscalation).Code)
+	// a task link doesn't confirm anything
+	taskLink := s.actions.Link(ActionComplete, "1", "tid1", time.Now())
+	s.Equal(http.StatusForbidden, s.serve(http.MethodPost, taskLink, ActionVerifyEscalation).Code)
+	s.Equal(http.StatusOK, s.serve(http.MethodGet, link, ActionVerifyEscalation).Code)
+	s.taskRepo.AssertNotCalled(s.T(), "VerifyEscalationEmail", mock.Anything, mock.Anything, mock.Anything)
+
+	s.taskRepo.On("VerifyEscalationEmail", mock.Anything, "1", "boss@tst.com").Return(true, nil).Once()
+	s.Equal(http.StatusOK, s.serve(http.MethodPost, link, ActionVerifyEscalation).Code)
+	// the user replaced the email meanwhile
+	s.taskRepo.On("VerifyEscalationEmail", mock.Anything, "1", "boss@tst.com").Return(false, nil).Once()
+	s.Equal(http.StatusGone, s.serve(http.MethodPost, link, ActionVerifyEscalation).Code)
+}
+
+func TestActionLinksTestSuite(t *testing.T) {
+	suite.Run(t, new(ActionLinksTestSuite))
+}
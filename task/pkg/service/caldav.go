@@ -0,0 +1,684 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"github.com/jakubjano/todolist/task/pkg/service/ical"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	nsDAV       = "DAV:"
+	nsCalDAV    = "urn:ietf:params:xml:ns:caldav"
+	nsCalServer = "http://calendarserver.org/ns/"
+
+	caldavCollection   = "tasks"
+	caldavSyncPrefix   = "urn:todolist:sync:"
+	caldavMaxBodyBytes = 1 << 20
+	caldavContentType  = "text/calendar; charset=utf-8; component=vtodo"
+)
+
+var (
+	caldavPrefixes = map[string]string{
+		nsDAV:       "d",
+		nsCalDAV:    "cal",
+		nsCalServer: "cs",
+	}
+	// resource names given by clients are kept with the tasks, the task IDs are generated
+	caldavName = regexp.MustCompile(`^[A-Za-z0-9_.@-]{1,200}$`)
+)
+
+// CalDAV exposes user's tasks as a single VTODO calendar collection (RFC 4791) so that
+// native reminder apps can sync them both ways. Clients sign in with HTTP Basic auth,
+// using a CalDAV app password of the user, and see the following resources:
+//
+//	{prefix}/                      principal discovery
+//	{prefix}/{userID}/             principal and calendar home
+//	{prefix}/{userID}/tasks/       calendar collection
+//	{prefix}/{userID}/tasks/{name}.ics
+//
+// Tasks created by clients get a generated ID like any other task and keep the resource name the client
+// chose, tasks created elsewhere are named by their ID
+type CalDAV struct {
+	taskRepo     repository.FSTaskInterface
+	calendarRepo repository.FSCalendarInterface
+	logger       *zap.Logger
+	prefix       string
+}
+
+// NewCalDAV creates the server handling requests under the path prefix, e.g. "/caldav"
+func NewCalDAV(taskRepo repository.FSTaskInterface, calendarRepo repository.FSCalendarInterface,
+	logger *zap.Logger, prefix string) *CalDAV {
+	return &CalDAV{
+		taskRepo:     taskRepo,
+		calendarRepo: calendarRepo,
+		logger:       logger,
+		prefix:       strings.TrimSuffix(prefix, "/"),
+	}
+}
+
+// davResponse is a resource in a multistatus response, props hold inner XML of the properties
+type davResponse struct {
+	href   string
+	props  map[xml.Name]string
+	status int
+}
+
+// propNames collects names of the child elements, e.g. of DAV:prop
+type propNames []xml.Name
+
+func (p *propNames) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			*p = append(*p, t.Name)
+			err = d.Skip()
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+type propfindRequest struct {
+	AllProp *struct{} `xml:"DAV: allprop"`
+	Prop    propNames `xml:"DAV: prop"`
+}
+
+type compFilter struct {
+	Name  string       `xml:"name,attr"`
+	Comps []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// reportRequest covers calendar-query, calendar-multiget and sync-collection reports
+type reportRequest struct {
+	XMLName   xml.Name
+	Prop      propNames   `xml:"DAV: prop"`
+	Hrefs     []string    `xml:"DAV: href"`
+	SyncToken string      `xml:"DAV: sync-token"`
+	Filter    *compFilter `xml:"urn:ietf:params:xml:ns:caldav filter>comp-filter"`
+}
+
+func (c *CalDAV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.Header().Set("DAV", "1, 3, calendar-access")
+		w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	account, ok := c.authenticate(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="todolist"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	log := c.logger.With(
+		zap.String("caller_id", account.UserID),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, c.prefix), "/")
+	var segments []string
+	if path != "" {
+		segments = strings.Split(path, "/")
+	}
+	if len(segments) > 0 && segments[0] != account.UserID {
+		log.Error(ErrUnauthorized.Error())
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	switch {
+	case len(segments) <= 1 && r.Method == "PROPFIND":
+		c.propfindHome(w, r, log, account.UserID, len(segments) == 0)
+	case len(segments) == 2 && segments[1] == caldavCollection && r.Method == "PROPFIND":
+		c.propfindCollection(w, r, log, account.UserID)
+	case len(segments) == 2 && segments[1] == caldavCollection && r.Method == "REPORT":
+		c.report(w, r, log, account.UserID)
+	case len(segments) == 3 && segments[1] == caldavCollection && strings.HasSuffix(segments[2], ".ics"):
+		name := strings.TrimSuffix(segments[2], ".ics")
+		if !caldavName.MatchString(name) {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		log = log.With(zap.String("resource", name))
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			c.get(w, r, log, account.UserID, name)
+		case http.MethodPut:
+			c.put(w, r, log, account, name)
+		case http.MethodDelete:
+			c.delete(w, r, log, account.UserID, name)
+		case "PROPFIND":
+			c.propfindTask(w, r, log, account.UserID, name)
+		default:
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}
+}
+
+// authenticate accepts a CalDAV app password as the Basic auth password, the username is ignored.
+// The calendar feed token isn't accepted, it only reads the tasks
+func (c *CalDAV) authenticate(r *http.Request) (repository.CalDAVPassword, bool) {
+	_, password, ok := r.BasicAuth()
+	if !ok || password == "" {
+		return repository.CalDAVPassword{}, false
+	}
+	account, err := c.calendarRepo.GetByPassword(r.Context(), hashToken(password))
+	if err != nil {
+		c.logger.Info(err.Error())
+		return repository.CalDAVPassword{}, false
+	}
+	return account, true
+}
+
+func (c *CalDAV) homeHref(userID string) string {
+	return c.prefix + "/" + userID + "/"
+}
+
+func (c *CalDAV) collectionHref(userID string) string {
+	return c.homeHref(userID) + caldavCollection + "/"
+}
+
+func (c *CalDAV) taskHref(task repository.Task) string {
+	name := task.CalDAVName
+	if name == "" {
+		name = task.TaskID
+	}
+	return c.collectionHref(task.UserID) + name + ".ics"
+}
+
+func (c *CalDAV) propfindHome(w http.ResponseWriter, r *http.Request, log *zap.Logger, userID string, root bool) {
+	req, err := parsePropfind(r)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	home := davResponse{
+		href: c.homeHref(userID),
+		props: map[xml.Name]string{
+			{Space: nsDAV, Local: "resourcetype"}:           "<d:collection/><d:principal/>",
+			{Space: nsDAV, Local: "displayname"}:            escapeXML(userID),
+			{Space: nsDAV, Local: "current-user-principal"}: hrefXML(c.homeHref(userID)),
+			{Space: nsDAV, Local: "principal-URL"}:          hrefXML(c.homeHref(userID)),
+			{Space: nsCalDAV, Local: "calendar-home-set"}:   hrefXML(c.homeHref(userID)),
+		},
+	}
+	if root {
+		home.href = c.prefix + "/"
+		home.props[xml.Name{Space: nsDAV, Local: "resourcetype"}] = "<d:collection/>"
+	}
+	responses := []davResponse{home}
+	if !root && r.Header.Get("Depth") != "0" {
+		collection, _, err := c.collection(r, userID)
+		if err != nil {
+			log.Error(err.Error())
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		responses = append(responses, collection)
+	}
+	writeMultistatus(w, responses, req.Prop, "")
+}
+
+func (c *CalDAV) propfindCollection(w http.ResponseWriter, r *http.Request, log *zap.Logger, userID string) {
+	req, err := parsePropfind(r)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	collection, tasks, err := c.collection(r, userID)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	responses := []davResponse{collection}
+	if r.Header.Get("Depth") != "0" {
+		for _, task := range tasks {
+			responses = append(responses, c.taskResponse(task, false))
+		}
+	}
+	writeMultistatus(w, responses, req.Prop, "")
+}
+
+func (c *CalDAV) propfindTask(w http.ResponseWriter, r *http.Request, log *zap.Logger, userID, name string) {
+	req, err := parsePropfind(r)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	task, found, err := c.getTask(r, userID, name)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	writeMultistatus(w, []davResponse{c.taskResponse(task, false)}, req.Prop, "")
+}
+
+// collection returns the calendar collection resource together with all tasks it contains
+func (c *CalDAV) collection(r *http.Request, userID string) (davResponse, []repository.Task, error) {
+	tasks, err := c.taskRepo.GetAll(r.Context(), userID)
+	if err != nil {
+		return davResponse{}, nil, err
+	}
+	ctag := collectionTag(tasks)
+	return davResponse{
+		href: c.collectionHref(userID),
+		props: map[xml.Name]string{
+			{Space: nsDAV, Local: "resourcetype"}:           "<d:collection/><cal:calendar/>",
+			{Space: nsDAV, Local: "displayname"}:            calendarName,
+			{Space: nsDAV, Local: "current-user-principal"}: hrefXML(c.homeHref(userID)),
+			{Space: nsDAV, Local: "owner"}:                  hrefXML(c.homeHref(userID)),
+			{Space: nsDAV, Local: "sync-token"}:             caldavSyncPrefix + ctag,
+			{Space: nsDAV, Local: "supported-report-set"}: "<d:supported-report><d:report><cal:calendar-query/></d:report></d:supported-report>" +
+				"<d:supported-report><d:report><cal:calendar-multiget/></d:report></d:supported-report>" +
+				"<d:supported-report><d:report><d:sync-collection/></d:report></d:supported-report>",
+			{Space: nsCalDAV, Local: "supported-calendar-component-set"}: `<cal:comp name="VTODO"/>`,
+			{Space: nsCalServer, Local: "getctag"}:                       ctag,
+		},
+	}, tasks, nil
+}
+
+func (c *CalDAV) taskResponse(task repository.Task, withData bool) davResponse {
+	resp := davResponse{
+		href: c.taskHref(task),
+		props: map[xml.Name]string{
+			{Space: nsDAV, Local: "resourcetype"}:   "",
+			{Space: nsDAV, Local: "getetag"}:        escapeXML(taskETag(task)),
+			{Space: nsDAV, Local: "getcontenttype"}: caldavContentType,
+		},
+	}
+	if withData {
+		buf := &bytes.Buffer{}
+		// encoding into a buffer can't fail
+		TasksToICal([]repository.Task{task}, false, time.Now()).Encode(buf)
+		resp.props[xml.Name{Space: nsCalDAV, Local: "calendar-data"}] = escapeXML(buf.String())
+	}
+	return resp
+}
+
+// report answers calendar-query, calendar-multiget and sync-collection reports.
+// Calendar queries return every task when VTODOs are requested, time ranges are not applied
+func (c *CalDAV) report(w http.ResponseWriter, r *http.Request, log *zap.Logger, userID string) {
+	req := reportRequest{}
+	err := xml.NewDecoder(http.MaxBytesReader(w, r.Body, caldavMaxBodyBytes)).Decode(&req)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	withData := false
+	for _, name := range req.Prop {
+		if name == (xml.Name{Space: nsCalDAV, Local: "calendar-data"}) {
+			withData = true
+		}
+	}
+	_, tasks, err := c.collection(r, userID)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	var responses []davResponse
+	syncToken := ""
+	switch req.XMLName {
+	case xml.Name{Space: nsCalDAV, Local: "calendar-query"}:
+		if req.Filter == nil || queriesTodos(*req.Filter) {
+			for _, task := range tasks {
+				responses = append(responses, c.taskResponse(task, withData))
+			}
+		}
+	case xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}:
+		byHref := make(map[string]repository.Task, len(tasks))
+		for _, task := range tasks {
+			byHref[c.taskHref(task)] = task
+		}
+		for _, href := range req.Hrefs {
+			task, ok := byHref[href]
+			if !ok {
+				responses = append(responses, davResponse{href: href, status: http.StatusNotFound})
+				continue
+			}
+			responses = append(responses, c.taskResponse(task, withData))
+		}
+	case xml.Name{Space: nsDAV, Local: "sync-collection"}:
+		// without a change log only the current state is known, clients with an outdated
+		// token are told to sync from scratch
+		current := caldavSyncPrefix + collectionTag(tasks)
+		switch req.SyncToken {
+		case current:
+		case "":
+			for _, task := range tasks {
+				responses = append(responses, c.taskResponse(task, withData))
+			}
+		default:
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, xml.Header+`<d:error xmlns:d="DAV:"><d:valid-sync-token/></d:error>`)
+			return
+		}
+		syncToken = current
+	default:
+		http.Error(w, "unsupported report", http.StatusForbidden)
+		return
+	}
+	writeMultistatus(w, responses, req.Prop, syncToken)
+}
+
+// queriesTodos checks whether the VCALENDAR comp-filter asks for VTODO components
+func queriesTodos(filter compFilter) bool {
+	if len(filter.Comps) == 0 {
+		return true
+	}
+	for _, comp := range filter.Comps {
+		if strings.EqualFold(comp.Name, "VTODO") {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CalDAV) get(w http.ResponseWriter, r *http.Request, log *zap.Logger, userID, name string) {
+	task, found, err := c.getTask(r, userID, name)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	buf := &bytes.Buffer{}
+	err = TasksToICal([]repository.Task{task}, false, time.Now()).Encode(buf)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", caldavContentType)
+	w.Header().Set("ETag", taskETag(task))
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(buf.Bytes())
+}
+
+// put creates or replaces the task from the VTODO in the body, honoring If-Match and If-None-Match
+func (c *CalDAV) put(w http.ResponseWriter, r *http.Request, log *zap.Logger, account repository.CalDAVPassword,
+	name string) {
+	existing, found, err := c.getTask(r, account.UserID, name)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if !preconditionsMet(r, existing, found) {
+		http.Error(w, http.StatusText(http.StatusPreconditionFailed), http.StatusPreconditionFailed)
+		return
+	}
+	cal, err := ical.Decode(http.MaxBytesReader(w, r.Body, caldavMaxBodyBytes))
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var todo *ical.Component
+	for i := range cal.Components {
+		if cal.Components[i].Name == "VTODO" {
+			todo = &cal.Components[i]
+			break
+		}
+	}
+	if todo == nil {
+		// CALDAV:supported-calendar-component precondition
+		http.Error(w, "only VTODO components are supported", http.StatusForbidden)
+		return
+	}
+	task := todoToTask(*todo, log)
+	task.UserID = account.UserID
+	task.UserEmail = account.UserEmail
+	task.CalDAVName = name
+	var saved repository.Task
+	if found {
+		task.TaskID = existing.TaskID
+		task.UserEmail = existing.UserEmail
+		task.CalDAVName = existing.CalDAVName
+		// VTODOs have no place for it
+		task.StopNagging = existing.StopNagging
+		saved, err = c.taskRepo.Update(r.Context(), task, account.UserID, existing.TaskID)
+	} else {
+		saved, err = c.taskRepo.Create(r.Context(), task)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("ETag", taskETag(saved))
+	if found {
+		log.Info("CalDAV updated task")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	log.Info("CalDAV created task")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (c *CalDAV) delete(w http.ResponseWriter, r *http.Request, log *zap.Logger, userID, name string) {
+	existing, found, err := c.getTask(r, userID, name)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	if !preconditionsMet(r, existing, found) {
+		http.Error(w, http.StatusText(http.StatusPreconditionFailed), http.StatusPreconditionFailed)
+		return
+	}
+	err = c.taskRepo.Delete(r.Context(), userID, existing.TaskID)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	log.Info("CalDAV deleted task")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getTask finds the task of the user by the resource name, either the one given by the client or the task ID
+func (c *CalDAV) getTask(r *http.Request, userID, name string) (repository.Task, bool, error) {
+	task, err := c.taskRepo.GetByCalDAVName(r.Context(), userID, name)
+	if status.Code(err) == codes.NotFound {
+		task, err = c.taskRepo.Get(r.Context(), userID, name)
+	}
+	if status.Code(err) == codes.NotFound {
+		return repository.Task{}, false, nil
+	}
+	if err != nil {
+		return repository.Task{}, false, err
+	}
+	return task, true, nil
+}
+
+func preconditionsMet(r *http.Request, existing repository.Task, found bool) bool {
+	if r.Header.Get("If-None-Match") == "*" && found {
+		return false
+	}
+	if match := r.Header.Get("If-Match"); match != "" {
+		return found && (match == "*" || match == taskETag(existing))
+	}
+	return true
+}
+
+// todoToTask maps the VTODO to a task, the due time falls back to the start of the todo
+func todoToTask(todo ical.Component, log *zap.Logger) repository.Task {
+	task := repository.Task{
+		Name:        todo.Text("SUMMARY"),
+		Description: todo.Text("DESCRIPTION"),
+		Completed:   strings.EqualFold(todo.Text("STATUS"), "COMPLETED") || todo.Prop("COMPLETED") != nil,
+	}
+	if uid := todo.Text("UID"); uid != "" {
+		task.ICalUID = uid
+	}
+	for _, name := range []string{"DUE", "DTSTART"} {
+		if prop := todo.Prop(name); prop != nil {
+			due, err := prop.Time()
+			if err != nil {
+				log.Info(err.Error(), zap.String("property", name))
+				continue
+			}
+			task.Time = due.Unix()
+			break
+		}
+	}
+	if prop := todo.Prop("RRULE"); prop != nil {
+		if err := ical.ValidateRRule(prop.Value); err == nil {
+			task.Recurrence = prop.Value
+		} else {
+			log.Info(err.Error(), zap.String("rrule", prop.Value))
+		}
+	}
+	return task
+}
+
+// taskETag hashes the stored representation of the task, DTSTAMP is left out by fixing it
+func taskETag(task repository.Task) string {
+	buf := &bytes.Buffer{}
+	TaskToVTodo(task, time.Unix(0, 0)).Encode(buf)
+	sum := sha256.Sum256(buf.Bytes())
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// collectionTag changes whenever any task of the collection is created, changed or deleted
+func collectionTag(tasks []repository.Task) string {
+	tags := make([]string, len(tasks))
+	for i, task := range tasks {
+		tags[i] = task.TaskID + taskETag(task)
+	}
+	sort.Strings(tags)
+	sum := sha256.Sum256([]byte(strings.Join(tags, "\n")))
+	return hex.EncodeToString(sum[:16])
+}
+
+func parsePropfind(r *http.Request) (propfindRequest, error) {
+	req := propfindRequest{}
+	body, err := io.ReadAll(io.LimitReader(r.Body, caldavMaxBodyBytes))
+	if err != nil {
+		return req, err
+	}
+	// empty body is an allprop request
+	if len(bytes.TrimSpace(body)) == 0 {
+		return req, nil
+	}
+	err = xml.Unmarshal(body, &req)
+	return req, err
+}
+
+// writeMultistatus writes the requested properties of the resources, properties the resources don't have
+// are reported as not found. All properties are written when none were requested
+func writeMultistatus(w http.ResponseWriter, responses []davResponse, requested propNames, syncToken string) {
+	b := &strings.Builder{}
+	b.WriteString(xml.Header)
+	b.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:cal="` + nsCalDAV + `" xmlns:cs="` + nsCalServer + `">`)
+	for _, resp := range responses {
+		b.WriteString("<d:response>" + hrefXML(resp.href))
+		if resp.status != 0 {
+			b.WriteString("<d:status>" + statusLine(resp.status) + "</d:status></d:response>")
+			continue
+		}
+		found, missing := &strings.Builder{}, &strings.Builder{}
+		if len(requested) == 0 {
+			names := make([]xml.Name, 0, len(resp.props))
+			for name := range resp.props {
+				names = append(names, name)
+			}
+			sort.Slice(names, func(i, j int) bool {
+				return names[i].Space+names[i].Local < names[j].Space+names[j].Local
+			})
+			for _, name := range names {
+				found.WriteString(propXML(name, resp.props[name]))
+			}
+		}
+		for _, name := range requested {
+			if value, ok := resp.props[name]; ok {
+				found.WriteString(propXML(name, value))
+			} else {
+				missing.WriteString(propXML(name, ""))
+			}
+		}
+		if found.Len() > 0 {
+			b.WriteString("<d:propstat><d:prop>" + found.String() + "</d:prop><d:status>" +
+				statusLine(http.StatusOK) + "</d:status></d:propstat>")
+		}
+		if missing.Len() > 0 {
+			b.WriteString("<d:propstat><d:prop>" + missing.String() + "</d:prop><d:status>" +
+				statusLine(http.StatusNotFound) + "</d:status></d:propstat>")
+		}
+		b.WriteString("</d:response>")
+	}
+	if syncToken != "" {
+		b.WriteString("<d:sync-token>" + escapeXML(syncToken) + "</d:sync-token>")
+	}
+	b.WriteString("</d:multistatus>")
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	io.WriteString(w, b.String())
+}
+
+// propXML writes the element with the prefix of a known namespace or its own xmlns
+func propXML(name xml.Name, inner string) string {
+	prefix, ok := caldavPrefixes[name.Space]
+	if !ok {
+		return "<" + name.Local + ` xmlns="` + escapeXML(name.Space) + `">` + inner + "</" + name.Local + ">"
+	}
+	tag := prefix + ":" + name.Local
+	return "<" + tag + ">" + inner + "</" + tag + ">"
+}
+
+func hrefXML(href string) string {
+	return "<d:href>" + escapeXML(href) + "</d:href>"
+}
+
+func statusLine(code int) string {
+	return "HTTP/1.1 " + strconv.Itoa(code) + " " + http.StatusText(code)
+}
+
+func escapeXML(s string) string {
+	b := &strings.Builder{}
+	// writing into a strings.Builder can't fail
+	xml.EscapeText(b, []byte(s))
+	return b.String()
+}
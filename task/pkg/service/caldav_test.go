@@ -0,0 +1,223 @@
+package service
+
+import (
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const todoBody = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" +
+	"BEGIN:VTODO\r\nUID:ABC-123\r\nSUMMARY:buy milk\\, bread\r\nDESCRIPTION:two of each\r\n" +
+	"DUE:20220719T000000Z\r\nSTATUS:COMPLETED\r\nRRULE:FREQ=WEEKLY\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+type CalDAVTestSuite struct {
+	suite.Suite
+	caldav       *CalDAV
+	taskRepo     *repository.FSTaskMock
+	calendarRepo *repository.FSCalendarMock
+	task         repository.Task
+}
+
+func (s *CalDAVTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.taskRepo = repository.NewMockRepo()
+	s.calendarRepo = repository.NewMockCalendarRepo()
+	s.caldav = NewCalDAV(s.taskRepo, s.calendarRepo, logger, "/caldav")
+	s.task = repository.Task{
+		CreatedAt: 1658102400,
+		Name:      "weekly review",
+		UserID:    "1",
+		UserEmail: "example1@tst.com",
+		Time:      1658188800,
+		TaskID:    "tid1",
+	}
+	s.calendarRepo.On("GetByPassword", mock.Anything, hashToken("secret")).
+		Return(repository.CalDAVPassword{UserID: "1", UserEmail: "example1@tst.com"}, nil)
+	s.calendarRepo.On("GetByPassword", mock.Anything, mock.Anything).
+		Return(repository.CalDAVPassword{}, repository.ErrPasswordNotFound)
+	// the feed token only reads the feed
+	s.calendarRepo.On("GetByToken", mock.Anything, hashToken("feed")).
+		Return(repository.CalendarFeed{UserID: "1", UserEmail: "example1@tst.com"}, nil)
+	s.taskRepo.On("GetByCalDAVName", mock.Anything, "1", mock.Anything).
+		Return(repository.Task{}, status.Error(codes.NotFound, "not found"))
+	s.taskRepo.On("GetAll", mock.Anything, "1").Return([]repository.Task{s.task}, nil)
+	s.taskRepo.On("Get", mock.Anything, "1", "tid1").Return(s.task, nil)
+	s.taskRepo.On("Get", mock.Anything, "1", mock.Anything).
+		Return(repository.Task{}, status.Error(codes.NotFound, "not found"))
+}
+
+func (s *CalDAVTestSuite) serve(method, path, password, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.SetBasicAuth("example1@tst.com", password)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	s.caldav.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *CalDAVTestSuite) TestAuth() {
+	rec := s.serve("PROPFIND", "/caldav/1/", "wrong", "", nil)
+	s.Equal(http.StatusUnauthorized, rec.Code)
+	s.Equal(`Basic realm="todolist"`, rec.Header().Get("WWW-Authenticate"))
+	rec = s.serve(http.MethodDelete, "/caldav/1/tasks/tid1.ics", "feed", "", nil)
+	s.Equal(http.StatusUnauthorized, rec.Code)
+
+	rec = s.serve("PROPFIND", "/caldav/2/tasks/", "secret", "", nil)
+	s.Equal(http.StatusForbidden, rec.Code)
+}
+
+func (s *CalDAVTestSuite) TestPropfind() {
+	body := `<?xml version="1.0"?><d:propfind xmlns:d="DAV:" xmlns:x="urn:unknown">` +
+		`<d:prop><d:resourcetype/><d:getetag/><x:color/></d:prop></d:propfind>`
+	rec := s.serve("PROPFIND", "/caldav/1/tasks/", "secret", body, map[string]string{"Depth": "1"})
+	s.Equal(http.StatusMultiStatus, rec.Code)
+	out := rec.Body.String()
+	s.Contains(out, "<d:href>/caldav/1/tasks/</d:href>")
+	s.Contains(out, "<d:resourcetype><d:collection/><cal:calendar/></d:resourcetype>")
+	s.Contains(out, "<d:href>/caldav/1/tasks/tid1.ics</d:href>")
+	s.Contains(out, "<d:getetag>"+escapeXML(taskETag(s.task))+"</d:getetag>")
+	s.Contains(out, `<color xmlns="urn:unknown"></color>`)
+	s.Contains(out, "HTTP/1.1 404 Not Found")
+
+	rec = s.serve("PROPFIND", "/caldav/1/", "secret", "", map[string]string{"Depth": "0"})
+	s.Equal(http.StatusMultiStatus, rec.Code)
+	s.Contains(rec.Body.String(), "<cal:calendar-home-set><d:href>/caldav/1/</d:href></cal:calendar-home-set>")
+	s.NotContains(rec.Body.String(), "/caldav/1/tasks/")
+}
+
+func (s *CalDAVTestSuite) TestReport() {
+	multiget := `<?xml version="1.0"?><cal:calendar-multiget xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">` +
+		`<d:prop><d:getetag/><cal:calendar-data/></d:prop>` +
+		`<d:href>/caldav/1/tasks/tid1.ics</d:href><d:href>/caldav/1/tasks/gone.ics</d:href></cal:calendar-multiget>`
+	rec := s.serve("REPORT", "/caldav/1/tasks/", "secret", multiget, nil)
+	s.Equal(http.StatusMultiStatus, rec.Code)
+	out := rec.Body.String()
+	s.Contains(out, "SUMMARY:weekly review")
+	s.Contains(out, "<d:response><d:href>/caldav/1/tasks/gone.ics</d:href><d:status>HTTP/1.1 404 Not Found</d:status></d:response>")
+
+	events := `<?xml version="1.0"?><cal:calendar-query xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">` +
+		`<d:prop><d:getetag/></d:prop><cal:filter><cal:comp-filter name="VCALENDAR">` +
+		`<cal:comp-filter name="VEVENT"/></cal:comp-filter></cal:filter></cal:calendar-query>`
+	rec = s.serve("REPORT", "/caldav/1/tasks/", "secret", events, nil)
+	s.Equal(http.StatusMultiStatus, rec.Code)
+	s.NotContains(rec.Body.String(), "tid1.ics")
+
+	token := caldavSyncPrefix + collectionTag([]repository.Task{s.task})
+	candidates := []struct {
+		token  string
+		code   int
+		listed bool
+	}{
+		{token: "", code: http.StatusMultiStatus, listed: true},
+		{token: token, code: http.StatusMultiStatus, listed: false},
+		{token: caldavSyncPrefix + "stale", code: http.StatusForbidden, listed: false},
+	}
+	for _, c := range candidates {
+		sync := `<?xml version="1.0"?><d:sync-collection xmlns:d="DAV:"><d:sync-token>` + c.token +
+			`</d:sync-token><d:sync-level>1</d:sync-level><d:prop><d:getetag/></d:prop></d:sync-collection>`
+		rec = s.serve("REPORT", "/caldav/1/tasks/", "secret", sync, nil)
+		s.Equal(c.code, rec.Code)
+		s.Equal(c.listed, strings.Contains(rec.Body.String(), "tid1.ics"))
+		if c.code == http.StatusMultiStatus {
+			s.Contains(rec.Body.String(), "<d:sync-token>"+token+"</d:sync-token>")
+		}
+	}
+}
+
+func (s *CalDAVTestSuite) TestGet() {
+	rec := s.serve(http.MethodGet, "/caldav/1/tasks/tid1.ics", "secret", "", nil)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal(taskETag(s.task), rec.Header().Get("ETag"))
+	s.Contains(rec.Body.String(), "UID:tid1@todolist\r\n")
+
+	rec = s.serve(http.MethodGet, "/caldav/1/tasks/missing.ics", "secret", "", nil)
+	s.Equal(http.StatusNotFound, rec.Code)
+}
+
+func (s *CalDAVTestSuite) TestPut() {
+	s.taskRepo.On("Update", mock.Anything, mock.Anything, "1", mock.Anything).Return(repository.Task{}, nil)
+	s.taskRepo.On("Create", mock.Anything, mock.Anything).Return(repository.Task{}, nil)
+
+	// the task gets a generated ID, the name chosen by the client is kept with it
+	rec := s.serve(http.MethodPut, "/caldav/1/tasks/ABC-123.ics", "secret", todoBody,
+		map[string]string{"If-None-Match": "*"})
+	s.Equal(http.StatusCreated, rec.Code)
+	s.taskRepo.AssertCalled(s.T(), "Create", mock.Anything, repository.Task{
+		Name:        "buy milk, bread",
+		Description: "two of each",
+		UserID:      "1",
+		UserEmail:   "example1@tst.com",
+		Time:        1658188800,
+		Completed:   true,
+		Recurrence:  "FREQ=WEEKLY",
+		ICalUID:     "ABC-123",
+		CalDAVName:  "ABC-123",
+	})
+	s.taskRepo.AssertNotCalled(s.T(), "Update", mock.Anything, mock.Anything, "1", "ABC-123")
+
+	rec = s.serve(http.MethodPut, "/caldav/1/tasks/tid1.ics", "secret", todoBody,
+		map[string]string{"If-None-Match": "*"})
+	s.Equal(http.StatusPreconditionFailed, rec.Code)
+	rec = s.serve(http.MethodPut, "/caldav/1/tasks/tid1.ics", "secret", todoBody,
+		map[string]string{"If-Match": `"outdated"`})
+	s.Equal(http.StatusPreconditionFailed, rec.Code)
+	rec = s.serve(http.MethodPut, "/caldav/1/tasks/tid1.ics", "secret", todoBody,
+		map[string]string{"If-Match": taskETag(s.task)})
+	s.Equal(http.StatusNoContent, rec.Code)
+
+	event := strings.ReplaceAll(todoBody, "VTODO", "VEVENT")
+	rec = s.serve(http.MethodPut, "/caldav/1/tasks/new.ics", "secret", event, nil)
+	s.Equal(http.StatusForbidden, rec.Code)
+	rec = s.serve(http.MethodPut, "/caldav/1/tasks/new.ics", "secret", "not a calendar", nil)
+	s.Equal(http.StatusBadRequest, rec.Code)
+}
+
+func (s *CalDAVTestSuite) TestNamedTask() {
+	named := s.task
+	named.TaskID = "generated"
+	named.CalDAVName = "ABC-123"
+	taskRepo := repository.NewMockRepo()
+	taskRepo.On("GetByCalDAVName", mock.Anything, "1", "ABC-123").Return(named, nil)
+	taskRepo.On("GetAll", mock.Anything, "1").Return([]repository.Task{named}, nil)
+	taskRepo.On("Update", mock.Anything, mock.Anything, "1", "generated").Return(named, nil)
+	s.caldav.taskRepo = taskRepo
+
+	rec := s.serve("PROPFIND", "/caldav/1/tasks/", "secret", "", map[string]string{"Depth": "1"})
+	s.Equal(http.StatusMultiStatus, rec.Code)
+	s.Contains(rec.Body.String(), "<d:href>/caldav/1/tasks/ABC-123.ics</d:href>")
+	s.NotContains(rec.Body.String(), "generated.ics")
+
+	rec = s.serve(http.MethodPut, "/caldav/1/tasks/ABC-123.ics", "secret", todoBody,
+		map[string]string{"If-Match": taskETag(named)})
+	s.Equal(http.StatusNoContent, rec.Code)
+	taskRepo.AssertCalled(s.T(), "Update", mock.Anything, mock.MatchedBy(func(task repository.Task) bool {
+		return task.TaskID == "generated" && task.CalDAVName == "ABC-123"
+	}), "1", "generated")
+}
+
+func (s *CalDAVTestSuite) TestDelete() {
+	s.taskRepo.On("Delete", mock.Anything, "1", "tid1").Return(nil)
+
+	rec := s.serve(http.MethodDelete, "/caldav/1/tasks/missing.ics", "secret", "", nil)
+	s.Equal(http.StatusNotFound, rec.Code)
+	rec = s.serve(http.MethodDelete, "/caldav/1/tasks/tid1.ics", "secret", "",
+		map[string]string{"If-Match": `"outdated"`})
+	s.Equal(http.StatusPreconditionFailed, rec.Code)
+	s.taskRepo.AssertNotCalled(s.T(), "Delete", mock.Anything, "1", "tid1")
+	rec = s.serve(http.MethodDelete, "/caldav/1/tasks/tid1.ics", "secret", "", nil)
+	s.Equal(http.StatusNoContent, rec.Code)
+	s.taskRepo.AssertCalled(s.T(), "Delete", mock.Anything, "1", "tid1")
+}
+
+func TestCalDAVTestSuite(t *testing.T) {
+	suite.Run(t, new(CalDAVTestSuite))
+}
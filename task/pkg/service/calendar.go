@@ -0,0 +1,269 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/ical"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	calendarTokenBytes = 32
+	calendarProdID     = "-//jakubjano//todolist//EN"
+	calendarName       = "Tasks"
+	// timed tasks are shown as events of this length in calendars ignoring VTODO
+	eventDuration = "PT30M"
+)
+
+// Calendar serves user's tasks as an iCalendar feed authorized by a secret token in the URL,
+// so that calendar apps can subscribe to it without signing in
+type Calendar struct {
+	taskRepo     repository.FSTaskInterface
+	calendarRepo repository.FSCalendarInterface
+	logger       *zap.Logger
+	feedURL      string
+}
+
+// NewCalendar creates the feed, feedURL is the address HandleFeed is served at
+func NewCalendar(taskRepo repository.FSTaskInterface, calendarRepo repository.FSCalendarInterface,
+	logger *zap.Logger, feedURL string) *Calendar {
+	return &Calendar{
+		taskRepo:     taskRepo,
+		calendarRepo: calendarRepo,
+		logger:       logger,
+		feedURL:      feedURL,
+	}
+}
+
+// RotateToken generates a new feed token for the user, only its hash is stored
+func (c *Calendar) RotateToken(ctx context.Context, userID, email string) (*v1.CalendarFeed, error) {
+	raw := make([]byte, calendarTokenBytes)
+	_, err := rand.Read(raw)
+	if err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	err = c.calendarRepo.SetToken(ctx, repository.CalendarFeed{
+		UserID:    userID,
+		UserEmail: email,
+		TokenHash: hashToken(token),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.CalendarFeed{
+		Token: token,
+		Url:   c.feedURL + "/" + token + ".ics",
+	}, nil
+}
+
+// CreatePassword generates a CalDAV app password for the user, only its hash is stored
+func (c *Calendar) CreatePassword(ctx context.Context, userID, email, name string) (*v1.CalDAVPassword, error) {
+	raw := make([]byte, calendarTokenBytes)
+	_, err := rand.Read(raw)
+	if err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	password, err := c.calendarRepo.CreatePassword(ctx, repository.CalDAVPassword{
+		UserID:       userID,
+		UserEmail:    email,
+		Name:         name,
+		PasswordHash: hashToken(token),
+	})
+	if err != nil {
+		return nil, err
+	}
+	created := passwordToApi(password)
+	created.Password = token
+	return created, nil
+}
+
+func passwordToApi(password repository.CalDAVPassword) *v1.CalDAVPassword {
+	return &v1.CalDAVPassword{
+		PasswordId: password.PasswordID,
+		Name:       password.Name,
+		CreatedAt:  password.CreatedAt,
+	}
+}
+
+// HandleFeed writes the .ics feed of the user owning the token from the path.
+// Adding ?events=true duplicates timed tasks as VEVENTs
+func (c *Calendar) HandleFeed(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	ctx := r.Context()
+	token := strings.TrimSuffix(pathParams["token"], ".ics")
+	feed, err := c.calendarRepo.GetByToken(ctx, hashToken(token))
+	if err != nil {
+		// unknown and revoked tokens look the same
+		c.logger.Info(err.Error())
+		http.NotFound(w, r)
+		return
+	}
+	log := c.logger.With(zap.String("user_id", feed.UserID))
+	tasks, err := c.taskRepo.GetAll(ctx, feed.UserID)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	events, _ := strconv.ParseBool(r.URL.Query().Get("events"))
+	buf := &bytes.Buffer{}
+	err = TasksToICal(tasks, events, time.Now()).Encode(buf)
+	if err != nil {
+		log.Error(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// TasksToICal builds a VCALENDAR with a VTODO for every task,
+// and a VEVENT for every task with due time when events is set
+func TasksToICal(tasks []repository.Task, events bool, now time.Time) ical.Component {
+	cal := ical.Component{Name: "VCALENDAR"}
+	cal.Add("VERSION", "2.0")
+	cal.Add("PRODID", calendarProdID)
+	cal.Add("CALSCALE", "GREGORIAN")
+	cal.AddText("X-WR-CALNAME", calendarName)
+	for _, task := range tasks {
+		cal.Components = append(cal.Components, TaskToVTodo(task, now))
+		if events && task.Time > 0 {
+			cal.Components = append(cal.Components, taskToVEvent(task, now))
+		}
+	}
+	return cal
+}
+
+// TaskToVTodo maps the task to a VTODO component, recurring tasks start at their due time
+func TaskToVTodo(task repository.Task, now time.Time) ical.Component {
+	todo := ical.Component{Name: "VTODO"}
+	uid := task.ICalUID
+	if uid == "" {
+		uid = task.TaskID + "@todolist"
+	}
+	todo.Add("UID", uid)
+	todo.AddTime("DTSTAMP", now)
+	if task.CreatedAt > 0 {
+		todo.AddTime("CREATED", time.Unix(task.CreatedAt, 0))
+	}
+	todo.AddText("SUMMARY", task.Name)
+	if task.Description != "" {
+		todo.AddText("DESCRIPTION", task.Description)
+	}
+	if task.Time > 0 {
+		if task.Recurrence != "" {
+			todo.AddTime("DTSTART", time.Unix(task.Time, 0))
+		}
+		todo.AddTime("DUE", time.Unix(task.Time, 0))
+	}
+	if task.Recurrence != "" {
+		todo.Add("RRULE", task.Recurrence)
+	}
+	if task.Completed {
+		todo.Add("STATUS", "COMPLETED")
+		todo.Add("PERCENT-COMPLETE", "100")
+		todo.AddTime("COMPLETED", time.Unix(task.CompletedAt, 0))
+	} else {
+		todo.Add("STATUS", "NEEDS-ACTION")
+	}
+	return todo
+}
+
+func taskToVEvent(task repository.Task, now time.Time) ical.Component {
+	event := ical.Component{Name: "VEVENT"}
+	event.Add("UID", task.TaskID+"-event@todolist")
+	event.AddTime("DTSTAMP", now)
+	event.AddTime("DTSTART", time.Unix(task.Time, 0))
+	event.Add("DURATION", eventDuration)
+	event.AddText("SUMMARY", task.Name)
+	if task.Description != "" {
+		event.AddText("DESCRIPTION", task.Description)
+	}
+	if task.Recurrence != "" {
+		event.Add("RRULE", task.Recurrence)
+	}
+	return event
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (ts *TaskService) RotateCalendarToken(ctx context.Context, _ *emptypb.Empty) (*v1.CalendarFeed, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	feed, err := ts.calendar.RotateToken(ctx, userCtx.UserID, userCtx.Email)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.CalendarFeed{}, apierror.Status(err)
+	}
+	log.Info("Rotated calendar token")
+	return feed, nil
+}
+
+func (ts *TaskService) CreateCalDAVPassword(ctx context.Context, in *v1.CalDAVPassword) (*v1.CalDAVPassword, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	password, err := ts.calendar.CreatePassword(ctx, userCtx.UserID, userCtx.Email, in.Name)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.CalDAVPassword{}, apierror.Status(err)
+	}
+	log.Info("Created CalDAV password", zap.String("password_id", password.PasswordId))
+	return password, nil
+}
+
+func (ts *TaskService) ListCalDAVPasswords(ctx context.Context, _ *emptypb.Empty) (*v1.CalDAVPasswordList, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	passwords, err := ts.calendar.calendarRepo.ListPasswords(ctx, userCtx.UserID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.CalDAVPasswordList{}, apierror.Status(err)
+	}
+	list := &v1.CalDAVPasswordList{}
+	for _, password := range passwords {
+		list.Passwords = append(list.Passwords, passwordToApi(password))
+	}
+	return list, nil
+}
+
+func (ts *TaskService) RevokeCalDAVPassword(ctx context.Context, in *v1.CalDAVPasswordRequest) (*emptypb.Empty, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("password_id", in.PasswordId),
+	)
+	err := ts.calendar.calendarRepo.RevokePassword(ctx, userCtx.UserID, in.PasswordId)
+	if err != nil {
+		log.Error(err.Error())
+		return &emptypb.Empty{}, apierror.Status(err)
+	}
+	log.Info("Revoked CalDAV password")
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type CalendarTestSuite struct {
+	suite.Suite
+	calendar     *Calendar
+	taskRepo     *repository.FSTaskMock
+	calendarRepo *repository.FSCalendarMock
+}
+
+func (s *CalendarTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.taskRepo = repository.NewMockRepo()
+	s.calendarRepo = repository.NewMockCalendarRepo()
+	s.calendar = NewCalendar(s.taskRepo, s.calendarRepo, logger, "http://localhost/task/calendar")
+}
+
+func (s *CalendarTestSuite) TestRotateToken() {
+	ctx := context.Background()
+	s.calendarRepo.On("SetToken", ctx, mock.Anything).Return(nil)
+	feed, err := s.calendar.RotateToken(ctx, "1", "example1@tst.com")
+	s.NoError(err)
+	s.Equal("http://localhost/task/calendar/"+feed.Token+".ics", feed.Url)
+	// raw token never reaches the database
+	s.calendarRepo.AssertCalled(s.T(), "SetToken", ctx, repository.CalendarFeed{
+		UserID:    "1",
+		UserEmail: "example1@tst.com",
+		TokenHash: hashToken(feed.Token),
+	})
+}
+
+func (s *CalendarTestSuite) TestCreatePassword() {
+	ctx := context.Background()
+	s.calendarRepo.On("CreatePassword", ctx, mock.Anything).
+		Return(repository.CalDAVPassword{PasswordID: "pid1", Name: "phone", CreatedAt: 1658102400}, nil)
+	password, err := s.calendar.CreatePassword(ctx, "1", "example1@tst.com", "phone")
+	s.NoError(err)
+	s.Equal("pid1", password.PasswordId)
+	s.Equal("phone", password.Name)
+	s.NotEmpty(password.Password)
+	// raw password never reaches the database
+	s.calendarRepo.AssertCalled(s.T(), "CreatePassword", ctx, repository.CalDAVPassword{
+		UserID:       "1",
+		UserEmail:    "example1@tst.com",
+		Name:         "phone",
+		PasswordHash: hashToken(password.Password),
+	})
+}
+
+func (s *CalendarTestSuite) TestHandleFeed() {
+	s.calendarRepo.On("GetByToken", mock.Anything, hashToken("secret")).
+		Return(repository.CalendarFeed{UserID: "1"}, nil)
+	s.calendarRepo.On("GetByToken", mock.Anything, hashToken("revoked")).
+		Return(repository.CalendarFeed{}, repository.ErrFeedNotFound)
+	s.taskRepo.On("GetAll", mock.Anything, "1").Return([]repository.Task{
+		{
+			CreatedAt:  1658102400,
+			Name:       "weekly review",
+			UserID:     "1",
+			Time:       1658188800,
+			TaskID:     "tid1",
+			Recurrence: "FREQ=WEEKLY",
+		},
+		{
+			CreatedAt:   1658102400,
+			Name:        "undated",
+			UserID:      "1",
+			TaskID:      "tid2",
+			Completed:   true,
+			CompletedAt: 1658106000,
+		},
+	}, nil)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/task/calendar/secret.ics?events=true", nil)
+	s.calendar.HandleFeed(rec, req, map[string]string{"token": "secret.ics"})
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	s.Equal(2, strings.Count(body, "BEGIN:VTODO\r\n"))
+	// only the timed task has an event
+	s.Equal(1, strings.Count(body, "BEGIN:VEVENT\r\n"))
+	s.Contains(body, "UID:tid1@todolist\r\n")
+	s.Contains(body, "DTSTART:20220719T000000Z\r\nDUE:20220719T000000Z\r\nRRULE:FREQ=WEEKLY\r\n")
+	s.Contains(body, "STATUS:COMPLETED\r\nPERCENT-COMPLETE:100\r\nCOMPLETED:20220718T010000Z\r\n")
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/task/calendar/revoked.ics", nil)
+	s.calendar.HandleFeed(rec, req, map[string]string{"token": "revoked.ics"})
+	s.Equal(http.StatusNotFound, rec.Code)
+}
+
+func TestCalendarTestSuite(t *testing.T) {
+	suite.Run(t, new(CalendarTestSuite))
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"github.com/jakubjano/todolist/task/pkg/events"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+)
+
+// UserCleanup removes the tasks and webhooks of users deleted by the user service
+type UserCleanup struct {
+	taskRepo    repository.FSTaskInterface
+	webhookRepo repository.FSWebhookInterface
+	logger      *zap.Logger
+}
+
+func NewUserCleanup(taskRepo repository.FSTaskInterface, webhookRepo repository.FSWebhookInterface,
+	logger *zap.Logger) *UserCleanup {
+	return &UserCleanup{
+		taskRepo:    taskRepo,
+		webhookRepo: webhookRepo,
+		logger:      logger,
+	}
+}
+
+func (c *UserCleanup) Subscribe(subscriber events.Subscriber) {
+	subscriber.Subscribe("user-cleanup", events.UserDeleted, c.HandleUserDeleted)
+}
+
+// HandleUserDeleted deletes the tasks one by one, so their deletions reach the change log and webhooks.
+// Webhooks go last, an error leaves the rest for the redelivered event
+func (c *UserCleanup) HandleUserDeleted(ctx context.Context, event events.Event) error {
+	data := events.UserData{}
+	err := event.Decode(&data)
+	if err != nil {
+		c.logger.Error(err.Error(), zap.String("event_id", event.ID))
+		return nil
+	}
+	log := c.logger.With(zap.String("user_id", data.UserID), zap.String("event_id", event.ID))
+	tasks, err := c.taskRepo.GetAll(ctx, data.UserID)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		err = c.taskRepo.Delete(ctx, data.UserID, task.TaskID)
+		if err != nil {
+			return err
+		}
+	}
+	webhooks, err := c.webhookRepo.List(ctx, data.UserID)
+	if err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		err = c.webhookRepo.Delete(ctx, data.UserID, webhook.WebhookID)
+		if err != nil {
+			return err
+		}
+	}
+	log.Info("Cleaned up deleted user", zap.Int("task_count", len(tasks)), zap.Int("webhook_count", len(webhooks)))
+	return nil
+}
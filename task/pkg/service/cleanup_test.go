@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"github.com/jakubjano/todolist/task/pkg/events"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"testing"
+)
+
+type UserCleanupTestSuite struct {
+	suite.Suite
+	cleanup     *UserCleanup
+	taskRepo    *repository.FSTaskMock
+	webhookRepo *repository.FSWebhookMock
+}
+
+func (s *UserCleanupTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.taskRepo = repository.NewMockRepo()
+	s.webhookRepo = repository.NewMockWebhookRepo()
+	s.cleanup = NewUserCleanup(s.taskRepo, s.webhookRepo, logger)
+}
+
+func (s *UserCleanupTestSuite) TestHandleUserDeleted() {
+	ctx := context.Background()
+	s.taskRepo.On("GetAll", ctx, "1").Return([]repository.Task{{TaskID: "tid1"}, {TaskID: "tid2"}}, nil)
+	s.taskRepo.On("Delete", ctx, "1", "tid1").Return(nil)
+	s.taskRepo.On("Delete", ctx, "1", "tid2").Return(nil)
+	s.webhookRepo.On("List", ctx, "1").Return([]repository.Webhook{{WebhookID: "wh1"}}, nil)
+	s.webhookRepo.On("Delete", ctx, "1", "wh1").Return(nil)
+	progress := events.NewMockProgress()
+	progress.On("Handled", ctx, mock.Anything, "user-cleanup").Return(false, nil)
+	progress.On("MarkHandled", ctx, mock.Anything, "user-cleanup").Return(nil)
+	bus := events.NewBus(progress, zap.NewNop())
+	s.cleanup.Subscribe(bus)
+
+	event, err := events.New(events.UserDeleted, "1", "1", events.UserData{UserID: "1"})
+	s.Require().NoError(err)
+	err = bus.Publish(ctx, event)
+	s.NoError(err)
+	s.taskRepo.AssertNumberOfCalls(s.T(), "Delete", 2)
+	s.webhookRepo.AssertCalled(s.T(), "Delete", ctx, "1", "wh1")
+}
+
+func TestUserCleanupTestSuite(t *testing.T) {
+	suite.Run(t, new(UserCleanupTestSuite))
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/mail"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"sort"
+	"time"
+)
+
+// Digests email the users who opted in a summary of their tasks due in the period and the overdue ones,
+// in the locale and time zone of their notification preferences
+type Digests struct {
+	digestRepo       repository.FSDigestInterface
+	taskRepo         repository.FSTaskInterface
+	notificationRepo repository.FSNotificationInterface
+	email            *notify.Email
+	logger           *zap.Logger
+}
+
+func NewDigests(digestRepo repository.FSDigestInterface, taskRepo repository.FSTaskInterface,
+	notificationRepo repository.FSNotificationInterface, email *notify.Email, logger *zap.Logger) *Digests {
+	return &Digests{
+		digestRepo:       digestRepo,
+		taskRepo:         taskRepo,
+		notificationRepo: notificationRepo,
+		email:            email,
+		logger:           logger,
+	}
+}
+
+// SendDigests sends the digests due at now. Every period of a user is claimed before its digest is sent,
+// so that it's never sent twice, a failed digest releases its period for the next run and doesn't stop the others
+func (d *Digests) SendDigests(ctx context.Context, now time.Time) error {
+	digests, err := d.digestRepo.SearchForDigests(ctx)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return err
+	}
+	sent := 0
+	for _, settings := range digests {
+		ok, err := d.sendDigest(ctx, settings, now)
+		if err != nil {
+			d.logger.Error(err.Error(), zap.String("user_id", settings.UserID))
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	if sent > 0 {
+		d.logger.Info("digests sent", zap.Int("digest_count", sent))
+	}
+	return nil
+}
+
+func (d *Digests) sendDigest(ctx context.Context, settings repository.DigestSettings, now time.Time) (bool, error) {
+	prefs, err := d.notificationRepo.GetPreferences(ctx, settings.UserID)
+	if err != nil {
+		return false, err
+	}
+	if !prefs.Notifies(v1.NotificationEventType_DIGEST_EVENT) {
+		return false, nil
+	}
+	period, ok := settings.Period(now.In(prefs.Location()))
+	if !ok || period.Name == settings.LastPeriod {
+		return false, nil
+	}
+	tasks, err := d.taskRepo.GetAll(ctx, settings.UserID)
+	if err != nil {
+		return false, err
+	}
+	claimed, err := d.digestRepo.ClaimPeriod(ctx, settings.UserID, period.Name)
+	if err != nil || !claimed {
+		return false, err
+	}
+	data := digestData(settings.Frequency, period, tasks, now, prefs.Locale, prefs.TimeZone)
+	// periods without any tasks are claimed without an email
+	if len(data.Due) == 0 && len(data.Overdue) == 0 {
+		return false, nil
+	}
+	to := settings.Email
+	if channel := prefs.Channels[notify.ChannelEmail]; channel.Target != "" {
+		to = channel.Target
+	}
+	err = d.email.Send(to, mail.TemplateDigest, data)
+	if err != nil {
+		releaseErr := d.digestRepo.ReleasePeriod(ctx, settings.UserID, period.Name, settings.LastPeriod)
+		if releaseErr != nil {
+			d.logger.Error(releaseErr.Error(), zap.String("user_id", settings.UserID))
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// digestData lists the uncompleted tasks due from now until the end of the period and the overdue ones,
+// both by their due time
+func digestData(frequency string, period repository.DigestPeriod, tasks []repository.Task, now time.Time,
+	locale, timeZone string) mail.DigestData {
+	if !mail.SupportedLocale(locale) {
+		locale = mail.DefaultLocale
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Time < tasks[j].Time })
+	data := mail.DigestData{
+		Frequency: frequency,
+		From:      mail.FormatTime(period.From.Unix(), locale, timeZone),
+		To:        mail.FormatTime(period.To.Unix(), locale, timeZone),
+		Locale:    locale,
+	}
+	for _, task := range tasks {
+		if task.Completed || task.Time <= 0 {
+			continue
+		}
+		digestTask := mail.DigestTask{Name: task.Name, Due: mail.FormatTime(task.Time, locale, timeZone)}
+		switch {
+		case task.Time < now.Unix():
+			data.Overdue = append(data.Overdue, digestTask)
+		case task.Time < period.To.Unix():
+			data.Due = append(data.Due, digestTask)
+		}
+	}
+	return data
+}
+
+func (ts *TaskService) GetDigestSettings(ctx context.Context, _ *emptypb.Empty) (*v1.DigestSettings, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	settings, err := ts.digests.digestRepo.GetSettings(ctx, userCtx.UserID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.DigestSettings{}, apierror.Status(err)
+	}
+	return settings.ToApi(), nil
+}
+
+func (ts *TaskService) UpdateDigestSettings(ctx context.Context, in *v1.DigestSettings) (*v1.DigestSettings, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	prev, err := ts.digests.digestRepo.GetSettings(ctx, userCtx.UserID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.DigestSettings{}, apierror.Status(err)
+	}
+	settings, err := ts.digests.digestRepo.SetSettings(ctx,
+		repository.DigestSettingsFromMsg(userCtx.UserID, userCtx.Email, in, prev))
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.DigestSettings{}, apierror.Status(err)
+	}
+	log.Info("Updated digest settings")
+	return settings.ToApi(), nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/mail"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
+	"github.com/jakubjano/todolist/task/pkg/service/notify/notifytest"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	netmail "net/mail"
+	"testing"
+	"time"
+)
+
+type DigestsTestSuite struct {
+	suite.Suite
+	digests          *Digests
+	ts               *TaskService
+	digestRepo       *repository.FSDigestMock
+	taskRepo         *repository.FSTaskMock
+	notificationRepo *repository.FSNotificationMock
+	sender           *notifytest.Sender
+	now              time.Time
+}
+
+func (s *DigestsTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.digestRepo = repository.NewMockDigestRepo()
+	s.taskRepo = repository.NewMockRepo()
+	s.notificationRepo = repository.NewMockNotificationRepo()
+	s.sender = &notifytest.Sender{}
+	templates, err := mail.ParseTemplates()
+	s.Require().NoError(err)
+	email := notify.NewEmail(s.sender, notify.EmailSettings{From: "todolist@tst.com", Templates: templates})
+	s.digests = NewDigests(s.digestRepo, s.taskRepo, s.notificationRepo, email, logger)
+	s.ts = NewTaskService(s.taskRepo, nil, nil, nil, nil, s.digests, nil, logger)
+	// Monday 8:30 in Prague
+	s.now = time.Date(2022, 7, 18, 6, 30, 0, 0, time.UTC)
+
+	prefs := repository.DefaultNotificationPreferences("")
+	prefs.TimeZone = "Europe/Prague"
+	prefs.Locale = "en-GB"
+	s.notificationRepo.On("GetPreferences", mock.Anything, mock.Anything).Return(prefs, nil)
+	s.digestRepo.On("SearchForDigests", mock.Anything).Return([]repository.DigestSettings{
+		{UserID: "1", Email: "example1@tst.com", Frequency: repository.DigestDaily, Hour: 8, LastPeriod: "2022-07-17"},
+		// sent already
+		{UserID: "2", Email: "example2@tst.com", Frequency: repository.DigestDaily, Hour: 8, LastPeriod: "2022-07-18"},
+		// not due until 9
+		{UserID: "3", Email: "example3@tst.com", Frequency: repository.DigestWeekly, Hour: 9, Weekday: 1},
+		// claimed by another instance
+		{UserID: "4", Email: "example4@tst.com", Frequency: repository.DigestWeekly, Hour: 8, Weekday: 1},
+	}, nil)
+	s.digestRepo.On("ClaimPeriod", mock.Anything, "1", "2022-07-18").Return(true, nil)
+	s.digestRepo.On("ClaimPeriod", mock.Anything, "4", "2022-07-18").Return(false, nil)
+	s.taskRepo.On("GetAll", mock.Anything, mock.Anything).Return([]repository.Task{
+		{Name: "tomorrow", Time: time.Date(2022, 7, 19, 10, 0, 0, 0, time.UTC).Unix()},
+		{Name: "today", Time: time.Date(2022, 7, 18, 15, 0, 0, 0, time.UTC).Unix()},
+		{Name: "overdue", Time: time.Date(2022, 7, 17, 10, 0, 0, 0, time.UTC).Unix()},
+		{Name: "done", Time: time.Date(2022, 7, 18, 15, 0, 0, 0, time.UTC).Unix(), Completed: true},
+		{Name: "someday"},
+	}, nil)
+}
+
+func (s *DigestsTestSuite) TestSendDigests() {
+	err := s.digests.SendDigests(context.Background(), s.now)
+	s.NoError(err)
+	s.digestRepo.AssertNumberOfCalls(s.T(), "ClaimPeriod", 2)
+	s.Require().Len(s.sender.Messages(), 1)
+	s.Equal([]string{"example1@tst.com"}, s.sender.Messages()[0].To)
+	message, err := netmail.ReadMessage(bytes.NewReader(s.sender.Messages()[0].Body))
+	s.Require().NoError(err)
+	s.Equal("Your daily digest: 1 due, 1 overdue", message.Header.Get("Subject"))
+	s.digestRepo.AssertNotCalled(s.T(), "ReleasePeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *DigestsTestSuite) TestSendDigestsFailed() {
+	s.sender.Err = errors.New("smtp down")
+	s.digestRepo.On("ReleasePeriod", mock.Anything, "1", "2022-07-18", "2022-07-17").Return(nil)
+	err := s.digests.SendDigests(context.Background(), s.now)
+	s.NoError(err)
+	s.digestRepo.AssertCalled(s.T(), "ReleasePeriod", mock.Anything, "1", "2022-07-18", "2022-07-17")
+}
+
+func (s *DigestsTestSuite) TestDigestData() {
+	period, ok := repository.DigestSettings{Frequency: repository.DigestWeekly, Weekday: 1}.Period(s.now)
+	s.Require().True(ok)
+	tasks, err := s.taskRepo.GetAll(context.Background(), "1")
+	s.Require().NoError(err)
+	data := digestData(repository.DigestWeekly, period, tasks, s.now, "", "")
+	s.Equal(mail.DigestData{
+		Frequency: repository.DigestWeekly,
+		From:      "07/18/2022 12:00 AM UTC",
+		To:        "07/25/2022 12:00 AM UTC",
+		Due: []mail.DigestTask{
+			{Name: "today", Due: "07/18/2022 3:00 PM UTC"},
+			{Name: "tomorrow", Due: "07/19/2022 10:00 AM UTC"},
+		},
+		Overdue: []mail.DigestTask{{Name: "overdue", Due: "07/17/2022 10:00 AM UTC"}},
+		Locale:  mail.DefaultLocale,
+	}, data)
+}
+
+func (s *DigestsTestSuite) TestUpdateDigestSettings() {
+	ctx := context.WithValue(context.Background(), middleware.ContextUser, &middleware.UserContext{
+		UserID: "1",
+		Email:  "example1@tst.com",
+		Role:   middleware.ContextUser,
+	})
+	s.digestRepo.On("GetSettings", mock.Anything, "1").Return(repository.DigestSettings{
+		UserID: "1", Frequency: repository.DigestDaily, LastPeriod: "2022-07-17",
+	}, nil)
+	var saved repository.DigestSettings
+	s.digestRepo.On("SetSettings", mock.Anything, mock.Anything).Return(repository.DigestSettings{}, nil).
+		Run(func(args mock.Arguments) {
+			saved = args.Get(1).(repository.DigestSettings)
+		})
+	_, err := s.ts.UpdateDigestSettings(ctx, &v1.DigestSettings{Frequency: v1.DigestFrequency_WEEKLY, Hour: 7, Weekday: 5})
+	s.NoError(err)
+	// the last period is kept, so the schedule change doesn't resend the digest
+	s.Equal(repository.DigestSettings{
+		UserID:     "1",
+		Email:      "example1@tst.com",
+		Frequency:  repository.DigestWeekly,
+		Hour:       7,
+		Weekday:    5,
+		LastPeriod: "2022-07-17",
+	}, saved)
+}
+
+func TestDigestsTestSuite(t *testing.T) {
+	suite.Run(t, new(DigestsTestSuite))
+}
@@ -1,8 +1,59 @@
 package service
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	"google.golang.org/grpc/codes"
+)
 
 var (
-	ErrUnauthorized    = errors.New("unauthorized entry")
-	ErrNoExpiringTasks = errors.New("no expiring tasks")
+	ErrUnauthorized      = apierror.New(codes.PermissionDenied, "PERMISSION_DENIED", "unauthorized entry")
+	ErrNoExpiringTasks   = errors.New("no expiring tasks")
+	ErrInvalidRange      = apierror.New(codes.InvalidArgument, "INVALID_TIME_RANGE", "invalid time range")
+	ErrTooManyTasks      = apierror.New(codes.InvalidArgument, "TOO_MANY_TASKS", "too many tasks")
+	ErrInvalidSyncToken  = apierror.New(codes.InvalidArgument, "INVALID_SYNC_TOKEN", "invalid sync token")
+	ErrSyncTokenExpired  = apierror.New(codes.FailedPrecondition, "SYNC_TOKEN_EXPIRED", "sync token expired, sync all tasks")
+	ErrInvalidWebhookURL = apierror.New(codes.InvalidArgument, "INVALID_WEBHOOK_URL",
+		"webhook url has to be an absolute http or https url")
+	ErrForbiddenWebhookURL = apierror.New(codes.InvalidArgument, "FORBIDDEN_WEBHOOK_URL",
+		"webhook url has to point at a public address")
+	ErrWeakWebhookSecret = apierror.New(codes.InvalidArgument, "WEAK_WEBHOOK_SECRET",
+		fmt.Sprintf("webhook secret has to be at least %d characters long", minWebhookSecretLength))
+	ErrTooManyWebhooks = apierror.New(codes.FailedPrecondition, "WEBHOOK_LIMIT_EXCEEDED",
+		fmt.Sprintf("at most %d webhooks per user", maxWebhooks))
+	ErrInvalidIdempotencyKey = apierror.New(codes.InvalidArgument, "INVALID_IDEMPOTENCY_KEY",
+		fmt.Sprintf("idempotency key has to be at most %d characters long", maxIdempotencyKeyLength))
+	ErrIdempotencyKeyReused = apierror.New(codes.FailedPrecondition, "IDEMPOTENCY_KEY_REUSED",
+		"idempotency key was used with a different request")
+	// in progress requests are aborted, the client may retry once the first request finishes
+	ErrIdempotentRequestInProgress = apierror.New(codes.Aborted, "IDEMPOTENT_REQUEST_IN_PROGRESS",
+		"request with the idempotency key is in progress")
+	ErrNotProtoMessage     = errors.New("not a protobuf message")
+	ErrInvalidName         = apierror.New(codes.InvalidArgument, "INVALID_RESOURCE_NAME", "invalid resource name")
+	ErrInvalidPageToken    = apierror.New(codes.InvalidArgument, "INVALID_PAGE_TOKEN", "invalid page token")
+	ErrDisplayNameRequired = apierror.New(codes.InvalidArgument, "DISPLAY_NAME_REQUIRED", "display name is required")
+	ErrInvalidUpdateMask   = apierror.New(codes.InvalidArgument, "INVALID_UPDATE_MASK",
+		"update mask contains a field that can't be updated")
+	ErrInvalidNotificationTarget = apierror.New(codes.InvalidArgument, "INVALID_NOTIFICATION_TARGET",
+		"notification target has to be an email address, an http or https url or an E.164 phone number by the channel")
+	ErrDuplicateNotificationChannel = apierror.New(codes.InvalidArgument, "DUPLICATE_NOTIFICATION_CHANNEL",
+		"notification channel is set more than once")
+	ErrUnsupportedLocale = apierror.New(codes.InvalidArgument, "UNSUPPORTED_LOCALE",
+		"locale isn't supported")
+	ErrInvalidTimeZone = apierror.New(codes.InvalidArgument, "INVALID_TIME_ZONE",
+		"time zone has to be an IANA time zone name, e.g. Europe/Prague")
+	ErrInvalidQuietHours = apierror.New(codes.InvalidArgument, "INVALID_QUIET_HOURS",
+		"quiet hours need both a start and a different end")
+	ErrConflictingNotificationEvents = apierror.New(codes.InvalidArgument, "CONFLICTING_NOTIFICATION_EVENTS",
+		"events have to be empty when notify_none is set")
+	ErrEscalationVerificationThrottled = apierror.New(codes.ResourceExhausted, "ESCALATION_VERIFICATION_THROTTLED",
+		"a confirmation was emailed to another escalation email a moment ago, try again in a few minutes")
+	ErrExportInterrupted        = errors.New("the export was interrupted, please start it again")
+	ErrNotificationFailed       = errors.New("notification failed")
+	ErrInvalidSchedulerSettings = errors.New("reminder horizon has to exceed the reconciliation interval")
+	ErrInvalidEscalation        = apierror.New(codes.InvalidArgument, "INVALID_ESCALATION",
+		"escalation needs both escalate_after and an escalation email")
+	ErrInvalidEscalationEmail = apierror.New(codes.InvalidArgument, "INVALID_ESCALATION_EMAIL",
+		"escalation email has to be an email address")
 )
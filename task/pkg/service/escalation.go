@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/emptypb"
+	netmail "net/mail"
+	"time"
+)
+
+// escalationVerificationInterval is how often the confirmation of the escalation email may be sent,
+// the address is someone else's
+const escalationVerificationInterval = time.Minute * 10
+
+func (ts *TaskService) GetEscalationPolicy(ctx context.Context, _ *emptypb.Empty) (*v1.EscalationPolicy, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	policy, err := ts.taskRepo.GetEscalationPolicy(ctx, userCtx.UserID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.EscalationPolicy{}, apierror.Status(err)
+	}
+	return policy.ToApi(), nil
+}
+
+func (ts *TaskService) UpdateEscalationPolicy(ctx context.Context, in *v1.EscalationPolicy) (*v1.EscalationPolicy, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	err := validateEscalationPolicy(in)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.EscalationPolicy{}, apierror.Status(err)
+	}
+	prev, err := ts.taskRepo.GetEscalationPolicy(ctx, userCtx.UserID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.EscalationPolicy{}, apierror.Status(err)
+	}
+	policy := repository.EscalationPolicyFromMsg(userCtx.UserID, in)
+	err = ts.verifyEscalationEmail(userCtx, &policy, prev, time.Now())
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.EscalationPolicy{}, apierror.Status(err)
+	}
+	policy, err = ts.taskRepo.SetEscalationPolicy(ctx, policy)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.EscalationPolicy{}, apierror.Status(err)
+	}
+	// the scheduler has the tasks the previous policy was done with checked again
+	log.Info("Updated escalation policy")
+	return policy.ToApi(), nil
+}
+
+// verifyEscalationEmail keeps the verification of the unchanged email and emails a new one the link confirming it.
+// Confirmations are sent escalationVerificationInterval apart at most, so the policy can't be used
+// to flood an address with them
+func (ts *TaskService) verifyEscalationEmail(userCtx *middleware.UserContext, policy *repository.EscalationPolicy,
+	prev repository.EscalationPolicy, now time.Time) error {
+	policy.VerificationSentAt = prev.VerificationSentAt
+	if policy.EscalationEmail == prev.EscalationEmail {
+		policy.EmailVerified = prev.EmailVerified
+	}
+	if policy.EscalationEmail == "" || policy.EmailVerified {
+		return nil
+	}
+	if now.Unix()-prev.VerificationSentAt < int64(escalationVerificationInterval.Seconds()) {
+		// the confirmation of the email is on its way
+		if policy.EscalationEmail == prev.EscalationEmail {
+			return nil
+		}
+		return ErrEscalationVerificationThrottled
+	}
+	err := ts.actions.SendEscalationVerification(userCtx.UserID, userCtx.Email, policy.EscalationEmail, now)
+	if err != nil {
+		return err
+	}
+	policy.VerificationSentAt = now.Unix()
+	return nil
+}
+
+func validateEscalationPolicy(in *v1.EscalationPolicy) error {
+	if (in.EscalateAfter > 0) != (in.EscalationEmail != "") {
+		return apierror.Field("escalate_after", ErrInvalidEscalation)
+	}
+	if in.EscalationEmail != "" {
+		_, err := netmail.ParseAddress(in.EscalationEmail)
+		if err != nil {
+			return apierror.Field("escalation_email", ErrInvalidEscalationEmail)
+		}
+	}
+	return nil
+}
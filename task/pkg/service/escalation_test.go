@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/mail"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
+	"github.com/jakubjano/todolist/task/pkg/service/notify/notifytest"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+type EscalationTestSuite struct {
+	suite.Suite
+	ts       *TaskService
+	taskRepo *repository.FSTaskMock
+	sender   *notifytest.Sender
+	ctx      context.Context
+}
+
+func (s *EscalationTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.taskRepo = repository.NewMockRepo()
+	s.sender = &notifytest.Sender{}
+	templates, err := mail.ParseTemplates()
+	s.Require().NoError(err)
+	email := notify.NewEmail(s.sender, notify.EmailSettings{From: "todolist@tst.com", Templates: templates})
+	actions := NewActionLinks(s.taskRepo, nil, email, logger, ActionLinkSettings{
+		BaseURL: "http://localhost/task/actions",
+		Secret:  []byte("secret"),
+		TTL:     time.Hour,
+	})
+	s.ts = NewTaskService(s.taskRepo, nil, nil, nil, nil, nil, actions, logger)
+	s.ctx = context.WithValue(context.Background(), middleware.ContextUser, &middleware.UserContext{
+		UserID: "1",
+		Email:  "example1@tst.com",
+		Role:   middleware.ContextUser,
+	})
+}
+
+func (s *EscalationTestSuite) TestUpdateEscalationPolicy() {
+	s.taskRepo.On("GetEscalationPolicy", mock.Anything, "1").Return(repository.EscalationPolicy{UserID: "1"}, nil)
+	var saved repository.EscalationPolicy
+	s.taskRepo.On("SetEscalationPolicy", mock.Anything, mock.Anything).Return(repository.EscalationPolicy{}, nil).
+		Run(func(args mock.Arguments) {
+			saved = args.Get(1).(repository.EscalationPolicy)
+		})
+	candidates := []struct {
+		in           *v1.EscalationPolicy
+		expectedCode codes.Code
+	}{
+		{in: &v1.EscalationPolicy{
+			Intervals:       []int64{86400, 3600},
+			EscalateAfter:   172800,
+			EscalationEmail: "boss@tst.com",
+		}},
+		// overdue reminders only
+		{in: &v1.EscalationPolicy{Intervals: []int64{3600}}},
+		{in: &v1.EscalationPolicy{EscalateAfter: 3600}, expectedCode: codes.InvalidArgument},
+		{in: &v1.EscalationPolicy{EscalationEmail: "boss@tst.com"}, expectedCode: codes.InvalidArgument},
+		{in: &v1.EscalationPolicy{EscalateAfter: 3600, EscalationEmail: "boss"}, expectedCode: codes.InvalidArgument},
+	}
+	for i, c := range candidates {
+		_, err := s.ts.UpdateEscalationPolicy(s.ctx, c.in)
+		s.Equalf(c.expectedCode, status.Code(err), "candidate %d", i+1)
+	}
+	s.taskRepo.AssertNumberOfCalls(s.T(), "SetEscalationPolicy", 2)
+	// the scheduler checks the tasks with the new policy, not the request
+	s.taskRepo.AssertNotCalled(s.T(), "RescheduleOverdue", mock.Anything, mock.Anything)
+	s.Equal(repository.EscalationPolicy{UserID: "1", Intervals: []int64{3600}}, saved)
+	// the escalation email got the link confirming it
+	s.Require().Len(s.sender.Messages(), 1)
+	s.Equal([]string{"boss@tst.com"}, s.sender.Messages()[0].To)
+	s.Contains(string(s.sender.Messages()[0].Body), "Subject: Confirm escalations from example1@tst.com")
+}
+
+func (s *EscalationTestSuite) TestEscalationEmailVerification() {
+	now := time.Now().Unix()
+	candidates := []struct {
+		prev             repository.EscalationPolicy
+		email            string
+		expectedCode     codes.Code
+		expectedVerified bool
+		expectedSent     bool
+	}{
+		// the verification of the unchanged email is kept
+		{
+			prev:             repository.EscalationPolicy{EscalationEmail: "boss@tst.com", EmailVerified: true},
+			email:            "boss@tst.com",
+			expectedVerified: true,
+		},
+		{
+			prev:         repository.EscalationPolicy{EscalationEmail: "boss@tst.com", EmailVerified: true},
+			email:        "boss2@tst.com",
+			expectedSent: true,
+		},
+		// the confirmation of the email is on its way
+		{
+			prev:  repository.EscalationPolicy{EscalationEmail: "boss@tst.com", VerificationSentAt: now - 60},
+			email: "boss@tst.com",
+		},
+		{
+			prev:         repository.EscalationPolicy{EscalationEmail: "boss@tst.com", VerificationSentAt: now - 60},
+			email:        "boss2@tst.com",
+			expectedCode: codes.ResourceExhausted,
+		},
+		{
+			prev:         repository.EscalationPolicy{EscalationEmail: "boss@tst.com", VerificationSentAt: now - 3600},
+			email:        "boss@tst.com",
+			expectedSent: true,
+		},
+	}
+	for i, c := range candidates {
+		s.taskRepo.ExpectedCalls = nil
+		s.taskRepo.On("GetEscalationPolicy", mock.Anything, "1").Return(c.prev, nil)
+		var saved repository.EscalationPolicy
+		s.taskRepo.On("SetEscalationPolicy", mock.Anything, mock.Anything).Return(repository.EscalationPolicy{}, nil).
+			Run(func(args mock.Arguments) {
+				saved = args.Get(1).(repository.EscalationPolicy)
+			})
+		sent := len(s.sender.Messages())
+		_, err := s.ts.UpdateEscalationPolicy(s.ctx, &v1.EscalationPolicy{EscalateAfter: 3600, EscalationEmail: c.email})
+		s.Equalf(c.expectedCode, status.Code(err), "candidate %d", i+1)
+		if err != nil {
+			continue
+		}
+		s.Equalf(c.expectedVerified, saved.EmailVerified, "candidate %d", i+1)
+		s.Equalf(c.expectedSent, len(s.sender.Messages()) > sent, "candidate %d", i+1)
+	}
+}
+
+func (s *EscalationTestSuite) TestGetEscalationPolicy() {
+	s.taskRepo.On("GetEscalationPolicy", mock.Anything, "1").Return(repository.EscalationPolicy{
+		UserID:          "1",
+		Intervals:       []int64{3600, 86400},
+		EscalateAfter:   172800,
+		EscalationEmail: "boss@tst.com",
+	}, nil)
+	policy, err := s.ts.GetEscalationPolicy(s.ctx, nil)
+	s.NoError(err)
+	s.Equal([]int64{3600, 86400}, policy.Intervals)
+	s.Equal(int64(172800), policy.EscalateAfter)
+	s.Equal("boss@tst.com", policy.EscalationEmail)
+}
+
+func TestEscalationTestSuite(t *testing.T) {
+	suite.Run(t, new(EscalationTestSuite))
+}
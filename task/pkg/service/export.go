@@ -0,0 +1,261 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/importer"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/jakubjano/todolist/task/pkg/service/storage"
+	"github.com/jakubjano/todolist/task/pkg/service/textformat"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// exportTimeout bounds a run, the sweep fails the jobs left pending or running past it by a stopped instance
+	exportTimeout = time.Minute * 10
+	// exportFinishTimeout is how long a run out of time has to record its failure
+	exportFinishTimeout = time.Second * 10
+)
+
+// Exporter writes user's tasks into the blob store in the background
+// and tracks the progress in export job documents
+type Exporter struct {
+	taskRepo   repository.FSTaskInterface
+	exportRepo repository.FSExportInterface
+	blobStore  storage.BlobStore
+	logger     *zap.Logger
+	linkTTL    time.Duration
+}
+
+func NewExporter(taskRepo repository.FSTaskInterface, exportRepo repository.FSExportInterface,
+	blobStore storage.BlobStore, logger *zap.Logger, linkTTL time.Duration) *Exporter {
+	return &Exporter{
+		taskRepo:   taskRepo,
+		exportRepo: exportRepo,
+		blobStore:  blobStore,
+		logger:     logger,
+		linkTTL:    linkTTL,
+	}
+}
+
+// Start creates a pending export job and runs it detached from the request, for exportTimeout at most
+func (e *Exporter) Start(ctx context.Context, userID string, format v1.ExportFormat) (repository.ExportJob, error) {
+	job, err := e.exportRepo.Create(ctx, repository.ExportJob{
+		UserID: userID,
+		Format: format.String(),
+		Status: v1.ExportStatus_PENDING.String(),
+	})
+	if err != nil {
+		return repository.ExportJob{}, err
+	}
+	go func() {
+		runCtx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+		e.Run(runCtx, job)
+	}()
+	return job, nil
+}
+
+// Run exports the tasks and stores the final state of the job
+func (e *Exporter) Run(ctx context.Context, job repository.ExportJob) {
+	log := e.logger.With(
+		zap.String("user_id", job.UserID),
+		zap.String("job_id", job.JobID),
+		zap.String("format", job.Format),
+	)
+	job.Status = v1.ExportStatus_RUNNING.String()
+	err := e.exportRepo.Update(ctx, job)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+	job, err = e.export(ctx, job)
+	job.FinishedAt = time.Now().Unix()
+	// a run out of time still records its failure
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), exportFinishTimeout)
+		defer cancel()
+	}
+	if err != nil {
+		log.Error(err.Error())
+		job.Status = v1.ExportStatus_FAILED.String()
+		job.Error = err.Error()
+	} else {
+		job.Status = v1.ExportStatus_DONE.String()
+	}
+	err = e.exportRepo.Update(ctx, job)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+	log.Info("Export finished", zap.String("status", job.Status), zap.Int32("task_count", job.TaskCount))
+}
+
+func (e *Exporter) export(ctx context.Context, job repository.ExportJob) (repository.ExportJob, error) {
+	tasks, err := e.taskRepo.GetAll(ctx, job.UserID)
+	if err != nil {
+		return job, err
+	}
+	var data []byte
+	var contentType, ext string
+	switch v1.ExportFormat_value[job.Format] {
+	case int32(v1.ExportFormat_CSV):
+		data, err = encodeTasksCSV(tasks)
+		contentType, ext = "text/csv", "csv"
+	default:
+		data, err = protojson.Marshal(repository.SliceToApi(tasks))
+		contentType, ext = "application/json", "json"
+	}
+	if err != nil {
+		return job, err
+	}
+	key := fmt.Sprintf("%s/%s/%s.%s", repository.CollectionExports, job.UserID, job.JobID, ext)
+	err = e.blobStore.Put(ctx, key, contentType, data)
+	if err != nil {
+		return job, err
+	}
+	job.BlobKey = key
+	job.TaskCount = int32(len(tasks))
+	return job, nil
+}
+
+// FailStale marks the jobs created before now less the export timeout that are still pending or running
+// as failed, the instance running them stopped. It returns how many jobs it failed
+func (e *Exporter) FailStale(ctx context.Context, now time.Time) (int, error) {
+	before := now.Add(-exportTimeout - exportFinishTimeout).Unix()
+	failed, err := e.exportRepo.FailStale(ctx, before, ErrExportInterrupted.Error())
+	if err != nil {
+		e.logger.Error(err.Error())
+		return failed, err
+	}
+	if failed > 0 {
+		e.logger.Info("Failed stale exports", zap.Int("job_count", failed))
+	}
+	return failed, nil
+}
+
+// Get returns the job with a fresh download link when the export is done
+func (e *Exporter) Get(ctx context.Context, userID, jobID string) (*v1.ExportJob, error) {
+	job, err := e.exportRepo.Get(ctx, userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	apiJob := job.ToApi()
+	if job.Status != v1.ExportStatus_DONE.String() {
+		return apiJob, nil
+	}
+	expires := time.Now().Add(e.linkTTL)
+	apiJob.DownloadUrl, err = e.blobStore.SignedURL(ctx, job.BlobKey, expires)
+	if err != nil {
+		return nil, err
+	}
+	apiJob.DownloadUrlExpiresAt = expires.Unix()
+	return apiJob, nil
+}
+
+// encodeTasksCSV writes a header row and one row per task with times in RFC 3339
+func encodeTasksCSV(tasks []repository.Task) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	err := w.Write([]string{"task_id", "name", "description", "time", "created_at", "completed", "completed_at"})
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		err = w.Write([]string{
+			task.TaskID,
+			task.Name,
+			task.Description,
+			formatUnix(task.Time),
+			formatUnix(task.CreatedAt),
+			strconv.FormatBool(task.Completed),
+			formatUnix(task.CompletedAt),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func formatUnix(unix int64) string {
+	if unix == 0 {
+		return ""
+	}
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
+
+func (ts *TaskService) ExportTasks(ctx context.Context, in *v1.ExportTasksRequest) (*v1.ExportJob, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("format", in.Format.String()),
+	)
+	job, err := ts.exporter.Start(ctx, userCtx.UserID, in.Format)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.ExportJob{}, apierror.Status(err)
+	}
+	log.Info("Started export", zap.String("job_id", job.JobID))
+	return job.ToApi(), nil
+}
+
+func (ts *TaskService) GetExportJob(ctx context.Context, in *v1.GetExportJobRequest) (*v1.ExportJob, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("job_id", in.JobId),
+	)
+	job, err := ts.exporter.Get(ctx, userCtx.UserID, in.JobId)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.ExportJob{}, apierror.Status(err)
+	}
+	return job, nil
+}
+
+func (ts *TaskService) ExportTasksText(ctx context.Context, in *v1.ExportTasksTextRequest) (*v1.TasksText, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("format", in.Format.String()),
+	)
+	var format func(io.Writer, []repository.Task) error
+	switch in.Format {
+	case v1.TextFormat_TODO_TXT:
+		format = textformat.FormatTodoTxt
+	case v1.TextFormat_MARKDOWN:
+		format = textformat.FormatMarkdown
+	default:
+		// formats of other task managers are import only
+		log.Error(importer.ErrUnsupportedFormat.Error())
+		return &v1.TasksText{}, apierror.Status(apierror.Field("format", importer.ErrUnsupportedFormat))
+	}
+	tasks, err := ts.taskRepo.GetAll(ctx, userCtx.UserID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.TasksText{}, apierror.Status(err)
+	}
+	b := &strings.Builder{}
+	err = format(b, tasks)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.TasksText{}, apierror.Status(err)
+	}
+	return &v1.TasksText{Format: in.Format, Content: b.String()}, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/jakubjano/todolist/task/pkg/service/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type ExporterTestSuite struct {
+	suite.Suite
+	exporter   *Exporter
+	store      *storage.LocalStore
+	taskRepo   *repository.FSTaskMock
+	exportRepo *repository.FSExportMock
+}
+
+func (s *ExporterTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.taskRepo = repository.NewMockRepo()
+	s.exportRepo = repository.NewMockExportRepo()
+	s.store = storage.NewLocalStore(s.T().TempDir(), "http://localhost/task/export/download", []byte("secret"))
+	s.exporter = NewExporter(s.taskRepo, s.exportRepo, s.store, logger, time.Minute)
+}
+
+func (s *ExporterTestSuite) TestRunCSV() {
+	ctx := context.Background()
+	job := repository.ExportJob{
+		JobID:  "job1",
+		UserID: "1",
+		Format: v1.ExportFormat_CSV.String(),
+		Status: v1.ExportStatus_PENDING.String(),
+	}
+	s.taskRepo.On("GetAll", ctx, "1").Return([]repository.Task{
+		{
+			CreatedAt:   1658102400,
+			Name:        "task1",
+			Description: "desc, with comma",
+			UserID:      "1",
+			Time:        1658188800,
+			TaskID:      "tid1",
+		},
+	}, nil)
+	var stored repository.ExportJob
+	s.exportRepo.On("Update", ctx, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(repository.ExportJob)
+	})
+	s.exporter.Run(ctx, job)
+	s.Equal(v1.ExportStatus_DONE.String(), stored.Status)
+	s.Equal(int32(1), stored.TaskCount)
+	s.Equal("exports/1/job1.csv", stored.BlobKey)
+
+	s.exportRepo.On("Get", ctx, "1", "job1").Return(stored, nil)
+
+	apiJob, err := s.exporter.Get(ctx, "1", "job1")
+	s.NoError(err)
+	s.NotEmpty(apiJob.DownloadUrl)
+	rec := httptest.NewRecorder()
+	s.store.Download(rec, httptest.NewRequest(http.MethodGet, apiJob.DownloadUrl, nil))
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("task_id,name,description,time,created_at,completed,completed_at\n"+
+		"tid1,task1,\"desc, with comma\",2022-07-19T00:00:00Z,2022-07-18T00:00:00Z,false,\n", rec.Body.String())
+}
+
+func (s *ExporterTestSuite) TestRunFailed() {
+	ctx := context.Background()
+	job := repository.ExportJob{
+		JobID:  "job2",
+		UserID: "2",
+		Format: v1.ExportFormat_JSON.String(),
+	}
+	s.taskRepo.On("GetAll", ctx, "2").Return([]repository.Task{}, ErrUnauthorized)
+	var stored repository.ExportJob
+	s.exportRepo.On("Update", ctx, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(repository.ExportJob)
+	})
+	s.exporter.Run(ctx, job)
+	s.Equal(v1.ExportStatus_FAILED.String(), stored.Status)
+	s.Equal(ErrUnauthorized.Error(), stored.Error)
+	s.NotZero(stored.FinishedAt)
+}
+
+func (s *ExporterTestSuite) TestRunTimeout() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	job := repository.ExportJob{JobID: "job3", UserID: "3", Format: v1.ExportFormat_JSON.String()}
+	s.taskRepo.On("GetAll", ctx, "3").Return([]repository.Task{}, context.Canceled)
+	s.exportRepo.On("Update", ctx, mock.Anything).Return(nil).Once()
+	var stored repository.ExportJob
+	// the failure is recorded with a context of its own
+	s.exportRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		s.NoError(args.Get(0).(context.Context).Err())
+		stored = args.Get(1).(repository.ExportJob)
+	})
+	s.exporter.Run(ctx, job)
+	s.Equal(v1.ExportStatus_FAILED.String(), stored.Status)
+}
+
+func (s *ExporterTestSuite) TestFailStale() {
+	ctx := context.Background()
+	now := time.Unix(1658102400, 0)
+	s.exportRepo.On("FailStale", ctx, now.Add(-exportTimeout-exportFinishTimeout).Unix(),
+		ErrExportInterrupted.Error()).Return(2, nil)
+	failed, err := s.exporter.FailStale(ctx, now)
+	s.NoError(err)
+	s.Equal(2, failed)
+}
+
+func TestExporterTestSuite(t *testing.T) {
+	suite.Run(t, new(ExporterTestSuite))
+}
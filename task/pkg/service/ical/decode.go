@@ -0,0 +1,172 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	timeLayoutLocal = "20060102T150405"
+	dateLayout      = "20060102"
+)
+
+var (
+	ErrMalformed = errors.New("malformed iCalendar data")
+)
+
+// Decode parses the first component of the iCalendar stream including its nested components
+func Decode(r io.Reader) (Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return Component{}, err
+	}
+	var stack []*Component
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, err := parseLine(line)
+		if err != nil {
+			return Component{}, err
+		}
+		switch strings.ToUpper(prop.Name) {
+		case "BEGIN":
+			stack = append(stack, &Component{Name: strings.ToUpper(prop.Value)})
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+				return Component{}, ErrMalformed
+			}
+			done := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return *done, nil
+			}
+			parent := stack[len(stack)-1]
+			parent.Components = append(parent.Components, *done)
+		default:
+			if len(stack) == 0 {
+				return Component{}, ErrMalformed
+			}
+			current := stack[len(stack)-1]
+			current.Props = append(current.Props, prop)
+		}
+	}
+	return Component{}, ErrMalformed
+}
+
+// unfold joins continuation lines starting with a space or tab to the previous line
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine splits a content line into name, parameters and value, parameter values may be quoted
+func parseLine(line string) (Property, error) {
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return Property{}, ErrMalformed
+	}
+	prop := Property{Name: strings.ToUpper(line[:i])}
+	rest := line[i:]
+	for rest[0] == ';' {
+		rest = rest[1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return Property{}, ErrMalformed
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return Property{}, ErrMalformed
+			}
+			value = rest[1 : end+1]
+			rest = rest[end+2:]
+		} else {
+			end := strings.IndexAny(rest, ";:")
+			if end < 0 {
+				return Property{}, ErrMalformed
+			}
+			value = rest[:end]
+			rest = rest[end:]
+		}
+		if rest == "" || (rest[0] != ';' && rest[0] != ':') {
+			return Property{}, ErrMalformed
+		}
+		if prop.Params == nil {
+			prop.Params = make(map[string]string)
+		}
+		prop.Params[name] = value
+	}
+	prop.Value = rest[1:]
+	return prop, nil
+}
+
+// Prop returns the first property with the name or nil
+func (c Component) Prop(name string) *Property {
+	for i := range c.Props {
+		if c.Props[i].Name == name {
+			return &c.Props[i]
+		}
+	}
+	return nil
+}
+
+// Text returns the unescaped TEXT value of the first property with the name
+func (c Component) Text(name string) string {
+	prop := c.Prop(name)
+	if prop == nil {
+		return ""
+	}
+	return UnescapeText(prop.Value)
+}
+
+// UnescapeText reverses EscapeText
+func UnescapeText(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if !escaped && r == '\\' {
+			escaped = true
+			continue
+		}
+		if escaped && (r == 'n' || r == 'N') {
+			r = '\n'
+		}
+		escaped = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Time parses a DATE or DATE-TIME value, times without the UTC designator are in the zone
+// of the TZID parameter or UTC when the zone is unknown
+func (p Property) Time() (time.Time, error) {
+	if p.Params["VALUE"] == "DATE" || len(p.Value) == len(dateLayout) {
+		return time.ParseInLocation(dateLayout, p.Value, time.UTC)
+	}
+	if strings.HasSuffix(p.Value, "Z") {
+		return time.Parse(timeLayoutUTC, p.Value)
+	}
+	loc := time.UTC
+	if tzid, ok := p.Params["TZID"]; ok {
+		if zone, err := time.LoadLocation(tzid); err == nil {
+			loc = zone
+		}
+	}
+	return time.ParseInLocation(timeLayoutLocal, p.Value, loc)
+}
@@ -0,0 +1,163 @@
+// Package ical encodes iCalendar (RFC 5545) objects
+package ical
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	maxLineOctets  = 75
+	timeLayoutUTC  = "20060102T150405Z"
+	lineSeparator  = "\r\n"
+	foldedLineHead = " "
+)
+
+var (
+	ErrInvalidRRule = errors.New("invalid recurrence rule")
+
+	frequencies = map[string]bool{
+		"SECONDLY": true,
+		"MINUTELY": true,
+		"HOURLY":   true,
+		"DAILY":    true,
+		"WEEKLY":   true,
+		"MONTHLY":  true,
+		"YEARLY":   true,
+	}
+)
+
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Component is a calendar object like VCALENDAR, VTODO or VEVENT with its properties
+// and nested components
+type Component struct {
+	Name       string
+	Props      []Property
+	Components []Component
+}
+
+// Add appends a property with already formatted value
+func (c *Component) Add(name, value string) {
+	c.Props = append(c.Props, Property{Name: name, Value: value})
+}
+
+// AddText appends a property with value escaped as TEXT
+func (c *Component) AddText(name, value string) {
+	c.Add(name, EscapeText(value))
+}
+
+// AddTime appends a property with a DATE-TIME value in UTC
+func (c *Component) AddTime(name string, t time.Time) {
+	c.Add(name, FormatTime(t))
+}
+
+// Encode writes the component with CRLF line endings and lines folded to 75 octets
+func (c Component) Encode(w io.Writer) error {
+	err := writeLine(w, "BEGIN:"+c.Name)
+	if err != nil {
+		return err
+	}
+	for _, prop := range c.Props {
+		err = writeLine(w, prop.String())
+		if err != nil {
+			return err
+		}
+	}
+	for _, nested := range c.Components {
+		err = nested.Encode(w)
+		if err != nil {
+			return err
+		}
+	}
+	return writeLine(w, "END:"+c.Name)
+}
+
+// String returns the unfolded content line of the property
+func (p Property) String() string {
+	var b strings.Builder
+	b.WriteString(p.Name)
+	names := make([]string, 0, len(p.Params))
+	for name := range p.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := p.Params[name]
+		if strings.ContainsAny(value, ";:,") {
+			value = `"` + value + `"`
+		}
+		b.WriteString(";" + name + "=" + value)
+	}
+	b.WriteString(":" + p.Value)
+	return b.String()
+}
+
+func writeLine(w io.Writer, line string) error {
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		// never split a multi-byte UTF-8 sequence
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString(lineSeparator + foldedLineHead)
+		line = line[cut:]
+		// the leading space of continuation lines counts into the limit
+		limit = maxLineOctets - len(foldedLineHead)
+	}
+	b.WriteString(line)
+	b.WriteString(lineSeparator)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// EscapeText escapes backslashes, semicolons, commas and newlines of a TEXT value
+func EscapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// FormatTime formats t as a DATE-TIME in UTC
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(timeLayoutUTC)
+}
+
+// ValidateRRule checks that the value is a list of NAME=VALUE rule parts with a known FREQ
+func ValidateRRule(rrule string) error {
+	freq := false
+	for _, part := range strings.Split(rrule, ";") {
+		name, value, found := strings.Cut(part, "=")
+		if !found || name == "" || value == "" {
+			return ErrInvalidRRule
+		}
+		if strings.ToUpper(name) == "FREQ" {
+			if !frequencies[strings.ToUpper(value)] {
+				return ErrInvalidRRule
+			}
+			freq = true
+		}
+	}
+	if !freq {
+		return ErrInvalidRRule
+	}
+	return nil
+}
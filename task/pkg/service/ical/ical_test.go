@@ -0,0 +1,99 @@
+package ical
+
+import (
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ICalTestSuite struct {
+	suite.Suite
+}
+
+func (s *ICalTestSuite) TestEncode() {
+	todo := Component{Name: "VTODO"}
+	todo.Add("UID", "tid1@todolist")
+	todo.AddTime("DUE", time.Date(2022, 7, 19, 10, 30, 0, 0, time.UTC))
+	todo.AddText("SUMMARY", "buy milk, eggs; bread\nand \\ butter")
+	cal := Component{Name: "VCALENDAR", Components: []Component{todo}}
+	cal.Add("VERSION", "2.0")
+
+	b := &strings.Builder{}
+	err := cal.Encode(b)
+	s.NoError(err)
+	s.Equal("BEGIN:VCALENDAR\r\n"+
+		"VERSION:2.0\r\n"+
+		"BEGIN:VTODO\r\n"+
+		"UID:tid1@todolist\r\n"+
+		"DUE:20220719T103000Z\r\n"+
+		`SUMMARY:buy milk\, eggs\; bread\nand \\ butter`+"\r\n"+
+		"END:VTODO\r\n"+
+		"END:VCALENDAR\r\n", b.String())
+}
+
+func (s *ICalTestSuite) TestFolding() {
+	todo := Component{Name: "VTODO"}
+	todo.AddText("DESCRIPTION", strings.Repeat("ž", 100))
+	b := &strings.Builder{}
+	err := todo.Encode(b)
+	s.NoError(err)
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	unfolded := ""
+	for _, line := range lines {
+		s.LessOrEqual(len(line), maxLineOctets)
+		unfolded += strings.TrimPrefix(line, " ")
+	}
+	s.Equal("BEGIN:VTODODESCRIPTION:"+strings.Repeat("ž", 100)+"END:VTODO", unfolded)
+}
+
+func (s *ICalTestSuite) TestValidateRRule() {
+	s.NoError(ValidateRRule("FREQ=WEEKLY;BYDAY=MO,WE"))
+	s.NoError(ValidateRRule("freq=daily;COUNT=5"))
+	s.ErrorIs(ValidateRRule(""), ErrInvalidRRule)
+	s.ErrorIs(ValidateRRule("BYDAY=MO"), ErrInvalidRRule)
+	s.ErrorIs(ValidateRRule("FREQ=FORTNIGHTLY"), ErrInvalidRRule)
+	s.ErrorIs(ValidateRRule("FREQ=DAILY;COUNT"), ErrInvalidRRule)
+}
+
+func (s *ICalTestSuite) TestDecode() {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:abc\r\n" +
+		"SUMMARY:buy milk\\, eggs\\; long summary folded over\r\n" +
+		"  two lines\r\n" +
+		"DUE;TZID=Europe/Prague:20220719T120000\r\n" +
+		"DTSTART;VALUE=DATE:20220718\r\n" +
+		"X-APPLE-SORT-ORDER;X-PARAM=\"a:b;c\":1\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+	cal, err := Decode(strings.NewReader(data))
+	s.NoError(err)
+	s.Equal("VCALENDAR", cal.Name)
+	s.Len(cal.Components, 1)
+	todo := cal.Components[0]
+	s.Equal("buy milk, eggs; long summary folded over two lines", todo.Text("SUMMARY"))
+	due, err := todo.Prop("DUE").Time()
+	s.NoError(err)
+	s.Equal(time.Date(2022, 7, 19, 10, 0, 0, 0, time.UTC), due.UTC())
+	start, err := todo.Prop("DTSTART").Time()
+	s.NoError(err)
+	s.Equal(time.Date(2022, 7, 18, 0, 0, 0, 0, time.UTC), start)
+	s.Equal("a:b;c", todo.Prop("X-APPLE-SORT-ORDER").Params["X-PARAM"])
+	s.Equal("1", todo.Prop("X-APPLE-SORT-ORDER").Value)
+
+	// encoding the decoded component gives back the same lines
+	b := &strings.Builder{}
+	s.NoError(todo.Encode(b))
+	s.Contains(b.String(), "X-APPLE-SORT-ORDER;X-PARAM=\"a:b;c\":1\r\n")
+
+	_, err = Decode(strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VCALENDAR\r\n"))
+	s.ErrorIs(err, ErrMalformed)
+	_, err = Decode(strings.NewReader("SUMMARY:outside\r\n"))
+	s.ErrorIs(err, ErrMalformed)
+}
+
+func TestICalTestSuite(t *testing.T) {
+	suite.Run(t, new(ICalTestSuite))
+}
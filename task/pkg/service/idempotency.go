@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"strings"
+	"time"
+)
+
+const (
+	// MetadataIdempotencyKey is the gRPC metadata key, the gateway maps the Idempotency-Key header onto it
+	MetadataIdempotencyKey  = "idempotency-key"
+	maxIdempotencyKeyLength = 255
+	// idempotencyLease has to outlast the request, a retry takes over the key after it runs out
+	idempotencyLease = time.Minute
+)
+
+// Idempotency replays the response of the first request to the requests repeated with the same
+// idempotency key, so retried calls don't create duplicate tasks. Keys are scoped to the user and method
+type Idempotency struct {
+	idempotencyRepo repository.FSIdempotencyInterface
+	logger          *zap.Logger
+	// methods are the full gRPC method names keys are accepted for
+	methods map[string]bool
+}
+
+func NewIdempotency(idempotencyRepo repository.FSIdempotencyInterface, logger *zap.Logger,
+	methods ...string) *Idempotency {
+	i := &Idempotency{
+		idempotencyRepo: idempotencyRepo,
+		logger:          logger,
+		methods:         make(map[string]bool),
+	}
+	for _, method := range methods {
+		i.methods[method] = true
+	}
+	return i
+}
+
+// UnaryInterceptor has to run after the auth interceptor, which puts the user into the context.
+// Requests without the key are passed through
+func (i *Idempotency) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		key := idempotencyKey(ctx)
+		if key == "" || !i.methods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+		log := i.logger.With(
+			zap.String("caller_email", userCtx.Email),
+			zap.String("caller_id", userCtx.UserID),
+			zap.String("method", info.FullMethod),
+			zap.String("idempotency_key", key),
+		)
+		if len(key) > maxIdempotencyKeyLength {
+			log.Error(ErrInvalidIdempotencyKey.Error())
+			return nil, apierror.Status(ErrInvalidIdempotencyKey)
+		}
+		requestHash, err := hashRequest(info.FullMethod, req)
+		if err != nil {
+			log.Error(err.Error())
+			return nil, apierror.Status(err)
+		}
+		// the method is a part of the document ID, keys may contain characters not allowed in IDs
+		docID := hashKey(info.FullMethod + "\n" + key)
+		record, reserved, err := i.idempotencyRepo.Reserve(ctx, userCtx.UserID, docID, repository.IdempotencyRecord{
+			Method:      info.FullMethod,
+			RequestHash: requestHash,
+		}, idempotencyLease)
+		if err != nil {
+			log.Error(err.Error())
+			return nil, apierror.Status(err)
+		}
+		if !reserved {
+			return i.replay(log, record, requestHash)
+		}
+		resp, err := handler(ctx, req)
+		if err != nil {
+			// failed requests may be retried with the same key
+			releaseErr := i.idempotencyRepo.Release(ctx, userCtx.UserID, docID)
+			if releaseErr != nil {
+				log.Error(releaseErr.Error())
+			}
+			return resp, err
+		}
+		response, err := marshalResponse(resp)
+		if err == nil {
+			err = i.idempotencyRepo.Complete(ctx, userCtx.UserID, docID, response)
+		}
+		if err != nil {
+			// the request succeeded, replays are refused as in progress until the lease runs out
+			log.Error(err.Error())
+		}
+		return resp, nil
+	}
+}
+
+func (i *Idempotency) replay(log *zap.Logger, record repository.IdempotencyRecord,
+	requestHash string) (interface{}, error) {
+	if record.RequestHash != requestHash {
+		log.Error(ErrIdempotencyKeyReused.Error())
+		return nil, apierror.Status(ErrIdempotencyKeyReused)
+	}
+	if !record.Completed {
+		log.Error(ErrIdempotentRequestInProgress.Error())
+		return nil, apierror.Status(ErrIdempotentRequestInProgress)
+	}
+	resp, err := unmarshalResponse(record.Response)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, apierror.Status(err)
+	}
+	log.Info("Replayed idempotent request")
+	return resp, nil
+}
+
+func idempotencyKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(MetadataIdempotencyKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(values[0])
+}
+
+// hashRequest tells requests apart, the same message is encoded the same way by the deterministic marshal
+func hashRequest(method string, req interface{}) (string, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return "", ErrNotProtoMessage
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	return hashKey(method + "\n" + string(b)), nil
+}
+
+func hashKey(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// marshalResponse keeps the type of the response with it, so the replay doesn't have to know the method
+func marshalResponse(resp interface{}) ([]byte, error) {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return nil, ErrNotProtoMessage
+	}
+	a, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(a)
+}
+
+func unmarshalResponse(b []byte) (proto.Message, error) {
+	a := &anypb.Any{}
+	err := proto.Unmarshal(b, a)
+	if err != nil {
+		return nil, err
+	}
+	return a.UnmarshalNew()
+}
+
+// IdempotencyHeaderMatcher forwards the Idempotency-Key header of gateway requests into the gRPC metadata
+func IdempotencyHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, "Idempotency-Key") {
+		return MetadataIdempotencyKey, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
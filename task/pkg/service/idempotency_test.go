@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+const createTaskMethod = "/task.TaskService/CreateTask"
+
+type IdempotencyTestSuite struct {
+	suite.Suite
+	idempotency     *Idempotency
+	idempotencyRepo *repository.FSIdempotencyMock
+	interceptor     grpc.UnaryServerInterceptor
+	handled         int
+}
+
+func (s *IdempotencyTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.idempotencyRepo = repository.NewMockIdempotencyRepo()
+	s.idempotency = NewIdempotency(s.idempotencyRepo, logger, createTaskMethod)
+	s.interceptor = s.idempotency.UnaryInterceptor()
+	s.handled = 0
+}
+
+func (s *IdempotencyTestSuite) call(key string, in *v1.Task, handlerErr error) (*v1.Task, error) {
+	ctx := context.WithValue(context.Background(), middleware.ContextUser, &middleware.UserContext{
+		UserID: "1",
+		Email:  "example1@tst.com",
+		Role:   "user",
+	})
+	if key != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(MetadataIdempotencyKey, key))
+	}
+	resp, err := s.interceptor(ctx, in, &grpc.UnaryServerInfo{FullMethod: createTaskMethod},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			s.handled++
+			if handlerErr != nil {
+				return &v1.Task{}, handlerErr
+			}
+			return &v1.Task{TaskId: "tid1", Name: req.(*v1.Task).Name}, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*v1.Task), nil
+}
+
+func (s *IdempotencyTestSuite) TestFirstRequest() {
+	var stored []byte
+	s.idempotencyRepo.On("Reserve", mock.Anything, "1", mock.Anything, mock.Anything, idempotencyLease).
+		Return(repository.IdempotencyRecord{}, true, nil)
+	s.idempotencyRepo.On("Complete", mock.Anything, "1", mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(3).([]byte)
+		})
+
+	resp, err := s.call("key1", &v1.Task{Name: "task1"}, nil)
+	s.NoError(err)
+	s.Equal("tid1", resp.TaskId)
+	s.Equal(1, s.handled)
+	replayed, err := unmarshalResponse(stored)
+	s.NoError(err)
+	s.True(proto.Equal(resp, replayed))
+}
+
+func (s *IdempotencyTestSuite) TestReplay() {
+	in := &v1.Task{Name: "task1"}
+	requestHash, err := hashRequest(createTaskMethod, in)
+	s.Require().NoError(err)
+	response, err := marshalResponse(&v1.Task{TaskId: "tid1", Name: "task1"})
+	s.Require().NoError(err)
+	candidates := []struct {
+		record       repository.IdempotencyRecord
+		in           *v1.Task
+		expectedCode codes.Code
+	}{
+		// same request gets the stored response
+		{
+			record:       repository.IdempotencyRecord{RequestHash: requestHash, Response: response, Completed: true},
+			in:           in,
+			expectedCode: codes.OK,
+		},
+		// key reused with another request
+		{
+			record:       repository.IdempotencyRecord{RequestHash: requestHash, Response: response, Completed: true},
+			in:           &v1.Task{Name: "task2"},
+			expectedCode: codes.FailedPrecondition,
+		},
+		// first request still running
+		{
+			record:       repository.IdempotencyRecord{RequestHash: requestHash},
+			in:           in,
+			expectedCode: codes.Aborted,
+		},
+	}
+	for _, c := range candidates {
+		s.SetupTest()
+		s.idempotencyRepo.On("Reserve", mock.Anything, "1", mock.Anything, mock.Anything, idempotencyLease).Return(c.record, false, nil)
+		resp, err := s.call("key1", c.in, nil)
+		s.Equal(c.expectedCode, status.Code(err))
+		s.Zero(s.handled)
+		if err == nil {
+			s.Equal("tid1", resp.TaskId)
+		}
+	}
+}
+
+func (s *IdempotencyTestSuite) TestFailedRequestReleasesKey() {
+	s.idempotencyRepo.On("Reserve", mock.Anything, "1", mock.Anything, mock.Anything, idempotencyLease).
+		Return(repository.IdempotencyRecord{}, true, nil)
+	s.idempotencyRepo.On("Release", mock.Anything, "1", mock.Anything).Return(nil)
+
+	_, err := s.call("key1", &v1.Task{Name: "task1"}, status.Error(codes.Internal, "failed"))
+	s.Equal(codes.Internal, status.Code(err))
+	s.idempotencyRepo.AssertCalled(s.T(), "Release", mock.Anything, "1", mock.Anything)
+	s.idempotencyRepo.AssertNotCalled(s.T(), "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *IdempotencyTestSuite) TestWithoutKey() {
+	resp, err := s.call("", &v1.Task{Name: "task1"}, nil)
+	s.NoError(err)
+	s.Equal("tid1", resp.TaskId)
+	s.idempotencyRepo.AssertNotCalled(s.T(), "Reserve", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyTestSuite))
+}
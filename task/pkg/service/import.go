@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/importer"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// maxImportTasks caps the number of tasks created by a single import
+const maxImportTasks = 1000
+
+// ImportTasks creates the caller's tasks parsed from the content by the adapter of the format.
+// Items that can't be parsed don't stop the import, they are returned together with the created tasks.
+// Dry run returns the tasks that would be created without creating them
+func (ts *TaskService) ImportTasks(ctx context.Context, in *v1.ImportTasksRequest) (*v1.ImportTasksResponse, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("format", in.Format.String()),
+		zap.Bool("dry_run", in.DryRun),
+	)
+	tasks, itemErrors, err := ts.importers.Parse(in.Format, strings.NewReader(in.Content))
+	if err != nil {
+		log.Error(err.Error())
+		field := "content"
+		if errors.Is(err, importer.ErrUnsupportedFormat) {
+			field = "format"
+		}
+		return &v1.ImportTasksResponse{}, apierror.Status(apierror.Field(field, err))
+	}
+	if len(tasks) > maxImportTasks {
+		log.Error(ErrTooManyTasks.Error(), zap.Int("task_count", len(tasks)))
+		return &v1.ImportTasksResponse{}, apierror.Status(ErrTooManyTasks)
+	}
+	resp := &v1.ImportTasksResponse{DryRun: in.DryRun}
+	for _, itemErr := range itemErrors {
+		resp.Errors = append(resp.Errors, &v1.ImportError{
+			Line:    int32(itemErr.Line),
+			Item:    itemErr.Item,
+			Message: itemErr.Err.Error(),
+		})
+	}
+	for _, task := range tasks {
+		task.UserID = userCtx.UserID
+		task.UserEmail = userCtx.Email
+		if in.DryRun {
+			resp.Tasks = append(resp.Tasks, repository.ToApi(task))
+			continue
+		}
+		created, err := ts.taskRepo.Create(ctx, task)
+		if err != nil {
+			log.Error(err.Error(), zap.Int("imported", len(resp.Tasks)))
+			return &v1.ImportTasksResponse{}, apierror.Status(err)
+		}
+		resp.Tasks = append(resp.Tasks, repository.ToApi(created))
+	}
+	log.Info("Imported tasks", zap.Int("task_count", len(resp.Tasks)), zap.Int("error_count", len(resp.Errors)))
+	return resp, nil
+}
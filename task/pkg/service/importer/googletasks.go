@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/jakubjano/todolist/task/pkg/service/textformat"
+	"io"
+	"strings"
+	"time"
+)
+
+// googleTasksExport is the Tasks.json file of Google Takeout
+type googleTasksExport struct {
+	Items []struct {
+		Title string           `json:"title"`
+		Items []googleTaskItem `json:"items"`
+	} `json:"items"`
+}
+
+type googleTaskItem struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Notes   string `json:"notes"`
+	Status  string `json:"status"`
+	Due     string `json:"due"`
+	Parent  string `json:"parent"`
+	Deleted bool   `json:"deleted"`
+}
+
+// ParseGoogleTasks reads the Google Takeout export of Google Tasks. Task lists become +project tags
+// and subtasks are added to the checklist of their parent, deleted tasks are left out
+func ParseGoogleTasks(r io.Reader) ([]repository.Task, []ItemError, error) {
+	export := googleTasksExport{}
+	err := json.NewDecoder(r).Decode(&export)
+	if err != nil {
+		return nil, nil, err
+	}
+	var tasks []repository.Task
+	var itemErrors []ItemError
+	for _, list := range export.Items {
+		// subtasks may come before their parents
+		subtasks := make(map[string]*checklist)
+		for _, item := range list.Items {
+			if item.Parent == "" || item.Deleted {
+				continue
+			}
+			if subtasks[item.Parent] == nil {
+				subtasks[item.Parent] = &checklist{}
+			}
+			subtasks[item.Parent].item(item.Title, item.Status == "completed")
+		}
+		for _, item := range list.Items {
+			if item.Parent != "" || item.Deleted {
+				continue
+			}
+			name := strings.TrimSpace(item.Title)
+			if name == "" {
+				itemErrors = append(itemErrors, ItemError{Item: fmt.Sprintf("task %s in %q", item.ID, list.Title),
+					Err: textformat.ErrMissingText})
+				continue
+			}
+			task := repository.Task{
+				Name:        withTags(name, tag("+", list.Title)),
+				Description: item.Notes,
+				Completed:   item.Status == "completed",
+			}
+			if item.Due != "" {
+				// Google Tasks keeps only the date of the due time, at midnight UTC
+				due, err := time.Parse(time.RFC3339, item.Due)
+				if err != nil {
+					itemErrors = append(itemErrors, ItemError{Item: fmt.Sprintf("task %q", name),
+						Err: fmt.Errorf("invalid due %q", item.Due)})
+					continue
+				}
+				task.Time = due.Unix()
+			}
+			if list, ok := subtasks[item.ID]; ok {
+				task.Description = list.appendTo(task.Description)
+			}
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, itemErrors, nil
+}
@@ -0,0 +1,140 @@
+// Package importer maps documents exported by task managers onto tasks. Every supported format
+// has an Adapter registered in a Registry, so new sources are added without touching the import RPC.
+//
+// Adapters follow the todo.txt conventions of the textformat package: lists and sections of the source
+// become +project tags and labels become @context tags in the task name, while checklists and subtasks
+// are appended to the description as a Markdown checklist
+package importer
+
+import (
+	"errors"
+	"fmt"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/jakubjano/todolist/task/pkg/service/textformat"
+	"io"
+	"strings"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported import format")
+
+// Adapter parses a document into tasks. Items that can't be mapped are reported and left out,
+// the error is returned only when the document as a whole can't be read
+type Adapter interface {
+	Parse(r io.Reader) ([]repository.Task, []ItemError, error)
+}
+
+// AdapterFunc allows to use a function as an Adapter
+type AdapterFunc func(r io.Reader) ([]repository.Task, []ItemError, error)
+
+func (f AdapterFunc) Parse(r io.Reader) ([]repository.Task, []ItemError, error) {
+	return f(r)
+}
+
+// ItemError reports a skipped item, by its line when the format has lines, or by Item otherwise
+type ItemError struct {
+	Line int
+	Item string
+	Err  error
+}
+
+func (e ItemError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Item, e.Err)
+}
+
+func (e ItemError) Unwrap() error {
+	return e.Err
+}
+
+type Registry struct {
+	adapters map[v1.TextFormat]Adapter
+}
+
+// NewRegistry returns a registry with the adapters of all formats supported out of the box
+func NewRegistry() *Registry {
+	r := &Registry{adapters: make(map[v1.TextFormat]Adapter)}
+	r.Register(v1.TextFormat_TODO_TXT, fromTextFormat(textformat.ParseTodoTxt))
+	r.Register(v1.TextFormat_MARKDOWN, fromTextFormat(textformat.ParseMarkdown))
+	r.Register(v1.TextFormat_TODOIST_CSV, AdapterFunc(ParseTodoist))
+	r.Register(v1.TextFormat_GOOGLE_TASKS_JSON, AdapterFunc(ParseGoogleTasks))
+	r.Register(v1.TextFormat_TRELLO_JSON, AdapterFunc(ParseTrello))
+	return r
+}
+
+// Register sets the adapter of the format, replacing the previous one
+func (r *Registry) Register(format v1.TextFormat, adapter Adapter) {
+	r.adapters[format] = adapter
+}
+
+func (r *Registry) Parse(format v1.TextFormat, in io.Reader) ([]repository.Task, []ItemError, error) {
+	adapter, ok := r.adapters[format]
+	if !ok {
+		return nil, nil, ErrUnsupportedFormat
+	}
+	return adapter.Parse(in)
+}
+
+func fromTextFormat(parse func(io.Reader) ([]repository.Task, []textformat.LineError, error)) Adapter {
+	return AdapterFunc(func(r io.Reader) ([]repository.Task, []ItemError, error) {
+		tasks, lineErrors, err := parse(r)
+		var itemErrors []ItemError
+		for _, lineErr := range lineErrors {
+			itemErrors = append(itemErrors, ItemError{Line: lineErr.Line, Err: lineErr.Err})
+		}
+		return tasks, itemErrors, err
+	})
+}
+
+// tag turns the name into a todo.txt tag with the prefix, e.g. "Home Office" into "+Home-Office"
+func tag(prefix, name string) string {
+	name = strings.Join(strings.Fields(name), "-")
+	if name == "" {
+		return ""
+	}
+	return prefix + name
+}
+
+// withTags appends the tags to the task name, skipping empty ones
+func withTags(name string, tags ...string) string {
+	for _, t := range tags {
+		if t != "" {
+			name += " " + t
+		}
+	}
+	return name
+}
+
+// checklist is a Markdown checklist appended to task description
+type checklist struct {
+	b strings.Builder
+}
+
+func (c *checklist) title(title string) {
+	if strings.TrimSpace(title) != "" {
+		c.b.WriteString(strings.TrimSpace(title) + ":\n")
+	}
+}
+
+func (c *checklist) item(name string, done bool) {
+	box := "- [ ] "
+	if done {
+		box = "- [x] "
+	}
+	c.b.WriteString(box + strings.Join(strings.Fields(name), " ") + "\n")
+}
+
+// appendTo adds the checklist after the description, separated by a blank line
+func (c *checklist) appendTo(description string) string {
+	list := strings.TrimSuffix(c.b.String(), "\n")
+	switch {
+	case list == "":
+		return description
+	case description == "":
+		return list
+	default:
+		return description + "\n\n" + list
+	}
+}
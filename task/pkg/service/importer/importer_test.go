@@ -0,0 +1,126 @@
+package importer
+
+import (
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/jakubjano/todolist/task/pkg/service/textformat"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"strings"
+	"testing"
+)
+
+type ImporterTestSuite struct {
+	suite.Suite
+}
+
+func (s *ImporterTestSuite) TestTodoist() {
+	doc := "TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE\n" +
+		"task,Plan sprint @work,,1,1,Jakub (1),,2022-07-19 10:00,en,Europe/Prague\n" +
+		"task,Book room,,4,2,Jakub (1),,,en,Europe/Prague\n" +
+		"note,Bring the laptop,,,,Jakub (1),,,,\n" +
+		",,,,,,,,,\n" +
+		"section,Home Office,,,,,,,,\n" +
+		"task,Water plants,,4,1,Jakub (1),,every monday,en,\n" +
+		"task,,,4,1,Jakub (1),,,en,\n" +
+		"task,Orphan,,4,x,Jakub (1),,,en,\n"
+	tasks, itemErrors, err := ParseTodoist(strings.NewReader(doc))
+	s.NoError(err)
+	s.Equal([]repository.Task{
+		{
+			Name:        "Plan sprint @work",
+			Description: "Bring the laptop\n\n- [ ] Book room",
+			Time:        1658217600,
+			Priority:    "A",
+		},
+		{
+			Name:        "Water plants +Home-Office",
+			Description: "Todoist date: every monday",
+		},
+	}, tasks)
+	s.Len(itemErrors, 2)
+	s.Equal(8, itemErrors[0].Line)
+	s.ErrorIs(itemErrors[0], textformat.ErrMissingText)
+	s.Equal(9, itemErrors[1].Line)
+}
+
+func (s *ImporterTestSuite) TestGoogleTasks() {
+	doc := `{"kind": "tasks#taskLists", "items": [{"kind": "tasks#taskList", "title": "My Tasks", "items": [
+		{"id": "s1", "title": "Milk", "status": "completed", "parent": "t1"},
+		{"id": "t1", "title": "Groceries", "notes": "Lidl", "status": "needsAction", "due": "2022-07-19T00:00:00.000Z"},
+		{"id": "t2", "title": "Gone", "status": "needsAction", "deleted": true},
+		{"id": "t3", "title": "Call mom", "status": "completed"},
+		{"id": "t4", "title": "Bad due", "status": "needsAction", "due": "tomorrow"}
+	]}]}`
+	tasks, itemErrors, err := ParseGoogleTasks(strings.NewReader(doc))
+	s.NoError(err)
+	s.Equal([]repository.Task{
+		{
+			Name:        "Groceries +My-Tasks",
+			Description: "Lidl\n\n- [x] Milk",
+			Time:        1658188800,
+		},
+		{
+			Name:      "Call mom +My-Tasks",
+			Completed: true,
+		},
+	}, tasks)
+	s.Len(itemErrors, 1)
+	s.Equal(`task "Bad due"`, itemErrors[0].Item)
+
+	_, _, err = ParseGoogleTasks(strings.NewReader("not json"))
+	s.Error(err)
+}
+
+func (s *ImporterTestSuite) TestTrello() {
+	doc := `{
+		"lists": [{"id": "l1", "name": "To Do"}, {"id": "l2", "name": "Old", "closed": true}],
+		"labels": [{"id": "b1", "name": "urgent"}, {"id": "b2", "name": "", "color": "green"}],
+		"cards": [
+			{"id": "c1", "name": "Release", "desc": "v1.2", "idList": "l1", "idLabels": ["b1", "b2"],
+				"due": "2022-07-19T10:00:00.000Z", "dueComplete": true},
+			{"id": "c2", "name": "Archived", "idList": "l1", "closed": true},
+			{"id": "c3", "name": "On closed list", "idList": "l2"},
+			{"id": "c4", "name": " ", "idList": "l1"}
+		],
+		"checklists": [
+			{"idCard": "c1", "name": "Steps", "pos": 1, "checkItems": [
+				{"name": "tag", "state": "incomplete", "pos": 2},
+				{"name": "build", "state": "complete", "pos": 1}
+			]}
+		]
+	}`
+	tasks, itemErrors, err := ParseTrello(strings.NewReader(doc))
+	s.NoError(err)
+	s.Equal([]repository.Task{
+		{
+			Name:        "Release +To-Do @urgent @green",
+			Description: "v1.2\n\nSteps:\n- [x] build\n- [ ] tag",
+			Time:        1658224800,
+			Completed:   true,
+		},
+	}, tasks)
+	s.Equal([]ItemError{{Item: "card c4", Err: textformat.ErrMissingText}}, itemErrors)
+}
+
+func (s *ImporterTestSuite) TestRegistry() {
+	registry := NewRegistry()
+	tasks, itemErrors, err := registry.Parse(v1.TextFormat_TODO_TXT, strings.NewReader("buy milk\n(A) due:2022-07-19\n"))
+	s.NoError(err)
+	s.Equal([]repository.Task{{Name: "buy milk"}}, tasks)
+	s.Equal([]ItemError{{Line: 2, Err: textformat.ErrMissingText}}, itemErrors)
+
+	_, _, err = registry.Parse(v1.TextFormat(99), strings.NewReader(""))
+	s.ErrorIs(err, ErrUnsupportedFormat)
+
+	registry.Register(v1.TextFormat(99), AdapterFunc(func(r io.Reader) ([]repository.Task, []ItemError, error) {
+		return []repository.Task{{Name: "custom"}}, nil, nil
+	}))
+	tasks, _, err = registry.Parse(v1.TextFormat(99), strings.NewReader(""))
+	s.NoError(err)
+	s.Equal([]repository.Task{{Name: "custom"}}, tasks)
+}
+
+func TestImporterTestSuite(t *testing.T) {
+	suite.Run(t, new(ImporterTestSuite))
+}
@@ -0,0 +1,170 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/jakubjano/todolist/task/pkg/service/textformat"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// todoistPriorities maps Todoist priorities, 1 being the highest, p4 means no priority
+var todoistPriorities = map[string]string{"1": "A", "2": "B", "3": "C"}
+
+// todoistDateLayouts are tried in order on the DATE column, which holds whatever the user typed
+var todoistDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"Jan 2 2006 15:04",
+	"Jan 2 2006",
+	"2 Jan 2006 15:04",
+	"2 Jan 2006",
+}
+
+// todoistTask is a top level task with the subtasks collected as its checklist
+type todoistTask struct {
+	task      repository.Task
+	subtasks  checklist
+	dateNotes []string
+}
+
+// ParseTodoist reads the CSV export of a Todoist project. Sections become +project tags,
+// labels are already @context tags in the task content, subtasks are added to the checklist
+// of their parent and notes to its description. Dates Todoist can't give as a calendar date,
+// e.g. "every monday", are kept in the description
+func ParseTodoist(r io.Reader) ([]repository.Task, []ItemError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"TYPE", "CONTENT"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+
+	var itemErrors []ItemError
+	var parsed []*todoistTask
+	var current *todoistTask
+	section := ""
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			itemErrors = append(itemErrors, ItemError{Line: parseErr.Line, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		content := field("CONTENT")
+		switch strings.ToLower(field("TYPE")) {
+		case "section":
+			section = content
+			current = nil
+		case "note":
+			if current == nil {
+				itemErrors = append(itemErrors, ItemError{Line: line, Err: errors.New("note without task")})
+				continue
+			}
+			current.task.Description = joinParagraphs(current.task.Description, content)
+		case "task":
+			if content == "" {
+				itemErrors = append(itemErrors, ItemError{Line: line, Err: textformat.ErrMissingText})
+				continue
+			}
+			indent := 1
+			if value := field("INDENT"); value != "" {
+				indent, err = strconv.Atoi(value)
+				if err != nil || indent < 1 {
+					itemErrors = append(itemErrors, ItemError{Line: line, Err: fmt.Errorf("invalid indent %q", value)})
+					continue
+				}
+			}
+			if indent > 1 {
+				if current == nil {
+					itemErrors = append(itemErrors, ItemError{Line: line, Err: errors.New("subtask without task")})
+					continue
+				}
+				current.subtasks.item(content, false)
+				continue
+			}
+			current = &todoistTask{task: repository.Task{
+				Name:        withTags(content, tag("+", section)),
+				Description: field("DESCRIPTION"),
+				Priority:    todoistPriorities[field("PRIORITY")],
+			}}
+			if date := field("DATE"); date != "" {
+				due, ok := parseTodoistDate(date, field("TIMEZONE"))
+				if ok {
+					current.task.Time = due.Unix()
+				} else {
+					current.dateNotes = append(current.dateNotes, "Todoist date: "+date)
+				}
+			}
+			parsed = append(parsed, current)
+		case "":
+			// blank separator rows
+		default:
+			itemErrors = append(itemErrors, ItemError{Line: line, Err: fmt.Errorf("unknown type %q", field("TYPE"))})
+		}
+	}
+	tasks := make([]repository.Task, len(parsed))
+	for i, p := range parsed {
+		for _, note := range p.dateNotes {
+			p.task.Description = joinParagraphs(p.task.Description, note)
+		}
+		p.task.Description = p.subtasks.appendTo(p.task.Description)
+		tasks[i] = p.task
+	}
+	return tasks, itemErrors, nil
+}
+
+// parseTodoistDate reads the date in the task's time zone, UTC is used when it is missing or unknown
+func parseTodoistDate(value, timezone string) (time.Time, bool) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "" {
+		loc = time.UTC
+	}
+	for _, layout := range todoistDateLayouts {
+		due, err := time.ParseInLocation(layout, value, loc)
+		if err == nil {
+			return due, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func joinParagraphs(text, paragraph string) string {
+	switch {
+	case paragraph == "":
+		return text
+	case text == "":
+		return paragraph
+	default:
+		return text + "\n\n" + paragraph
+	}
+}
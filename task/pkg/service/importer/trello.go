@@ -0,0 +1,122 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/jakubjano/todolist/task/pkg/service/textformat"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// trelloBoard is the JSON export of a Trello board, reduced to the fields tasks are made of
+type trelloBoard struct {
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Labels []struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+	Cards []struct {
+		ID          string   `json:"id"`
+		Name        string   `json:"name"`
+		Desc        string   `json:"desc"`
+		Closed      bool     `json:"closed"`
+		IDList      string   `json:"idList"`
+		IDLabels    []string `json:"idLabels"`
+		Due         string   `json:"due"`
+		DueComplete bool     `json:"dueComplete"`
+	} `json:"cards"`
+	Checklists []struct {
+		IDCard     string  `json:"idCard"`
+		Name       string  `json:"name"`
+		Pos        float64 `json:"pos"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+// ParseTrello reads the JSON export of a Trello board. Lists become +project tags, labels @context tags,
+// named by color when they have no name, and card checklists are added to the description.
+// Archived cards and cards on archived lists are left out
+func ParseTrello(r io.Reader) ([]repository.Task, []ItemError, error) {
+	board := trelloBoard{}
+	err := json.NewDecoder(r).Decode(&board)
+	if err != nil {
+		return nil, nil, err
+	}
+	lists := make(map[string]string, len(board.Lists))
+	closedLists := make(map[string]bool)
+	for _, list := range board.Lists {
+		lists[list.ID] = list.Name
+		closedLists[list.ID] = list.Closed
+	}
+	labels := make(map[string]string, len(board.Labels))
+	for _, label := range board.Labels {
+		labels[label.ID] = label.Name
+		if label.Name == "" {
+			labels[label.ID] = label.Color
+		}
+	}
+	sort.SliceStable(board.Checklists, func(i, j int) bool {
+		return board.Checklists[i].Pos < board.Checklists[j].Pos
+	})
+	checklists := make(map[string]*checklist)
+	for _, list := range board.Checklists {
+		if checklists[list.IDCard] == nil {
+			checklists[list.IDCard] = &checklist{}
+		}
+		sort.SliceStable(list.CheckItems, func(i, j int) bool {
+			return list.CheckItems[i].Pos < list.CheckItems[j].Pos
+		})
+		checklists[list.IDCard].title(list.Name)
+		for _, item := range list.CheckItems {
+			checklists[list.IDCard].item(item.Name, item.State == "complete")
+		}
+	}
+
+	var tasks []repository.Task
+	var itemErrors []ItemError
+	for _, card := range board.Cards {
+		if card.Closed || closedLists[card.IDList] {
+			continue
+		}
+		name := strings.TrimSpace(card.Name)
+		if name == "" {
+			itemErrors = append(itemErrors, ItemError{Item: "card " + card.ID, Err: textformat.ErrMissingText})
+			continue
+		}
+		tags := []string{tag("+", lists[card.IDList])}
+		for _, id := range card.IDLabels {
+			tags = append(tags, tag("@", labels[id]))
+		}
+		task := repository.Task{
+			Name:        withTags(name, tags...),
+			Description: card.Desc,
+			Completed:   card.DueComplete,
+		}
+		if card.Due != "" {
+			due, err := time.Parse(time.RFC3339, card.Due)
+			if err != nil {
+				itemErrors = append(itemErrors, ItemError{Item: fmt.Sprintf("card %q", name),
+					Err: fmt.Errorf("invalid due %q", card.Due)})
+				continue
+			}
+			task.Time = due.Unix()
+		}
+		if list, ok := checklists[card.ID]; ok {
+			task.Description = list.appendTo(task.Description)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, itemErrors, nil
+}
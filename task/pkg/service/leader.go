@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"expvar"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"os"
+	"sync"
+	"time"
+)
+
+// LeaseScheduler is the lease of the instance running the reminder and digest jobs
+const LeaseScheduler = "scheduler"
+
+// leaderMetrics are served on /debug/vars, keyed by "{lease}.{metric}": the holder and term of the lease
+// as last seen, whether this instance leads and how many times it became the leader
+var leaderMetrics = expvar.NewMap("leader_election")
+
+// LeaderSettings control the election, the lease expires TTL after it was last renewed every RenewEvery.
+// RenewEvery has to be well below TTL, so a slow renewal doesn't hand the lease over
+type LeaderSettings struct {
+	Lease      string
+	Holder     string
+	TTL        time.Duration
+	RenewEvery time.Duration
+}
+
+// Elector elects one leader among the instances with a lease in Firestore. The leader renews the lease,
+// the others try to take it over once it expires
+type Elector struct {
+	leaseRepo repository.FSLeaseInterface
+	logger    *zap.Logger
+	settings  LeaderSettings
+
+	mu sync.RWMutex
+	// leadingUntil is when the lease expires as seen by this instance, both are zero when another one leads
+	leadingUntil time.Time
+	term         int64
+}
+
+func NewElector(leaseRepo repository.FSLeaseInterface, logger *zap.Logger, settings LeaderSettings) *Elector {
+	return &Elector{
+		leaseRepo: leaseRepo,
+		logger:    logger.With(zap.String("lease", settings.Lease), zap.String("holder", settings.Holder)),
+		settings:  settings,
+	}
+}
+
+// NewHolderID names the instance in leases, the host name with a random suffix
+func NewHolderID() (string, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+	random := make([]byte, 4)
+	_, err = rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+	return host + "-" + hex.EncodeToString(random), nil
+}
+
+// Run renews or takes over the lease until the context is done, then releases it
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.settings.RenewEvery)
+	defer ticker.Stop()
+	for {
+		e.Renew(ctx)
+		select {
+		case <-ctx.Done():
+			e.Release(context.Background())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Renew tries to acquire the lease once. A failed renewal keeps the leadership until the lease
+// expires, the time is taken before the request, so the leader stops before the others can take over
+func (e *Elector) Renew(ctx context.Context) {
+	start := time.Now()
+	lease, acquired, err := e.leaseRepo.Acquire(ctx, e.settings.Lease, e.settings.Holder, e.settings.TTL)
+	if err != nil {
+		e.logger.Error(err.Error())
+		return
+	}
+	e.mu.Lock()
+	previousTerm := e.term
+	if acquired {
+		e.leadingUntil = start.Add(e.settings.TTL)
+		e.term = lease.Term
+	} else {
+		e.leadingUntil = time.Time{}
+		e.term = 0
+	}
+	e.mu.Unlock()
+
+	e.recordMetrics(lease, acquired)
+	switch {
+	case acquired && previousTerm != lease.Term:
+		leaderMetrics.Add(e.settings.Lease+".elections", 1)
+		e.logger.Info("became the leader", zap.Int64("term", lease.Term))
+	case !acquired && previousTerm != 0:
+		e.logger.Info("lost the leadership", zap.String("leader", lease.Holder), zap.Int64("term", lease.Term))
+	}
+}
+
+// Release hands the lease over right away when this instance leads
+func (e *Elector) Release(ctx context.Context) {
+	if !e.IsLeader() {
+		return
+	}
+	e.mu.Lock()
+	e.leadingUntil = time.Time{}
+	e.term = 0
+	e.mu.Unlock()
+	leaderMetrics.Set(e.settings.Lease+".is_leader", new(expvar.Int))
+	err := e.leaseRepo.Release(ctx, e.settings.Lease, e.settings.Holder)
+	if err != nil {
+		e.logger.Error(err.Error())
+		return
+	}
+	e.logger.Info("released the leadership")
+}
+
+// IsLeader tells whether this instance holds an unexpired lease
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return time.Now().Before(e.leadingUntil)
+}
+
+// Lead returns the job running only while this instance leads, for cron jobs every instance schedules
+func (e *Elector) Lead(job func()) func() {
+	return func() {
+		if e.IsLeader() {
+			job()
+		}
+	}
+}
+
+func (e *Elector) recordMetrics(lease repository.Lease, acquired bool) {
+	holder, term, isLeader := new(expvar.String), new(expvar.Int), new(expvar.Int)
+	holder.Set(lease.Holder)
+	term.Set(lease.Term)
+	if acquired {
+		isLeader.Set(1)
+	}
+	leaderMetrics.Set(e.settings.Lease+".holder", holder)
+	leaderMetrics.Set(e.settings.Lease+".term", term)
+	leaderMetrics.Set(e.settings.Lease+".is_leader", isLeader)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+type ElectorTestSuite struct {
+	suite.Suite
+	leaseRepo *repository.FSLeaseMock
+	elector   *Elector
+}
+
+func (s *ElectorTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.leaseRepo = repository.NewMockLeaseRepo()
+	s.elector = NewElector(s.leaseRepo, logger, LeaderSettings{
+		Lease:      "test",
+		Holder:     "a",
+		TTL:        time.Minute,
+		RenewEvery: 10 * time.Second,
+	})
+}
+
+func (s *ElectorTestSuite) TestRenew() {
+	ctx := context.Background()
+	runs := 0
+	job := s.elector.Lead(func() { runs++ })
+
+	s.leaseRepo.On("Acquire", mock.Anything, "test", "a", time.Minute).
+		Return(repository.Lease{Name: "test", Holder: "b", Term: 3}, false, nil).Once()
+	s.elector.Renew(ctx)
+	s.False(s.elector.IsLeader())
+	job()
+	s.Equal(0, runs)
+	s.Equal(`"b"`, leaderMetrics.Get("test.holder").String())
+	s.Equal("0", leaderMetrics.Get("test.is_leader").String())
+
+	s.leaseRepo.On("Acquire", mock.Anything, "test", "a", time.Minute).
+		Return(repository.Lease{Name: "test", Holder: "a", Term: 4}, true, nil).Once()
+	s.elector.Renew(ctx)
+	s.True(s.elector.IsLeader())
+	job()
+	s.Equal(1, runs)
+	s.Equal(`"a"`, leaderMetrics.Get("test.holder").String())
+	s.Equal("1", leaderMetrics.Get("test.is_leader").String())
+	s.Equal("4", leaderMetrics.Get("test.term").String())
+	elections := leaderMetrics.Get("test.elections").(*expvar.Int).Value()
+
+	// a failed renewal keeps the leadership until the lease expires
+	s.leaseRepo.On("Acquire", mock.Anything, "test", "a", time.Minute).
+		Return(repository.Lease{}, false, errors.New("unavailable")).Once()
+	s.elector.Renew(ctx)
+	s.True(s.elector.IsLeader())
+
+	// renewals don't count as elections
+	s.leaseRepo.On("Acquire", mock.Anything, "test", "a", time.Minute).
+		Return(repository.Lease{Name: "test", Holder: "a", Term: 4}, true, nil).Once()
+	s.elector.Renew(ctx)
+	s.Equal(elections, leaderMetrics.Get("test.elections").(*expvar.Int).Value())
+
+	s.leaseRepo.On("Release", mock.Anything, "test", "a").Return(nil).Once()
+	s.elector.Release(ctx)
+	s.False(s.elector.IsLeader())
+	s.leaseRepo.AssertExpectations(s.T())
+}
+
+func (s *ElectorTestSuite) TestLeadershipExpires() {
+	s.elector.settings.TTL = time.Millisecond
+	s.leaseRepo.On("Acquire", mock.Anything, "test", "a", time.Millisecond).
+		Return(repository.Lease{Name: "test", Holder: "a", Term: 1}, true, nil)
+	s.elector.Renew(context.Background())
+	time.Sleep(5 * time.Millisecond)
+	// not renewed in time, another instance may have taken over
+	s.False(s.elector.IsLeader())
+}
+
+func TestElectorTestSuite(t *testing.T) {
+	suite.Run(t, new(ElectorTestSuite))
+}
@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultLocale formats the times of users who haven't set their locale
+const DefaultLocale = "en-US"
+
+// timeLayouts are the date and time layouts of the supported locales, keyed by the lower case BCP 47 tag.
+// Layouts are numeric, so that they don't need translated month and weekday names
+var timeLayouts = map[string]string{
+	"en-us": "01/02/2006 3:04 PM",
+	"en-gb": "02/01/2006 15:04",
+	"en":    "01/02/2006 3:04 PM",
+	"cs":    "2. 1. 2006 15:04",
+	"sk":    "2. 1. 2006 15:04",
+	"de":    "02.01.2006 15:04",
+	"pl":    "02.01.2006 15:04",
+	"fr":    "02/01/2006 15:04",
+	"es":    "02/01/2006 15:04",
+	"it":    "02/01/2006 15:04",
+	"ja":    "2006/01/02 15:04",
+	"iso":   "2006-01-02 15:04",
+}
+
+// SupportedLocale tells whether times can be formatted in the locale, either the exact tag or its language
+func SupportedLocale(locale string) bool {
+	_, ok := timeLayout(locale)
+	return ok
+}
+
+func timeLayout(locale string) (string, bool) {
+	tag := strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	if layout, ok := timeLayouts[tag]; ok {
+		return layout, true
+	}
+	if dash := strings.Index(tag, "-"); dash > 0 {
+		layout, ok := timeLayouts[tag[:dash]]
+		return layout, ok
+	}
+	return "", false
+}
+
+// FormatTime formats the unix time in the locale and IANA time zone, unknown ones fall back
+// to DefaultLocale and UTC. The time zone abbreviation is appended
+func FormatTime(unix int64, locale, timeZone string) string {
+	layout, ok := timeLayout(locale)
+	if !ok {
+		layout, _ = timeLayout(DefaultLocale)
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil || timeZone == "" {
+		loc = time.UTC
+	}
+	return time.Unix(unix, 0).In(loc).Format(layout + " MST")
+}
@@ -0,0 +1,159 @@
+package mail
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+type MailTestSuite struct {
+	suite.Suite
+}
+
+func (s *MailTestSuite) TestFormatTime() {
+	candidates := []struct {
+		locale   string
+		timeZone string
+		expected string
+	}{
+		{locale: "en-US", timeZone: "America/New_York", expected: "07/17/2022 8:00 PM EDT"},
+		{locale: "cs-CZ", timeZone: "Europe/Prague", expected: "18. 7. 2022 02:00 CEST"},
+		{locale: "de_AT", timeZone: "Europe/Vienna", expected: "18.07.2022 02:00 CEST"},
+		{locale: "ja", timeZone: "Asia/Tokyo", expected: "2022/07/18 09:00 JST"},
+		// unknown locales and time zones fall back to en-US and UTC
+		{locale: "xx", timeZone: "Mars/Olympus", expected: "07/18/2022 12:00 AM UTC"},
+		{expected: "07/18/2022 12:00 AM UTC"},
+	}
+	for i, c := range candidates {
+		s.Equalf(c.expected, FormatTime(1658102400, c.locale, c.timeZone), "candidate %d", i+1)
+	}
+	s.True(SupportedLocale("en-GB"))
+	s.True(SupportedLocale("fr-CA"))
+	s.False(SupportedLocale("xx-YY"))
+}
+
+func (s *MailTestSuite) TestRender() {
+	templates, err := ParseTemplates()
+	s.Require().NoError(err)
+	subject, text, html, err := templates.Render(TemplateReminder, ReminderData{
+		TaskName:    "<b>call</b> mom",
+		Description: "about the weekend",
+		Due:         "18. 7. 2022 02:00 CEST",
+		Locale:      "cs",
+	})
+	s.NoError(err)
+	s.Equal("Reminder: <b>call</b> mom is due 18. 7. 2022 02:00 CEST", subject)
+	s.Contains(text, "<b>call</b> mom")
+	s.Contains(text, "about the weekend")
+	// the HTML part escapes the data
+	s.Contains(html, "&lt;b&gt;call&lt;/b&gt; mom")
+	s.NotContains(html, "<b>call</b>")
+
+	s.NotContains(text, "Mark it done")
+
+	_, text, html, err = templates.Render(TemplateReminder, ReminderData{
+		TaskName:    "call mom",
+		Due:         "07/18/2022 2:00 AM CEST",
+		CompleteURL: "http://localhost/task/actions/complete?user=1&task=tid1",
+		SnoozeURL:   "http://localhost/task/actions/snooze?user=1&task=tid1",
+	})
+	s.NoError(err)
+	s.Contains(text, "http://localhost/task/actions/complete?user=1&task=tid1")
+	s.Contains(text, "http://localhost/task/actions/snooze?user=1&task=tid1")
+	s.Contains(html, `href="http://localhost/task/actions/complete?user=1&amp;task=tid1"`)
+	s.Contains(html, `href="http://localhost/task/actions/snooze?user=1&amp;task=tid1"`)
+
+	subject, text, html, err = templates.Render(TemplateEscalationVerification, EscalationVerificationData{
+		Owner:      "example1@tst.com",
+		ConfirmURL: "http://localhost/task/actions/verify-escalation?user=1&email=boss%40tst.com",
+	})
+	s.NoError(err)
+	s.Equal("Confirm escalations from example1@tst.com", subject)
+	s.Contains(text, "http://localhost/task/actions/verify-escalation?user=1&email=boss%40tst.com")
+	s.Contains(html, `href="http://localhost/task/actions/verify-escalation?user=1&amp;email=boss%40tst.com"`)
+
+	_, _, _, err = templates.Render("missing", ReminderData{})
+	s.ErrorIs(err, ErrUnknownTemplate)
+}
+
+func (s *MailTestSuite) TestRenderDigest() {
+	templates, err := ParseTemplates()
+	s.Require().NoError(err)
+	subject, text, html, err := templates.Render(TemplateDigest, DigestData{
+		Frequency: "daily",
+		From:      "07/18/2022 12:00 AM UTC",
+		To:        "07/19/2022 12:00 AM UTC",
+		Due:       []DigestTask{{Name: "call mom", Due: "07/18/2022 5:00 PM UTC"}},
+		Overdue:   []DigestTask{{Name: "pay rent", Due: "07/15/2022 12:00 AM UTC"}},
+		Locale:    "en-US",
+	})
+	s.NoError(err)
+	s.Equal("Your daily digest: 1 due, 1 overdue", subject)
+	s.Contains(text, "  - call mom, due 07/18/2022 5:00 PM UTC")
+	s.Contains(text, "  - pay rent, due 07/15/2022 12:00 AM UTC")
+	s.Less(strings.Index(text, "Overdue"), strings.Index(text, "Due\n"))
+	s.Contains(html, "<strong>call mom</strong>")
+}
+
+func (s *MailTestSuite) TestMessageBytes() {
+	now := time.Date(2022, 7, 17, 12, 0, 0, 0, time.UTC)
+	messageID, err := NewMessageID("Todolist <todolist@tst.com>", now)
+	s.Require().NoError(err)
+	s.Regexp(`^<\d+\.[0-9a-f]{24}@tst\.com>$`, messageID)
+	s.Equal("<tid1_3600@tst.com>", MessageID("tid1_3600", "Todolist <todolist@tst.com>"))
+	s.Equal("<tid1_3600@localhost>", MessageID("tid1_3600", ""))
+
+	raw, err := Message{
+		From:      "todolist@tst.com",
+		To:        []string{"example1@tst.com", "example2@tst.com"},
+		Subject:   "Reminder: úkol",
+		Date:      now,
+		MessageID: messageID,
+		Text:      "plain",
+		HTML:      "<p>html</p>",
+	}.Bytes()
+	s.Require().NoError(err)
+
+	message, err := netmail.ReadMessage(bytes.NewReader(raw))
+	s.Require().NoError(err)
+	s.Equal("example1@tst.com, example2@tst.com", message.Header.Get("To"))
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	s.NoError(err)
+	s.Equal("Reminder: úkol", subject)
+	date, err := message.Header.Date()
+	s.NoError(err)
+	s.True(now.Equal(date))
+	s.Equal(messageID, message.Header.Get("Message-ID"))
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	s.Require().NoError(err)
+	s.Equal("multipart/alternative", mediaType)
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for _, expected := range []struct {
+		contentType string
+		content     string
+	}{
+		{contentType: "text/plain; charset=utf-8", content: "plain"},
+		{contentType: "text/html; charset=utf-8", content: "<p>html</p>"},
+	} {
+		part, err := reader.NextPart()
+		s.Require().NoError(err)
+		s.Equal(expected.contentType, part.Header.Get("Content-Type"))
+		// multipart decodes quoted-printable parts itself and drops the header
+		content, err := io.ReadAll(part)
+		s.NoError(err)
+		s.Equal(expected.content, string(content))
+	}
+	_, err = reader.NextPart()
+	s.ErrorIs(err, io.EOF)
+}
+
+func TestMailTestSuite(t *testing.T) {
+	suite.Run(t, new(MailTestSuite))
+}
@@ -0,0 +1,93 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Message is a multipart/alternative email with a plain text and an HTML part
+type Message struct {
+	From      string
+	To        []string
+	Subject   string
+	Date      time.Time
+	MessageID string
+	Text      string
+	HTML      string
+}
+
+// NewMessageID returns a unique Message-ID in the domain of the sender address
+func NewMessageID(from string, now time.Time) (string, error) {
+	random := make([]byte, 12)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+	return MessageID(fmt.Sprintf("%d.%s", now.UnixNano(), hex.EncodeToString(random)), from), nil
+}
+
+// MessageID returns the Message-ID of the id in the domain of the sender address. Emails sent again
+// with the same id keep their Message-ID, so that mail clients show them once
+func MessageID(id, from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 && at < len(from)-1 {
+		domain = strings.TrimSuffix(from[at+1:], ">")
+	}
+	return "<" + id + "@" + domain + ">"
+}
+
+// Bytes returns the message in the RFC 5322 format SMTP servers accept, lines end with CRLF
+func (m Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+	header := func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+	header("From", m.From)
+	header("To", strings.Join(m.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", m.Date.Format(time.RFC1123Z))
+	header("Message-ID", m.MessageID)
+	header("MIME-Version", "1.0")
+	header("Content-Type", "multipart/alternative; boundary="+body.Boundary())
+	buf.WriteString("\r\n")
+
+	// the last part is the preferred one
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{contentType: "text/plain; charset=utf-8", content: m.Text},
+		{contentType: "text/html; charset=utf-8", content: m.HTML},
+	} {
+		w, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		_, err = qp.Write([]byte(part.content))
+		if err != nil {
+			return nil, err
+		}
+		err = qp.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := body.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
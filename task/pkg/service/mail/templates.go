@@ -0,0 +1,134 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	// the time zones of users don't depend on the tzdata of the image
+	_ "time/tzdata"
+)
+
+const (
+	TemplateReminder   = "reminder"
+	TemplateOverdue    = "overdue"
+	TemplateEscalation = "escalation"
+	TemplateDigest     = "digest"
+	// TemplateEscalationVerification asks the escalation email to confirm it wants the escalations
+	TemplateEscalationVerification = "escalation_verification"
+
+	textSuffix = ".txt.tmpl"
+	htmlSuffix = ".html.tmpl"
+)
+
+var (
+	ErrUnknownTemplate = errors.New("unknown email template")
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Templates render the subject and both parts of emails from the {name}.txt.tmpl and {name}.html.tmpl files.
+// The subject is the "subject" template defined in the text file, every email is parsed on its own,
+// so that all of them can define it
+type Templates struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+// ParseTemplates parses the templates embedded in the binary
+func ParseTemplates() (*Templates, error) {
+	t := &Templates{
+		text: make(map[string]*texttemplate.Template),
+		html: make(map[string]*htmltemplate.Template),
+	}
+	textFiles, err := fs.Glob(templateFS, "templates/*"+textSuffix)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range textFiles {
+		name := strings.TrimSuffix(path.Base(file), textSuffix)
+		t.text[name], err = texttemplate.ParseFS(templateFS, file)
+		if err != nil {
+			return nil, err
+		}
+		t.html[name], err = htmltemplate.ParseFS(templateFS, "templates/"+name+htmlSuffix)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Render executes the templates of the email with the data, the HTML part escapes it
+func (t *Templates) Render(name string, data interface{}) (subject, text, html string, err error) {
+	textTmpl, htmlTmpl := t.text[name], t.html[name]
+	if textTmpl == nil || htmlTmpl == nil || textTmpl.Lookup("subject") == nil {
+		return "", "", "", fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	text, err = execute(textTmpl.Execute, data)
+	if err != nil {
+		return "", "", "", err
+	}
+	subject, err = execute(textTmpl.Lookup("subject").Execute, data)
+	if err != nil {
+		return "", "", "", err
+	}
+	html, err = execute(htmlTmpl.Execute, data)
+	if err != nil {
+		return "", "", "", err
+	}
+	// header values can't span lines
+	subject = strings.Join(strings.Fields(subject), " ")
+	return subject, text, html, nil
+}
+
+func execute(execute func(w io.Writer, data interface{}) error, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	err := execute(&buf, data)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ReminderData is the data of the reminder, overdue and escalation templates, Due is already formatted
+// for the user. The action links are left out when empty, Owner is the email of the user escalations are about
+type ReminderData struct {
+	TaskName    string
+	Description string
+	Due         string
+	Locale      string
+	CompleteURL string
+	SnoozeURL   string
+	Owner       string
+}
+
+// EscalationVerificationData is the data of the escalation verification template, Owner is the email
+// of the user who set the escalation email
+type EscalationVerificationData struct {
+	Owner      string
+	ConfirmURL string
+	Locale     string
+}
+
+// DigestData is the data of the digest template, the times are already formatted for the user
+type DigestData struct {
+	Frequency string
+	From      string
+	To        string
+	Due       []DigestTask
+	Overdue   []DigestTask
+	Locale    string
+}
+
+type DigestTask struct {
+	Name string
+	Due  string
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/internal/netguard"
+	"github.com/jakubjano/todolist/task/pkg/service/mail"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/emptypb"
+	netmail "net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var phoneNumber = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// Notifications fans the reminders out to the channels enabled by the owners of the tasks
+type Notifications struct {
+	notificationRepo repository.FSNotificationInterface
+	notifiers        map[string]notify.Notifier
+	logger           *zap.Logger
+}
+
+func NewNotifications(notificationRepo repository.FSNotificationInterface, logger *zap.Logger,
+	notifiers ...notify.Notifier) *Notifications {
+	n := &Notifications{
+		notificationRepo: notificationRepo,
+		notifiers:        make(map[string]notify.Notifier),
+		logger:           logger,
+	}
+	for _, notifier := range notifiers {
+		n.notifiers[notifier.Channel()] = notifier
+	}
+	return n
+}
+
+// Notify sends the notification through every enabled channel of the user but the delivered ones, email channels
+// without a target go to the email of the task owner. A failed channel doesn't stop the others. It returns
+// the channels that have the notification, the delivered ones included, and an error naming the failed channels,
+// so a retry sends the notification through them only
+func (n *Notifications) Notify(ctx context.Context, email string, notification notify.Notification,
+	delivered []string) ([]string, error) {
+	log := n.logger.With(
+		zap.String("user_id", notification.UserID),
+		zap.String("task_id", notification.TaskID),
+	)
+	prefs, err := n.notificationRepo.GetPreferences(ctx, notification.UserID)
+	if err != nil {
+		return delivered, err
+	}
+	// users who turned reminders off get none
+	event := v1.NotificationEventType_REMINDER_EVENT
+	if notification.Kind == notify.KindOverdue {
+		event = v1.NotificationEventType_OVERDUE_EVENT
+	}
+	if !prefs.Notifies(event) {
+		return delivered, nil
+	}
+	notification.Locale, notification.TimeZone = prefs.Locale, prefs.TimeZone
+	done := make(map[string]bool, len(delivered))
+	for _, name := range delivered {
+		done[name] = true
+	}
+	var failed []string
+	for name, channel := range prefs.Channels {
+		notifier, ok := n.notifiers[name]
+		if !channel.Enabled || !ok || done[name] {
+			continue
+		}
+		target := notify.Target{Address: channel.Target, Secret: channel.Secret}
+		if name == notify.ChannelEmail && target.Address == "" {
+			target.Address = email
+		}
+		err = notifier.Notify(ctx, target, notification)
+		if err != nil {
+			log.Error(err.Error(), zap.String("channel", name))
+			failed = append(failed, name)
+			continue
+		}
+		delivered = append(delivered, name)
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return delivered, fmt.Errorf("%w: %s", ErrNotificationFailed, strings.Join(failed, ", "))
+	}
+	return delivered, nil
+}
+
+// Escalate emails the escalation of the task to the address, in the locale and time zone of the task owner.
+// It ignores the channels and events of the owner, the escalation is for someone else
+func (n *Notifications) Escalate(ctx context.Context, email string, notification notify.Notification) error {
+	notifier, ok := n.notifiers[notify.ChannelEmail]
+	if !ok {
+		return ErrNotificationFailed
+	}
+	prefs, err := n.notificationRepo.GetPreferences(ctx, notification.UserID)
+	if err != nil {
+		return err
+	}
+	notification.Locale, notification.TimeZone = prefs.Locale, prefs.TimeZone
+	return notifier.Notify(ctx, notify.Target{Address: email}, notification)
+}
+
+// QuietUntil returns the end of the quiet hours of the user now falls in, ok is false outside of them
+func (n *Notifications) QuietUntil(ctx context.Context, userID string, now time.Time) (until time.Time, ok bool, err error) {
+	prefs, err := n.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	until, ok = prefs.QuietUntil(now)
+	return until, ok, nil
+}
+
+func validateNotificationPreferences(in *v1.NotificationPreferences) error {
+	if in.Locale != "" && !mail.SupportedLocale(in.Locale) {
+		return apierror.Field("locale", ErrUnsupportedLocale)
+	}
+	if in.TimeZone != "" {
+		_, err := time.LoadLocation(in.TimeZone)
+		// Local is the zone of the server, not an IANA name
+		if err != nil || in.TimeZone == "Local" {
+			return apierror.Field("time_zone", ErrInvalidTimeZone)
+		}
+	}
+	if quiet := in.GetQuietHours(); (quiet.GetStart() == "") != (quiet.GetEnd() == "") ||
+		quiet.GetStart() != "" && quiet.GetStart() == quiet.GetEnd() {
+		return apierror.Field("quiet_hours", ErrInvalidQuietHours)
+	}
+	if in.NotifyNone && len(in.Events) > 0 {
+		return apierror.Field("events", ErrConflictingNotificationEvents)
+	}
+	seen := make(map[v1.NotificationChannelType]bool)
+	for i, channel := range in.Channels {
+		field := fmt.Sprintf("channels[%d]", i)
+		if seen[channel.Type] {
+			return apierror.Field(field+".type", ErrDuplicateNotificationChannel)
+		}
+		seen[channel.Type] = true
+		if !validNotificationTarget(channel.Type, channel.Target, channel.Enabled) {
+			return apierror.Field(field+".target", ErrInvalidNotificationTarget)
+		}
+		if channel.Secret != "" && len(channel.Secret) < minWebhookSecretLength {
+			return apierror.Field(field+".secret", ErrWeakWebhookSecret)
+		}
+	}
+	return nil
+}
+
+// validNotificationTarget lets enabled channels other than email go without a target. URLs pointing
+// at the internal network are refused, the notifiers check the resolved addresses again
+func validNotificationTarget(channelType v1.NotificationChannelType, target string, enabled bool) bool {
+	if target == "" {
+		return channelType == v1.NotificationChannelType_EMAIL || !enabled
+	}
+	switch channelType {
+	case v1.NotificationChannelType_EMAIL:
+		_, err := netmail.ParseAddress(target)
+		return err == nil
+	case v1.NotificationChannelType_WEBHOOK, v1.NotificationChannelType_SLACK:
+		u, err := url.Parse(target)
+		return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+			netguard.CheckURL(target) == nil
+	case v1.NotificationChannelType_SMS:
+		return phoneNumber.MatchString(target)
+	default:
+		return false
+	}
+}
+
+func (ts *TaskService) GetNotificationPreferences(ctx context.Context, _ *emptypb.Empty) (*v1.NotificationPreferences, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	prefs, err := ts.notifications.notificationRepo.GetPreferences(ctx, userCtx.UserID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.NotificationPreferences{}, apierror.Status(err)
+	}
+	return prefs.ToApi(), nil
+}
+
+func (ts *TaskService) UpdateNotificationPreferences(ctx context.Context,
+	in *v1.NotificationPreferences) (*v1.NotificationPreferences, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	err := validateNotificationPreferences(in)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.NotificationPreferences{}, apierror.Status(err)
+	}
+	prev, err := ts.notifications.notificationRepo.GetPreferences(ctx, userCtx.UserID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.NotificationPreferences{}, apierror.Status(err)
+	}
+	prefs, err := ts.notifications.notificationRepo.SetPreferences(ctx,
+		repository.NotificationPreferencesFromMsg(userCtx.UserID, in, prev))
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.NotificationPreferences{}, apierror.Status(err)
+	}
+	log.Info("Updated notification preferences")
+	return prefs.ToApi(), nil
+}
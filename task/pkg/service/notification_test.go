@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/mail"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
+	"github.com/jakubjano/todolist/task/pkg/service/notify/notifytest"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type NotificationsTestSuite struct {
+	suite.Suite
+	notifications    *Notifications
+	ts               *TaskService
+	notificationRepo *repository.FSNotificationMock
+	sender           *notifytest.Sender
+	webhookServer    *notifytest.Server
+	slackServer      *notifytest.Server
+	ctx              context.Context
+}
+
+func (s *NotificationsTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.notificationRepo = repository.NewMockNotificationRepo()
+	s.sender = &notifytest.Sender{}
+	s.webhookServer = notifytest.NewServer()
+	s.slackServer = notifytest.NewServer()
+	templates, err := mail.ParseTemplates()
+	s.Require().NoError(err)
+	s.notifications = NewNotifications(s.notificationRepo, logger,
+		notify.NewEmail(s.sender, notify.EmailSettings{From: "todolist@tst.com", Templates: templates}),
+		// the test servers listen on loopback
+		notify.NewWebhookWithClient(&http.Client{Timeout: time.Second}),
+		notify.NewSlackWithClient(&http.Client{Timeout: time.Second}),
+	)
+	s.ts = NewTaskService(repository.NewMockRepo(), nil, nil, nil, s.notifications, nil, nil, logger)
+	s.ctx = context.WithValue(context.Background(), middleware.ContextUser, &middleware.UserContext{
+		UserID: "1",
+		Email:  "example1@tst.com",
+		Role:   middleware.ContextUser,
+	})
+}
+
+func (s *NotificationsTestSuite) TearDownTest() {
+	s.webhookServer.Close()
+	s.slackServer.Close()
+}
+
+func (s *NotificationsTestSuite) TestNotify() {
+	ctx := context.Background()
+	notification := notify.Notification{UserID: "1", TaskID: "tid1", TaskName: "task1", DueTime: 1658102400}
+	s.notificationRepo.On("GetPreferences", mock.Anything, "1").Return(repository.NotificationPreferences{
+		UserID:   "1",
+		Locale:   "cs",
+		TimeZone: "Europe/Prague",
+		Channels: map[string]repository.NotificationChannel{
+			notify.ChannelEmail:   {Enabled: true},
+			notify.ChannelWebhook: {Enabled: true, Target: s.webhookServer.URL},
+			notify.ChannelSlack:   {Enabled: false, Target: s.slackServer.URL},
+			// no notifier is registered for SMS
+			notify.ChannelSMS: {Enabled: true, Target: "+420123456789"},
+		},
+	}, nil)
+	delivered, err := s.notifications.Notify(ctx, "example1@tst.com", notification, nil)
+	s.NoError(err)
+	s.ElementsMatch([]string{notify.ChannelEmail, notify.ChannelWebhook}, delivered)
+	s.Len(s.sender.Messages(), 1)
+	s.Equal([]string{"example1@tst.com"}, s.sender.Messages()[0].To)
+	// the due time is in the locale and time zone of the user
+	s.Contains(string(s.sender.Messages()[0].Body), "18. 7. 2022 02:00 CEST")
+	s.Len(s.webhookServer.Requests(), 1)
+	s.Empty(s.slackServer.Requests())
+
+	// a failed channel doesn't stop the others, the retry goes to the failed one only
+	s.webhookServer.SetStatus(http.StatusInternalServerError)
+	delivered, err = s.notifications.Notify(ctx, "example1@tst.com", notification, nil)
+	s.ErrorIs(err, ErrNotificationFailed)
+	s.Contains(err.Error(), notify.ChannelWebhook)
+	s.Equal([]string{notify.ChannelEmail}, delivered)
+	s.Len(s.sender.Messages(), 2)
+	s.webhookServer.SetStatus(http.StatusOK)
+	delivered, err = s.notifications.Notify(ctx, "example1@tst.com", notification, delivered)
+	s.NoError(err)
+	s.ElementsMatch([]string{notify.ChannelEmail, notify.ChannelWebhook}, delivered)
+	s.Len(s.sender.Messages(), 2)
+	s.Len(s.webhookServer.Requests(), 3)
+
+	s.sender.Err = errors.New("smtp down")
+	_, err = s.notifications.Notify(ctx, "example1@tst.com", notification, []string{notify.ChannelWebhook})
+	s.ErrorIs(err, ErrNotificationFailed)
+	s.Len(s.webhookServer.Requests(), 3)
+}
+
+func (s *NotificationsTestSuite) TestNotifyRemindersOff() {
+	s.notificationRepo.On("GetPreferences", mock.Anything, "1").Return(repository.NotificationPreferences{
+		UserID:   "1",
+		Channels: map[string]repository.NotificationChannel{notify.ChannelEmail: {Enabled: true}},
+		Events:   []string{v1.NotificationEventType_DIGEST_EVENT.String()},
+	}, nil)
+	_, err := s.notifications.Notify(context.Background(), "example1@tst.com",
+		notify.Notification{UserID: "1", TaskID: "tid1", TaskName: "task1"}, nil)
+	s.NoError(err)
+	s.Empty(s.sender.Messages())
+}
+
+func (s *NotificationsTestSuite) TestUpdateNotificationPreferences() {
+	s.notificationRepo.On("GetPreferences", mock.Anything, "1").Return(repository.NotificationPreferences{
+		UserID: "1",
+		Channels: map[string]repository.NotificationChannel{
+			notify.ChannelWebhook: {Enabled: true, Target: "https://example.com/hook", Secret: "0123456789abcdef"},
+		},
+	}, nil)
+	var saved repository.NotificationPreferences
+	s.notificationRepo.On("SetPreferences", mock.Anything, mock.Anything).Return(repository.NotificationPreferences{}, nil).
+		Run(func(args mock.Arguments) {
+			saved = args.Get(1).(repository.NotificationPreferences)
+		})
+	candidates := []struct {
+		in           *v1.NotificationPreferences
+		expectedCode codes.Code
+	}{
+		{in: &v1.NotificationPreferences{
+			Channels: []*v1.NotificationChannel{
+				{Type: v1.NotificationChannelType_EMAIL, Enabled: true},
+				{Type: v1.NotificationChannelType_WEBHOOK, Enabled: true, Target: "https://example.com/hook2"},
+				{Type: v1.NotificationChannelType_SMS, Enabled: true, Target: "+420123456789"},
+			},
+			Locale:   "en-GB",
+			TimeZone: "Europe/London",
+		}},
+		{in: &v1.NotificationPreferences{Locale: "xx-YY"}, expectedCode: codes.InvalidArgument},
+		{in: &v1.NotificationPreferences{QuietHours: &v1.QuietHours{Start: "22:00"}}, expectedCode: codes.InvalidArgument},
+		{
+			in:           &v1.NotificationPreferences{QuietHours: &v1.QuietHours{Start: "22:00", End: "22:00"}},
+			expectedCode: codes.InvalidArgument,
+		},
+		{in: &v1.NotificationPreferences{TimeZone: "Mars/Olympus"}, expectedCode: codes.InvalidArgument},
+		{in: &v1.NotificationPreferences{TimeZone: "Local"}, expectedCode: codes.InvalidArgument},
+		{
+			in: &v1.NotificationPreferences{NotifyNone: true,
+				Events: []v1.NotificationEventType{v1.NotificationEventType_DIGEST_EVENT}},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			in: &v1.NotificationPreferences{Channels: []*v1.NotificationChannel{
+				{Type: v1.NotificationChannelType_SLACK, Enabled: true, Target: "ftp://example.com"},
+			}},
+			expectedCode: codes.InvalidArgument,
+		},
+		// targets in the internal network
+		{
+			in: &v1.NotificationPreferences{Channels: []*v1.NotificationChannel{
+				{Type: v1.NotificationChannelType_WEBHOOK, Enabled: true,
+					Target: "http://169.254.169.254/computeMetadata/v1/"},
+			}},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			in: &v1.NotificationPreferences{Channels: []*v1.NotificationChannel{
+				{Type: v1.NotificationChannelType_SLACK, Enabled: true, Target: "http://metadata.google.internal/"},
+			}},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			in: &v1.NotificationPreferences{Channels: []*v1.NotificationChannel{
+				{Type: v1.NotificationChannelType_SMS, Enabled: true},
+			}},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			in: &v1.NotificationPreferences{Channels: []*v1.NotificationChannel{
+				{Type: v1.NotificationChannelType_EMAIL, Enabled: true},
+				{Type: v1.NotificationChannelType_EMAIL, Enabled: false},
+			}},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+	for i, c := range candidates {
+		_, err := s.ts.UpdateNotificationPreferences(s.ctx, c.in)
+		s.Equalf(c.expectedCode, status.Code(err), "candidate %d", i+1)
+	}
+	s.notificationRepo.AssertNumberOfCalls(s.T(), "SetPreferences", 1)
+	// the secret left empty is kept
+	s.Equal(map[string]repository.NotificationChannel{
+		notify.ChannelEmail:   {Enabled: true},
+		notify.ChannelWebhook: {Enabled: true, Target: "https://example.com/hook2", Secret: "0123456789abcdef"},
+		notify.ChannelSMS:     {Enabled: true, Target: "+420123456789"},
+	}, saved.Channels)
+	s.Equal("en-GB", saved.Locale)
+	s.Equal("Europe/London", saved.TimeZone)
+}
+
+func (s *NotificationsTestSuite) TestGetNotificationPreferences() {
+	s.notificationRepo.On("GetPreferences", mock.Anything, "1").Return(repository.NotificationPreferences{
+		UserID: "1",
+		Channels: map[string]repository.NotificationChannel{
+			notify.ChannelSlack:   {Enabled: true, Target: "https://hooks.example.com/1"},
+			notify.ChannelWebhook: {Enabled: true, Target: "https://example.com/hook", Secret: "0123456789abcdef"},
+		},
+	}, nil)
+	prefs, err := s.ts.GetNotificationPreferences(s.ctx, nil)
+	s.Require().NoError(err)
+	s.Require().Len(prefs.Channels, 2)
+	s.Equal(v1.NotificationChannelType_WEBHOOK, prefs.Channels[0].Type)
+	s.True(prefs.Channels[0].HasSecret)
+	s.Empty(prefs.Channels[0].Secret)
+	s.Equal(v1.NotificationChannelType_SLACK, prefs.Channels[1].Type)
+}
+
+func TestNotificationsTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationsTestSuite))
+}
@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"context"
+	"github.com/jakubjano/todolist/task/pkg/service/mail"
+	"time"
+)
+
+// Sender sends a raw email message
+type Sender interface {
+	Send(to []string, message []byte) error
+}
+
+// EmailSettings set the From header and the templates of the emails
+type EmailSettings struct {
+	From      string
+	Templates *mail.Templates
+}
+
+// Email sends notifications as multipart emails rendered from the template of their kind, the target is the email address
+type Email struct {
+	sender   Sender
+	settings EmailSettings
+}
+
+func NewEmail(sender Sender, settings EmailSettings) *Email {
+	return &Email{
+		sender:   sender,
+		settings: settings,
+	}
+}
+
+func (e *Email) Channel() string {
+	return ChannelEmail
+}
+
+func (e *Email) Notify(_ context.Context, target Target, n Notification) error {
+	if target.Address == "" {
+		return ErrNoTarget
+	}
+	locale := n.Locale
+	if !mail.SupportedLocale(locale) {
+		locale = mail.DefaultLocale
+	}
+	messageID := ""
+	if n.ID != "" {
+		messageID = mail.MessageID(n.ID, e.settings.From)
+	}
+	template := mail.TemplateReminder
+	switch n.Kind {
+	case KindOverdue:
+		template = mail.TemplateOverdue
+	case KindEscalation:
+		template = mail.TemplateEscalation
+	}
+	return e.send(target.Address, template, messageID, mail.ReminderData{
+		TaskName:    n.TaskName,
+		Description: n.Description,
+		Due:         mail.FormatTime(n.DueTime, locale, n.TimeZone),
+		Locale:      locale,
+		CompleteURL: n.CompleteURL,
+		SnoozeURL:   n.SnoozeURL,
+		Owner:       n.Owner,
+	})
+}
+
+// Send renders the email template with the data and sends it to the address
+func (e *Email) Send(to, name string, data interface{}) error {
+	return e.send(to, name, "", data)
+}
+
+func (e *Email) send(to, name, messageID string, data interface{}) error {
+	message, err := e.Message(to, name, messageID, data, time.Now())
+	if err != nil {
+		return err
+	}
+	raw, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+	return e.sender.Send([]string{to}, raw)
+}
+
+// Message renders the email template with the data as sent at now, an empty messageID gets a new one
+func (e *Email) Message(to, name, messageID string, data interface{}, now time.Time) (mail.Message, error) {
+	subject, text, html, err := e.settings.Templates.Render(name, data)
+	if err != nil {
+		return mail.Message{}, err
+	}
+	if messageID == "" {
+		messageID, err = mail.NewMessageID(e.settings.From, now)
+		if err != nil {
+			return mail.Message{}, err
+		}
+	}
+	return mail.Message{
+		From:      e.settings.From,
+		To:        []string{to},
+		Subject:   subject,
+		Date:      now,
+		MessageID: messageID,
+		Text:      text,
+		HTML:      html,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Channels a notification can be sent through, also the keys of the channel preferences of users
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelSlack   = "slack"
+	ChannelSMS     = "sms"
+)
+
+// Kinds of notifications, reminders of tasks due soon have none
+const (
+	KindOverdue    = "overdue"
+	KindEscalation = "escalation"
+)
+
+var (
+	ErrNoTarget = errors.New("notification channel has no target")
+)
+
+// Notification is a reminder of a task. Offset is the number of seconds before the due time it was set for,
+// the locale and IANA time zone of the user format the due time. ID is the same for every attempt
+// to send the reminder, so receivers can drop duplicates. The signed action links complete and snooze
+// the task without logging in, they are empty when the links are off. Overdue reminders have a negative
+// Offset, escalations go to the escalation email of the user, Owner is the email of the user then
+type Notification struct {
+	ID          string
+	Kind        string
+	UserID      string
+	TaskID      string
+	TaskName    string
+	Description string
+	DueTime     int64
+	Offset      int64
+	Locale      string
+	TimeZone    string
+	CompleteURL string
+	SnoozeURL   string
+	Owner       string
+}
+
+// Text is the plain text of the notification, short enough for an SMS
+func (n Notification) Text() string {
+	switch n.Kind {
+	case KindOverdue:
+		return "Your task is overdue: " + n.TaskName
+	case KindEscalation:
+		return "A task of " + n.Owner + " is overdue: " + n.TaskName
+	default:
+		return "Your task is expiring soon: " + n.TaskName
+	}
+}
+
+// Target is where a channel delivers to: an email address, a URL or a phone number.
+// Secret signs the requests of the channels that support it
+type Target struct {
+	Address string
+	Secret  string
+}
+
+// Notifier sends notifications through a single channel
+type Notifier interface {
+	Channel() string
+	Notify(ctx context.Context, target Target, n Notification) error
+}
+
+// post sends the JSON body and returns an error unless the receiver responded with 2xx
+func post(ctx context.Context, client *http.Client, req *http.Request) error {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todolist-notifications")
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// drain the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns "sha256=" and the hex HMAC-SHA256 of "{timestamp}.{payload}" keyed by the secret,
+// the scheme of the task webhooks
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout}
+}
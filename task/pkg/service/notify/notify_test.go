@@ -0,0 +1,180 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/jakubjano/todolist/task/pkg/service/mail"
+	"github.com/jakubjano/todolist/task/pkg/service/notify/notifytest"
+	"github.com/stretchr/testify/suite"
+	"mime"
+	"net/http"
+	netmail "net/mail"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type NotifyTestSuite struct {
+	suite.Suite
+	server       *notifytest.Server
+	notification Notification
+}
+
+func (s *NotifyTestSuite) SetupTest() {
+	s.server = notifytest.NewServer()
+	s.notification = Notification{
+		UserID:   "1",
+		TaskID:   "tid1",
+		TaskName: "task1",
+		DueTime:  1658102400,
+		Offset:   3600,
+	}
+}
+
+func (s *NotifyTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *NotifyTestSuite) TestEmail() {
+	sender := &notifytest.Sender{}
+	templates, err := mail.ParseTemplates()
+	s.Require().NoError(err)
+	email := NewEmail(sender, EmailSettings{From: "todolist@tst.com", Templates: templates})
+	s.Equal(ChannelEmail, email.Channel())
+	err = email.Notify(context.Background(), Target{Address: "example1@tst.com"}, s.notification)
+	s.NoError(err)
+	s.Require().Len(sender.Messages(), 1)
+	s.Equal([]string{"example1@tst.com"}, sender.Messages()[0].To)
+
+	message, err := netmail.ReadMessage(bytes.NewReader(sender.Messages()[0].Body))
+	s.Require().NoError(err)
+	s.Equal("todolist@tst.com", message.Header.Get("From"))
+	s.Equal("example1@tst.com", message.Header.Get("To"))
+	s.Equal("Reminder: task1 is due 07/18/2022 12:00 AM UTC", message.Header.Get("Subject"))
+	s.Regexp(`^<\d+\.[0-9a-f]+@tst\.com>$`, message.Header.Get("Message-ID"))
+	_, err = message.Header.Date()
+	s.NoError(err)
+	mediaType, _, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	s.NoError(err)
+	s.Equal("multipart/alternative", mediaType)
+	s.ErrorIs(email.Notify(context.Background(), Target{}, s.notification), ErrNoTarget)
+}
+
+func (s *NotifyTestSuite) TestEmailKinds() {
+	sender := &notifytest.Sender{}
+	templates, err := mail.ParseTemplates()
+	s.Require().NoError(err)
+	email := NewEmail(sender, EmailSettings{From: "todolist@tst.com", Templates: templates})
+	candidates := []struct {
+		kind            string
+		expectedSubject string
+	}{
+		{kind: "", expectedSubject: "Reminder: task1 is due 07/18/2022 12:00 AM UTC"},
+		{kind: KindOverdue, expectedSubject: "Overdue: task1 was due 07/18/2022 12:00 AM UTC"},
+		{kind: KindEscalation, expectedSubject: "Escalation: task1 of example1@tst.com is overdue"},
+	}
+	for i, c := range candidates {
+		notification := s.notification
+		notification.Kind = c.kind
+		notification.Owner = "example1@tst.com"
+		err = email.Notify(context.Background(), Target{Address: "boss@tst.com"}, notification)
+		s.NoErrorf(err, "candidate %d", i+1)
+		message, err := netmail.ReadMessage(bytes.NewReader(sender.Messages()[i].Body))
+		s.Require().NoError(err)
+		s.Equalf(c.expectedSubject, message.Header.Get("Subject"), "candidate %d", i+1)
+	}
+}
+
+func (s *NotifyTestSuite) TestText() {
+	candidates := []struct {
+		kind         string
+		expectedText string
+	}{
+		{kind: "", expectedText: "Your task is expiring soon: task1"},
+		{kind: KindOverdue, expectedText: "Your task is overdue: task1"},
+		{kind: KindEscalation, expectedText: "A task of example1@tst.com is overdue: task1"},
+	}
+	for i, c := range candidates {
+		notification := s.notification
+		notification.Kind = c.kind
+		notification.Owner = "example1@tst.com"
+		s.Equalf(c.expectedText, notification.Text(), "candidate %d", i+1)
+	}
+}
+
+func (s *NotifyTestSuite) TestWebhook() {
+	webhook := NewWebhookWithClient(&http.Client{Timeout: time.Second})
+	err := webhook.Notify(context.Background(), Target{Address: s.server.URL, Secret: "0123456789abcdef"}, s.notification)
+	s.Require().NoError(err)
+	requests := s.server.Requests()
+	s.Require().Len(requests, 1)
+	payload := WebhookPayload{}
+	s.NoError(json.Unmarshal(requests[0].Body, &payload))
+	s.Equal(WebhookPayload{
+		Type:     EventReminder,
+		UserID:   "1",
+		TaskID:   "tid1",
+		TaskName: "task1",
+		DueTime:  1658102400,
+		Offset:   3600,
+		Text:     "Your task is expiring soon: task1",
+	}, payload)
+	timestamp, err := strconv.ParseInt(requests[0].Header.Get(HeaderTimestamp), 10, 64)
+	s.NoError(err)
+	s.Equal(Sign("0123456789abcdef", timestamp, requests[0].Body), requests[0].Header.Get(HeaderSignature))
+
+	// targets without a secret get unsigned requests
+	err = webhook.Notify(context.Background(), Target{Address: s.server.URL}, s.notification)
+	s.NoError(err)
+	s.Empty(s.server.Requests()[1].Header.Get(HeaderSignature))
+	s.Empty(s.server.Requests()[1].Header.Get(HeaderDelivery))
+
+	// queued reminders carry their ID
+	s.notification.ID = "tid1_1658102400_3600"
+	err = webhook.Notify(context.Background(), Target{Address: s.server.URL}, s.notification)
+	s.NoError(err)
+	s.Equal("tid1_1658102400_3600", s.server.Requests()[2].Header.Get(HeaderDelivery))
+
+	// overdue reminders are events of their own
+	s.notification.Kind = KindOverdue
+	err = webhook.Notify(context.Background(), Target{Address: s.server.URL}, s.notification)
+	s.NoError(err)
+	s.Equal(EventOverdue, s.server.Requests()[3].Header.Get(HeaderEvent))
+	s.Contains(string(s.server.Requests()[3].Body), `"type":"TASK_OVERDUE"`)
+
+	s.server.SetStatus(http.StatusGone)
+	s.Error(webhook.Notify(context.Background(), Target{Address: s.server.URL}, s.notification))
+}
+
+func (s *NotifyTestSuite) TestSlack() {
+	slack := NewSlackWithClient(&http.Client{Timeout: time.Second})
+	err := slack.Notify(context.Background(), Target{Address: s.server.URL}, s.notification)
+	s.Require().NoError(err)
+	s.JSONEq(`{"text":"Your task is expiring soon: task1"}`, string(s.server.Requests()[0].Body))
+}
+
+func (s *NotifyTestSuite) TestInternalTarget() {
+	// the test server listens on loopback, which the notifiers for user targets don't connect to
+	err := NewWebhook(time.Second).Notify(context.Background(), Target{Address: s.server.URL}, s.notification)
+	s.Error(err)
+	err = NewSlack(time.Second).Notify(context.Background(), Target{Address: s.server.URL}, s.notification)
+	s.Error(err)
+	s.Empty(s.server.Requests())
+}
+
+func (s *NotifyTestSuite) TestSMS() {
+	sms := NewSMS(SMSSettings{URL: s.server.URL, APIKey: "key", From: "todolist", Timeout: time.Second})
+	err := sms.Notify(context.Background(), Target{Address: "+420123456789"}, s.notification)
+	s.Require().NoError(err)
+	request := s.server.Requests()[0]
+	s.Equal("Bearer key", request.Header.Get("Authorization"))
+	s.JSONEq(`{"from":"todolist","to":"+420123456789","text":"Your task is expiring soon: task1"}`, string(request.Body))
+
+	s.server.SetStatus(http.StatusInternalServerError)
+	s.Error(sms.Notify(context.Background(), Target{Address: "+420123456789"}, s.notification))
+}
+
+func TestNotifyTestSuite(t *testing.T) {
+	suite.Run(t, new(NotifyTestSuite))
+}
@@ -0,0 +1,80 @@
+// Package notifytest provides local fakes of the services notifications are sent to
+package notifytest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Request is a request received by the fake server
+type Request struct {
+	Header http.Header
+	Body   []byte
+}
+
+// Server is a local HTTP server standing in for webhook receivers, Slack and SMS gateways.
+// It records the requests and responds with the status set by SetStatus, 200 by default
+type Server struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []Request
+	status   int
+}
+
+func NewServer() *Server {
+	s := &Server{status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, Request{Header: r.Header.Clone(), Body: body})
+	w.WriteHeader(s.status)
+}
+
+func (s *Server) SetStatus(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// Sender is a fake email sender recording the messages, Err fails the sends
+type Sender struct {
+	mu       sync.Mutex
+	Err      error
+	messages []Message
+}
+
+type Message struct {
+	To   []string
+	Body []byte
+}
+
+func (s *Sender) Send(to []string, message []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return s.Err
+	}
+	s.messages = append(s.messages, Message{To: to, Body: message})
+	return nil
+}
+
+// Messages returns the messages sent so far
+func (s *Sender) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message{}, s.messages...)
+}
@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/jakubjano/todolist/task/internal/netguard"
+	"net/http"
+	"time"
+)
+
+// SlackMessage is the body of Slack incoming webhooks, understood by Mattermost and Rocket.Chat as well
+type SlackMessage struct {
+	Text string `json:"text"`
+}
+
+// Slack posts notifications to the incoming webhook URL of the target
+type Slack struct {
+	client *http.Client
+}
+
+// NewSlack returns the notifier connecting only to public addresses, the targets are given by users
+func NewSlack(timeout time.Duration) *Slack {
+	return NewSlackWithClient(netguard.NewClient(timeout))
+}
+
+func NewSlackWithClient(client *http.Client) *Slack {
+	return &Slack{
+		client: client,
+	}
+}
+
+func (s *Slack) Channel() string {
+	return ChannelSlack
+}
+
+func (s *Slack) Notify(ctx context.Context, target Target, n Notification) error {
+	if target.Address == "" {
+		return ErrNoTarget
+	}
+	payload, err := json.Marshal(SlackMessage{Text: n.Text()})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, target.Address, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.client, req)
+}
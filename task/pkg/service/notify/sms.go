@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// SMSMessage is the body posted to the SMS gateway
+type SMSMessage struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+// SMSSettings point at an HTTP SMS gateway, the API key is sent as a bearer token
+type SMSSettings struct {
+	URL     string
+	APIKey  string
+	From    string
+	Timeout time.Duration
+}
+
+// SMS sends notifications through the SMS gateway, the target is the phone number in E.164
+type SMS struct {
+	client   *http.Client
+	settings SMSSettings
+}
+
+func NewSMS(settings SMSSettings) *SMS {
+	return &SMS{
+		client:   newHTTPClient(settings.Timeout),
+		settings: settings,
+	}
+}
+
+func (s *SMS) Channel() string {
+	return ChannelSMS
+}
+
+func (s *SMS) Notify(ctx context.Context, target Target, n Notification) error {
+	if target.Address == "" {
+		return ErrNoTarget
+	}
+	payload, err := json.Marshal(SMSMessage{
+		From: s.settings.From,
+		To:   target.Address,
+		Text: n.Text(),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.settings.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.settings.APIKey)
+	return post(ctx, s.client, req)
+}
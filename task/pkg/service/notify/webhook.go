@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/jakubjano/todolist/task/internal/netguard"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// EventReminder is the X-Todolist-Event of reminders sent to generic webhooks, EventOverdue of overdue reminders
+	EventReminder = "TASK_REMINDER"
+	EventOverdue  = "TASK_OVERDUE"
+
+	HeaderSignature = "X-Todolist-Signature"
+	HeaderTimestamp = "X-Todolist-Timestamp"
+	HeaderEvent     = "X-Todolist-Event"
+	HeaderDelivery  = "X-Todolist-Delivery"
+)
+
+// WebhookPayload is the JSON body of reminders sent to generic webhooks
+type WebhookPayload struct {
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type"`
+	UserID   string `json:"user_id"`
+	TaskID   string `json:"task_id"`
+	TaskName string `json:"task_name"`
+	DueTime  int64  `json:"due_time"`
+	Offset   int64  `json:"offset"`
+	Text     string `json:"text"`
+}
+
+// Webhook posts notifications as JSON to the target URL, signed like the task webhooks when the target has a secret
+type Webhook struct {
+	client *http.Client
+}
+
+// NewWebhook returns the notifier connecting only to public addresses, the targets are given by users
+func NewWebhook(timeout time.Duration) *Webhook {
+	return NewWebhookWithClient(netguard.NewClient(timeout))
+}
+
+func NewWebhookWithClient(client *http.Client) *Webhook {
+	return &Webhook{
+		client: client,
+	}
+}
+
+func (w *Webhook) Channel() string {
+	return ChannelWebhook
+}
+
+func (w *Webhook) Notify(ctx context.Context, target Target, n Notification) error {
+	if target.Address == "" {
+		return ErrNoTarget
+	}
+	event := EventReminder
+	if n.Kind == KindOverdue {
+		event = EventOverdue
+	}
+	payload, err := json.Marshal(WebhookPayload{
+		ID:       n.ID,
+		Type:     event,
+		UserID:   n.UserID,
+		TaskID:   n.TaskID,
+		TaskName: n.TaskName,
+		DueTime:  n.DueTime,
+		Offset:   n.Offset,
+		Text:     n.Text(),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, target.Address, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(HeaderEvent, event)
+	if n.ID != "" {
+		req.Header.Set(HeaderDelivery, n.ID)
+	}
+	if target.Secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(HeaderSignature, Sign(target.Secret, timestamp, payload))
+	}
+	return post(ctx, w.client, req)
+}
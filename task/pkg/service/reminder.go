@@ -1,24 +1,41 @@
 package service
 
 import (
-	"cloud.google.com/go/firestore"
 	"context"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/events"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
 	"github.com/jakubjano/todolist/task/pkg/service/repository"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/emptypb"
 	"net/smtp"
 	"strconv"
+	"time"
 )
 
 const (
-	batchSize = 500
-	smtpPort  = 25
+	smtpPort = 25
 )
 
+// ReminderQueueSettings control the sending of queued reminders, a reminder is sent at most Attempts times
+// with the delay doubling from Backoff, every attempt locks it for Lease. BatchSize reminders are sent per run
+type ReminderQueueSettings struct {
+	Attempts  int
+	Backoff   time.Duration
+	Lease     time.Duration
+	BatchSize int
+}
+
 type Reminder struct {
-	taskRepo    repository.FSTaskInterface
-	logger      *zap.Logger
-	emailSender EmailSender
-	fs          *firestore.Client
+	taskRepo      repository.FSTaskInterface
+	reminderQueue repository.FSReminderQueueInterface
+	logger        *zap.Logger
+	notifications *Notifications
+	// actions sign the complete and snooze links of the reminders, there are none when nil
+	actions  *ActionLinks
+	settings ReminderQueueSettings
 }
 
 type EmailSender interface {
@@ -46,64 +63,204 @@ func NewEmailSender(emailSetting *Settings) EmailSender {
 	return &emailSender{emailSetting}
 }
 
-func NewReminder(taskRepo repository.FSTaskInterface, logger *zap.Logger, emailSender EmailSender,
-	fs *firestore.Client) *Reminder {
+func NewReminder(taskRepo repository.FSTaskInterface, reminderQueue repository.FSReminderQueueInterface,
+	logger *zap.Logger, notifications *Notifications, actions *ActionLinks, settings ReminderQueueSettings) *Reminder {
 	return &Reminder{
-		taskRepo:    taskRepo,
-		logger:      logger,
-		emailSender: emailSender,
-		fs:          fs,
+		taskRepo:      taskRepo,
+		reminderQueue: reminderQueue,
+		logger:        logger,
+		notifications: notifications,
+		actions:       actions,
+		settings:      settings,
 	}
 }
 
-// RemindUserViaEmail checks if there are any reminders to send out to users
-// then iterates through each task and sends it via smtp to the corresponding email address with a prebuilt message
-// after the reminders are sent, RemindUserViaEmail flags the tasks with boolean and updates the database
-func (r *Reminder) RemindUserViaEmail(ctx context.Context) error {
-	reminders, err := r.taskRepo.SearchForExpiringTasks(ctx)
+// EnqueueReminders marks the reminders due at now as sent on their tasks and queues the ones to send
+func (r *Reminder) EnqueueReminders(ctx context.Context, now int64) error {
+	reminders, err := r.taskRepo.SearchForExpiringTasks(ctx, now)
 	if err != nil {
 		r.logger.Error(err.Error())
 		return err
 	}
-	if len(reminders) < 1 {
-		r.logger.Info("No expiring tasks")
-		return nil
-	}
-	batch := r.fs.Batch()
-	taskCount := 0
-	mapLength := len(reminders)
-	emailCount := 0
 	for email, tasks := range reminders {
-		emailCount += 1
-		for i, task := range tasks {
+		for _, task := range tasks {
 			log := r.logger.With(
 				zap.String("email", email),
 				zap.String("task", task.Name),
 			)
-			message := []byte("Your task is expiring soon: " + task.Name)
-			err = r.emailSender.Send([]string{email}, message)
+			delivery, queued, err := r.reminderQueue.FireReminders(ctx, task.TaskID, now)
 			if err != nil {
 				log.Error(err.Error())
-				return err
+				continue
 			}
-			log.Info("reminder sent")
-			//update task_list duplicate collection
-			batch.Set(r.fs.Collection(repository.TaskList).Doc(task.TaskID), map[string]interface{}{
-				"reminderSent": true,
-			}, firestore.MergeAll)
-			// increment task count after sending reminder and adding Set operation into batch
-			// commit when taskCount reaches the max limit of operations in batch write or when the iteration
-			//		through the entire map is done (last iteration over tasks in the last email key)
-			taskCount += 1
-			if taskCount == batchSize || (emailCount == mapLength && i == len(tasks)-1) {
-				_, err = batch.Commit(ctx)
-				if err != nil {
-					log.Error(err.Error())
-					return err
-				}
-				taskCount = 0
+			if queued {
+				log.Info("reminder queued", zap.String("delivery_id", delivery.DeliveryID))
 			}
 		}
 	}
 	return nil
 }
+
+// EnqueueOverdue applies the escalation policies of their owners to the tasks due for them at now
+// and queues the overdue reminders and escalations to send
+func (r *Reminder) EnqueueOverdue(ctx context.Context, now int64) error {
+	tasks, err := r.taskRepo.SearchForOverdueTasks(ctx, now)
+	if err != nil {
+		r.logger.Error(err.Error())
+		return err
+	}
+	for _, task := range tasks {
+		log := r.logger.With(
+			zap.String("user_id", task.UserID),
+			zap.String("task_id", task.TaskID),
+		)
+		deliveries, err := r.reminderQueue.FireOverdue(ctx, task.TaskID, now)
+		if err != nil {
+			log.Error(err.Error())
+			continue
+		}
+		for _, delivery := range deliveries {
+			log.Info("overdue reminder queued", zap.String("delivery_id", delivery.DeliveryID),
+				zap.String("kind", delivery.Kind))
+		}
+	}
+	return nil
+}
+
+// SendReminders sends the queued reminders due at now, the ones due in the quiet hours of their users
+// are deferred to the end of the quiet hours. Escalations aren't, the quiet hours are of the task owner,
+// not of the escalation address. Every reminder is claimed before it's sent
+// and marked as sent after, failed ones are retried with backoff until they run out of attempts
+// and go to the dead letters. A reminder that couldn't be marked as sent is sent again after its lease
+// with the same ID, which receivers can drop
+func (r *Reminder) SendReminders(ctx context.Context, now int64) error {
+	due, err := r.reminderQueue.Due(ctx, now, r.settings.BatchSize)
+	if err != nil {
+		r.logger.Error(err.Error())
+		return err
+	}
+	for _, pending := range due {
+		log := r.logger.With(
+			zap.String("delivery_id", pending.DeliveryID),
+			zap.String("user_id", pending.UserID),
+			zap.String("task_id", pending.TaskID),
+		)
+		var until time.Time
+		quiet := false
+		if pending.Kind != notify.KindEscalation {
+			until, quiet, err = r.notifications.QuietUntil(ctx, pending.UserID, time.Unix(now, 0))
+			if err != nil {
+				log.Error(err.Error())
+				continue
+			}
+		}
+		if quiet {
+			err = r.reminderQueue.Defer(ctx, pending.DeliveryID, until.Unix())
+			if err != nil {
+				log.Error(err.Error())
+				continue
+			}
+			log.Info("reminder deferred by quiet hours", zap.Time("until", until))
+			continue
+		}
+		delivery, claimed, err := r.reminderQueue.Claim(ctx, pending.DeliveryID, r.settings.Lease)
+		if err != nil {
+			log.Error(err.Error())
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		err = r.send(ctx, delivery)
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}
+	return nil
+}
+
+func (r *Reminder) send(ctx context.Context, delivery repository.ReminderDelivery) error {
+	log := r.logger.With(
+		zap.String("delivery_id", delivery.DeliveryID),
+		zap.Int32("attempt", delivery.Attempts),
+	)
+	notification := notify.Notification{
+		ID:          delivery.DeliveryID,
+		Kind:        delivery.Kind,
+		UserID:      delivery.UserID,
+		TaskID:      delivery.TaskID,
+		TaskName:    delivery.TaskName,
+		Description: delivery.Description,
+		DueTime:     delivery.DueTime,
+		Offset:      delivery.Offset,
+		Owner:       delivery.OwnerEmail,
+	}
+	// escalations aren't for the task owner to act on
+	if r.actions != nil && delivery.Kind != notify.KindEscalation {
+		now := time.Now()
+		notification.CompleteURL = r.actions.Link(ActionComplete, delivery.UserID, delivery.TaskID, now)
+		notification.SnoozeURL = r.actions.Link(ActionSnooze, delivery.UserID, delivery.TaskID, now)
+	}
+	var err error
+	if delivery.Kind == notify.KindEscalation {
+		err = r.notifications.Escalate(ctx, delivery.Email, notification)
+	} else {
+		// channels that got the reminder at an earlier attempt don't get it again
+		delivery.Delivered, err = r.notifications.Notify(ctx, delivery.Email, notification, delivery.Delivered)
+	}
+	if err != nil {
+		if int(delivery.Attempts) >= r.settings.Attempts {
+			log.Info("reminder dead-lettered", zap.Error(err))
+			return r.reminderQueue.DeadLetter(ctx, delivery, err.Error())
+		}
+		log.Info("reminder failed", zap.Error(err))
+		retryAt := time.Now().Add(r.settings.Backoff << (delivery.Attempts - 1)).Unix()
+		return r.reminderQueue.Retry(ctx, delivery.DeliveryID, retryAt, err.Error(), delivery.Delivered)
+	}
+	event, err := events.New(events.ReminderSent, delivery.UserID, delivery.TaskID, events.ReminderData{
+		TaskID: delivery.TaskID,
+		Email:  delivery.Email,
+		Offset: delivery.Offset,
+	})
+	if err != nil {
+		return err
+	}
+	err = r.reminderQueue.MarkSent(ctx, delivery, event)
+	if err != nil {
+		return err
+	}
+	log.Info("reminder sent", zap.Int64("offset", delivery.Offset))
+	return nil
+}
+
+func (ts *TaskService) GetReminderSettings(ctx context.Context, _ *emptypb.Empty) (*v1.ReminderSettings, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	settings, err := ts.taskRepo.GetReminderSettings(ctx, userCtx.UserID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.ReminderSettings{}, apierror.Status(err)
+	}
+	return &v1.ReminderSettings{Reminders: settings.Reminders}, nil
+}
+
+func (ts *TaskService) UpdateReminderSettings(ctx context.Context, in *v1.ReminderSettings) (*v1.ReminderSettings, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	settings, err := ts.taskRepo.SetReminderSettings(ctx, repository.ReminderSettings{
+		UserID:    userCtx.UserID,
+		Reminders: in.Reminders,
+	})
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.ReminderSettings{}, apierror.Status(err)
+	}
+	log.Info("Updated reminder settings")
+	return &v1.ReminderSettings{Reminders: settings.Reminders}, nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/jakubjano/todolist/task/pkg/events"
+	"github.com/jakubjano/todolist/task/pkg/service/mail"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
+	"github.com/jakubjano/todolist/task/pkg/service/notify/notifytest"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+type ReminderQueueTestSuite struct {
+	suite.Suite
+	reminder         *Reminder
+	taskRepo         *repository.FSTaskMock
+	reminderQueue    *repository.FSReminderQueueMock
+	notificationRepo *repository.FSNotificationMock
+	sender           *notifytest.Sender
+	delivery         repository.ReminderDelivery
+}
+
+func (s *ReminderQueueTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.taskRepo = repository.NewMockRepo()
+	s.reminderQueue = repository.NewMockReminderQueue()
+	s.sender = &notifytest.Sender{}
+	s.notificationRepo = repository.NewMockNotificationRepo()
+	s.notificationRepo.On("GetPreferences", mock.Anything, "1").
+		Return(repository.DefaultNotificationPreferences("1"), nil)
+	templates, err := mail.ParseTemplates()
+	s.Require().NoError(err)
+	notifications := NewNotifications(s.notificationRepo, logger,
+		notify.NewEmail(s.sender, notify.EmailSettings{From: "todolist@tst.com", Templates: templates}))
+	s.reminder = NewReminder(s.taskRepo, s.reminderQueue, logger, notifications, nil, ReminderQueueSettings{
+		Attempts:  3,
+		Backoff:   time.Minute,
+		Lease:     time.Minute,
+		BatchSize: 10,
+	})
+	s.delivery = repository.NewReminderDelivery(repository.Task{
+		TaskID:    "tid1",
+		UserID:    "1",
+		UserEmail: "example1@tst.com",
+		Name:      "task1",
+		Time:      1658102400,
+	}, 3600, 1658098800)
+	s.reminderQueue.On("Due", mock.Anything, int64(1658098800), 10).
+		Return([]repository.ReminderDelivery{s.delivery}, nil)
+}
+
+func (s *ReminderQueueTestSuite) claim(attempts int32) {
+	claimed := s.delivery
+	claimed.Attempts = attempts
+	s.reminderQueue.On("Claim", mock.Anything, s.delivery.DeliveryID, time.Minute).Return(claimed, true, nil)
+}
+
+func (s *ReminderQueueTestSuite) TestEnqueueReminders() {
+	s.taskRepo.On("SearchForExpiringTasks", mock.Anything, int64(1658098800)).Return(map[string][]repository.Task{
+		"example1@tst.com": {{TaskID: "tid1"}, {TaskID: "tid2"}, {TaskID: "tid3"}},
+	}, nil)
+	s.reminderQueue.On("FireReminders", mock.Anything, "tid1", int64(1658098800)).Return(s.delivery, true, nil)
+	s.reminderQueue.On("FireReminders", mock.Anything, "tid2", int64(1658098800)).
+		Return(repository.ReminderDelivery{}, false, errors.New("aborted"))
+	s.reminderQueue.On("FireReminders", mock.Anything, "tid3", int64(1658098800)).
+		Return(repository.ReminderDelivery{}, false, nil)
+	// a failed task doesn't stop the others
+	err := s.reminder.EnqueueReminders(context.Background(), 1658098800)
+	s.NoError(err)
+	s.reminderQueue.AssertNumberOfCalls(s.T(), "FireReminders", 3)
+}
+
+func (s *ReminderQueueTestSuite) TestSendReminders() {
+	s.claim(1)
+	s.reminderQueue.On("MarkSent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	err := s.reminder.SendReminders(context.Background(), 1658098800)
+	s.NoError(err)
+	s.Require().Len(s.sender.Messages(), 1)
+	// the Message-ID is the same for every attempt
+	s.Contains(string(s.sender.Messages()[0].Body), "Message-ID: <tid1_1658102400_3600@tst.com>")
+	event := s.reminderQueue.Calls[2].Arguments.Get(2).(events.Event)
+	s.Equal(events.ReminderSent, event.Type)
+	s.Equal("tid1", event.AggregateID)
+}
+
+func (s *ReminderQueueTestSuite) TestSendRemindersClaimed() {
+	s.reminderQueue.On("Claim", mock.Anything, s.delivery.DeliveryID, time.Minute).
+		Return(repository.ReminderDelivery{}, false, nil)
+	err := s.reminder.SendReminders(context.Background(), 1658098800)
+	s.NoError(err)
+	s.Empty(s.sender.Messages())
+}
+
+func (s *ReminderQueueTestSuite) TestSendRemindersRetry() {
+	s.sender.Err = errors.New("smtp down")
+	s.claim(2)
+	s.reminderQueue.On("Retry", mock.Anything, s.delivery.DeliveryID, mock.Anything, "notification failed: email",
+		[]string(nil)).Return(nil)
+	start := time.Now()
+	err := s.reminder.SendReminders(context.Background(), 1658098800)
+	s.NoError(err)
+	// the backoff doubles with every attempt
+	retryAt := s.reminderQueue.Calls[2].Arguments.Get(2).(int64)
+	s.InDelta(start.Add(2*time.Minute).Unix(), retryAt, 1)
+	s.reminderQueue.AssertNotCalled(s.T(), "MarkSent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ReminderQueueTestSuite) TestSendRemindersDeadLetter() {
+	s.sender.Err = errors.New("smtp down")
+	s.claim(3)
+	s.reminderQueue.On("DeadLetter", mock.Anything, mock.Anything, "notification failed: email").Return(nil)
+	err := s.reminder.SendReminders(context.Background(), 1658098800)
+	s.NoError(err)
+	s.reminderQueue.AssertCalled(s.T(), "DeadLetter", mock.Anything, mock.Anything, mock.Anything)
+	s.reminderQueue.AssertNotCalled(s.T(), "Retry", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything)
+}
+
+func (s *ReminderQueueTestSuite) TestSendRemindersQuietHours() {
+	prefs := repository.DefaultNotificationPreferences("1")
+	prefs.TimeZone = "Europe/Prague"
+	// 1658098800 is 1:00 in Prague
+	prefs.QuietHours = repository.QuietHours{Start: "22:00", End: "07:00"}
+	s.notificationRepo.ExpectedCalls = nil
+	s.notificationRepo.On("GetPreferences", mock.Anything, "1").Return(prefs, nil)
+	s.reminderQueue.On("Defer", mock.Anything, s.delivery.DeliveryID, int64(1658120400)).Return(nil)
+	err := s.reminder.SendReminders(context.Background(), 1658098800)
+	s.NoError(err)
+	s.Empty(s.sender.Messages())
+	s.reminderQueue.AssertNotCalled(s.T(), "Claim", mock.Anything, mock.Anything, mock.Anything)
+	s.reminderQueue.AssertCalled(s.T(), "Defer", mock.Anything, s.delivery.DeliveryID, int64(1658120400))
+}
+
+func (s *ReminderQueueTestSuite) TestEnqueueOverdue() {
+	s.taskRepo.On("SearchForOverdueTasks", mock.Anything, int64(1658098800)).Return([]repository.Task{
+		{TaskID: "tid1"}, {TaskID: "tid2"},
+	}, nil)
+	s.reminderQueue.On("FireOverdue", mock.Anything, "tid1", int64(1658098800)).
+		Return([]repository.ReminderDelivery{}, errors.New("aborted"))
+	s.reminderQueue.On("FireOverdue", mock.Anything, "tid2", int64(1658098800)).
+		Return([]repository.ReminderDelivery{s.delivery}, nil)
+	// a failed task doesn't stop the others
+	err := s.reminder.EnqueueOverdue(context.Background(), 1658098800)
+	s.NoError(err)
+	s.reminderQueue.AssertNumberOfCalls(s.T(), "FireOverdue", 2)
+}
+
+func (s *ReminderQueueTestSuite) TestSendEscalation() {
+	s.delivery = repository.NewEscalationDelivery(repository.Task{
+		TaskID:    "tid1",
+		UserID:    "1",
+		UserEmail: "example1@tst.com",
+		Name:      "task1",
+		Time:      1658012400,
+	}, "boss@tst.com", 1658098800)
+	// the quiet hours of the owner don't defer escalations
+	prefs := repository.DefaultNotificationPreferences("1")
+	prefs.TimeZone = "Europe/Prague"
+	prefs.QuietHours = repository.QuietHours{Start: "22:00", End: "07:00"}
+	s.notificationRepo.ExpectedCalls = nil
+	s.notificationRepo.On("GetPreferences", mock.Anything, "1").Return(prefs, nil)
+	s.reminderQueue.ExpectedCalls = nil
+	s.reminderQueue.On("Due", mock.Anything, int64(1658098800), 10).
+		Return([]repository.ReminderDelivery{s.delivery}, nil)
+	s.claim(1)
+	s.reminderQueue.On("MarkSent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	err := s.reminder.SendReminders(context.Background(), 1658098800)
+	s.NoError(err)
+	// the escalation goes to the escalation email only
+	s.Require().Len(s.sender.Messages(), 1)
+	s.Equal([]string{"boss@tst.com"}, s.sender.Messages()[0].To)
+	body := string(s.sender.Messages()[0].Body)
+	s.Contains(body, "Subject: Escalation: task1 of example1@tst.com is overdue")
+	s.Contains(body, "Message-ID: <tid1_1658012400_escalation@tst.com>")
+	s.reminderQueue.AssertNotCalled(s.T(), "Defer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReminderQueueTestSuite(t *testing.T) {
+	suite.Run(t, new(ReminderQueueTestSuite))
+}
@@ -6,6 +6,9 @@ package service
 import (
 	"cloud.google.com/go/firestore"
 	"context"
+	"errors"
+	"github.com/jakubjano/todolist/task/pkg/service/mail"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
 	"github.com/jakubjano/todolist/task/pkg/service/repository"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -20,6 +23,7 @@ type ReminderTestSuite struct {
 	taskRepo    repository.FSTaskInterface
 	clientMock  *ClientMock
 	reminder    *Reminder
+	scheduler   *ReminderScheduler
 	emailSender EmailSender
 }
 
@@ -31,8 +35,23 @@ func (s *ReminderTestSuite) SetupSuite() {
 	logger, err := NewLogger()
 	s.NoError(err)
 	clientMock := NewClientMock()
-	reminder := NewReminder(taskRepo, logger, clientMock, client)
+	templates, err := mail.ParseTemplates()
+	s.Require().NoError(err)
+	notifications := NewNotifications(repository.NewFSNotification(client.Collection(repository.CollectionUsers)),
+		logger, notify.NewEmail(clientMock, notify.EmailSettings{From: "todolist@tst.com", Templates: templates}))
+	reminderQueue := repository.NewFSReminderQueue(client.Collection(repository.CollectionReminderQueue), client)
+	reminder := NewReminder(taskRepo, reminderQueue, logger, notifications, nil, ReminderQueueSettings{
+		Attempts:  2,
+		Lease:     time.Minute,
+		BatchSize: 100,
+	})
+	scheduler, err := NewReminderScheduler(reminder, logger, SchedulerSettings{
+		ReconcileEvery: time.Minute,
+		Horizon:        time.Hour,
+	}, func() bool { return true })
+	s.Require().NoError(err)
 	s.reminder = reminder
+	s.scheduler = scheduler
 	s.client = client
 	s.taskRepo = taskRepo
 	s.clientMock = clientMock
@@ -40,6 +59,9 @@ func (s *ReminderTestSuite) SetupSuite() {
 }
 
 func (s *ReminderTestSuite) SetupTest() {
+	// the notifiers keep the mock, every test starts it over
+	s.clientMock.ExpectedCalls = nil
+	s.clientMock.Calls = nil
 	// Add test data to DB
 	ctx := context.Background()
 	batchCreate := s.client.Batch()
@@ -110,187 +132,169 @@ func (s *ReminderTestSuite) SetupTest() {
 
 	tasks := []repository.Task{
 		{
-			CreatedAt:    1,
-			Name:         "task1",
-			Description:  "desc1",
-			UserID:       "1",
-			UserEmail:    "example1@tst.com",
-			Time:         7,
-			TaskID:       "tid1",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task1",
+			Description: "desc1",
+			UserID:      "1",
+			UserEmail:   "example1@tst.com",
+			Time:        7,
+			TaskID:      "tid1",
 		},
 		{
-			CreatedAt:    2,
-			Name:         "task2",
-			Description:  "desc2",
-			UserID:       "1",
-			UserEmail:    "example1@tst.com",
-			Time:         time.Now().Add(time.Minute * 1).Unix(),
-			TaskID:       "tid2",
-			ReminderSent: false,
+			CreatedAt:   2,
+			Name:        "task2",
+			Description: "desc2",
+			UserID:      "1",
+			UserEmail:   "example1@tst.com",
+			Time:        time.Now().Add(time.Minute * 1).Unix(),
+			TaskID:      "tid2",
 		},
 		{
-			CreatedAt:    3,
-			Name:         "task3",
-			Description:  "desc3",
-			UserID:       "1",
-			UserEmail:    "example1@tst.com",
-			Time:         10,
-			TaskID:       "tid3",
-			ReminderSent: false,
+			CreatedAt:   3,
+			Name:        "task3",
+			Description: "desc3",
+			UserID:      "1",
+			UserEmail:   "example1@tst.com",
+			Time:        10,
+			TaskID:      "tid3",
 		},
 		{
-			CreatedAt:    5,
-			Name:         "task4",
-			Description:  "desc4",
-			UserID:       "2",
-			UserEmail:    "example2@tst.com",
-			Time:         10,
-			TaskID:       "tid4",
-			ReminderSent: false,
+			CreatedAt:   5,
+			Name:        "task4",
+			Description: "desc4",
+			UserID:      "2",
+			UserEmail:   "example2@tst.com",
+			Time:        10,
+			TaskID:      "tid4",
 		},
 		{
-			CreatedAt:    5,
-			Name:         "task5",
-			Description:  "desc5",
-			UserID:       "3",
-			UserEmail:    "example3@tst.com",
-			Time:         10,
-			TaskID:       "tid5",
-			ReminderSent: false,
+			CreatedAt:   5,
+			Name:        "task5",
+			Description: "desc5",
+			UserID:      "3",
+			UserEmail:   "example3@tst.com",
+			Time:        10,
+			TaskID:      "tid5",
 		},
 		{
-			CreatedAt:    5,
-			Name:         "task6",
-			Description:  "desc6",
-			UserID:       "4",
-			UserEmail:    "example6@tst.com",
-			Time:         10,
-			TaskID:       "tid6",
-			ReminderSent: false,
+			CreatedAt:   5,
+			Name:        "task6",
+			Description: "desc6",
+			UserID:      "4",
+			UserEmail:   "example6@tst.com",
+			Time:        10,
+			TaskID:      "tid6",
 		},
 		// tasks for reminder batch write testing
 		// User 6
 		{
-			CreatedAt:    1,
-			Name:         "task7",
-			Description:  "desc7",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid7",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task7",
+			Description: "desc7",
+			UserID:      "6",
+			UserEmail:   "example6@tst.com",
+			Time:        time.Now().Add(time.Minute * 4).Unix(),
+			TaskID:      "tid7",
 		},
 		{
-			CreatedAt:    1,
-			Name:         "task8",
-			Description:  "desc8",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid8",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task8",
+			Description: "desc8",
+			UserID:      "6",
+			UserEmail:   "example6@tst.com",
+			Time:        time.Now().Add(time.Minute * 4).Unix(),
+			TaskID:      "tid8",
 		},
 		{
-			CreatedAt:    1,
-			Name:         "task9",
-			Description:  "desc9",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid9",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task9",
+			Description: "desc9",
+			UserID:      "6",
+			UserEmail:   "example6@tst.com",
+			Time:        time.Now().Add(time.Minute * 4).Unix(),
+			TaskID:      "tid9",
 		},
 		{
-			CreatedAt:    1,
-			Name:         "task10",
-			Description:  "desc10",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid10",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task10",
+			Description: "desc10",
+			UserID:      "6",
+			UserEmail:   "example6@tst.com",
+			Time:        time.Now().Add(time.Minute * 4).Unix(),
+			TaskID:      "tid10",
 		},
 		{
-			CreatedAt:    1,
-			Name:         "task11",
-			Description:  "desc11",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid11",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task11",
+			Description: "desc11",
+			UserID:      "6",
+			UserEmail:   "example6@tst.com",
+			Time:        time.Now().Add(time.Minute * 4).Unix(),
+			TaskID:      "tid11",
 		},
 		{
-			CreatedAt:    1,
-			Name:         "task12",
-			Description:  "desc12",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid12",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task12",
+			Description: "desc12",
+			UserID:      "6",
+			UserEmail:   "example6@tst.com",
+			Time:        time.Now().Add(time.Minute * 4).Unix(),
+			TaskID:      "tid12",
 		},
 		// User 7
 		{
-			CreatedAt:    1,
-			Name:         "task13",
-			Description:  "desc13",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid13",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task13",
+			Description: "desc13",
+			UserID:      "7",
+			UserEmail:   "example7@tst.com",
+			Time:        time.Now().Add(time.Minute * 3).Unix(),
+			TaskID:      "tid13",
 		},
 		{
-			CreatedAt:    1,
-			Name:         "task14",
-			Description:  "desc14",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid14",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task14",
+			Description: "desc14",
+			UserID:      "7",
+			UserEmail:   "example7@tst.com",
+			Time:        time.Now().Add(time.Minute * 3).Unix(),
+			TaskID:      "tid14",
 		},
 		{
-			CreatedAt:    1,
-			Name:         "task15",
-			Description:  "desc15",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid15",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task15",
+			Description: "desc15",
+			UserID:      "7",
+			UserEmail:   "example7@tst.com",
+			Time:        time.Now().Add(time.Minute * 3).Unix(),
+			TaskID:      "tid15",
 		},
 		{
-			CreatedAt:    1,
-			Name:         "task16",
-			Description:  "desc16",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid16",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task16",
+			Description: "desc16",
+			UserID:      "7",
+			UserEmail:   "example7@tst.com",
+			Time:        time.Now().Add(time.Minute * 3).Unix(),
+			TaskID:      "tid16",
 		},
 		{
-			CreatedAt:    1,
-			Name:         "task17",
-			Description:  "desc17",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid17",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task17",
+			Description: "desc17",
+			UserID:      "7",
+			UserEmail:   "example7@tst.com",
+			Time:        time.Now().Add(time.Minute * 3).Unix(),
+			TaskID:      "tid17",
 		},
 		{
-			CreatedAt:    1,
-			Name:         "task18",
-			Description:  "desc18",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid18",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task18",
+			Description: "desc18",
+			UserID:      "7",
+			UserEmail:   "example7@tst.com",
+			Time:        time.Now().Add(time.Minute * 3).Unix(),
+			TaskID:      "tid18",
 		},
 	}
 
@@ -313,6 +317,9 @@ func (s *ReminderTestSuite) SetupTest() {
 	taskListBatch := s.client.Batch()
 	for _, task := range tasks {
 		taskListRef := s.client.Collection(repository.TaskList).Doc(task.TaskID)
+		// reminders are searched for in the task list only
+		task.Reminders = repository.DefaultReminders
+		task.ScheduleReminders(time.Now().Unix())
 		taskListBatch.Set(taskListRef, task)
 	}
 	_, err = taskListBatch.Commit(ctx)
@@ -332,15 +339,18 @@ func (s *ReminderTestSuite) TearDownTest() {
 	_, err = batch.Commit(ctx)
 	s.NoError(err)
 
-	// delete task list too
-	taskListBatch := s.client.Batch()
-	taskListDocs, err := s.client.Collection(repository.TaskList).Documents(ctx).GetAll()
-	s.NoError(err)
-	for _, doc := range taskListDocs {
-		taskListBatch.Delete(doc.Ref)
+	// delete task list and the queued reminders too
+	for _, collection := range []string{repository.TaskList, repository.CollectionReminderQueue,
+		repository.CollectionReminderDeadLetters} {
+		collectionBatch := s.client.Batch()
+		collectionDocs, err := s.client.Collection(collection).Documents(ctx).GetAll()
+		s.NoError(err)
+		for _, doc := range collectionDocs {
+			collectionBatch.Delete(doc.Ref)
+		}
+		_, err = collectionBatch.Commit(ctx)
+		s.NoError(err)
 	}
-	_, err = taskListBatch.Commit(ctx)
-	s.NoError(err)
 }
 
 func (s *ReminderTestSuite) TearDownSuite() {
@@ -348,18 +358,129 @@ func (s *ReminderTestSuite) TearDownSuite() {
 	s.NoError(err)
 }
 
-func (s *ReminderTestSuite) TestRemindUserViaEmail() {
+func (s *ReminderTestSuite) TestRemind() {
 	ctx := context.Background()
 	s.clientMock.On("Send",
 		mock.Anything,
 		mock.Anything,
 	).Return(nil)
-	err := s.reminder.RemindUserViaEmail(ctx)
+	s.scheduler.run(ctx, true)
+	s.clientMock.AssertNumberOfCalls(s.T(),
+		"Send",
+		13,
+	)
+	// the reminders were marked as sent
+	s.scheduler.run(ctx, true)
 	s.clientMock.AssertNumberOfCalls(s.T(),
 		"Send",
 		13,
 	)
+}
+
+func (s *ReminderTestSuite) TestRemindFailed() {
+	ctx := context.Background()
+	s.clientMock.On("Send",
+		mock.Anything,
+		mock.Anything,
+	).Return(errors.New("smtp down"))
+	s.scheduler.run(ctx, true)
+	// every reminder is retried right away without a backoff, the second attempt is the last one
+	s.scheduler.run(ctx, true)
+	s.clientMock.AssertNumberOfCalls(s.T(),
+		"Send",
+		26,
+	)
+	deadLetters, err := s.client.Collection(repository.CollectionReminderDeadLetters).Documents(ctx).GetAll()
+	s.NoError(err)
+	s.Len(deadLetters, 13)
+	queued, err := s.client.Collection(repository.CollectionReminderQueue).Documents(ctx).GetAll()
+	s.NoError(err)
+	s.Empty(queued)
+}
+
+func (s *ReminderTestSuite) TestRemindOverdue() {
+	ctx := context.Background()
+	s.clientMock.On("Send",
+		mock.Anything,
+		mock.Anything,
+	).Return(nil)
+	_, err := s.taskRepo.SetEscalationPolicy(ctx, repository.EscalationPolicy{
+		UserID:          "2",
+		Intervals:       []int64{1},
+		EscalateAfter:   1,
+		EscalationEmail: "boss@tst.com",
+		EmailVerified:   true,
+	})
+	s.Require().NoError(err)
+	// task4 of user 2 is long overdue
+	overdue := map[string]interface{}{"nextOverdueAt": time.Now().Unix()}
+	_, err = s.client.Collection(repository.TaskList).Doc("tid4").Set(ctx, overdue, firestore.MergeAll)
+	s.Require().NoError(err)
+	_, err = s.client.Collection(repository.CollectionUsers).Doc("2").Collection(repository.CollectionTasks).
+		Doc("tid4").Set(ctx, overdue, firestore.MergeAll)
+	s.Require().NoError(err)
+
+	s.scheduler.run(ctx, true)
+	// the reminders, the overdue reminder and the escalation
+	s.clientMock.AssertNumberOfCalls(s.T(), "Send", 15)
+	s.clientMock.AssertCalled(s.T(), "Send", []string{"boss@tst.com"}, mock.Anything)
+	task, err := s.taskRepo.Get(ctx, "2", "tid4")
+	s.NoError(err)
+	s.Equal(int32(1), task.OverdueNotices)
+	s.True(task.Escalated)
+	s.Equal(int64(0), task.NextOverdueAt)
+
+	// the policy is done with the task
+	s.scheduler.run(ctx, true)
+	s.clientMock.AssertNumberOfCalls(s.T(), "Send", 15)
+}
+
+func (s *ReminderTestSuite) TestStaleReminders() {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	queue := func(name string) (repository.Task, repository.ReminderDelivery) {
+		task, err := s.taskRepo.Create(ctx, repository.Task{
+			UserID:    "1",
+			UserEmail: "example1@tst.com",
+			Name:      name,
+			Time:      now + 60,
+			Reminders: []int64{300},
+		})
+		s.Require().NoError(err)
+		delivery, queued, err := s.reminder.reminderQueue.FireReminders(ctx, task.TaskID, now)
+		s.Require().NoError(err)
+		s.Require().True(queued)
+		return task, delivery
+	}
+	pending := func(taskID string) int {
+		docs, err := s.client.Collection(repository.CollectionReminderQueue).
+			Where("taskID", "==", taskID).
+			Where("status", "==", repository.ReminderPending).Documents(ctx).GetAll()
+		s.Require().NoError(err)
+		return len(docs)
+	}
+
+	// completing or deleting the task removes its pending reminders
+	completed, _ := queue("completed")
+	completed.Completed = true
+	_, err := s.taskRepo.Update(ctx, completed, completed.UserID, completed.TaskID)
+	s.NoError(err)
+	s.Zero(pending(completed.TaskID))
+	deleted, _ := queue("deleted")
+	s.NoError(s.taskRepo.Delete(ctx, deleted.UserID, deleted.TaskID))
+	s.Zero(pending(deleted.TaskID))
+
+	// a reminder of the task moved to another time is dropped when claimed
+	moved, delivery := queue("moved")
+	later := map[string]interface{}{"time": now + 3600}
+	_, err = s.client.Collection(repository.TaskList).Doc(moved.TaskID).Set(ctx, later, firestore.MergeAll)
+	s.Require().NoError(err)
+	_, claimed, err := s.reminder.reminderQueue.Claim(ctx, delivery.DeliveryID, time.Minute)
+	s.NoError(err)
+	s.False(claimed)
+	doc, err := s.client.Collection(repository.CollectionReminderQueue).Doc(delivery.DeliveryID).Get(ctx)
 	s.NoError(err)
+	s.Equal(repository.ReminderDropped, doc.Data()["status"])
 }
 
 func TestReminderTestSuite(t *testing.T) {
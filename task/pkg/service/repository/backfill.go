@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+const (
+	// CollectionMigrations marks the finished backfills, a document per backfill
+	CollectionMigrations = "migrations"
+	// MigrationReminders is the backfill of reminders of tasks written before they had NextReminderAt
+	MigrationReminders = "reminders"
+	// MigrationOverdue is the backfill of tasks written before they had NextOverdueAt
+	MigrationOverdue = "overdue"
+)
+
+// migration is kept under migrations/{name} once the backfill of the name is done
+type migration struct {
+	Name        string `firestore:"name"`
+	Tasks       int    `firestore:"tasks"`
+	CompletedAt int64  `firestore:"completedAt"`
+}
+
+func (f *FSTask) migrated(ctx context.Context, name string) (bool, error) {
+	_, err := f.client.Collection(CollectionMigrations).Doc(name).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (f *FSTask) markMigrated(ctx context.Context, name string, tasks int) error {
+	_, err := f.client.Collection(CollectionMigrations).Doc(name).Set(ctx, migration{
+		Name:        name,
+		Tasks:       tasks,
+		CompletedAt: time.Now().Unix(),
+	})
+	return err
+}
+
+// BackfillReminders gives the tasks due after now that were written without reminders the defaults
+// of their users and schedules the next one, the reminder search skips them otherwise. Reminders
+// that would have fired before now count as sent. It runs once, later calls return right away.
+// It returns how many tasks were scheduled
+func (f *FSTask) BackfillReminders(ctx context.Context, now int64) (int, error) {
+	done, err := f.migrated(ctx, MigrationReminders)
+	if err != nil || done {
+		return 0, err
+	}
+	docs := f.client.Collection(TaskList).Where("time", ">", now).Documents(ctx)
+	settings := make(map[string][]int64)
+	scheduled := 0
+	for {
+		doc, err := docs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return scheduled, err
+		}
+		if _, err := doc.DataAt("reminders"); err == nil {
+			continue
+		}
+		task := Task{}
+		err = doc.DataTo(&task)
+		if err != nil {
+			return scheduled, err
+		}
+		reminders, ok := settings[task.UserID]
+		if !ok {
+			userSettings, err := f.GetReminderSettings(ctx, task.UserID)
+			if err != nil {
+				return scheduled, err
+			}
+			reminders = NormalizeReminders(userSettings.Reminders)
+			settings[task.UserID] = reminders
+		}
+		ok, err = f.backfillTask(ctx, task, reminders, now)
+		if err != nil {
+			return scheduled, err
+		}
+		if ok {
+			scheduled++
+		}
+	}
+	return scheduled, f.markMigrated(ctx, MigrationReminders, scheduled)
+}
+
+// backfillTask schedules the reminders of the task unless it was written with reminders meanwhile
+func (f *FSTask) backfillTask(ctx context.Context, task Task, reminders []int64, now int64) (bool, error) {
+	listRef := f.client.Collection(TaskList).Doc(task.TaskID)
+	taskRef := f.fs.Doc(task.UserID).Collection(CollectionTasks).Doc(task.TaskID)
+	backfilled := false
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		backfilled = false
+		doc, err := tx.Get(listRef)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := doc.DataAt("reminders"); err == nil {
+			return nil
+		}
+		task.Reminders = reminders
+		task.RemindersSent = nil
+		for _, offset := range reminders {
+			if task.Time-offset <= now {
+				task.RemindersSent = append(task.RemindersSent, offset)
+			}
+		}
+		task.ScheduleReminders(now)
+		fields := map[string]interface{}{
+			"reminders":      task.Reminders,
+			"remindersSent":  task.RemindersSent,
+			"nextReminderAt": task.NextReminderAt,
+		}
+		err = tx.Set(taskRef, fields, firestore.MergeAll)
+		if err != nil {
+			return err
+		}
+		backfilled = true
+		return tx.Set(listRef, fields, firestore.MergeAll)
+	})
+	return backfilled, err
+}
+
+// BackfillOverdue has the escalation policies applied to the open tasks written without nextOverdueAt,
+// the overdue search skips them otherwise. Tasks due already get only the steps of the policy still ahead. It runs once,
+// later calls return right away. It returns how many tasks were backfilled
+func (f *FSTask) BackfillOverdue(ctx context.Context, now int64) (int, error) {
+	done, err := f.migrated(ctx, MigrationOverdue)
+	if err != nil || done {
+		return 0, err
+	}
+	docs := f.client.Collection(TaskList).Where("completed", "==", false).Documents(ctx)
+	scheduled := 0
+	for {
+		doc, err := docs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return scheduled, err
+		}
+		if _, err := doc.DataAt("nextOverdueAt"); err == nil {
+			continue
+		}
+		task := Task{}
+		err = doc.DataTo(&task)
+		if err != nil {
+			return scheduled, err
+		}
+		ok, err := f.scheduleOverdue(ctx, task.UserID, task.TaskID, now, true)
+		if err != nil {
+			return scheduled, err
+		}
+		if ok {
+			scheduled++
+		}
+	}
+	return scheduled, f.markMigrated(ctx, MigrationOverdue, scheduled)
+}
+
+// scheduleOverdue has the escalation policy applied to the open task from now, keeping the overdue reminders
+// and the escalation sent already and skipping the ones past already. The backfill passes missing, it leaves the tasks written with nextOverdueAt
+// meanwhile alone. It returns false when nextOverdueAt didn't change
+func (f *FSTask) scheduleOverdue(ctx context.Context, userID, taskID string, now int64, missing bool) (bool, error) {
+	listRef := f.client.Collection(TaskList).Doc(taskID)
+	taskRef := f.fs.Doc(userID).Collection(CollectionTasks).Doc(taskID)
+	scheduled := false
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		scheduled = false
+		doc, err := tx.Get(listRef)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, err = doc.DataAt("nextOverdueAt")
+		if missing && err == nil {
+			return nil
+		}
+		task := Task{}
+		err = doc.DataTo(&task)
+		if err != nil {
+			return err
+		}
+		policy := EscalationPolicy{UserID: userID}
+		policyDoc, err := tx.Get(escalationPolicyRef(f.fs, userID))
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			err = policyDoc.DataTo(&policy)
+			if err != nil {
+				return err
+			}
+		}
+		before := task
+		policy.CatchUpOverdue(&task, &before, now)
+		if !missing && task.NextOverdueAt == before.NextOverdueAt && task.OverdueNotices == before.OverdueNotices &&
+			task.Escalated == before.Escalated {
+			return nil
+		}
+		fields := map[string]interface{}{
+			"overdueNotices": task.OverdueNotices,
+			"escalated":      task.Escalated,
+			"nextOverdueAt":  task.NextOverdueAt,
+		}
+		err = tx.Set(taskRef, fields, firestore.MergeAll)
+		if err != nil {
+			return err
+		}
+		scheduled = true
+		return tx.Set(listRef, fields, firestore.MergeAll)
+	})
+	return scheduled, err
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"errors"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+const (
+	CalendarFeeds   = "calendar_feeds"
+	CalDAVPasswords = "caldav_passwords"
+)
+
+var (
+	ErrFeedNotFound     = errors.New("calendar feed not found")
+	ErrPasswordNotFound = apierror.New(codes.NotFound, "CALDAV_PASSWORD_NOT_FOUND", "caldav password not found")
+)
+
+type FSCalendarInterface interface {
+	SetToken(ctx context.Context, feed CalendarFeed) error
+	GetByToken(ctx context.Context, tokenHash string) (CalendarFeed, error)
+	CreatePassword(ctx context.Context, password CalDAVPassword) (CalDAVPassword, error)
+	ListPasswords(ctx context.Context, userID string) ([]CalDAVPassword, error)
+	RevokePassword(ctx context.Context, userID, passwordID string) error
+	GetByPassword(ctx context.Context, passwordHash string) (CalDAVPassword, error)
+}
+
+// CalendarFeed keeps only a hash of the feed token, one document per user.
+// The token only reads the tasks, it's part of the feed URL shared with calendar apps
+type CalendarFeed struct {
+	UserID    string `firestore:"userID"`
+	UserEmail string `firestore:"email"`
+	TokenHash string `firestore:"tokenHash"`
+	CreatedAt int64  `firestore:"createdAt"`
+}
+
+// CalDAVPassword is an app password of a CalDAV client, which writes tasks on behalf of the user's email.
+// Only a hash of the password is kept, users have one for every client so that they can revoke it alone
+type CalDAVPassword struct {
+	PasswordID   string `firestore:"passwordID"`
+	UserID       string `firestore:"userID"`
+	UserEmail    string `firestore:"email"`
+	Name         string `firestore:"name"`
+	PasswordHash string `firestore:"passwordHash"`
+	CreatedAt    int64  `firestore:"createdAt"`
+}
+
+type FSCalendar struct {
+	fs     *firestore.CollectionRef
+	client *firestore.Client
+}
+
+func NewFSCalendar(fs *firestore.CollectionRef, client *firestore.Client) *FSCalendar {
+	return &FSCalendar{
+		fs:     fs,
+		client: client,
+	}
+}
+
+// SetToken replaces the previous token of the user
+func (f *FSCalendar) SetToken(ctx context.Context, feed CalendarFeed) error {
+	feed.CreatedAt = time.Now().Unix()
+	_, err := f.fs.Doc(feed.UserID).Set(ctx, feed)
+	return err
+}
+
+func (f *FSCalendar) GetByToken(ctx context.Context, tokenHash string) (CalendarFeed, error) {
+	docs, err := f.fs.Where("tokenHash", "==", tokenHash).Limit(1).Documents(ctx).GetAll()
+	if err != nil {
+		return CalendarFeed{}, err
+	}
+	if len(docs) == 0 {
+		return CalendarFeed{}, ErrFeedNotFound
+	}
+	feed := CalendarFeed{}
+	err = docs[0].DataTo(&feed)
+	if err != nil {
+		return CalendarFeed{}, err
+	}
+	return feed, nil
+}
+
+func (f *FSCalendar) passwords() *firestore.CollectionRef {
+	return f.client.Collection(CalDAVPasswords)
+}
+
+func (f *FSCalendar) CreatePassword(ctx context.Context, password CalDAVPassword) (CalDAVPassword, error) {
+	docRef := f.passwords().NewDoc()
+	password.PasswordID = docRef.ID
+	password.CreatedAt = time.Now().Unix()
+	_, err := docRef.Create(ctx, password)
+	if err != nil {
+		return CalDAVPassword{}, err
+	}
+	return password, nil
+}
+
+// ListPasswords returns the passwords of the user ordered from the oldest, which needs a composite index
+func (f *FSCalendar) ListPasswords(ctx context.Context, userID string) ([]CalDAVPassword, error) {
+	docs := f.passwords().Where("userID", "==", userID).OrderBy("createdAt", firestore.Asc).Documents(ctx)
+	var passwords []CalDAVPassword
+	for {
+		doc, err := docs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		password := CalDAVPassword{}
+		err = doc.DataTo(&password)
+		if err != nil {
+			return nil, err
+		}
+		passwords = append(passwords, password)
+	}
+	return passwords, nil
+}
+
+// RevokePassword deletes the password, passwords of other users look like missing ones
+func (f *FSCalendar) RevokePassword(ctx context.Context, userID, passwordID string) error {
+	docRef := f.passwords().Doc(passwordID)
+	return f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if status.Code(err) == codes.NotFound {
+			return ErrPasswordNotFound
+		}
+		if err != nil {
+			return err
+		}
+		password := CalDAVPassword{}
+		err = doc.DataTo(&password)
+		if err != nil {
+			return err
+		}
+		if password.UserID != userID {
+			return ErrPasswordNotFound
+		}
+		return tx.Delete(docRef)
+	})
+}
+
+func (f *FSCalendar) GetByPassword(ctx context.Context, passwordHash string) (CalDAVPassword, error) {
+	docs, err := f.passwords().Where("passwordHash", "==", passwordHash).Limit(1).Documents(ctx).GetAll()
+	if err != nil {
+		return CalDAVPassword{}, err
+	}
+	if len(docs) == 0 {
+		return CalDAVPassword{}, ErrPasswordNotFound
+	}
+	password := CalDAVPassword{}
+	err = docs[0].DataTo(&password)
+	if err != nil {
+		return CalDAVPassword{}, err
+	}
+	return password, nil
+}
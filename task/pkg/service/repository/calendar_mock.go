@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type FSCalendarMock struct {
+	mock.Mock
+}
+
+func NewMockCalendarRepo() *FSCalendarMock {
+	return &FSCalendarMock{}
+}
+
+func (m *FSCalendarMock) SetToken(ctx context.Context, feed CalendarFeed) error {
+	args := m.Called(ctx, feed)
+	return args.Error(0)
+}
+
+func (m *FSCalendarMock) GetByToken(ctx context.Context, tokenHash string) (CalendarFeed, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(CalendarFeed), args.Error(1)
+}
+
+func (m *FSCalendarMock) CreatePassword(ctx context.Context, password CalDAVPassword) (CalDAVPassword, error) {
+	args := m.Called(ctx, password)
+	return args.Get(0).(CalDAVPassword), args.Error(1)
+}
+
+func (m *FSCalendarMock) ListPasswords(ctx context.Context, userID string) ([]CalDAVPassword, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]CalDAVPassword), args.Error(1)
+}
+
+func (m *FSCalendarMock) RevokePassword(ctx context.Context, userID, passwordID string) error {
+	args := m.Called(ctx, userID, passwordID)
+	return args.Error(0)
+}
+
+func (m *FSCalendarMock) GetByPassword(ctx context.Context, passwordHash string) (CalDAVPassword, error) {
+	args := m.Called(ctx, passwordHash)
+	return args.Get(0).(CalDAVPassword), args.Error(1)
+}
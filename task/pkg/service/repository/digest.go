@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type FSDigestInterface interface {
+	GetSettings(ctx context.Context, userID string) (DigestSettings, error)
+	SetSettings(ctx context.Context, settings DigestSettings) (DigestSettings, error)
+	SearchForDigests(ctx context.Context) ([]DigestSettings, error)
+	ClaimPeriod(ctx context.Context, userID, period string) (bool, error)
+	ReleasePeriod(ctx context.Context, userID, period, prev string) error
+}
+
+// FSDigest stores the digest schedules in the settings sub collection of the user
+type FSDigest struct {
+	fs     *firestore.CollectionRef
+	client *firestore.Client
+}
+
+func NewFSDigest(fs *firestore.CollectionRef, client *firestore.Client) *FSDigest {
+	return &FSDigest{
+		fs:     fs,
+		client: client,
+	}
+}
+
+func (f *FSDigest) settingsRef(userID string) *firestore.DocumentRef {
+	return f.fs.Doc(userID).Collection(CollectionSettings).Doc(DocDigestSettings)
+}
+
+// GetSettings returns settings with digests off for users who haven't opted in
+func (f *FSDigest) GetSettings(ctx context.Context, userID string) (DigestSettings, error) {
+	doc, err := f.settingsRef(userID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return DigestSettings{UserID: userID}, nil
+	}
+	if err != nil {
+		return DigestSettings{}, err
+	}
+	settings := DigestSettings{}
+	err = doc.DataTo(&settings)
+	if err != nil {
+		return DigestSettings{}, err
+	}
+	return settings, nil
+}
+
+func (f *FSDigest) SetSettings(ctx context.Context, settings DigestSettings) (DigestSettings, error) {
+	settings.UpdatedAt = time.Now().Unix()
+	_, err := f.settingsRef(settings.UserID).Set(ctx, settings)
+	if err != nil {
+		return DigestSettings{}, err
+	}
+	return settings, nil
+}
+
+// SearchForDigests returns the settings of all users who opted in, whether their digest is due
+// depends on their time zone. The collection group query needs a single field index exemption
+// on frequency for the settings collection group
+func (f *FSDigest) SearchForDigests(ctx context.Context) ([]DigestSettings, error) {
+	docs, err := f.client.CollectionGroup(CollectionSettings).
+		Where("frequency", "in", []string{DigestDaily, DigestWeekly}).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	digests := make([]DigestSettings, 0, len(docs))
+	for _, doc := range docs {
+		settings := DigestSettings{}
+		err = doc.DataTo(&settings)
+		if err != nil {
+			return nil, err
+		}
+		digests = append(digests, settings)
+	}
+	return digests, nil
+}
+
+// ClaimPeriod records the period as the last one sent unless it already is. It returns true to the only caller
+// that claimed the period, which sends the digest, so that concurrent jobs never send it twice
+func (f *FSDigest) ClaimPeriod(ctx context.Context, userID, period string) (bool, error) {
+	docRef := f.settingsRef(userID)
+	claimed := false
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = false
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		settings := DigestSettings{}
+		err = doc.DataTo(&settings)
+		if err != nil {
+			return err
+		}
+		if settings.LastPeriod == period {
+			return nil
+		}
+		claimed = true
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "lastPeriod", Value: period},
+			{Path: "lastSentAt", Value: time.Now().Unix()},
+		})
+	})
+	if err != nil {
+		return false, err
+	}
+	return claimed, nil
+}
+
+// ReleasePeriod restores the previous period after a digest failed, so the next run retries it
+func (f *FSDigest) ReleasePeriod(ctx context.Context, userID, period, prev string) error {
+	docRef := f.settingsRef(userID)
+	return f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		settings := DigestSettings{}
+		err = doc.DataTo(&settings)
+		if err != nil {
+			return err
+		}
+		if settings.LastPeriod != period {
+			return nil
+		}
+		return tx.Update(docRef, []firestore.Update{{Path: "lastPeriod", Value: prev}})
+	})
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type FSDigestMock struct {
+	mock.Mock
+}
+
+func NewMockDigestRepo() *FSDigestMock {
+	return &FSDigestMock{}
+}
+
+func (m *FSDigestMock) GetSettings(ctx context.Context, userID string) (DigestSettings, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(DigestSettings), args.Error(1)
+}
+
+func (m *FSDigestMock) SetSettings(ctx context.Context, settings DigestSettings) (DigestSettings, error) {
+	args := m.Called(ctx, settings)
+	return args.Get(0).(DigestSettings), args.Error(1)
+}
+
+func (m *FSDigestMock) SearchForDigests(ctx context.Context) ([]DigestSettings, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]DigestSettings), args.Error(1)
+}
+
+func (m *FSDigestMock) ClaimPeriod(ctx context.Context, userID, period string) (bool, error) {
+	args := m.Called(ctx, userID, period)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *FSDigestMock) ReleasePeriod(ctx context.Context, userID, period, prev string) error {
+	args := m.Called(ctx, userID, period, prev)
+	return args.Error(0)
+}
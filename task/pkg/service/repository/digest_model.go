@@ -0,0 +1,94 @@
+package repository
+
+import (
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	"time"
+)
+
+const (
+	// DocDigestSettings is the document of the digest schedule under users/{uid}/settings
+	DocDigestSettings = "digest"
+
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+
+	// digestPeriodLayout formats the local date a period starts on
+	digestPeriodLayout = "2006-01-02"
+)
+
+var digestFrequencies = map[v1.DigestFrequency]string{
+	v1.DigestFrequency_DAILY:  DigestDaily,
+	v1.DigestFrequency_WEEKLY: DigestWeekly,
+}
+
+// DigestSettings is the digest schedule of the user, an empty frequency is off.
+// LastPeriod is the start date of the last period a digest was sent for, see Period
+type DigestSettings struct {
+	UserID     string `firestore:"userID"`
+	Email      string `firestore:"email"`
+	Frequency  string `firestore:"frequency"`
+	Hour       int    `firestore:"hour"`
+	Weekday    int    `firestore:"weekday"`
+	LastPeriod string `firestore:"lastPeriod"`
+	LastSentAt int64  `firestore:"lastSentAt"`
+	UpdatedAt  int64  `firestore:"updatedAt"`
+}
+
+// DigestPeriod is the period a digest summarizes, tasks due from From until To
+type DigestPeriod struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+// Period returns the period the digest is due for at now, the location of now is the time zone of the user.
+// Daily periods are the days, weekly ones the seven days from the weekday. A period is due from the hour
+// on its first day, ok is false before it and for users without digests. Missed periods aren't sent later
+func (d DigestSettings) Period(now time.Time) (period DigestPeriod, ok bool) {
+	days := 1
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch d.Frequency {
+	case DigestDaily:
+	case DigestWeekly:
+		days = 7
+		start = start.AddDate(0, 0, -((int(now.Weekday()) - d.Weekday + 7) % 7))
+	default:
+		return DigestPeriod{}, false
+	}
+	// not start.Add, days with a DST change don't have 24 hours
+	if now.Before(time.Date(start.Year(), start.Month(), start.Day(), d.Hour, 0, 0, 0, now.Location())) {
+		return DigestPeriod{}, false
+	}
+	return DigestPeriod{
+		Name: start.Format(digestPeriodLayout),
+		From: start,
+		To:   start.AddDate(0, 0, days),
+	}, true
+}
+
+func (d DigestSettings) ToApi() *v1.DigestSettings {
+	settings := &v1.DigestSettings{
+		Hour:       int32(d.Hour),
+		Weekday:    int32(d.Weekday),
+		LastPeriod: d.LastPeriod,
+	}
+	for frequency, name := range digestFrequencies {
+		if name == d.Frequency {
+			settings.Frequency = frequency
+		}
+	}
+	return settings
+}
+
+// DigestSettingsFromMsg keeps the last period of prev, so changing the schedule doesn't resend a digest
+func DigestSettingsFromMsg(userID, email string, msg *v1.DigestSettings, prev DigestSettings) DigestSettings {
+	return DigestSettings{
+		UserID:     userID,
+		Email:      email,
+		Frequency:  digestFrequencies[msg.Frequency],
+		Hour:       int(msg.Hour),
+		Weekday:    int(msg.Weekday),
+		LastPeriod: prev.LastPeriod,
+		LastSentAt: prev.LastSentAt,
+	}
+}
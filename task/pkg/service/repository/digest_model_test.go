@@ -0,0 +1,75 @@
+package repository
+
+import (
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type DigestModelTestSuite struct {
+	suite.Suite
+}
+
+func (s *DigestModelTestSuite) TestPeriod() {
+	prague, err := time.LoadLocation("Europe/Prague")
+	s.Require().NoError(err)
+	// Monday
+	now := time.Date(2022, 7, 18, 8, 30, 0, 0, prague)
+	candidates := []struct {
+		settings DigestSettings
+		now      time.Time
+		expected string
+		ok       bool
+	}{
+		{settings: DigestSettings{Frequency: DigestDaily, Hour: 8}, now: now, expected: "2022-07-18", ok: true},
+		{settings: DigestSettings{Frequency: DigestDaily, Hour: 9}, now: now},
+		// the same instant is 2:30 in New York
+		{settings: DigestSettings{Frequency: DigestDaily, Hour: 8}, now: now.In(mustLoadLocation("America/New_York"))},
+		{settings: DigestSettings{Frequency: DigestDaily, Hour: 2}, now: now.In(mustLoadLocation("America/New_York")),
+			expected: "2022-07-18", ok: true},
+		{settings: DigestSettings{Frequency: DigestWeekly, Hour: 8, Weekday: 1}, now: now, expected: "2022-07-18", ok: true},
+		{settings: DigestSettings{Frequency: DigestWeekly, Hour: 9, Weekday: 5}, now: now, expected: "2022-07-15", ok: true},
+		// the weekday has come, the hour hasn't yet
+		{settings: DigestSettings{Frequency: DigestWeekly, Hour: 9, Weekday: 1}, now: now},
+		{settings: DigestSettings{Hour: 8}, now: now},
+	}
+	for i, c := range candidates {
+		period, ok := c.settings.Period(c.now)
+		s.Equalf(c.ok, ok, "candidate %d", i+1)
+		s.Equalf(c.expected, period.Name, "candidate %d", i+1)
+	}
+
+	period, _ := DigestSettings{Frequency: DigestWeekly, Hour: 8, Weekday: 1}.Period(now)
+	s.Equal(time.Date(2022, 7, 18, 0, 0, 0, 0, prague), period.From)
+	s.Equal(time.Date(2022, 7, 25, 0, 0, 0, 0, prague), period.To)
+}
+
+func (s *DigestModelTestSuite) TestDigestSettingsFromMsg() {
+	prev := DigestSettings{UserID: "1", Frequency: DigestDaily, LastPeriod: "2022-07-18", LastSentAt: 1658125800}
+	settings := DigestSettingsFromMsg("1", "example1@tst.com",
+		&v1.DigestSettings{Frequency: v1.DigestFrequency_WEEKLY, Hour: 7, Weekday: 1, LastPeriod: "2000-01-01"}, prev)
+	s.Equal(DigestSettings{
+		UserID:     "1",
+		Email:      "example1@tst.com",
+		Frequency:  DigestWeekly,
+		Hour:       7,
+		Weekday:    1,
+		LastPeriod: "2022-07-18",
+		LastSentAt: 1658125800,
+	}, settings)
+	s.Equal(v1.DigestFrequency_WEEKLY, settings.ToApi().Frequency)
+	s.Equal(v1.DigestFrequency_DIGEST_OFF, DigestSettings{}.ToApi().Frequency)
+}
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+func TestDigestModelTestSuite(t *testing.T) {
+	suite.Run(t, new(DigestModelTestSuite))
+}
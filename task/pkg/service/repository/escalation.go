@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+func escalationPolicyRef(users *firestore.CollectionRef, userID string) *firestore.DocumentRef {
+	return users.Doc(userID).Collection(CollectionSettings).Doc(DocEscalationPolicy)
+}
+
+// GetEscalationPolicy returns the zero policy for users who haven't set one
+func (f *FSTask) GetEscalationPolicy(ctx context.Context, userID string) (EscalationPolicy, error) {
+	doc, err := escalationPolicyRef(f.fs, userID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return EscalationPolicy{UserID: userID}, nil
+	}
+	if err != nil {
+		return EscalationPolicy{}, err
+	}
+	policy := EscalationPolicy{}
+	err = doc.DataTo(&policy)
+	if err != nil {
+		return EscalationPolicy{}, err
+	}
+	return policy, nil
+}
+
+// SetEscalationPolicy replaces the policy of the user, it's applied to the tasks from their next overdue check.
+// RescheduleOverdue has it applied to the tasks the previous policy was done with
+func (f *FSTask) SetEscalationPolicy(ctx context.Context, policy EscalationPolicy) (EscalationPolicy, error) {
+	policy.Intervals = NormalizeIntervals(policy.Intervals)
+	policy.UpdatedAt = time.Now().Unix()
+	policy.Rescheduled = false
+	_, err := escalationPolicyRef(f.fs, policy.UserID).Set(ctx, policy)
+	if err != nil {
+		return EscalationPolicy{}, err
+	}
+	return policy, nil
+}
+
+// RescheduleOverdue applies the escalation policies updated since they were last applied to the open tasks
+// of their users from now, the ones the previous policy was done with or checks later than the new one would
+// included. Overdue reminders and escalations sent already aren't sent again, the ones the tasks are past already
+// aren't sent at all. A policy updated meanwhile is rescheduled again by the next call, a failed one too.
+// It returns how many tasks were rescheduled. The collection group query needs a single field index exemption
+// on rescheduled for the settings collection group
+func (f *FSTask) RescheduleOverdue(ctx context.Context, now int64) (int, error) {
+	docs, err := f.client.CollectionGroup(CollectionSettings).
+		Where("rescheduled", "==", false).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	rescheduled := 0
+	for _, doc := range docs {
+		policy := EscalationPolicy{}
+		err = doc.DataTo(&policy)
+		if err != nil {
+			return rescheduled, err
+		}
+		n, err := f.rescheduleUser(ctx, policy.UserID, now)
+		rescheduled += n
+		if err != nil {
+			return rescheduled, err
+		}
+		err = f.markRescheduled(ctx, policy)
+		if err != nil {
+			return rescheduled, err
+		}
+	}
+	return rescheduled, nil
+}
+
+// rescheduleUser has the escalation policy of the user applied to their open tasks from now
+func (f *FSTask) rescheduleUser(ctx context.Context, userID string, now int64) (int, error) {
+	docs := f.fs.Doc(userID).Collection(CollectionTasks).Where("completed", "==", false).Documents(ctx)
+	rescheduled := 0
+	for {
+		doc, err := docs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return rescheduled, err
+		}
+		ok, err := f.scheduleOverdue(ctx, userID, doc.Ref.ID, now, false)
+		if err != nil {
+			return rescheduled, err
+		}
+		if ok {
+			rescheduled++
+		}
+	}
+	return rescheduled, nil
+}
+
+// markRescheduled records the policy as applied unless it was updated since it was read
+func (f *FSTask) markRescheduled(ctx context.Context, policy EscalationPolicy) error {
+	ref := escalationPolicyRef(f.fs, policy.UserID)
+	return f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		current := EscalationPolicy{}
+		err = doc.DataTo(&current)
+		if err != nil {
+			return err
+		}
+		if current.UpdatedAt != policy.UpdatedAt {
+			return nil
+		}
+		return tx.Set(ref, map[string]interface{}{"rescheduled": true}, firestore.MergeAll)
+	})
+}
+
+// SearchForOverdueTasks returns the tasks the escalation policies of their users are due to be applied to at now
+func (f *FSTask) SearchForOverdueTasks(ctx context.Context, now int64) (tasks []Task, err error) {
+	taskDocs, err := f.client.Collection(TaskList).
+		Where("nextOverdueAt", ">", 0).
+		Where("nextOverdueAt", "<=", now).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, taskDoc := range taskDocs {
+		task := Task{}
+		err = taskDoc.DataTo(&task)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// VerifyEscalationEmail marks the escalation email of the user as confirmed. It returns false when
+// the policy has another email by now, the confirmation was of the previous one
+func (f *FSTask) VerifyEscalationEmail(ctx context.Context, userID, email string) (bool, error) {
+	ref := escalationPolicyRef(f.fs, userID)
+	verified := false
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		verified = false
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		policy := EscalationPolicy{}
+		err = doc.DataTo(&policy)
+		if err != nil {
+			return err
+		}
+		if policy.EscalationEmail != email {
+			return nil
+		}
+		verified = true
+		// the tasks the unconfirmed escalation was left out of are rescheduled
+		return tx.Set(ref, map[string]interface{}{
+			"emailVerified": true,
+			"updatedAt":     time.Now().Unix(),
+			"rescheduled":   false,
+		}, firestore.MergeAll)
+	})
+	return verified, err
+}
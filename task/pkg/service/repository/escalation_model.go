@@ -0,0 +1,119 @@
+package repository
+
+import (
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	"sort"
+)
+
+// DocEscalationPolicy is the document of the escalation policy under users/{uid}/settings
+const DocEscalationPolicy = "escalation"
+
+// EscalationPolicy is what happens to the overdue tasks of the user. Overdue reminders are sent Intervals
+// seconds after the due time, the task is escalated to EscalationEmail EscalateAfter seconds after it,
+// once the owner of the address confirmed it. VerificationSentAt is when the last confirmation was sent.
+// The zero policy, which users who haven't set one get, does neither. Rescheduled tells whether the open tasks
+// of the user have the policy applied since it was last updated
+type EscalationPolicy struct {
+	UserID             string  `firestore:"userID"`
+	Intervals          []int64 `firestore:"intervals"`
+	EscalateAfter      int64   `firestore:"escalateAfter"`
+	EscalationEmail    string  `firestore:"escalationEmail"`
+	EmailVerified      bool    `firestore:"emailVerified"`
+	VerificationSentAt int64   `firestore:"verificationSentAt"`
+	UpdatedAt          int64   `firestore:"updatedAt"`
+	Rescheduled        bool    `firestore:"rescheduled"`
+}
+
+// NormalizeIntervals sorts the intervals from the earliest overdue reminder and drops duplicates
+func NormalizeIntervals(intervals []int64) []int64 {
+	normalized := make([]int64, 0, len(intervals))
+	seen := make(map[int64]bool)
+	for _, interval := range intervals {
+		if !seen[interval] {
+			seen[interval] = true
+			normalized = append(normalized, interval)
+		}
+	}
+	sort.Slice(normalized, func(i, j int) bool { return normalized[i] < normalized[j] })
+	return normalized
+}
+
+func (p EscalationPolicy) escalates() bool {
+	return p.EscalateAfter > 0 && p.EscalationEmail != "" && p.EmailVerified
+}
+
+// FireOverdue applies the policy to the task at now. It marks the overdue reminders and the escalation due
+// at now as sent and schedules the next one. Overdue reminders due together are sent once, as the latest one,
+// interval is the one to send, notice is false when there is none. escalate tells whether to escalate the task
+func (p EscalationPolicy) FireOverdue(t *Task, now int64) (interval int64, notice, escalate bool) {
+	if t.Time <= 0 || t.Completed || t.StopNagging {
+		t.NextOverdueAt = 0
+		return 0, false, false
+	}
+	overdue := now - t.Time
+	for int(t.OverdueNotices) < len(p.Intervals) && p.Intervals[t.OverdueNotices] <= overdue {
+		interval = p.Intervals[t.OverdueNotices]
+		notice = true
+		t.OverdueNotices++
+	}
+	if p.escalates() && !t.Escalated && p.EscalateAfter <= overdue {
+		t.Escalated = true
+		escalate = true
+	}
+	t.NextOverdueAt = 0
+	if int(t.OverdueNotices) < len(p.Intervals) {
+		t.NextOverdueAt = t.Time + p.Intervals[t.OverdueNotices]
+	}
+	if p.escalates() && !t.Escalated && (t.NextOverdueAt == 0 || t.Time+p.EscalateAfter < t.NextOverdueAt) {
+		t.NextOverdueAt = t.Time + p.EscalateAfter
+	}
+	return interval, notice, escalate
+}
+
+// ScheduleOverdue has the escalation policy applied to the task once it's due. The overdue reminders
+// and the escalation sent before the write are kept unless the due time moved. Tasks due already get
+// the policy applied right away, completed tasks, undated ones and the ones not to nag get 0
+func (t *Task) ScheduleOverdue(before *Task, now int64) {
+	t.OverdueNotices, t.Escalated = 0, false
+	if before != nil && before.Time == t.Time {
+		t.OverdueNotices, t.Escalated = before.OverdueNotices, before.Escalated
+	}
+	t.NextOverdueAt = 0
+	if t.Time <= 0 || t.Completed || t.StopNagging {
+		return
+	}
+	t.NextOverdueAt = t.Time
+	if t.NextOverdueAt < now {
+		t.NextOverdueAt = now
+	}
+}
+
+// CatchUpOverdue schedules the task like ScheduleOverdue, except that the overdue reminders and the escalation
+// the task is past already at now count as sent. Backfills and policy changes use it, so that old overdue tasks
+// get the steps of the policy still ahead of them instead of all the past ones at once
+func (p EscalationPolicy) CatchUpOverdue(t *Task, before *Task, now int64) {
+	t.ScheduleOverdue(before, now)
+	if t.NextOverdueAt == 0 || t.Time >= now {
+		return
+	}
+	p.FireOverdue(t, now)
+}
+
+func (p EscalationPolicy) ToApi() *v1.EscalationPolicy {
+	return &v1.EscalationPolicy{
+		Intervals:               p.Intervals,
+		EscalateAfter:           p.EscalateAfter,
+		EscalationEmail:         p.EscalationEmail,
+		EscalationEmailVerified: p.EmailVerified,
+	}
+}
+
+// EscalationPolicyFromMsg leaves the verification out, only the confirmation link sets it
+func EscalationPolicyFromMsg(userID string, msg *v1.EscalationPolicy) EscalationPolicy {
+	return EscalationPolicy{
+		UserID:          userID,
+		Intervals:       msg.Intervals,
+		EscalateAfter:   msg.EscalateAfter,
+		EscalationEmail: msg.EscalationEmail,
+	}
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type EscalationModelTestSuite struct {
+	suite.Suite
+}
+
+func (s *EscalationModelTestSuite) TestFireOverdue() {
+	policy := EscalationPolicy{
+		Intervals:       []int64{3600, 14400, 86400},
+		EscalateAfter:   7200,
+		EscalationEmail: "boss@tst.com",
+		EmailVerified:   true,
+	}
+	task := Task{TaskID: "tid1", Time: 1658102400, NextOverdueAt: 1658102400}
+	candidates := []struct {
+		now              int64
+		expectedInterval int64
+		expectedNotice   bool
+		expectedEscalate bool
+		expectedNext     int64
+	}{
+		// due now, nothing to send yet
+		{now: 1658102400, expectedNext: 1658102400 + 3600},
+		{now: 1658102400 + 3600, expectedInterval: 3600, expectedNotice: true, expectedNext: 1658102400 + 7200},
+		{now: 1658102400 + 7200, expectedEscalate: true, expectedNext: 1658102400 + 14400},
+		// late runs send the latest overdue reminder once
+		{now: 1658102400 + 90000, expectedInterval: 86400, expectedNotice: true, expectedNext: 0},
+		{now: 1658102400 + 180000, expectedNext: 0},
+	}
+	for i, c := range candidates {
+		interval, notice, escalate := policy.FireOverdue(&task, c.now)
+		s.Equalf(c.expectedInterval, interval, "candidate %d", i+1)
+		s.Equalf(c.expectedNotice, notice, "candidate %d", i+1)
+		s.Equalf(c.expectedEscalate, escalate, "candidate %d", i+1)
+		s.Equalf(c.expectedNext, task.NextOverdueAt, "candidate %d", i+1)
+	}
+	s.Equal(int32(3), task.OverdueNotices)
+	s.True(task.Escalated)
+}
+
+func (s *EscalationModelTestSuite) TestFireOverdueStopped() {
+	policy := EscalationPolicy{Intervals: []int64{3600}, EscalateAfter: 3600, EscalationEmail: "boss@tst.com",
+		EmailVerified: true}
+	candidates := []Task{
+		{Time: 1658102400, StopNagging: true},
+		{Time: 1658102400, Completed: true},
+		{Time: 0},
+	}
+	for i, task := range candidates {
+		task.NextOverdueAt = 1658102400
+		_, notice, escalate := policy.FireOverdue(&task, 1658102400+86400)
+		s.Falsef(notice, "candidate %d", i+1)
+		s.Falsef(escalate, "candidate %d", i+1)
+		s.Equalf(int64(0), task.NextOverdueAt, "candidate %d", i+1)
+	}
+	// the zero policy is done with the task once it's due
+	task := Task{Time: 1658102400, NextOverdueAt: 1658102400}
+	_, notice, escalate := EscalationPolicy{}.FireOverdue(&task, 1658102400)
+	s.False(notice)
+	s.False(escalate)
+	s.Equal(int64(0), task.NextOverdueAt)
+}
+
+func (s *EscalationModelTestSuite) TestScheduleOverdue() {
+	before := &Task{Time: 1658102400, OverdueNotices: 2, Escalated: true}
+	candidates := []struct {
+		before            *Task
+		task              Task
+		expectedNext      int64
+		expectedSent      int32
+		expectedEscalated bool
+	}{
+		{task: Task{Time: 1658102400}, expectedNext: 1658102400},
+		// tasks due already are checked right away
+		{task: Task{Time: 1658016000}, expectedNext: 1658098800},
+		{before: before, task: Task{Time: 1658102400}, expectedNext: 1658102400, expectedSent: 2, expectedEscalated: true},
+		// moving the due time starts over
+		{before: before, task: Task{Time: 1658188800}, expectedNext: 1658188800},
+		{before: before, task: Task{Time: 1658102400, Completed: true}, expectedSent: 2, expectedEscalated: true},
+		{task: Task{Time: 1658102400, StopNagging: true}},
+		{task: Task{}},
+	}
+	for i, c := range candidates {
+		c.task.ScheduleOverdue(c.before, 1658098800)
+		s.Equalf(c.expectedNext, c.task.NextOverdueAt, "candidate %d", i+1)
+		s.Equalf(c.expectedSent, c.task.OverdueNotices, "candidate %d", i+1)
+		s.Equalf(c.expectedEscalated, c.task.Escalated, "candidate %d", i+1)
+	}
+}
+
+func (s *EscalationModelTestSuite) TestCatchUpOverdue() {
+	policy := EscalationPolicy{
+		Intervals:       []int64{3600, 14400, 86400},
+		EscalateAfter:   7200,
+		EscalationEmail: "boss@tst.com",
+		EmailVerified:   true,
+	}
+	candidates := []struct {
+		task              Task
+		expectedNext      int64
+		expectedSent      int32
+		expectedEscalated bool
+	}{
+		{task: Task{Time: 1658102400}, expectedNext: 1658102400},
+		// the steps past already are skipped, not sent at once
+		{task: Task{Time: 1658098800 - 10800}, expectedNext: 1658098800 + 3600, expectedSent: 1, expectedEscalated: true},
+		{task: Task{Time: 1658098800 - 172800}, expectedSent: 3, expectedEscalated: true},
+		{task: Task{Time: 1658098800 - 10800, Completed: true}},
+	}
+	for i, c := range candidates {
+		policy.CatchUpOverdue(&c.task, nil, 1658098800)
+		s.Equalf(c.expectedNext, c.task.NextOverdueAt, "candidate %d", i+1)
+		s.Equalf(c.expectedSent, c.task.OverdueNotices, "candidate %d", i+1)
+		s.Equalf(c.expectedEscalated, c.task.Escalated, "candidate %d", i+1)
+	}
+}
+
+func (s *EscalationModelTestSuite) TestNewEscalationDelivery() {
+	task := Task{TaskID: "tid1", UserID: "1", UserEmail: "example1@tst.com", Name: "task1", Time: 1658102400}
+	overdue := NewReminderDelivery(task, -3600, 1658106000)
+	s.Equal("tid1_1658102400_-3600", overdue.DeliveryID)
+	s.Equal("overdue", overdue.Kind)
+	s.Equal("example1@tst.com", overdue.Email)
+
+	escalation := NewEscalationDelivery(task, "boss@tst.com", 1658109600)
+	s.Equal("tid1_1658102400_escalation", escalation.DeliveryID)
+	s.Equal("escalation", escalation.Kind)
+	s.Equal("boss@tst.com", escalation.Email)
+	s.Equal("example1@tst.com", escalation.OwnerEmail)
+	s.Equal(int64(1658109600), escalation.NextAttemptAt)
+}
+
+func (s *EscalationModelTestSuite) TestNormalizeIntervals() {
+	s.Equal([]int64{3600, 14400, 86400}, NormalizeIntervals([]int64{86400, 3600, 14400, 3600}))
+	s.Equal([]int64{}, NormalizeIntervals(nil))
+}
+
+func (s *EscalationModelTestSuite) TestFireOverdueUnverified() {
+	policy := EscalationPolicy{Intervals: []int64{3600}, EscalateAfter: 7200, EscalationEmail: "boss@tst.com"}
+	task := Task{Time: 1658102400, NextOverdueAt: 1658102400}
+	// nothing is escalated to an email nobody confirmed
+	_, notice, escalate := policy.FireOverdue(&task, 1658102400+86400)
+	s.True(notice)
+	s.False(escalate)
+	s.False(task.Escalated)
+	s.Equal(int64(0), task.NextOverdueAt)
+}
+
+func TestEscalationModelTestSuite(t *testing.T) {
+	suite.Run(t, new(EscalationModelTestSuite))
+}
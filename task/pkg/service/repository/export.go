@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type FSExportInterface interface {
+	Create(ctx context.Context, job ExportJob) (ExportJob, error)
+	Get(ctx context.Context, userID, jobID string) (ExportJob, error)
+	Update(ctx context.Context, job ExportJob) error
+	FailStale(ctx context.Context, before int64, reason string) (int, error)
+}
+
+// FSExport stores export jobs in a sub collection of the user
+type FSExport struct {
+	fs     *firestore.CollectionRef
+	client *firestore.Client
+}
+
+func NewFSExport(fs *firestore.CollectionRef, client *firestore.Client) *FSExport {
+	return &FSExport{
+		fs:     fs,
+		client: client,
+	}
+}
+
+func (f *FSExport) Create(ctx context.Context, job ExportJob) (ExportJob, error) {
+	docRef := f.fs.Doc(job.UserID).Collection(CollectionExports).NewDoc()
+	job.JobID = docRef.ID
+	job.CreatedAt = time.Now().Unix()
+	_, err := docRef.Set(ctx, job)
+	if err != nil {
+		return ExportJob{}, err
+	}
+	return job, nil
+}
+
+func (f *FSExport) Get(ctx context.Context, userID, jobID string) (ExportJob, error) {
+	doc, err := f.fs.Doc(userID).Collection(CollectionExports).Doc(jobID).Get(ctx)
+	if err != nil {
+		return ExportJob{}, err
+	}
+	job := ExportJob{}
+	err = doc.DataTo(&job)
+	if err != nil {
+		return ExportJob{}, err
+	}
+	return job, nil
+}
+
+func (f *FSExport) Update(ctx context.Context, job ExportJob) error {
+	_, err := f.fs.Doc(job.UserID).Collection(CollectionExports).Doc(job.JobID).Set(ctx, job)
+	return err
+}
+
+// FailStale marks the jobs created before that are still pending or running as failed with the reason.
+// A job finished meanwhile is left alone. The collection group query needs a composite index
+// on status and createdAt for the exports collection group
+func (f *FSExport) FailStale(ctx context.Context, before int64, reason string) (int, error) {
+	docs, err := f.client.CollectionGroup(CollectionExports).
+		Where("status", "in", []string{v1.ExportStatus_PENDING.String(), v1.ExportStatus_RUNNING.String()}).
+		Where("createdAt", "<", before).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	failed := 0
+	for _, doc := range docs {
+		stale := false
+		err = f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			stale = false
+			current, err := tx.Get(doc.Ref)
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			job := ExportJob{}
+			err = current.DataTo(&job)
+			if err != nil {
+				return err
+			}
+			if job.Status != v1.ExportStatus_PENDING.String() && job.Status != v1.ExportStatus_RUNNING.String() {
+				return nil
+			}
+			stale = true
+			return tx.Set(doc.Ref, map[string]interface{}{
+				"status":     v1.ExportStatus_FAILED.String(),
+				"error":      reason,
+				"finishedAt": time.Now().Unix(),
+			}, firestore.MergeAll)
+		})
+		if err != nil {
+			return failed, err
+		}
+		if stale {
+			failed++
+		}
+	}
+	return failed, nil
+}
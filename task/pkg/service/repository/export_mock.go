@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type FSExportMock struct {
+	mock.Mock
+}
+
+func NewMockExportRepo() *FSExportMock {
+	return &FSExportMock{}
+}
+
+func (m *FSExportMock) Create(ctx context.Context, job ExportJob) (ExportJob, error) {
+	args := m.Called(ctx, job)
+	return args.Get(0).(ExportJob), args.Error(1)
+}
+
+func (m *FSExportMock) Get(ctx context.Context, userID, jobID string) (ExportJob, error) {
+	args := m.Called(ctx, userID, jobID)
+	return args.Get(0).(ExportJob), args.Error(1)
+}
+
+func (m *FSExportMock) Update(ctx context.Context, job ExportJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *FSExportMock) FailStale(ctx context.Context, before int64, reason string) (int, error) {
+	args := m.Called(ctx, before, reason)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+)
+
+const (
+	CollectionExports = "exports"
+)
+
+// ExportJob tracks a background export of user's tasks into the blob store.
+// Format and Status hold names of the v1.ExportFormat and v1.ExportStatus values
+type ExportJob struct {
+	JobID      string `firestore:"jobID"`
+	UserID     string `firestore:"userID"`
+	Format     string `firestore:"format"`
+	Status     string `firestore:"status"`
+	CreatedAt  int64  `firestore:"createdAt"`
+	FinishedAt int64  `firestore:"finishedAt"`
+	TaskCount  int32  `firestore:"taskCount"`
+	BlobKey    string `firestore:"blobKey"`
+	Error      string `firestore:"error"`
+}
+
+func (j ExportJob) ToApi() *v1.ExportJob {
+	return &v1.ExportJob{
+		JobId:      j.JobID,
+		Format:     v1.ExportFormat(v1.ExportFormat_value[j.Format]),
+		Status:     v1.ExportStatus(v1.ExportStatus_value[j.Status]),
+		CreatedAt:  j.CreatedAt,
+		FinishedAt: j.FinishedAt,
+		TaskCount:  j.TaskCount,
+		Error:      j.Error,
+	}
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+const (
+	CollectionIdempotency = "idempotency"
+	// IdempotencyTTL is how long a key is remembered, expired records are ignored and removed
+	// by the Firestore TTL policy on expireAt
+	IdempotencyTTL = time.Hour * 24
+)
+
+type FSIdempotencyInterface interface {
+	Reserve(ctx context.Context, userID, key string, record IdempotencyRecord,
+		lease time.Duration) (IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, userID, key string, response []byte) error
+	Release(ctx context.Context, userID, key string) error
+}
+
+// IdempotencyRecord remembers the request made with an idempotency key, kept under users/{uid}/idempotency/{key}.
+// Response is empty until the request completes, LockedUntil is the end of the lease of the request in progress
+type IdempotencyRecord struct {
+	Method      string    `firestore:"method"`
+	RequestHash string    `firestore:"requestHash"`
+	Response    []byte    `firestore:"response"`
+	Completed   bool      `firestore:"completed"`
+	LockedUntil time.Time `firestore:"lockedUntil"`
+	CreatedAt   int64     `firestore:"createdAt"`
+	ExpireAt    time.Time `firestore:"expireAt"`
+}
+
+type FSIdempotency struct {
+	fs     *firestore.CollectionRef
+	client *firestore.Client
+}
+
+func NewFSIdempotency(fs *firestore.CollectionRef, client *firestore.Client) *FSIdempotency {
+	return &FSIdempotency{
+		fs:     fs,
+		client: client,
+	}
+}
+
+// Reserve stores the record leased for the request unless the key is in use already. It returns true
+// when the record was stored, otherwise the record the key was used with first. A retry of the same request
+// takes over the reservation once its lease runs out, the request holding it may have never finished
+func (f *FSIdempotency) Reserve(ctx context.Context, userID, key string, record IdempotencyRecord,
+	lease time.Duration) (IdempotencyRecord, bool, error) {
+	docRef := f.fs.Doc(userID).Collection(CollectionIdempotency).Doc(key)
+	var existing IdempotencyRecord
+	reserved := false
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		reserved = false
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		now := time.Now()
+		if err == nil {
+			existing = IdempotencyRecord{}
+			err = doc.DataTo(&existing)
+			if err != nil {
+				return err
+			}
+			// TTL policy deletes expired records with a delay
+			abandoned := !existing.Completed && !existing.LockedUntil.After(now) &&
+				existing.RequestHash == record.RequestHash
+			if existing.ExpireAt.After(now) && !abandoned {
+				return nil
+			}
+		}
+		record.LockedUntil = now.Add(lease)
+		record.CreatedAt = now.Unix()
+		record.ExpireAt = now.Add(IdempotencyTTL)
+		reserved = true
+		return tx.Set(docRef, record)
+	})
+	if err != nil {
+		return IdempotencyRecord{}, false, err
+	}
+	if reserved {
+		return record, true, nil
+	}
+	return existing, false, nil
+}
+
+// Complete stores the response replayed to the requests repeated with the key
+func (f *FSIdempotency) Complete(ctx context.Context, userID, key string, response []byte) error {
+	_, err := f.fs.Doc(userID).Collection(CollectionIdempotency).Doc(key).Update(ctx, []firestore.Update{
+		{Path: "response", Value: response},
+		{Path: "completed", Value: true},
+	})
+	return err
+}
+
+// Release forgets the key of a failed request, so it can be retried with the same key
+func (f *FSIdempotency) Release(ctx context.Context, userID, key string) error {
+	_, err := f.fs.Doc(userID).Collection(CollectionIdempotency).Doc(key).Delete(ctx)
+	return err
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type FSIdempotencyMock struct {
+	mock.Mock
+}
+
+func NewMockIdempotencyRepo() *FSIdempotencyMock {
+	return &FSIdempotencyMock{}
+}
+
+func (m *FSIdempotencyMock) Reserve(ctx context.Context, userID, key string,
+	record IdempotencyRecord, lease time.Duration) (IdempotencyRecord, bool, error) {
+	args := m.Called(ctx, userID, key, record, lease)
+	return args.Get(0).(IdempotencyRecord), args.Bool(1), args.Error(2)
+}
+
+func (m *FSIdempotencyMock) Complete(ctx context.Context, userID, key string, response []byte) error {
+	args := m.Called(ctx, userID, key, response)
+	return args.Error(0)
+}
+
+func (m *FSIdempotencyMock) Release(ctx context.Context, userID, key string) error {
+	args := m.Called(ctx, userID, key)
+	return args.Error(0)
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type FSLeaseInterface interface {
+	Acquire(ctx context.Context, name, holder string, ttl time.Duration) (Lease, bool, error)
+	Release(ctx context.Context, name, holder string) error
+}
+
+// FSLease keeps the leases instances elect their leaders with
+type FSLease struct {
+	fs     *firestore.CollectionRef
+	client *firestore.Client
+}
+
+func NewFSLease(fs *firestore.CollectionRef, client *firestore.Client) *FSLease {
+	return &FSLease{
+		fs:     fs,
+		client: client,
+	}
+}
+
+// Acquire acquires or renews the lease for the holder, see Lease.Acquire. It returns the lease as stored,
+// so the holder of a lease held by someone else is known too
+func (f *FSLease) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (Lease, bool, error) {
+	docRef := f.fs.Doc(name)
+	var lease Lease
+	acquired := false
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		lease = Lease{Name: name}
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			err = doc.DataTo(&lease)
+			if err != nil {
+				return err
+			}
+		}
+		lease, acquired = lease.Acquire(holder, time.Now(), ttl)
+		if !acquired {
+			return nil
+		}
+		return tx.Set(docRef, lease)
+	})
+	if err != nil {
+		return Lease{}, false, err
+	}
+	return lease, acquired, nil
+}
+
+// Release expires the lease right away if the holder still holds it, so another instance takes over
+// without waiting for the expiry
+func (f *FSLease) Release(ctx context.Context, name, holder string) error {
+	docRef := f.fs.Doc(name)
+	return f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		lease := Lease{}
+		err = doc.DataTo(&lease)
+		if err != nil {
+			return err
+		}
+		if lease.Holder != holder {
+			return nil
+		}
+		return tx.Update(docRef, []firestore.Update{{Path: "expiresAt", Value: time.Now()}})
+	})
+}
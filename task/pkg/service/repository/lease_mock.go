@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type FSLeaseMock struct {
+	mock.Mock
+}
+
+func NewMockLeaseRepo() *FSLeaseMock {
+	return &FSLeaseMock{}
+}
+
+func (m *FSLeaseMock) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (Lease, bool, error) {
+	args := m.Called(ctx, name, holder, ttl)
+	return args.Get(0).(Lease), args.Bool(1), args.Error(2)
+}
+
+func (m *FSLeaseMock) Release(ctx context.Context, name, holder string) error {
+	args := m.Called(ctx, name, holder)
+	return args.Error(0)
+}
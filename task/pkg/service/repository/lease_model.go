@@ -0,0 +1,34 @@
+package repository
+
+import "time"
+
+const (
+	CollectionLeases = "leases"
+)
+
+// Lease is held by one instance at a time until it expires, kept under leases/{name}. Term grows every time
+// another instance takes the lease over, so a term names a single leadership
+type Lease struct {
+	Name       string    `firestore:"name"`
+	Holder     string    `firestore:"holder"`
+	Term       int64     `firestore:"term"`
+	AcquiredAt time.Time `firestore:"acquiredAt"`
+	RenewedAt  time.Time `firestore:"renewedAt"`
+	ExpiresAt  time.Time `firestore:"expiresAt"`
+}
+
+// Acquire returns the lease after the holder tried to acquire it at now. The holder renews its own lease
+// and takes over a lease nobody holds or that expired, ok is false when someone else holds it
+func (l Lease) Acquire(holder string, now time.Time, ttl time.Duration) (lease Lease, ok bool) {
+	if l.Holder != holder && l.Holder != "" && now.Before(l.ExpiresAt) {
+		return l, false
+	}
+	if l.Holder != holder {
+		l.Holder = holder
+		l.Term++
+		l.AcquiredAt = now
+	}
+	l.RenewedAt = now
+	l.ExpiresAt = now.Add(ttl)
+	return l, true
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type LeaseModelTestSuite struct {
+	suite.Suite
+}
+
+func (s *LeaseModelTestSuite) TestAcquire() {
+	now := time.Date(2022, 7, 18, 8, 0, 0, 0, time.UTC)
+	lease, ok := Lease{Name: "scheduler"}.Acquire("a", now, 30*time.Second)
+	s.True(ok)
+	s.Equal(Lease{
+		Name:       "scheduler",
+		Holder:     "a",
+		Term:       1,
+		AcquiredAt: now,
+		RenewedAt:  now,
+		ExpiresAt:  now.Add(30 * time.Second),
+	}, lease)
+
+	// others wait for the expiry
+	_, ok = lease.Acquire("b", now.Add(10*time.Second), 30*time.Second)
+	s.False(ok)
+
+	// the holder renews its term
+	lease, ok = lease.Acquire("a", now.Add(10*time.Second), 30*time.Second)
+	s.True(ok)
+	s.Equal(int64(1), lease.Term)
+	s.Equal(now, lease.AcquiredAt)
+	s.Equal(now.Add(40*time.Second), lease.ExpiresAt)
+
+	// an expired lease is taken over
+	lease, ok = lease.Acquire("b", now.Add(40*time.Second), 30*time.Second)
+	s.True(ok)
+	s.Equal("b", lease.Holder)
+	s.Equal(int64(2), lease.Term)
+	s.Equal(now.Add(40*time.Second), lease.AcquiredAt)
+}
+
+func TestLeaseModelTestSuite(t *testing.T) {
+	suite.Run(t, new(LeaseModelTestSuite))
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type FSNotificationInterface interface {
+	GetPreferences(ctx context.Context, userID string) (NotificationPreferences, error)
+	SetPreferences(ctx context.Context, prefs NotificationPreferences) (NotificationPreferences, error)
+}
+
+// FSNotification stores the notification preferences in the settings sub collection of the user
+type FSNotification struct {
+	fs *firestore.CollectionRef
+}
+
+func NewFSNotification(fs *firestore.CollectionRef) *FSNotification {
+	return &FSNotification{
+		fs: fs,
+	}
+}
+
+func (f *FSNotification) preferencesRef(userID string) *firestore.DocumentRef {
+	return f.fs.Doc(userID).Collection(CollectionSettings).Doc(DocNotificationSettings)
+}
+
+// GetPreferences returns DefaultNotificationPreferences for users who haven't set any
+func (f *FSNotification) GetPreferences(ctx context.Context, userID string) (NotificationPreferences, error) {
+	doc, err := f.preferencesRef(userID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return DefaultNotificationPreferences(userID), nil
+	}
+	if err != nil {
+		return NotificationPreferences{}, err
+	}
+	prefs := NotificationPreferences{}
+	err = doc.DataTo(&prefs)
+	if err != nil {
+		return NotificationPreferences{}, err
+	}
+	return prefs, nil
+}
+
+func (f *FSNotification) SetPreferences(ctx context.Context, prefs NotificationPreferences) (NotificationPreferences, error) {
+	prefs.UpdatedAt = time.Now().Unix()
+	_, err := f.preferencesRef(prefs.UserID).Set(ctx, prefs)
+	if err != nil {
+		return NotificationPreferences{}, err
+	}
+	return prefs, nil
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type FSNotificationMock struct {
+	mock.Mock
+}
+
+func NewMockNotificationRepo() *FSNotificationMock {
+	return &FSNotificationMock{}
+}
+
+func (m *FSNotificationMock) GetPreferences(ctx context.Context, userID string) (NotificationPreferences, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(NotificationPreferences), args.Error(1)
+}
+
+func (m *FSNotificationMock) SetPreferences(ctx context.Context, prefs NotificationPreferences) (NotificationPreferences, error) {
+	args := m.Called(ctx, prefs)
+	return args.Get(0).(NotificationPreferences), args.Error(1)
+}
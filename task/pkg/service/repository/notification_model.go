@@ -0,0 +1,180 @@
+package repository
+
+import (
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
+	"sort"
+	"time"
+)
+
+const (
+	// DocNotificationSettings is the document of the notification preferences under users/{uid}/settings
+	DocNotificationSettings = "notifications"
+)
+
+// channelTypes maps the API channel types onto the channels of the notify package
+var channelTypes = map[v1.NotificationChannelType]string{
+	v1.NotificationChannelType_EMAIL:   notify.ChannelEmail,
+	v1.NotificationChannelType_WEBHOOK: notify.ChannelWebhook,
+	v1.NotificationChannelType_SLACK:   notify.ChannelSlack,
+	v1.NotificationChannelType_SMS:     notify.ChannelSMS,
+}
+
+// NotificationChannel is where a channel delivers to, an empty email target is the email of the task owner
+type NotificationChannel struct {
+	Enabled bool   `firestore:"enabled"`
+	Target  string `firestore:"target"`
+	Secret  string `firestore:"secret"`
+}
+
+// QuietHours are HH:MM in the time zone of the preferences, the window spans midnight when End is before Start
+type QuietHours struct {
+	Start string `firestore:"start"`
+	End   string `firestore:"end"`
+}
+
+// NotificationPreferences keeps the channels of the user by the notify channel names,
+// the locale and time zone format the times in the emails. Events hold names
+// of the v1.NotificationEventType values the user gets, empty is all of them.
+// None turns all of them off, escalations to other addresses aren't affected
+type NotificationPreferences struct {
+	UserID     string                         `firestore:"userID"`
+	Channels   map[string]NotificationChannel `firestore:"channels"`
+	Locale     string                         `firestore:"locale"`
+	TimeZone   string                         `firestore:"timeZone"`
+	QuietHours QuietHours                     `firestore:"quietHours"`
+	Events     []string                       `firestore:"events"`
+	None       bool                           `firestore:"none"`
+	UpdatedAt  int64                          `firestore:"updatedAt"`
+}
+
+// DefaultNotificationPreferences are the preferences of users who haven't set any, reminders go to their email
+func DefaultNotificationPreferences(userID string) NotificationPreferences {
+	return NotificationPreferences{
+		UserID: userID,
+		Channels: map[string]NotificationChannel{
+			notify.ChannelEmail: {Enabled: true},
+		},
+	}
+}
+
+// Notifies tells whether the user gets the notifications of the event type
+func (p NotificationPreferences) Notifies(event v1.NotificationEventType) bool {
+	if p.None {
+		return false
+	}
+	if len(p.Events) == 0 {
+		return true
+	}
+	for _, e := range p.Events {
+		if e == event.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// Location returns the time zone of the user, UTC when it's unset or unknown
+func (p NotificationPreferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.TimeZone)
+	if err != nil || p.TimeZone == "" {
+		return time.UTC
+	}
+	return loc
+}
+
+// QuietUntil returns the end of the quiet hours now falls in, ok is false outside of them
+func (p NotificationPreferences) QuietUntil(now time.Time) (until time.Time, ok bool) {
+	start, startOk := minuteOfDay(p.QuietHours.Start)
+	end, endOk := minuteOfDay(p.QuietHours.End)
+	if !startOk || !endOk || start == end {
+		return time.Time{}, false
+	}
+	local := now.In(p.Location())
+	minute := local.Hour()*60 + local.Minute()
+	endToday := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, local.Location())
+	switch {
+	case start < end && minute >= start && minute < end:
+		return endToday, true
+	case start > end && minute < end:
+		return endToday, true
+	case start > end && minute >= start:
+		return time.Date(local.Year(), local.Month(), local.Day()+1, end/60, end%60, 0, 0, local.Location()), true
+	}
+	return time.Time{}, false
+}
+
+// minuteOfDay parses HH:MM
+func minuteOfDay(clock string) (int, bool) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// ChannelName returns the notify channel of the API channel type
+func ChannelName(channelType v1.NotificationChannelType) string {
+	return channelTypes[channelType]
+}
+
+// ToApi leaves the secrets out, channels are ordered by their type
+func (p NotificationPreferences) ToApi() *v1.NotificationPreferences {
+	prefs := &v1.NotificationPreferences{
+		Locale:   p.Locale,
+		TimeZone: p.TimeZone,
+		QuietHours: &v1.QuietHours{
+			Start: p.QuietHours.Start,
+			End:   p.QuietHours.End,
+		},
+		NotifyNone: p.None,
+	}
+	for _, e := range p.Events {
+		prefs.Events = append(prefs.Events, v1.NotificationEventType(v1.NotificationEventType_value[e]))
+	}
+	for channelType, name := range channelTypes {
+		channel, ok := p.Channels[name]
+		if !ok {
+			continue
+		}
+		prefs.Channels = append(prefs.Channels, &v1.NotificationChannel{
+			Type:      channelType,
+			Enabled:   channel.Enabled,
+			Target:    channel.Target,
+			HasSecret: channel.Secret != "",
+		})
+	}
+	sort.Slice(prefs.Channels, func(i, j int) bool { return prefs.Channels[i].Type < prefs.Channels[j].Type })
+	return prefs
+}
+
+// NotificationPreferencesFromMsg keeps the secrets of prev the message leaves empty
+func NotificationPreferencesFromMsg(userID string, msg *v1.NotificationPreferences, prev NotificationPreferences) NotificationPreferences {
+	prefs := NotificationPreferences{
+		UserID:   userID,
+		Channels: make(map[string]NotificationChannel),
+		Locale:   msg.Locale,
+		TimeZone: msg.TimeZone,
+		QuietHours: QuietHours{
+			Start: msg.QuietHours.GetStart(),
+			End:   msg.QuietHours.GetEnd(),
+		},
+		None: msg.NotifyNone,
+	}
+	for _, e := range msg.Events {
+		prefs.Events = append(prefs.Events, e.String())
+	}
+	for _, channel := range msg.Channels {
+		name := ChannelName(channel.Type)
+		secret := channel.Secret
+		if secret == "" {
+			secret = prev.Channels[name].Secret
+		}
+		prefs.Channels[name] = NotificationChannel{
+			Enabled: channel.Enabled,
+			Target:  channel.Target,
+			Secret:  secret,
+		}
+	}
+	return prefs
+}
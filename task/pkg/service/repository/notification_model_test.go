@@ -0,0 +1,74 @@
+package repository
+
+import (
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type NotificationModelTestSuite struct {
+	suite.Suite
+}
+
+func (s *NotificationModelTestSuite) TestQuietUntil() {
+	prague := mustLoadLocation("Europe/Prague")
+	candidates := []struct {
+		quietHours QuietHours
+		now        time.Time
+		expected   time.Time
+		ok         bool
+	}{
+		{
+			quietHours: QuietHours{Start: "22:00", End: "07:00"},
+			now:        time.Date(2022, 7, 18, 23, 30, 0, 0, prague),
+			expected:   time.Date(2022, 7, 19, 7, 0, 0, 0, prague),
+			ok:         true,
+		},
+		{
+			quietHours: QuietHours{Start: "22:00", End: "07:00"},
+			now:        time.Date(2022, 7, 18, 6, 59, 0, 0, prague),
+			expected:   time.Date(2022, 7, 18, 7, 0, 0, 0, prague),
+			ok:         true,
+		},
+		{quietHours: QuietHours{Start: "22:00", End: "07:00"}, now: time.Date(2022, 7, 18, 7, 0, 0, 0, prague)},
+		{
+			quietHours: QuietHours{Start: "12:00", End: "13:30"},
+			now:        time.Date(2022, 7, 18, 12, 0, 0, 0, prague),
+			expected:   time.Date(2022, 7, 18, 13, 30, 0, 0, prague),
+			ok:         true,
+		},
+		{quietHours: QuietHours{Start: "12:00", End: "13:30"}, now: time.Date(2022, 7, 18, 11, 59, 0, 0, prague)},
+		// the window is in the time zone of the user, 22:30 in Prague is 20:30 UTC
+		{quietHours: QuietHours{Start: "22:00", End: "07:00"}, now: time.Date(2022, 7, 18, 20, 30, 0, 0, time.UTC),
+			expected: time.Date(2022, 7, 19, 7, 0, 0, 0, prague), ok: true},
+		{quietHours: QuietHours{}, now: time.Date(2022, 7, 18, 23, 30, 0, 0, prague)},
+	}
+	for i, c := range candidates {
+		prefs := NotificationPreferences{TimeZone: "Europe/Prague", QuietHours: c.quietHours}
+		until, ok := prefs.QuietUntil(c.now)
+		s.Equalf(c.ok, ok, "candidate %d", i+1)
+		s.Truef(c.expected.Equal(until), "candidate %d: %s", i+1, until)
+	}
+}
+
+func (s *NotificationModelTestSuite) TestNotifies() {
+	s.True(NotificationPreferences{}.Notifies(v1.NotificationEventType_DIGEST_EVENT))
+	prefs := NotificationPreferencesFromMsg("1", &v1.NotificationPreferences{
+		Events:     []v1.NotificationEventType{v1.NotificationEventType_REMINDER_EVENT},
+		QuietHours: &v1.QuietHours{Start: "22:00", End: "07:00"},
+	}, NotificationPreferences{})
+	s.True(prefs.Notifies(v1.NotificationEventType_REMINDER_EVENT))
+	s.False(prefs.Notifies(v1.NotificationEventType_DIGEST_EVENT))
+	s.Equal(QuietHours{Start: "22:00", End: "07:00"}, prefs.QuietHours)
+	s.Equal([]v1.NotificationEventType{v1.NotificationEventType_REMINDER_EVENT}, prefs.ToApi().Events)
+	// no events with none set are none of them, not all
+	prefs = NotificationPreferencesFromMsg("1", &v1.NotificationPreferences{NotifyNone: true}, NotificationPreferences{})
+	s.False(prefs.Notifies(v1.NotificationEventType_REMINDER_EVENT))
+	s.False(prefs.Notifies(v1.NotificationEventType_DIGEST_EVENT))
+	s.True(prefs.ToApi().NotifyNone)
+}
+
+func TestNotificationModelTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationModelTestSuite))
+}
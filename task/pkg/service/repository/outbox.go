@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"github.com/jakubjano/todolist/task/pkg/events"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// recordEvent adds the domain event of the task write into the transaction.
+// before is nil for newly created tasks and after is nil for deleted tasks
+func (f *FSTask) recordEvent(tx *firestore.Transaction, userID, taskID string, before, after *Task) error {
+	eventType := events.TaskUpdated
+	switch {
+	case before == nil:
+		eventType = events.TaskCreated
+	case after == nil:
+		eventType = events.TaskDeleted
+	}
+	data := events.TaskData{TaskID: taskID}
+	if after != nil {
+		task, err := protojson.Marshal(ToApi(*after))
+		if err != nil {
+			return err
+		}
+		data.Task = task
+		data.Completion = after.Completed && (before == nil || !before.Completed)
+	}
+	event, err := events.New(eventType, userID, taskID, data)
+	if err != nil {
+		return err
+	}
+	return f.outbox.AddTx(tx, event)
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+func (f *FSTask) reminderSettingsRef(userID string) *firestore.DocumentRef {
+	return f.fs.Doc(userID).Collection(CollectionSettings).Doc(DocReminderSettings)
+}
+
+// GetReminderSettings returns DefaultReminders for users who haven't set their own
+func (f *FSTask) GetReminderSettings(ctx context.Context, userID string) (ReminderSettings, error) {
+	doc, err := f.reminderSettingsRef(userID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return ReminderSettings{UserID: userID, Reminders: append([]int64{}, DefaultReminders...)}, nil
+	}
+	if err != nil {
+		return ReminderSettings{}, err
+	}
+	settings := ReminderSettings{}
+	err = doc.DataTo(&settings)
+	if err != nil {
+		return ReminderSettings{}, err
+	}
+	if settings.Reminders == nil {
+		settings.Reminders = []int64{}
+	}
+	return settings, nil
+}
+
+// SetReminderSettings changes the defaults of tasks created from now on, existing tasks keep their reminders
+func (f *FSTask) SetReminderSettings(ctx context.Context, settings ReminderSettings) (ReminderSettings, error) {
+	settings.Reminders = NormalizeReminders(settings.Reminders)
+	if settings.Reminders == nil {
+		settings.Reminders = []int64{}
+	}
+	settings.UpdatedAt = time.Now().Unix()
+	_, err := f.reminderSettingsRef(settings.UserID).Set(ctx, settings)
+	if err != nil {
+		return ReminderSettings{}, err
+	}
+	return settings, nil
+}
+
+// scheduleReminders gives tasks without reminders the defaults of the user and schedules the next one
+func (f *FSTask) scheduleReminders(ctx context.Context, before *Task, task *Task) error {
+	if task.Reminders == nil && before != nil && before.Reminders != nil {
+		task.Reminders = before.Reminders
+	}
+	if task.Reminders == nil {
+		settings, err := f.GetReminderSettings(ctx, task.UserID)
+		if err != nil {
+			return err
+		}
+		task.Reminders = settings.Reminders
+	}
+	task.Reminders = NormalizeReminders(task.Reminders)
+	task.RemindersSent = keptReminders(before, *task)
+	task.ScheduleReminders(time.Now().Unix())
+	return nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	CollectionSettings = "settings"
+	// DocReminderSettings is the document of the reminder defaults under users/{uid}/settings
+	DocReminderSettings = "reminders"
+	// ReminderGracePeriod is how late a reminder may still be sent, e.g. after the reminder cron was down
+	ReminderGracePeriod = time.Hour
+)
+
+// DefaultReminders are the offsets of users who haven't set their own, 5 minutes before the due time
+var DefaultReminders = []int64{300}
+
+// ReminderSettings holds the reminder offsets new tasks of the user get, empty sets no reminders
+type ReminderSettings struct {
+	UserID    string  `firestore:"userID"`
+	Reminders []int64 `firestore:"reminders"`
+	UpdatedAt int64   `firestore:"updatedAt"`
+}
+
+// NormalizeReminders sorts the offsets from the earliest reminder and drops duplicates.
+// nil stays nil, so that it can still tell an unset list from an empty one
+func NormalizeReminders(offsets []int64) []int64 {
+	if offsets == nil {
+		return nil
+	}
+	normalized := make([]int64, 0, len(offsets))
+	seen := make(map[int64]bool)
+	for _, offset := range offsets {
+		if !seen[offset] {
+			seen[offset] = true
+			normalized = append(normalized, offset)
+		}
+	}
+	sort.Slice(normalized, func(i, j int) bool { return normalized[i] > normalized[j] })
+	return normalized
+}
+
+// reminderFired tells whether the reminder of the offset was already sent
+func (t Task) reminderFired(offset int64) bool {
+	for _, fired := range t.RemindersSent {
+		if fired == offset {
+			return true
+		}
+	}
+	return false
+}
+
+// ScheduleReminders sets NextReminderAt to the fire time of the earliest reminder that hasn't been sent yet.
+// Completed tasks, tasks already due at now and tasks without pending reminders get 0, which is never searched for
+func (t *Task) ScheduleReminders(now int64) {
+	t.NextReminderAt = 0
+	if t.Completed || t.Time <= now {
+		return
+	}
+	for _, offset := range t.Reminders {
+		if t.reminderFired(offset) {
+			continue
+		}
+		fireAt := t.Time - offset
+		if t.NextReminderAt == 0 || fireAt < t.NextReminderAt {
+			t.NextReminderAt = fireAt
+		}
+	}
+}
+
+// FireReminders marks every reminder due at now as sent and schedules the next one. Reminders due together,
+// e.g. a day and an hour before a task created an hour before its due time, are sent once, as the one closest
+// to the due time. It returns the offset of the reminder to send, false when there is none or it's too late for it
+func (t *Task) FireReminders(now int64) (int64, bool) {
+	var offset int64
+	due := false
+	for _, o := range t.Reminders {
+		if t.reminderFired(o) || t.Time-o > now {
+			continue
+		}
+		t.RemindersSent = append(t.RemindersSent, o)
+		if !due || o < offset {
+			offset = o
+		}
+		due = true
+	}
+	t.ScheduleReminders(now)
+	if !due || now-(t.Time-offset) > int64(ReminderGracePeriod.Seconds()) {
+		return 0, false
+	}
+	return offset, true
+}
+
+// keptReminders returns the sent reminders of before that still apply to after,
+// moving the due time or dropping an offset lets the reminder fire again
+func keptReminders(before *Task, after Task) []int64 {
+	if before == nil || before.Time != after.Time {
+		return nil
+	}
+	var kept []int64
+	for _, offset := range after.Reminders {
+		if before.reminderFired(offset) {
+			kept = append(kept, offset)
+		}
+	}
+	return kept
+}
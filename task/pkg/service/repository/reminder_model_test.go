@@ -0,0 +1,97 @@
+package repository
+
+import (
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ReminderModelTestSuite struct {
+	suite.Suite
+}
+
+func (s *ReminderModelTestSuite) TestNormalizeReminders() {
+	s.Nil(NormalizeReminders(nil))
+	s.Equal([]int64{}, NormalizeReminders([]int64{}))
+	s.Equal([]int64{86400, 3600, 0}, NormalizeReminders([]int64{0, 3600, 86400, 3600}))
+}
+
+func (s *ReminderModelTestSuite) TestScheduleReminders() {
+	candidates := []struct {
+		task     Task
+		expected int64
+	}{
+		{task: Task{Time: 100000, Reminders: []int64{86400, 3600, 0}}, expected: 100000 - 86400},
+		{task: Task{Time: 100000, Reminders: []int64{86400, 3600, 0}, RemindersSent: []int64{86400}}, expected: 100000 - 3600},
+		{task: Task{Time: 100000, Reminders: []int64{3600}, RemindersSent: []int64{3600}}, expected: 0},
+		{task: Task{Time: 100000, Reminders: []int64{3600}, Completed: true}, expected: 0},
+		// due tasks are not reminded anymore
+		{task: Task{Time: 1000, Reminders: []int64{0}}, expected: 0},
+		{task: Task{Time: 100000}, expected: 0},
+	}
+	for i, c := range candidates {
+		c.task.ScheduleReminders(10000)
+		s.Equalf(c.expected, c.task.NextReminderAt, "candidate %d", i+1)
+	}
+}
+
+func (s *ReminderModelTestSuite) TestFireReminders() {
+	// every reminder is sent once
+	task := Task{Time: 100000, Reminders: []int64{86400, 3600, 0}}
+	task.ScheduleReminders(0)
+	offset, send := task.FireReminders(task.NextReminderAt)
+	s.True(send)
+	s.Equal(int64(86400), offset)
+	s.Equal(int64(100000-3600), task.NextReminderAt)
+	_, send = task.FireReminders(task.NextReminderAt - 1)
+	s.False(send)
+	offset, send = task.FireReminders(task.NextReminderAt)
+	s.True(send)
+	s.Equal(int64(3600), offset)
+	offset, send = task.FireReminders(100000)
+	s.True(send)
+	s.Equal(int64(0), offset)
+	s.Equal(int64(0), task.NextReminderAt)
+	s.Equal([]int64{86400, 3600, 0}, task.RemindersSent)
+
+	// reminders due together are sent as the one closest to the due time
+	task = Task{Time: 100000, Reminders: []int64{86400, 3600, 0}}
+	offset, send = task.FireReminders(100000 - 1800)
+	s.True(send)
+	s.Equal(int64(3600), offset)
+	s.Equal(int64(100000), task.NextReminderAt)
+
+	// late reminders are only marked as sent
+	task = Task{Time: 100000, Reminders: []int64{0}}
+	_, send = task.FireReminders(100000 + 2*3600)
+	s.False(send)
+	s.Equal([]int64{0}, task.RemindersSent)
+}
+
+func (s *ReminderModelTestSuite) TestKeptReminders() {
+	before := &Task{Time: 100000, Reminders: []int64{3600, 0}, RemindersSent: []int64{3600}}
+	s.Equal([]int64{3600}, keptReminders(before, Task{Time: 100000, Reminders: []int64{86400, 3600}}))
+	s.Nil(keptReminders(before, Task{Time: 100000, Reminders: []int64{0}}))
+	s.Nil(keptReminders(before, Task{Time: 200000, Reminders: []int64{3600, 0}}))
+	s.Nil(keptReminders(nil, Task{Time: 100000, Reminders: []int64{3600}}))
+}
+
+func (s *ReminderModelTestSuite) TestRemindersFromMsg() {
+	candidates := []struct {
+		msg      *v1.Task
+		expected []int64
+	}{
+		{msg: &v1.Task{Reminders: []int64{3600, 0}}, expected: []int64{3600, 0}},
+		// missing reminders are kept unset
+		{msg: &v1.Task{}, expected: nil},
+		{msg: &v1.Task{ClearReminders: true}, expected: []int64{}},
+		{msg: &v1.Task{Reminders: []int64{3600}, ClearReminders: true}, expected: []int64{3600}},
+	}
+	for i, c := range candidates {
+		s.Equalf(c.expected, TaskFromMsg(c.msg).Reminders, "candidate %d", i+1)
+	}
+}
+
+func TestReminderModelTestSuite(t *testing.T) {
+	suite.Run(t, new(ReminderModelTestSuite))
+}
@@ -0,0 +1,374 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"github.com/jakubjano/todolist/task/pkg/events"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type FSReminderQueueInterface interface {
+	FireReminders(ctx context.Context, taskID string, now int64) (ReminderDelivery, bool, error)
+	FireOverdue(ctx context.Context, taskID string, now int64) ([]ReminderDelivery, error)
+	Due(ctx context.Context, now int64, n int) ([]ReminderDelivery, error)
+	Claim(ctx context.Context, deliveryID string, lease time.Duration) (ReminderDelivery, bool, error)
+	MarkSent(ctx context.Context, delivery ReminderDelivery, event events.Event) error
+	Retry(ctx context.Context, deliveryID string, nextAttemptAt int64, lastError string, delivered []string) error
+	Defer(ctx context.Context, deliveryID string, until int64) error
+	Snooze(ctx context.Context, delivery ReminderDelivery) (bool, error)
+	DeadLetter(ctx context.Context, delivery ReminderDelivery, lastError string) error
+}
+
+// FSReminderQueue queues the reminders of tasks for sending, in a collection of its own
+type FSReminderQueue struct {
+	fs     *firestore.CollectionRef
+	client *firestore.Client
+	outbox *events.FSOutbox
+}
+
+func NewFSReminderQueue(fs *firestore.CollectionRef, client *firestore.Client) *FSReminderQueue {
+	return &FSReminderQueue{
+		fs:     fs,
+		client: client,
+		outbox: events.NewFSOutbox(client.Collection(events.CollectionOutbox), client),
+	}
+}
+
+// FireReminders marks the reminders of the task due at now as sent and queues the one to send in a single
+// transaction, so a reminder is either queued or still due. It returns false when there's nothing to send,
+// when the task is gone, overdue or the reminder is queued already
+func (f *FSReminderQueue) FireReminders(ctx context.Context, taskID string, now int64) (ReminderDelivery, bool, error) {
+	taskListRef := f.client.Collection(TaskList).Doc(taskID)
+	var delivery ReminderDelivery
+	queued := false
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		queued = false
+		doc, err := tx.Get(taskListRef)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		task := Task{}
+		err = doc.DataTo(&task)
+		if err != nil {
+			return err
+		}
+		// another instance fired the reminders meanwhile
+		if task.NextReminderAt <= 0 || task.NextReminderAt > now {
+			return nil
+		}
+		ownerRef, owned, err := f.ownerTaskRef(tx, task.UserID, taskID)
+		if err != nil || !owned {
+			return err
+		}
+		offset, send := task.FireReminders(now)
+		var deliveryRef *firestore.DocumentRef
+		if send {
+			delivery = NewReminderDelivery(task, offset, now)
+			deliveryRef = f.fs.Doc(delivery.DeliveryID)
+			_, err = tx.Get(deliveryRef)
+			if err == nil {
+				send = false
+			} else if status.Code(err) != codes.NotFound {
+				return err
+			}
+		}
+		// both copies keep the sent reminders, so that updates of the task don't send them again
+		reminderFields := map[string]interface{}{
+			"remindersSent":  task.RemindersSent,
+			"nextReminderAt": task.NextReminderAt,
+		}
+		err = tx.Set(ownerRef, reminderFields, firestore.MergeAll)
+		if err != nil {
+			return err
+		}
+		err = tx.Set(taskListRef, reminderFields, firestore.MergeAll)
+		if err != nil {
+			return err
+		}
+		if !send {
+			return nil
+		}
+		queued = true
+		return tx.Create(deliveryRef, delivery)
+	})
+	if err != nil || !queued {
+		return ReminderDelivery{}, false, err
+	}
+	return delivery, true, nil
+}
+
+// FireOverdue applies the escalation policy of the task owner to the task at now and queues the overdue
+// reminder and the escalation to send, all in a single transaction like FireReminders. It returns
+// the queued ones, none when the task is gone or isn't due for the policy yet
+func (f *FSReminderQueue) FireOverdue(ctx context.Context, taskID string, now int64) ([]ReminderDelivery, error) {
+	taskListRef := f.client.Collection(TaskList).Doc(taskID)
+	var queued []ReminderDelivery
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		queued = nil
+		doc, err := tx.Get(taskListRef)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		task := Task{}
+		err = doc.DataTo(&task)
+		if err != nil {
+			return err
+		}
+		// another instance applied the policy meanwhile
+		if task.NextOverdueAt <= 0 || task.NextOverdueAt > now {
+			return nil
+		}
+		ownerRef, owned, err := f.ownerTaskRef(tx, task.UserID, taskID)
+		if err != nil || !owned {
+			return err
+		}
+		policy := EscalationPolicy{UserID: task.UserID}
+		policyDoc, err := tx.Get(escalationPolicyRef(f.client.Collection(CollectionUsers), task.UserID))
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			err = policyDoc.DataTo(&policy)
+			if err != nil {
+				return err
+			}
+		}
+		interval, notice, escalate := policy.FireOverdue(&task, now)
+		var deliveries []ReminderDelivery
+		if notice {
+			deliveries = append(deliveries, NewReminderDelivery(task, -interval, now))
+		}
+		if escalate {
+			deliveries = append(deliveries, NewEscalationDelivery(task, policy.EscalationEmail, now))
+		}
+		// reads go before the writes in transactions
+		for _, delivery := range deliveries {
+			_, err = tx.Get(f.fs.Doc(delivery.DeliveryID))
+			if err == nil {
+				continue
+			}
+			if status.Code(err) != codes.NotFound {
+				return err
+			}
+			queued = append(queued, delivery)
+		}
+		overdueFields := map[string]interface{}{
+			"overdueNotices": task.OverdueNotices,
+			"escalated":      task.Escalated,
+			"nextOverdueAt":  task.NextOverdueAt,
+		}
+		err = tx.Set(ownerRef, overdueFields, firestore.MergeAll)
+		if err != nil {
+			return err
+		}
+		err = tx.Set(taskListRef, overdueFields, firestore.MergeAll)
+		if err != nil {
+			return err
+		}
+		for _, delivery := range queued {
+			err = tx.Create(f.fs.Doc(delivery.DeliveryID), delivery)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return queued, nil
+}
+
+// ownerTaskRef returns the copy of the task under its owner. The owner is read from task_list, so the copy
+// is checked to exist before the fields are merged into it, false when the user has no such task
+func (f *FSReminderQueue) ownerTaskRef(tx *firestore.Transaction, userID, taskID string) (*firestore.DocumentRef, bool, error) {
+	if userID == "" {
+		return nil, false, nil
+	}
+	ref := f.client.Collection(CollectionUsers).Doc(userID).Collection(CollectionTasks).Doc(taskID)
+	_, err := tx.Get(ref)
+	if status.Code(err) == codes.NotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return ref, true, nil
+}
+
+// Due returns at most n pending reminders to send at now, the longest waiting first
+func (f *FSReminderQueue) Due(ctx context.Context, now int64, n int) ([]ReminderDelivery, error) {
+	docs := f.fs.Where("status", "==", ReminderPending).
+		Where("nextAttemptAt", "<=", now).
+		OrderBy("nextAttemptAt", firestore.Asc).
+		Limit(n).Documents(ctx)
+	var due []ReminderDelivery
+	for {
+		doc, err := docs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		delivery := ReminderDelivery{}
+		err = doc.DataTo(&delivery)
+		if err != nil {
+			return nil, err
+		}
+		due = append(due, delivery)
+	}
+	return due, nil
+}
+
+// Claim locks the pending reminder for the lease and counts the attempt, so other instances skip it meanwhile.
+// It returns false when the reminder is sent, dead-lettered or locked already. Reminders of tasks that were
+// deleted, completed or moved to another time since they were queued are dropped instead of claimed
+func (f *FSReminderQueue) Claim(ctx context.Context, deliveryID string, lease time.Duration) (ReminderDelivery, bool, error) {
+	docRef := f.fs.Doc(deliveryID)
+	var delivery ReminderDelivery
+	claimed := false
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = false
+		doc, err := tx.Get(docRef)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		delivery = ReminderDelivery{}
+		err = doc.DataTo(&delivery)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if delivery.Status != ReminderPending || delivery.LockedUntil.After(now) {
+			return nil
+		}
+		stale, err := f.stale(tx, delivery)
+		if err != nil {
+			return err
+		}
+		if stale {
+			return tx.Update(docRef, []firestore.Update{
+				{Path: "status", Value: ReminderDropped},
+				{Path: "expireAt", Value: now.Add(ReminderSentTTL)},
+			})
+		}
+		claimed = true
+		delivery.Attempts++
+		delivery.LockedUntil = now.Add(lease)
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "attempts", Value: delivery.Attempts},
+			{Path: "lockedUntil", Value: delivery.LockedUntil},
+		})
+	})
+	if err != nil || !claimed {
+		return ReminderDelivery{}, false, err
+	}
+	return delivery, true, nil
+}
+
+// stale tells whether the reminder no longer applies to its task as read in the transaction.
+// The owner stopping the nagging drops the overdue reminders and the escalations only
+func (f *FSReminderQueue) stale(tx *firestore.Transaction, delivery ReminderDelivery) (bool, error) {
+	doc, err := tx.Get(f.client.Collection(TaskList).Doc(delivery.TaskID))
+	if status.Code(err) == codes.NotFound {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	task := Task{}
+	err = doc.DataTo(&task)
+	if err != nil {
+		return false, err
+	}
+	nagging := delivery.Kind == notify.KindOverdue || delivery.Kind == notify.KindEscalation
+	return task.Completed || task.Time != delivery.DueTime || nagging && task.StopNagging, nil
+}
+
+// pendingDeliveries returns the reminders of the task waiting to be sent, read in the transaction
+func pendingDeliveries(tx *firestore.Transaction, client *firestore.Client,
+	taskID string) ([]*firestore.DocumentRef, error) {
+	docs, err := tx.Documents(client.Collection(CollectionReminderQueue).
+		Where("taskID", "==", taskID).
+		Where("status", "==", ReminderPending)).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]*firestore.DocumentRef, 0, len(docs))
+	for _, doc := range docs {
+		refs = append(refs, doc.Ref)
+	}
+	return refs, nil
+}
+
+// MarkSent records the reminder as sent together with its event
+func (f *FSReminderQueue) MarkSent(ctx context.Context, delivery ReminderDelivery, event events.Event) error {
+	now := time.Now()
+	batch := f.client.Batch()
+	batch.Update(f.fs.Doc(delivery.DeliveryID), []firestore.Update{
+		{Path: "status", Value: ReminderSent},
+		{Path: "sentAt", Value: now.Unix()},
+		{Path: "lastError", Value: ""},
+		{Path: "expireAt", Value: now.Add(ReminderSentTTL)},
+	})
+	f.outbox.Add(batch, event)
+	_, err := batch.Commit(ctx)
+	return err
+}
+
+// Retry unlocks the reminder after a failed attempt, it's due again at nextAttemptAt
+// to the channels other than the delivered ones
+func (f *FSReminderQueue) Retry(ctx context.Context, deliveryID string, nextAttemptAt int64, lastError string,
+	delivered []string) error {
+	_, err := f.fs.Doc(deliveryID).Update(ctx, []firestore.Update{
+		{Path: "nextAttemptAt", Value: nextAttemptAt},
+		{Path: "lastError", Value: lastError},
+		{Path: "lockedUntil", Value: time.Time{}},
+		{Path: "delivered", Value: delivered},
+	})
+	return err
+}
+
+// Defer moves the pending reminder to until without using up an attempt
+func (f *FSReminderQueue) Defer(ctx context.Context, deliveryID string, until int64) error {
+	_, err := f.fs.Doc(deliveryID).Update(ctx, []firestore.Update{{Path: "nextAttemptAt", Value: until}})
+	return err
+}
+
+// Snooze queues the reminder unless it's queued already, it returns false for a repeated snooze
+func (f *FSReminderQueue) Snooze(ctx context.Context, delivery ReminderDelivery) (bool, error) {
+	_, err := f.fs.Doc(delivery.DeliveryID).Create(ctx, delivery)
+	if status.Code(err) == codes.AlreadyExists {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeadLetter moves the reminder out of attempts to the dead letters, where it's kept until removed by hand
+func (f *FSReminderQueue) DeadLetter(ctx context.Context, delivery ReminderDelivery, lastError string) error {
+	delivery.LastError = lastError
+	delivery.FailedAt = time.Now().Unix()
+	delivery.LockedUntil = time.Time{}
+	batch := f.client.Batch()
+	batch.Set(f.client.Collection(CollectionReminderDeadLetters).Doc(delivery.DeliveryID), delivery)
+	batch.Delete(f.fs.Doc(delivery.DeliveryID))
+	_, err := batch.Commit(ctx)
+	return err
+}
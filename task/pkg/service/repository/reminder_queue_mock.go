@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"github.com/jakubjano/todolist/task/pkg/events"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type FSReminderQueueMock struct {
+	mock.Mock
+}
+
+func NewMockReminderQueue() *FSReminderQueueMock {
+	return &FSReminderQueueMock{}
+}
+
+func (m *FSReminderQueueMock) FireReminders(ctx context.Context, taskID string, now int64) (ReminderDelivery, bool, error) {
+	args := m.Called(ctx, taskID, now)
+	return args.Get(0).(ReminderDelivery), args.Bool(1), args.Error(2)
+}
+
+func (m *FSReminderQueueMock) FireOverdue(ctx context.Context, taskID string, now int64) ([]ReminderDelivery, error) {
+	args := m.Called(ctx, taskID, now)
+	return args.Get(0).([]ReminderDelivery), args.Error(1)
+}
+
+func (m *FSReminderQueueMock) Due(ctx context.Context, now int64, n int) ([]ReminderDelivery, error) {
+	args := m.Called(ctx, now, n)
+	return args.Get(0).([]ReminderDelivery), args.Error(1)
+}
+
+func (m *FSReminderQueueMock) Claim(ctx context.Context, deliveryID string, lease time.Duration) (ReminderDelivery, bool, error) {
+	args := m.Called(ctx, deliveryID, lease)
+	return args.Get(0).(ReminderDelivery), args.Bool(1), args.Error(2)
+}
+
+func (m *FSReminderQueueMock) MarkSent(ctx context.Context, delivery ReminderDelivery, event events.Event) error {
+	args := m.Called(ctx, delivery, event)
+	return args.Error(0)
+}
+
+func (m *FSReminderQueueMock) Retry(ctx context.Context, deliveryID string, nextAttemptAt int64, lastError string,
+	delivered []string) error {
+	args := m.Called(ctx, deliveryID, nextAttemptAt, lastError, delivered)
+	return args.Error(0)
+}
+
+func (m *FSReminderQueueMock) Defer(ctx context.Context, deliveryID string, until int64) error {
+	args := m.Called(ctx, deliveryID, until)
+	return args.Error(0)
+}
+
+func (m *FSReminderQueueMock) Snooze(ctx context.Context, delivery ReminderDelivery) (bool, error) {
+	args := m.Called(ctx, delivery)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *FSReminderQueueMock) DeadLetter(ctx context.Context, delivery ReminderDelivery, lastError string) error {
+	args := m.Called(ctx, delivery, lastError)
+	return args.Error(0)
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
+	"time"
+)
+
+const (
+	CollectionReminderQueue       = "reminder_queue"
+	CollectionReminderDeadLetters = "reminder_dead_letters"
+
+	ReminderPending = "pending"
+	ReminderSent    = "sent"
+	// ReminderDropped are the reminders that went stale before they were sent, the task was deleted,
+	// completed or moved to another time meanwhile
+	ReminderDropped = "dropped"
+
+	// ReminderSentTTL is how long sent reminders are kept, so the same reminder isn't queued again meanwhile.
+	// They are removed by the Firestore TTL policy on expireAt
+	ReminderSentTTL = time.Hour * 24 * 7
+)
+
+// ReminderDelivery is a reminder queued for sending, kept under reminder_queue/{id}. Pending reminders
+// are sent once nextAttemptAt passes, Attempts counts the claims of the reminder, so an instance that died
+// while sending it uses up an attempt too. Reminders out of attempts move to reminder_dead_letters/{id}.
+// Due reminders are queried by status and nextAttemptAt, which needs a composite index. Kind is one
+// of the notify kinds, reminders of tasks due soon have none. Escalations are sent to Email,
+// OwnerEmail is the email of the task owner then. Delivered are the notification channels
+// that got the reminder already, retries skip them
+type ReminderDelivery struct {
+	DeliveryID    string     `firestore:"deliveryID"`
+	Kind          string     `firestore:"kind,omitempty"`
+	UserID        string     `firestore:"userID"`
+	Email         string     `firestore:"email"`
+	TaskID        string     `firestore:"taskID"`
+	TaskName      string     `firestore:"taskName"`
+	Description   string     `firestore:"description"`
+	DueTime       int64      `firestore:"dueTime"`
+	Offset        int64      `firestore:"offset"`
+	Status        string     `firestore:"status"`
+	Attempts      int32      `firestore:"attempts"`
+	NextAttemptAt int64      `firestore:"nextAttemptAt"`
+	LockedUntil   time.Time  `firestore:"lockedUntil"`
+	LastError     string     `firestore:"lastError"`
+	CreatedAt     int64      `firestore:"createdAt"`
+	SentAt        int64      `firestore:"sentAt"`
+	FailedAt      int64      `firestore:"failedAt"`
+	ExpireAt      *time.Time `firestore:"expireAt,omitempty"`
+	OwnerEmail    string     `firestore:"ownerEmail,omitempty"`
+	Delivered     []string   `firestore:"delivered,omitempty"`
+}
+
+// ReminderDeliveryID identifies the reminder of the task for the due time and offset, a task moved
+// to another time gets new reminders
+func ReminderDeliveryID(taskID string, dueTime, offset int64) string {
+	return fmt.Sprintf("%s_%d_%d", taskID, dueTime, offset)
+}
+
+// SnoozedDeliveryID identifies the reminder snoozed with the action link expiring at expires,
+// so every link snoozes once
+func SnoozedDeliveryID(taskID string, expires int64) string {
+	return fmt.Sprintf("%s_snoozed_%d", taskID, expires)
+}
+
+// EscalationDeliveryID identifies the escalation of the task for the due time
+func EscalationDeliveryID(taskID string, dueTime int64) string {
+	return fmt.Sprintf("%s_%d_escalation", taskID, dueTime)
+}
+
+// NewReminderDelivery returns the pending reminder of the task for the offset, due right away.
+// Negative offsets are overdue reminders
+func NewReminderDelivery(task Task, offset, now int64) ReminderDelivery {
+	kind := ""
+	if offset < 0 {
+		kind = notify.KindOverdue
+	}
+	return ReminderDelivery{
+		DeliveryID:    ReminderDeliveryID(task.TaskID, task.Time, offset),
+		Kind:          kind,
+		UserID:        task.UserID,
+		Email:         task.UserEmail,
+		TaskID:        task.TaskID,
+		TaskName:      task.Name,
+		Description:   task.Description,
+		DueTime:       task.Time,
+		Offset:        offset,
+		Status:        ReminderPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// NewEscalationDelivery returns the pending escalation of the task to the email, due right away
+func NewEscalationDelivery(task Task, email string, now int64) ReminderDelivery {
+	delivery := NewReminderDelivery(task, task.Time-now, now)
+	delivery.DeliveryID = EscalationDeliveryID(task.TaskID, task.Time)
+	delivery.Kind = notify.KindEscalation
+	delivery.Email = email
+	delivery.OwnerEmail = task.UserEmail
+	return delivery
+}
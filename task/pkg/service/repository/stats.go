@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"time"
+)
+
+const (
+	CollectionStats = "stats"
+	statsDateLayout = "2006-01-02"
+)
+
+// DailyStats is a per-user aggregate of task activity on a single UTC day.
+// Documents are keyed by date and only ever changed with increments in the same transaction
+// as the task write, so reports don't have to scan the whole task history
+type DailyStats struct {
+	Date      string `firestore:"date"`
+	Created   int64  `firestore:"created"`
+	Completed int64  `firestore:"completed"`
+	// Due counts tasks due on this day, DueCompleted those of them completed in time
+	Due          int64 `firestore:"due"`
+	DueCompleted int64 `firestore:"dueCompleted"`
+	// LeadTimeSum sums seconds from creation to completion of tasks completed on this day
+	LeadTimeSum int64 `firestore:"leadTimeSum"`
+}
+
+// StatsDate returns the key of the daily stats document the unix time falls into
+func StatsDate(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(statsDateLayout)
+}
+
+// statsDelta maps date -> field -> increment
+type statsDelta map[string]map[string]int64
+
+func (d statsDelta) add(unix int64, field string, n int64) {
+	date := StatsDate(unix)
+	if d[date] == nil {
+		d[date] = make(map[string]int64)
+	}
+	d[date][field] += n
+}
+
+// addDue adds the contribution of task's due time, sign is 1 or -1
+func (d statsDelta) addDue(task Task, sign int64) {
+	if task.Time <= 0 {
+		return
+	}
+	d.add(task.Time, "due", sign)
+	if task.Completed && task.CompletedAt <= task.Time {
+		d.add(task.Time, "dueCompleted", sign)
+	}
+}
+
+// addCompletion adds the contribution of task's completion, sign is 1 or -1
+func (d statsDelta) addCompletion(task Task, sign int64) {
+	if !task.Completed {
+		return
+	}
+	d.add(task.CompletedAt, "completed", sign)
+	d.add(task.CompletedAt, "leadTimeSum", sign*(task.CompletedAt-task.CreatedAt))
+}
+
+// newStatsDelta computes the change of the daily aggregates caused by a task write.
+// before is nil for newly created tasks and after is nil for deleted tasks.
+// Deleting a task keeps its creation and completion in history but removes it from due counts
+func newStatsDelta(before, after *Task) statsDelta {
+	delta := make(statsDelta)
+	if before == nil && after != nil {
+		delta.add(after.CreatedAt, "created", 1)
+	}
+	if before != nil {
+		delta.addDue(*before, -1)
+		if after != nil {
+			delta.addCompletion(*before, -1)
+		}
+	}
+	if after != nil {
+		delta.addDue(*after, 1)
+		delta.addCompletion(*after, 1)
+	}
+	return delta
+}
+
+// recordStats adds the stats increments for a task write into the transaction
+func (f *FSTask) recordStats(tx *firestore.Transaction, userID string, before, after *Task) error {
+	for date, fields := range newStatsDelta(before, after) {
+		data := map[string]interface{}{"date": date}
+		for field, n := range fields {
+			if n != 0 {
+				data[field] = firestore.Increment(n)
+			}
+		}
+		if len(data) == 1 {
+			continue
+		}
+		err := tx.Set(f.fs.Doc(userID).Collection(CollectionStats).Doc(date), data, firestore.MergeAll)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"google.golang.org/api/iterator"
+	"sort"
+	"time"
+)
+
+const (
+	CollectionChanges = "changes"
+	// TombstoneTTL is how long deletions are kept in the change log, clients that haven't synced for longer
+	// have to download everything again. Expired tombstones are removed by the Firestore TTL policy on expireAt
+	TombstoneTTL = time.Hour * 24 * 30
+)
+
+// Change is the latest write of a task, kept under users/{uid}/changes/{taskID}.
+// Change log is updated in the same transaction as the task, ordered by the commit time of the write
+type Change struct {
+	TaskID    string    `firestore:"taskID"`
+	UpdatedAt time.Time `firestore:"updatedAt,serverTimestamp"`
+	Deleted   bool      `firestore:"deleted"`
+	// Created marks writes that created the task
+	Created bool `firestore:"created"`
+	// Task is the state after the write, nil for deletions
+	Task *Task `firestore:"task"`
+	// ExpireAt is set for tombstones only
+	ExpireAt *time.Time `firestore:"expireAt,omitempty"`
+}
+
+// SyncCursor is a position in the change log, zero cursor points before the first change
+type SyncCursor struct {
+	UpdatedAt time.Time
+	TaskID    string
+}
+
+func (c Change) Cursor() SyncCursor {
+	return SyncCursor{UpdatedAt: c.UpdatedAt, TaskID: c.TaskID}
+}
+
+// recordChange adds the write of the task into the transaction.
+// before is nil for newly created tasks and after is nil for deleted tasks
+func (f *FSTask) recordChange(tx *firestore.Transaction, userID, taskID string, before, after *Task) error {
+	change := Change{TaskID: taskID, Task: after, Created: before == nil}
+	if after == nil {
+		expireAt := time.Now().Add(TombstoneTTL)
+		change.Deleted = true
+		change.ExpireAt = &expireAt
+	}
+	return tx.Set(f.fs.Doc(userID).Collection(CollectionChanges).Doc(taskID), change)
+}
+
+// GetChanges returns at most n changes of the user written after the cursor, oldest first
+func (f *FSTask) GetChanges(ctx context.Context, userID string, after SyncCursor, n int) (changes []Change, err error) {
+	changeQuery := f.changesAfter(userID, after).Limit(n).Documents(ctx)
+	for {
+		doc, err := changeQuery.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		change := Change{}
+		err = doc.DataTo(&change)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// WatchChanges calls handle with every change of the user written after the cursor, oldest first,
+// until the context is done or handle fails. Changes already written are handled right away
+func (f *FSTask) WatchChanges(ctx context.Context, userID string, after SyncCursor, handle func(Change) error) error {
+	snapshots := f.changesAfter(userID, after).Snapshots(ctx)
+	defer snapshots.Stop()
+	for {
+		snapshot, err := snapshots.Next()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return err
+		}
+		var changes []Change
+		for _, docChange := range snapshot.Changes {
+			// documents only leave the query when expired tombstones are deleted
+			if docChange.Kind == firestore.DocumentRemoved {
+				continue
+			}
+			change := Change{}
+			err = docChange.Doc.DataTo(&change)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, change)
+		}
+		sort.Slice(changes, func(i, j int) bool {
+			if changes[i].UpdatedAt.Equal(changes[j].UpdatedAt) {
+				return changes[i].TaskID < changes[j].TaskID
+			}
+			return changes[i].UpdatedAt.Before(changes[j].UpdatedAt)
+		})
+		for _, change := range changes {
+			err = handle(change)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (f *FSTask) changesAfter(userID string, after SyncCursor) firestore.Query {
+	query := f.fs.Doc(userID).Collection(CollectionChanges).
+		OrderBy("updatedAt", firestore.Asc).
+		OrderBy(firestore.DocumentID, firestore.Asc)
+	if !after.UpdatedAt.IsZero() {
+		query = query.StartAfter(after.UpdatedAt, after.TaskID)
+	}
+	return query
+}
+
+// GetLatestChange returns the cursor of the last change of the user, zero cursor when there is none
+func (f *FSTask) GetLatestChange(ctx context.Context, userID string) (SyncCursor, error) {
+	docs, err := f.fs.Doc(userID).Collection(CollectionChanges).
+		OrderBy("updatedAt", firestore.Desc).
+		OrderBy(firestore.DocumentID, firestore.Desc).
+		Limit(1).Documents(ctx).GetAll()
+	if err != nil || len(docs) == 0 {
+		return SyncCursor{}, err
+	}
+	change := Change{}
+	err = docs[0].DataTo(&change)
+	if err != nil {
+		return SyncCursor{}, err
+	}
+	return change.Cursor(), nil
+}
@@ -3,29 +3,52 @@ package repository
 import (
 	"cloud.google.com/go/firestore"
 	"context"
+	"github.com/jakubjano/todolist/task/pkg/events"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"time"
 )
 
 type FSTaskInterface interface {
 	Create(ctx context.Context, in Task) (Task, error)
 	Get(ctx context.Context, userID, taskID string) (Task, error)
+	GetByCalDAVName(ctx context.Context, userID, name string) (Task, error)
 	Update(ctx context.Context, newTask Task, userID, taskID string) (Task, error)
 	Delete(ctx context.Context, userID, taskID string) error
 	GetLastN(ctx context.Context, userID string, n int32) (tasks []Task, err error)
 	GetExpired(ctx context.Context, userID string) (expiredTasks []Task, err error)
-	SearchForExpiringTasks(ctx context.Context) (map[string][]Task, error)
+	SearchForExpiringTasks(ctx context.Context, now int64) (map[string][]Task, error)
+	BackfillReminders(ctx context.Context, now int64) (int, error)
+	BackfillOverdue(ctx context.Context, now int64) (int, error)
+	SearchForUpcomingReminders(ctx context.Context, until int64) ([]Task, error)
+	SearchForOverdueTasks(ctx context.Context, now int64) ([]Task, error)
+	List(ctx context.Context, filter TaskFilter) (tasks []Task, err error)
+	GetDailyStats(ctx context.Context, userID string, from, to int64) (stats []DailyStats, err error)
+	GetAll(ctx context.Context, userID string) (tasks []Task, err error)
+	GetPage(ctx context.Context, userID string, after PageCursor, n int) (tasks []Task, err error)
+	GetChanges(ctx context.Context, userID string, after SyncCursor, n int) (changes []Change, err error)
+	GetLatestChange(ctx context.Context, userID string) (SyncCursor, error)
+	WatchChanges(ctx context.Context, userID string, after SyncCursor, handle func(Change) error) error
+	GetReminderSettings(ctx context.Context, userID string) (ReminderSettings, error)
+	SetReminderSettings(ctx context.Context, settings ReminderSettings) (ReminderSettings, error)
+	GetEscalationPolicy(ctx context.Context, userID string) (EscalationPolicy, error)
+	SetEscalationPolicy(ctx context.Context, policy EscalationPolicy) (EscalationPolicy, error)
+	VerifyEscalationEmail(ctx context.Context, userID, email string) (bool, error)
+	RescheduleOverdue(ctx context.Context, now int64) (int, error)
 }
 
 type FSTask struct {
 	fs     *firestore.CollectionRef
 	client *firestore.Client
+	outbox *events.FSOutbox
 }
 
 func NewFSTask(fs *firestore.CollectionRef, client *firestore.Client) *FSTask {
 	return &FSTask{
 		fs:     fs,
 		client: client,
+		outbox: events.NewFSOutbox(client.Collection(events.CollectionOutbox), client),
 	}
 }
 
@@ -34,14 +57,25 @@ func (f *FSTask) Create(ctx context.Context, in Task) (Task, error) {
 	docRef := f.fs.Doc(in.UserID).Collection(CollectionTasks).NewDoc()
 	in.TaskID = docRef.ID
 	in.CreatedAt = time.Now().Unix()
-	// todo validation for time
-	// todo validation of input strings -> max length of name , desc
-	_, err := docRef.Set(ctx, in)
+	in.UpdatedAt = in.CreatedAt
+	in.CompletedAt = completedAt(nil, in)
+	err := f.scheduleReminders(ctx, nil, &in)
 	if err != nil {
 		return Task{}, err
 	}
-	// redundant data for optimization
-	_, err = f.client.Collection(TaskList).Doc(docRef.ID).Set(ctx, in)
+	in.ScheduleOverdue(nil, in.CreatedAt)
+	err = f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		err := tx.Create(docRef, in)
+		if err != nil {
+			return err
+		}
+		// redundant data for optimization
+		err = tx.Set(f.client.Collection(TaskList).Doc(docRef.ID), in)
+		if err != nil {
+			return err
+		}
+		return f.recordWrite(tx, in.UserID, docRef.ID, nil, &in)
+	})
 	if err != nil {
 		return Task{}, err
 	}
@@ -61,34 +95,166 @@ func (f *FSTask) Get(ctx context.Context, userID, taskID string) (Task, error) {
 	return task, nil
 }
 
-func (f *FSTask) Update(ctx context.Context, newTask Task, userID, taskID string) (Task, error) {
-	// todo add field updatedAt instead of updating createdAt
-	newTask.CreatedAt = time.Now().Unix()
-	_, err := f.fs.Doc(userID).Collection(CollectionTasks).Doc(taskID).Set(ctx, newTask)
+// GetByCalDAVName returns the task of the user created by a CalDAV client under the resource name
+func (f *FSTask) GetByCalDAVName(ctx context.Context, userID, name string) (Task, error) {
+	docs, err := f.fs.Doc(userID).Collection(CollectionTasks).
+		Where("caldavName", "==", name).Limit(1).Documents(ctx).GetAll()
 	if err != nil {
 		return Task{}, err
 	}
-	// redundant data for optimization
-	_, err = f.client.Collection(TaskList).Doc(taskID).Set(ctx, newTask)
+	if len(docs) == 0 {
+		return Task{}, status.Error(codes.NotFound, "task not found")
+	}
+	task := Task{}
+	err = docs[0].DataTo(&task)
 	if err != nil {
 		return Task{}, err
 	}
-	return newTask, nil
+	return task, nil
+}
+
+// Update replaces the task, the write is derived from the stored task in a transaction,
+// so that concurrent writes don't count into the stats twice
+func (f *FSTask) Update(ctx context.Context, newTask Task, userID, taskID string) (Task, error) {
+	docRef := f.fs.Doc(userID).Collection(CollectionTasks).Doc(taskID)
+	var updated Task
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		task := newTask
+		// the task is stored under the user, it can't be handed over to anybody else
+		task.UserID = userID
+		before, err := f.getByRef(tx, docRef)
+		if err != nil {
+			return err
+		}
+		if before != nil {
+			task.CreatedAt = before.CreatedAt
+			// updates through the API don't know the UID and the name given by CalDAV clients
+			if task.ICalUID == "" {
+				task.ICalUID = before.ICalUID
+			}
+			if task.CalDAVName == "" {
+				task.CalDAVName = before.CalDAVName
+			}
+		} else {
+			task.CreatedAt = time.Now().Unix()
+		}
+		task.UpdatedAt = time.Now().Unix()
+		task.CompletedAt = completedAt(before, task)
+		err = f.scheduleReminders(ctx, before, &task)
+		if err != nil {
+			return err
+		}
+		task.ScheduleOverdue(before, task.UpdatedAt)
+		// reminders of a completed task are not sent anymore
+		var pending []*firestore.DocumentRef
+		if before != nil && !before.Completed && task.Completed {
+			pending, err = pendingDeliveries(tx, f.client, taskID)
+			if err != nil {
+				return err
+			}
+		}
+		err = tx.Set(docRef, task)
+		if err != nil {
+			return err
+		}
+		err = deleteAll(tx, pending)
+		if err != nil {
+			return err
+		}
+		// redundant data for optimization
+		err = tx.Set(f.client.Collection(TaskList).Doc(taskID), task)
+		if err != nil {
+			return err
+		}
+		updated = task
+		return f.recordWrite(tx, userID, taskID, before, &task)
+	})
+	if err != nil {
+		return Task{}, err
+	}
+	return updated, nil
 }
 
 func (f *FSTask) Delete(ctx context.Context, userID, taskID string) error {
-	_, err := f.fs.Doc(userID).Collection(CollectionTasks).Doc(taskID).Delete(ctx)
+	docRef := f.fs.Doc(userID).Collection(CollectionTasks).Doc(taskID)
+	return f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		before, err := f.getByRef(tx, docRef)
+		if err != nil {
+			return err
+		}
+		pending, err := pendingDeliveries(tx, f.client, taskID)
+		if err != nil {
+			return err
+		}
+		err = tx.Delete(docRef)
+		if err != nil {
+			return err
+		}
+		err = deleteAll(tx, pending)
+		if err != nil {
+			return err
+		}
+		// redundant operation for optimization
+		err = tx.Delete(f.client.Collection(TaskList).Doc(taskID))
+		if err != nil || before == nil {
+			return err
+		}
+		return f.recordWrite(tx, userID, taskID, before, nil)
+	})
+}
+
+// recordWrite adds the stats, the change log entry and the event of the task write into the transaction.
+// before is nil for newly created tasks and after is nil for deleted tasks
+func (f *FSTask) recordWrite(tx *firestore.Transaction, userID, taskID string, before, after *Task) error {
+	err := f.recordStats(tx, userID, before, after)
 	if err != nil {
 		return err
 	}
-	// redundant operation for optimization
-	_, err = f.client.Collection(TaskList).Doc(taskID).Delete(ctx)
+	err = f.recordChange(tx, userID, taskID, before, after)
 	if err != nil {
 		return err
 	}
+	return f.recordEvent(tx, userID, taskID, before, after)
+}
+
+func deleteAll(tx *firestore.Transaction, refs []*firestore.DocumentRef) error {
+	for _, ref := range refs {
+		err := tx.Delete(ref)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// getByRef returns nil without error when the task does not exist
+func (f *FSTask) getByRef(tx *firestore.Transaction, docRef *firestore.DocumentRef) (*Task, error) {
+	doc, err := tx.Get(docRef)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	task := Task{}
+	err = doc.DataTo(&task)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// completedAt keeps the original completion time of tasks that were already completed
+func completedAt(before *Task, after Task) int64 {
+	if !after.Completed {
+		return 0
+	}
+	if before != nil && before.Completed {
+		return before.CompletedAt
+	}
+	return time.Now().Unix()
+}
+
 func (f *FSTask) GetLastN(ctx context.Context, userID string, n int32) (tasks []Task, err error) {
 	// todo -> put cap on a number of tasks returned?
 	taskQuery := f.fs.Doc(userID).Collection(CollectionTasks).OrderBy("createdAt", firestore.Desc).Limit(int(n)).Documents(ctx)
@@ -110,6 +276,65 @@ func (f *FSTask) GetLastN(ctx context.Context, userID string, n int32) (tasks []
 	return tasks, nil
 }
 
+// GetAll returns every task of the user ordered from the oldest
+func (f *FSTask) GetAll(ctx context.Context, userID string) (tasks []Task, err error) {
+	taskQuery := f.fs.Doc(userID).Collection(CollectionTasks).OrderBy("createdAt", firestore.Asc).Documents(ctx)
+	for {
+		doc, err := taskQuery.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		task := Task{}
+		err = doc.DataTo(&task)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// PageCursor points at the last task of a page returned by GetPage
+type PageCursor struct {
+	CreatedAt int64
+	TaskID    string
+}
+
+func (t Task) PageCursor() PageCursor {
+	return PageCursor{CreatedAt: t.CreatedAt, TaskID: t.TaskID}
+}
+
+// GetPage returns at most n tasks of the user created before the cursor, newest first.
+// The zero cursor starts from the newest task, the ID orders tasks created in the same second
+func (f *FSTask) GetPage(ctx context.Context, userID string, after PageCursor, n int) (tasks []Task, err error) {
+	query := f.fs.Doc(userID).Collection(CollectionTasks).
+		OrderBy("createdAt", firestore.Desc).
+		OrderBy(firestore.DocumentID, firestore.Desc)
+	if after.TaskID != "" {
+		query = query.StartAfter(after.CreatedAt, after.TaskID)
+	}
+	taskQuery := query.Limit(n).Documents(ctx)
+	for {
+		doc, err := taskQuery.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		task := Task{}
+		err = doc.DataTo(&task)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
 func (f *FSTask) GetExpired(ctx context.Context, userID string) (expiredTasks []Task, err error) {
 	taskQuery := f.fs.Doc(userID).Collection(CollectionTasks).Where("time", "<=", time.Now().Unix()).Documents(ctx)
 	for {
@@ -130,12 +355,12 @@ func (f *FSTask) GetExpired(ctx context.Context, userID string) (expiredTasks []
 	return expiredTasks, nil
 }
 
-func (f *FSTask) SearchForExpiringTasks(ctx context.Context) (map[string][]Task, error) {
+// SearchForExpiringTasks returns the tasks with a reminder due at now by the email of their owner
+func (f *FSTask) SearchForExpiringTasks(ctx context.Context, now int64) (map[string][]Task, error) {
 	toRemind := make(map[string][]Task)
 	taskDocs, err := f.client.Collection(TaskList).
-		Where("reminderSent", "==", false).
-		Where("time", ">", time.Now().Unix()).
-		Where("time", "<", time.Now().Add(time.Minute*5).Unix()).Documents(ctx).GetAll()
+		Where("nextReminderAt", ">", 0).
+		Where("nextReminderAt", "<=", now).Documents(ctx).GetAll()
 	if err != nil {
 		return nil, err
 	}
@@ -149,3 +374,81 @@ func (f *FSTask) SearchForExpiringTasks(ctx context.Context) (map[string][]Task,
 	}
 	return toRemind, nil
 }
+
+// SearchForUpcomingReminders returns the tasks with a reminder due until then, the earliest first.
+// The ones that are due already are included, so that late reminders are sent too
+func (f *FSTask) SearchForUpcomingReminders(ctx context.Context, until int64) (tasks []Task, err error) {
+	taskDocs, err := f.client.Collection(TaskList).
+		Where("nextReminderAt", ">", 0).
+		Where("nextReminderAt", "<=", until).
+		OrderBy("nextReminderAt", firestore.Asc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, taskDoc := range taskDocs {
+		task := Task{}
+		err = taskDoc.DataTo(&task)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// List queries the task_list collection across all users, narrowed down by the optional filter fields
+func (f *FSTask) List(ctx context.Context, filter TaskFilter) (tasks []Task, err error) {
+	query := f.client.Collection(TaskList).Query
+	if filter.UserID != "" {
+		query = query.Where("userID", "==", filter.UserID)
+	}
+	if filter.UserEmail != "" {
+		query = query.Where("email", "==", filter.UserEmail)
+	}
+	limit := int(filter.N)
+	if limit <= 0 || limit > MaxListSize {
+		limit = MaxListSize
+	}
+	taskQuery := query.OrderBy("createdAt", firestore.Desc).Limit(limit).Documents(ctx)
+	for {
+		doc, err := taskQuery.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		task := Task{}
+		err = doc.DataTo(&task)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// GetDailyStats returns the daily aggregates of the user for days between the unix times from and to.
+// Days without any activity have no document and are left out
+func (f *FSTask) GetDailyStats(ctx context.Context, userID string, from, to int64) (stats []DailyStats, err error) {
+	statsQuery := f.fs.Doc(userID).Collection(CollectionStats).
+		Where("date", ">=", StatsDate(from)).
+		Where("date", "<=", StatsDate(to)).
+		OrderBy("date", firestore.Asc).Documents(ctx)
+	for {
+		doc, err := statsQuery.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		day := DailyStats{}
+		err = doc.DataTo(&day)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, day)
+	}
+	return stats, nil
+}
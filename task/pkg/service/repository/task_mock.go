@@ -18,6 +18,11 @@ func (m *FSTaskMock) Get(ctx context.Context, userID, taskID string) (Task, erro
 	return args.Get(0).(Task), args.Error(1)
 }
 
+func (m *FSTaskMock) GetByCalDAVName(ctx context.Context, userID, name string) (Task, error) {
+	args := m.Called(ctx, userID, name)
+	return args.Get(0).(Task), args.Error(1)
+}
+
 func (m *FSTaskMock) Create(ctx context.Context, in Task) (Task, error) {
 	args := m.Called(ctx, in)
 	return args.Get(0).(Task), args.Error(1)
@@ -38,12 +43,97 @@ func (m *FSTaskMock) GetLastN(ctx context.Context, userID string, n int32) (task
 	return args.Get(0).([]Task), args.Error(1)
 }
 
+func (m *FSTaskMock) GetPage(ctx context.Context, userID string, after PageCursor, n int) (tasks []Task, err error) {
+	args := m.Called(ctx, userID, after, n)
+	return args.Get(0).([]Task), args.Error(1)
+}
+
 func (m *FSTaskMock) GetExpired(ctx context.Context, userID string) (expiredTasks []Task, err error) {
 	args := m.Called(ctx, userID)
 	return args.Get(0).([]Task), args.Error(1)
 }
 
-func (m *FSTaskMock) SearchForExpiringTasks(ctx context.Context) (map[string][]Task, error) {
-	args := m.Called(ctx)
+func (m *FSTaskMock) SearchForExpiringTasks(ctx context.Context, now int64) (map[string][]Task, error) {
+	args := m.Called(ctx, now)
 	return args.Get(0).(map[string][]Task), args.Error(1)
 }
+
+func (m *FSTaskMock) BackfillReminders(ctx context.Context, now int64) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *FSTaskMock) BackfillOverdue(ctx context.Context, now int64) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *FSTaskMock) SearchForUpcomingReminders(ctx context.Context, until int64) ([]Task, error) {
+	args := m.Called(ctx, until)
+	return args.Get(0).([]Task), args.Error(1)
+}
+
+func (m *FSTaskMock) SearchForOverdueTasks(ctx context.Context, now int64) ([]Task, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]Task), args.Error(1)
+}
+
+func (m *FSTaskMock) List(ctx context.Context, filter TaskFilter) (tasks []Task, err error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Task), args.Error(1)
+}
+
+func (m *FSTaskMock) GetDailyStats(ctx context.Context, userID string, from, to int64) (stats []DailyStats, err error) {
+	args := m.Called(ctx, userID, from, to)
+	return args.Get(0).([]DailyStats), args.Error(1)
+}
+
+func (m *FSTaskMock) GetAll(ctx context.Context, userID string) (tasks []Task, err error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]Task), args.Error(1)
+}
+
+func (m *FSTaskMock) GetChanges(ctx context.Context, userID string, after SyncCursor, n int) (changes []Change, err error) {
+	args := m.Called(ctx, userID, after, n)
+	return args.Get(0).([]Change), args.Error(1)
+}
+
+func (m *FSTaskMock) GetLatestChange(ctx context.Context, userID string) (SyncCursor, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(SyncCursor), args.Error(1)
+}
+
+func (m *FSTaskMock) WatchChanges(ctx context.Context, userID string, after SyncCursor, handle func(Change) error) error {
+	args := m.Called(ctx, userID, after, handle)
+	return args.Error(0)
+}
+
+func (m *FSTaskMock) GetReminderSettings(ctx context.Context, userID string) (ReminderSettings, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(ReminderSettings), args.Error(1)
+}
+
+func (m *FSTaskMock) SetReminderSettings(ctx context.Context, settings ReminderSettings) (ReminderSettings, error) {
+	args := m.Called(ctx, settings)
+	return args.Get(0).(ReminderSettings), args.Error(1)
+}
+
+func (m *FSTaskMock) GetEscalationPolicy(ctx context.Context, userID string) (EscalationPolicy, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(EscalationPolicy), args.Error(1)
+}
+
+func (m *FSTaskMock) SetEscalationPolicy(ctx context.Context, policy EscalationPolicy) (EscalationPolicy, error) {
+	args := m.Called(ctx, policy)
+	return args.Get(0).(EscalationPolicy), args.Error(1)
+}
+
+func (m *FSTaskMock) RescheduleOverdue(ctx context.Context, now int64) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *FSTaskMock) VerifyEscalationEmail(ctx context.Context, userID, email string) (bool, error) {
+	args := m.Called(ctx, userID, email)
+	return args.Bool(0), args.Error(1)
+}
@@ -8,17 +8,48 @@ const (
 	CollectionTasks = "tasks"
 	CollectionUsers = "users"
 	TaskList        = "task_list"
+
+	// MaxListSize caps the number of tasks returned by a single List query
+	MaxListSize = 500
 )
 
 type Task struct {
-	CreatedAt    int64  `firestore:"createdAt"`
-	Name         string `firestore:"name"`
-	Description  string `firestore:"description"`
-	UserID       string `firestore:"userID"`
-	UserEmail    string `firestore:"email"`
-	Time         int64  `firestore:"time"`
-	TaskID       string `firestore:"taskID"`
-	ReminderSent bool   `firestore:"reminderSent"`
+	CreatedAt   int64  `firestore:"createdAt"`
+	UpdatedAt   int64  `firestore:"updatedAt"`
+	Name        string `firestore:"name"`
+	Description string `firestore:"description"`
+	UserID      string `firestore:"userID"`
+	UserEmail   string `firestore:"email"`
+	Time        int64  `firestore:"time"`
+	TaskID      string `firestore:"taskID"`
+	Completed   bool   `firestore:"completed"`
+	CompletedAt int64  `firestore:"completedAt"`
+	Recurrence  string `firestore:"recurrence"`
+	Priority    string `firestore:"priority"`
+	// Reminders are offsets in seconds before Time, nil gets the defaults of the user
+	Reminders []int64 `firestore:"reminders"`
+	// RemindersSent are the offsets of Reminders already sent
+	RemindersSent []int64 `firestore:"remindersSent"`
+	// NextReminderAt is the unix time of the next reminder to send, 0 when there is none
+	NextReminderAt int64 `firestore:"nextReminderAt"`
+	// StopNagging turns off the overdue reminders and the escalation of the task
+	StopNagging bool `firestore:"stopNagging"`
+	// OverdueNotices counts the overdue reminders sent, Escalated tells whether the task was escalated.
+	// NextOverdueAt is the unix time the escalation policy is applied to the task next, 0 when it's done
+	OverdueNotices int32 `firestore:"overdueNotices"`
+	Escalated      bool  `firestore:"escalated"`
+	NextOverdueAt  int64 `firestore:"nextOverdueAt"`
+	// ICalUID is the UID of the VTODO the task was created from by a CalDAV client
+	ICalUID string `firestore:"icalUID,omitempty"`
+	// CalDAVName is the resource name the CalDAV client created the task under, other tasks are named by their ID
+	CalDAVName string `firestore:"caldavName,omitempty"`
+}
+
+// TaskFilter narrows down tasks listed across all users, empty fields are ignored
+type TaskFilter struct {
+	UserID    string
+	UserEmail string
+	N         int32
 }
 
 // User type redefined in the task microservice to maintain its independence on the user microservice
@@ -40,18 +71,43 @@ func TaskFromMsg(msg *v1.Task) Task {
 		Time:        msg.Time,
 		TaskID:      msg.TaskId,
 		UserEmail:   msg.UserEmail,
+		Completed:   msg.Completed,
+		Recurrence:  msg.Recurrence,
+		Priority:    msg.Priority,
+		Reminders:   remindersFromMsg(msg),
+		StopNagging: msg.StopNagging,
+	}
+}
+
+// remindersFromMsg keeps reminders missing in the message unset, an empty repeated field can't be told
+// from a missing one, so the reminders are cleared only when the message asks for it
+func remindersFromMsg(msg *v1.Task) []int64 {
+	if len(msg.Reminders) > 0 {
+		return msg.Reminders
+	}
+	if msg.ClearReminders {
+		return []int64{}
 	}
+	return nil
 }
 
 func ToApi(task Task) *v1.Task {
 	return &v1.Task{
-		TaskId:      task.TaskID,
-		CreatedAt:   task.CreatedAt,
-		Name:        task.Name,
-		Description: task.Description,
-		Time:        task.Time,
-		UserId:      task.UserID,
-		UserEmail:   task.UserEmail,
+		TaskId:         task.TaskID,
+		CreatedAt:      task.CreatedAt,
+		UpdatedAt:      task.UpdatedAt,
+		Name:           task.Name,
+		Description:    task.Description,
+		Time:           task.Time,
+		UserId:         task.UserID,
+		UserEmail:      task.UserEmail,
+		Completed:      task.Completed,
+		CompletedAt:    task.CompletedAt,
+		Recurrence:     task.Recurrence,
+		Priority:       task.Priority,
+		Reminders:      task.Reminders,
+		NextReminderAt: task.NextReminderAt,
+		StopNagging:    task.StopNagging,
 	}
 }
 
@@ -59,13 +115,21 @@ func SliceToApi(tasks []Task) *v1.TaskList {
 	apiTasks := make([]*v1.Task, len(tasks))
 	for i, task := range tasks {
 		apiTasks[i] = &v1.Task{
-			TaskId:      task.TaskID,
-			CreatedAt:   task.CreatedAt,
-			Name:        task.Name,
-			Description: task.Description,
-			Time:        task.Time,
-			UserId:      task.UserID,
-			UserEmail:   task.UserEmail,
+			TaskId:         task.TaskID,
+			CreatedAt:      task.CreatedAt,
+			UpdatedAt:      task.UpdatedAt,
+			Name:           task.Name,
+			Description:    task.Description,
+			Time:           task.Time,
+			UserId:         task.UserID,
+			UserEmail:      task.UserEmail,
+			Completed:      task.Completed,
+			CompletedAt:    task.CompletedAt,
+			Recurrence:     task.Recurrence,
+			Priority:       task.Priority,
+			Reminders:      task.Reminders,
+			NextReminderAt: task.NextReminderAt,
+			StopNagging:    task.StopNagging,
 		}
 	}
 	return &v1.TaskList{Tasks: apiTasks}
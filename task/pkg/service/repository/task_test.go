@@ -6,6 +6,8 @@ package repository
 import (
 	"cloud.google.com/go/firestore"
 	"context"
+	"errors"
+	"github.com/jakubjano/todolist/task/pkg/events"
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -103,187 +105,169 @@ func (s *RepoTaskTestSuite) SetupTest() {
 
 	tasks := []Task{
 		{
-			CreatedAt:    1,
-			Name:         "task1",
-			Description:  "desc1",
-			UserID:       "1",
-			UserEmail:    "example1@tst.com",
-			Time:         7,
-			TaskID:       "tid1",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    2,
-			Name:         "task2",
-			Description:  "desc2",
-			UserID:       "1",
-			UserEmail:    "example1@tst.com",
-			Time:         time.Now().Add(time.Minute * 1).Unix(),
-			TaskID:       "tid2",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    3,
-			Name:         "task3",
-			Description:  "desc3",
-			UserID:       "1",
-			UserEmail:    "example1@tst.com",
-			Time:         10,
-			TaskID:       "tid3",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    5,
-			Name:         "task4",
-			Description:  "desc4",
-			UserID:       "2",
-			UserEmail:    "example2@tst.com",
-			Time:         10,
-			TaskID:       "tid4",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    5,
-			Name:         "task5",
-			Description:  "desc5",
-			UserID:       "3",
-			UserEmail:    "example3@tst.com",
-			Time:         10,
-			TaskID:       "tid5",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    5,
-			Name:         "task6",
-			Description:  "desc6",
-			UserID:       "4",
-			UserEmail:    "example6@tst.com",
-			Time:         10,
-			TaskID:       "tid6",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task1",
+			Description: "desc1",
+			UserID:      "1",
+			UserEmail:   "example1@tst.com",
+			Time:        7,
+			TaskID:      "tid1",
+		},
+		{
+			CreatedAt:   2,
+			Name:        "task2",
+			Description: "desc2",
+			UserID:      "1",
+			UserEmail:   "example1@tst.com",
+			Time:        time.Now().Add(time.Minute * 1).Unix(),
+			TaskID:      "tid2",
+		},
+		{
+			CreatedAt:   3,
+			Name:        "task3",
+			Description: "desc3",
+			UserID:      "1",
+			UserEmail:   "example1@tst.com",
+			Time:        10,
+			TaskID:      "tid3",
+		},
+		{
+			CreatedAt:   5,
+			Name:        "task4",
+			Description: "desc4",
+			UserID:      "2",
+			UserEmail:   "example2@tst.com",
+			Time:        10,
+			TaskID:      "tid4",
+		},
+		{
+			CreatedAt:   5,
+			Name:        "task5",
+			Description: "desc5",
+			UserID:      "3",
+			UserEmail:   "example3@tst.com",
+			Time:        10,
+			TaskID:      "tid5",
+		},
+		{
+			CreatedAt:   5,
+			Name:        "task6",
+			Description: "desc6",
+			UserID:      "4",
+			UserEmail:   "example6@tst.com",
+			Time:        10,
+			TaskID:      "tid6",
 		},
 		// tasks for reminder batch write testing
 		// User 6
 		{
-			CreatedAt:    1,
-			Name:         "task7",
-			Description:  "desc7",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid7",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task8",
-			Description:  "desc8",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid8",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task9",
-			Description:  "desc9",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid9",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task10",
-			Description:  "desc10",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid10",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task11",
-			Description:  "desc11",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid11",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task12",
-			Description:  "desc12",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid12",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task7",
+			Description: "desc7",
+			UserID:      "6",
+			UserEmail:   "example6@tst.com",
+			Time:        time.Now().Add(time.Minute * 4).Unix(),
+			TaskID:      "tid7",
+		},
+		{
+			CreatedAt:   1,
+			Name:        "task8",
+			Description: "desc8",
+			UserID:      "6",
+			UserEmail:   "example6@tst.com",
+			Time:        time.Now().Add(time.Minute * 4).Unix(),
+			TaskID:      "tid8",
+		},
+		{
+			CreatedAt:   1,
+			Name:        "task9",
+			Description: "desc9",
+			UserID:      "6",
+			UserEmail:   "example6@tst.com",
+			Time:        time.Now().Add(time.Minute * 4).Unix(),
+			TaskID:      "tid9",
+		},
+		{
+			CreatedAt:   1,
+			Name:        "task10",
+			Description: "desc10",
+			UserID:      "6",
+			UserEmail:   "example6@tst.com",
+			Time:        time.Now().Add(time.Minute * 4).Unix(),
+			TaskID:      "tid10",
+		},
+		{
+			CreatedAt:   1,
+			Name:        "task11",
+			Description: "desc11",
+			UserID:      "6",
+			UserEmail:   "example6@tst.com",
+			Time:        time.Now().Add(time.Minute * 4).Unix(),
+			TaskID:      "tid11",
+		},
+		{
+			CreatedAt:   1,
+			Name:        "task12",
+			Description: "desc12",
+			UserID:      "6",
+			UserEmail:   "example6@tst.com",
+			Time:        time.Now().Add(time.Minute * 4).Unix(),
+			TaskID:      "tid12",
 		},
 		// User 7
 		{
-			CreatedAt:    1,
-			Name:         "task13",
-			Description:  "desc13",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid13",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task14",
-			Description:  "desc14",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid14",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task15",
-			Description:  "desc15",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid15",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task16",
-			Description:  "desc16",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid16",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task17",
-			Description:  "desc17",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid17",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task18",
-			Description:  "desc18",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid18",
-			ReminderSent: false,
+			CreatedAt:   1,
+			Name:        "task13",
+			Description: "desc13",
+			UserID:      "7",
+			UserEmail:   "example7@tst.com",
+			Time:        time.Now().Add(time.Minute * 3).Unix(),
+			TaskID:      "tid13",
+		},
+		{
+			CreatedAt:   1,
+			Name:        "task14",
+			Description: "desc14",
+			UserID:      "7",
+			UserEmail:   "example7@tst.com",
+			Time:        time.Now().Add(time.Minute * 3).Unix(),
+			TaskID:      "tid14",
+		},
+		{
+			CreatedAt:   1,
+			Name:        "task15",
+			Description: "desc15",
+			UserID:      "7",
+			UserEmail:   "example7@tst.com",
+			Time:        time.Now().Add(time.Minute * 3).Unix(),
+			TaskID:      "tid15",
+		},
+		{
+			CreatedAt:   1,
+			Name:        "task16",
+			Description: "desc16",
+			UserID:      "7",
+			UserEmail:   "example7@tst.com",
+			Time:        time.Now().Add(time.Minute * 3).Unix(),
+			TaskID:      "tid16",
+		},
+		{
+			CreatedAt:   1,
+			Name:        "task17",
+			Description: "desc17",
+			UserID:      "7",
+			UserEmail:   "example7@tst.com",
+			Time:        time.Now().Add(time.Minute * 3).Unix(),
+			TaskID:      "tid17",
+		},
+		{
+			CreatedAt:   1,
+			Name:        "task18",
+			Description: "desc18",
+			UserID:      "7",
+			UserEmail:   "example7@tst.com",
+			Time:        time.Now().Add(time.Minute * 3).Unix(),
+			TaskID:      "tid18",
 		},
 	}
 
@@ -305,6 +289,9 @@ func (s *RepoTaskTestSuite) SetupTest() {
 	taskListBatch := s.client.Batch()
 	for _, task := range tasks {
 		taskListRef := s.client.Collection(TaskList).Doc(task.TaskID)
+		// reminders are searched for in the task list only
+		task.Reminders = DefaultReminders
+		task.ScheduleReminders(time.Now().Unix())
 		taskListBatch.Set(taskListRef, task)
 	}
 	_, err = taskListBatch.Commit(ctx)
@@ -353,14 +340,13 @@ func (s *RepoTaskTestSuite) TestGetTask() {
 			taskID: "tid1",
 			userID: "1",
 			expectedResult: Task{
-				CreatedAt:    1,
-				Name:         "task1",
-				Description:  "desc1",
-				UserID:       "1",
-				UserEmail:    "example1@tst.com",
-				Time:         7,
-				TaskID:       "tid1",
-				ReminderSent: false,
+				CreatedAt:   1,
+				Name:        "task1",
+				Description: "desc1",
+				UserID:      "1",
+				UserEmail:   "example1@tst.com",
+				Time:        7,
+				TaskID:      "tid1",
 			},
 			expectedCode: codes.OK,
 		},
@@ -404,24 +390,24 @@ func (s *RepoTaskTestSuite) TestCreateTask() {
 		// valid input
 		{
 			input: Task{
-				CreatedAt:    time.Now().Unix(),
-				Name:         "task1",
-				Description:  "task1desc",
-				UserID:       "5",
-				UserEmail:    "example5@tst.com",
-				Time:         10,
-				TaskID:       "6",
-				ReminderSent: false,
+				CreatedAt:   time.Now().Unix(),
+				Name:        "task1",
+				Description: "task1desc",
+				UserID:      "5",
+				UserEmail:   "example5@tst.com",
+				Time:        10,
+				TaskID:      "6",
 			},
 			expectedResult: Task{
-				CreatedAt:    time.Now().Unix(),
-				Name:         "task1",
-				Description:  "task1desc",
-				UserID:       "5",
-				UserEmail:    "example5@tst.com",
-				Time:         10,
-				TaskID:       "6",
-				ReminderSent: false,
+				CreatedAt:   time.Now().Unix(),
+				UpdatedAt:   time.Now().Unix(),
+				Name:        "task1",
+				Description: "task1desc",
+				UserID:      "5",
+				UserEmail:   "example5@tst.com",
+				Time:        10,
+				TaskID:      "6",
+				Reminders:   DefaultReminders,
 			},
 			expectedCode: codes.OK,
 		},
@@ -435,7 +421,6 @@ func (s *RepoTaskTestSuite) TestCreateTask() {
 		//		UserEmail:    "",
 		//		Time:         0,
 		//		TaskID:       "",
-		//		ReminderSent: false,
 		//	},
 		//	expectedResult: Task{},
 		//	expectedCode:   codes.InvalidArgument,
@@ -444,24 +429,26 @@ func (s *RepoTaskTestSuite) TestCreateTask() {
 		//-> userID, userEmail is passed through context
 		{
 			input: Task{
-				CreatedAt:    time.Now().Unix(),
-				Name:         "wrong_email",
-				Description:  "task with invalid email",
-				UserID:       "4",
-				UserEmail:    "@@@gamil.cz",
-				Time:         time.Now().Add(time.Minute * 60).Unix(),
-				TaskID:       "55",
-				ReminderSent: false,
+				CreatedAt:   time.Now().Unix(),
+				Name:        "wrong_email",
+				Description: "task with invalid email",
+				UserID:      "4",
+				UserEmail:   "@@@gamil.cz",
+				Time:        time.Now().Add(time.Minute * 60).Unix(),
+				TaskID:      "55",
 			},
 			expectedResult: Task{
-				CreatedAt:    time.Now().Unix(),
-				Name:         "wrong_email",
-				Description:  "task with invalid email",
-				UserID:       "4",
-				UserEmail:    "@@@gamil.cz",
-				Time:         time.Now().Add(time.Minute * 60).Unix(),
-				TaskID:       "55",
-				ReminderSent: false,
+				CreatedAt:   time.Now().Unix(),
+				UpdatedAt:   time.Now().Unix(),
+				Name:        "wrong_email",
+				Description: "task with invalid email",
+				UserID:      "4",
+				UserEmail:   "@@@gamil.cz",
+				Time:        time.Now().Add(time.Minute * 60).Unix(),
+				TaskID:      "55",
+				// 5 minutes before the due time
+				Reminders:      DefaultReminders,
+				NextReminderAt: time.Now().Add(time.Minute * 55).Unix(),
 			},
 			expectedCode: codes.OK,
 		},
@@ -489,24 +476,23 @@ func (s *RepoTaskTestSuite) TestUpdateTask() {
 			userID: "1",
 			taskID: "tid1",
 			input: Task{
-				CreatedAt:    5,
-				Name:         "newName",
-				Description:  "newDesc",
-				UserID:       "1",
-				UserEmail:    "example1@tst.com",
-				Time:         11,
-				TaskID:       "tid1",
-				ReminderSent: false,
+				CreatedAt:   5,
+				Name:        "newName",
+				Description: "newDesc",
+				UserID:      "1",
+				UserEmail:   "example1@tst.com",
+				Time:        11,
+				TaskID:      "tid1",
 			},
 			expectedResult: Task{
-				CreatedAt:    5,
-				Name:         "newName",
-				Description:  "newDesc",
-				UserID:       "1",
-				UserEmail:    "example1@tst.com",
-				Time:         11,
-				TaskID:       "tid1",
-				ReminderSent: false,
+				CreatedAt:   5,
+				Name:        "newName",
+				Description: "newDesc",
+				UserID:      "1",
+				UserEmail:   "example1@tst.com",
+				Time:        11,
+				TaskID:      "tid1",
+				Reminders:   DefaultReminders,
 			},
 			expectedCode: codes.OK,
 		},
@@ -515,24 +501,48 @@ func (s *RepoTaskTestSuite) TestUpdateTask() {
 			userID: "1",
 			taskID: "tid777",
 			input: Task{
-				CreatedAt:    5,
-				Name:         "newName",
-				Description:  "newDesc",
-				UserID:       "1",
-				UserEmail:    "example1@tst.com",
-				Time:         11,
-				TaskID:       "tid777",
-				ReminderSent: false,
+				CreatedAt:   5,
+				Name:        "newName",
+				Description: "newDesc",
+				UserID:      "1",
+				UserEmail:   "example1@tst.com",
+				Time:        11,
+				TaskID:      "tid777",
+			},
+			expectedResult: Task{
+				CreatedAt:   5,
+				Name:        "newName",
+				Description: "newDesc",
+				UserID:      "1",
+				UserEmail:   "example1@tst.com",
+				Time:        11,
+				TaskID:      "tid777",
+				Reminders:   DefaultReminders,
+			},
+			expectedCode: codes.OK,
+		},
+		// the task stays with the user it is stored under
+		{
+			userID: "1",
+			taskID: "tid1",
+			input: Task{
+				CreatedAt:   5,
+				Name:        "newName",
+				Description: "newDesc",
+				UserID:      "2",
+				UserEmail:   "example1@tst.com",
+				Time:        11,
+				TaskID:      "tid1",
 			},
 			expectedResult: Task{
-				CreatedAt:    5,
-				Name:         "newName",
-				Description:  "newDesc",
-				UserID:       "1",
-				UserEmail:    "example1@tst.com",
-				Time:         11,
-				TaskID:       "tid777",
-				ReminderSent: false,
+				CreatedAt:   5,
+				Name:        "newName",
+				Description: "newDesc",
+				UserID:      "1",
+				UserEmail:   "example1@tst.com",
+				Time:        11,
+				TaskID:      "tid1",
+				Reminders:   DefaultReminders,
 			},
 			expectedCode: codes.OK,
 		},
@@ -540,6 +550,8 @@ func (s *RepoTaskTestSuite) TestUpdateTask() {
 	for i, candidate := range candidates {
 		task, err := s.taskRepo.Update(ctx, candidate.input, candidate.userID, candidate.taskID)
 		task.CreatedAt = candidate.input.CreatedAt
+		s.NotZerof(task.UpdatedAt, "candidate %d", i+1)
+		task.UpdatedAt = 0
 		s.Equalf(candidate.expectedResult, task, "candidate %d", i+1)
 		s.Equalf(candidate.expectedCode, status.Code(err), "candidate %d", i+1)
 	}
@@ -595,24 +607,22 @@ func (s *RepoTaskTestSuite) TestGetLastNTasks() {
 			n:      2,
 			expectedResult: []Task{
 				{
-					CreatedAt:    3,
-					Name:         "task3",
-					Description:  "desc3",
-					UserID:       "1",
-					UserEmail:    "example1@tst.com",
-					Time:         10,
-					TaskID:       "tid3",
-					ReminderSent: false,
+					CreatedAt:   3,
+					Name:        "task3",
+					Description: "desc3",
+					UserID:      "1",
+					UserEmail:   "example1@tst.com",
+					Time:        10,
+					TaskID:      "tid3",
 				},
 				{
-					CreatedAt:    2,
-					Name:         "task2",
-					Description:  "desc2",
-					UserID:       "1",
-					UserEmail:    "example1@tst.com",
-					Time:         time.Now().Add(time.Minute * 1).Unix(),
-					TaskID:       "tid2",
-					ReminderSent: false,
+					CreatedAt:   2,
+					Name:        "task2",
+					Description: "desc2",
+					UserID:      "1",
+					UserEmail:   "example1@tst.com",
+					Time:        time.Now().Add(time.Minute * 1).Unix(),
+					TaskID:      "tid2",
 				},
 			},
 			expectedCode: codes.OK,
@@ -623,14 +633,13 @@ func (s *RepoTaskTestSuite) TestGetLastNTasks() {
 			n:      3,
 			expectedResult: []Task{
 				{
-					CreatedAt:    5,
-					Name:         "task4",
-					Description:  "desc4",
-					UserID:       "2",
-					UserEmail:    "example2@tst.com",
-					Time:         10,
-					TaskID:       "tid4",
-					ReminderSent: false,
+					CreatedAt:   5,
+					Name:        "task4",
+					Description: "desc4",
+					UserID:      "2",
+					UserEmail:   "example2@tst.com",
+					Time:        10,
+					TaskID:      "tid4",
 				},
 			},
 			expectedCode: codes.OK,
@@ -656,24 +665,22 @@ func (s *RepoTaskTestSuite) TestGetExpiredTasks() {
 			userID: "1",
 			expectedResult: []Task{
 				{
-					CreatedAt:    1,
-					Name:         "task1",
-					Description:  "desc1",
-					UserID:       "1",
-					UserEmail:    "example1@tst.com",
-					Time:         7,
-					TaskID:       "tid1",
-					ReminderSent: false,
+					CreatedAt:   1,
+					Name:        "task1",
+					Description: "desc1",
+					UserID:      "1",
+					UserEmail:   "example1@tst.com",
+					Time:        7,
+					TaskID:      "tid1",
 				},
 				{
-					CreatedAt:    3,
-					Name:         "task3",
-					Description:  "desc3",
-					UserID:       "1",
-					UserEmail:    "example1@tst.com",
-					Time:         10,
-					TaskID:       "tid3",
-					ReminderSent: false,
+					CreatedAt:   3,
+					Name:        "task3",
+					Description: "desc3",
+					UserID:      "1",
+					UserEmail:   "example1@tst.com",
+					Time:        10,
+					TaskID:      "tid3",
 				},
 			},
 			expectedCode: codes.OK,
@@ -694,146 +701,538 @@ func (s *RepoTaskTestSuite) TestGetExpiredTasks() {
 
 func (s *RepoTaskTestSuite) TestSearchForExpiringTasks() {
 	ctx := context.Background()
-	expectedResult := make(map[string][]Task)
-	expectedResult["example1@tst.com"] = []Task{
-		{
-			CreatedAt:    2,
-			Name:         "task2",
-			Description:  "desc2",
-			UserID:       "1",
-			UserEmail:    "example1@tst.com",
-			Time:         time.Now().Add(time.Minute * 1).Unix(),
-			TaskID:       "tid2",
-			ReminderSent: false,
-		},
-	}
-	expectedResult["example6@tst.com"] = []Task{
-		{
-			CreatedAt:    1,
-			Name:         "task10",
-			Description:  "desc10",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid10",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task11",
-			Description:  "desc11",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid11",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task12",
-			Description:  "desc12",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid12",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task7",
-			Description:  "desc7",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid7",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task8",
-			Description:  "desc8",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid8",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task9",
-			Description:  "desc9",
-			UserID:       "6",
-			UserEmail:    "example6@tst.com",
-			Time:         time.Now().Add(time.Minute * 4).Unix(),
-			TaskID:       "tid9",
-			ReminderSent: false,
-		},
-	}
-	expectedResult["example7@tst.com"] = []Task{
-		{
-			CreatedAt:    1,
-			Name:         "task13",
-			Description:  "desc13",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid13",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task14",
-			Description:  "desc14",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid14",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task15",
-			Description:  "desc15",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid15",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task16",
-			Description:  "desc16",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid16",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task17",
-			Description:  "desc17",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid17",
-			ReminderSent: false,
-		},
-		{
-			CreatedAt:    1,
-			Name:         "task18",
-			Description:  "desc18",
-			UserID:       "7",
-			UserEmail:    "example7@tst.com",
-			Time:         time.Now().Add(time.Minute * 3).Unix(),
-			TaskID:       "tid18",
-			ReminderSent: false,
-		},
-	}
-	tasks, err := s.taskRepo.SearchForExpiringTasks(ctx)
-	s.NoError(err)
-	s.Equalf(expectedResult, tasks, "ok")
+	// tasks due within the 5 minutes of the default reminder
+	expectedResult := map[string][]string{
+		"example1@tst.com": {"tid2"},
+		"example6@tst.com": {"tid10", "tid11", "tid12", "tid7", "tid8", "tid9"},
+		"example7@tst.com": {"tid13", "tid14", "tid15", "tid16", "tid17", "tid18"},
+	}
+	tasks, err := s.taskRepo.SearchForExpiringTasks(ctx, time.Now().Unix())
+	s.NoError(err)
+	taskIDs := make(map[string][]string)
+	for email, emailTasks := range tasks {
+		for _, task := range emailTasks {
+			s.Equal([]int64{300}, task.Reminders)
+			taskIDs[email] = append(taskIDs[email], task.TaskID)
+		}
+	}
+	s.Equalf(expectedResult, taskIDs, "ok")
+}
+
+func (s *RepoTaskTestSuite) TestSearchForUpcomingReminders() {
+	ctx := context.Background()
+	now := time.Now()
+	// the reminders of task2 are due four minutes ago, of user 7 two minutes ago and of user 6 a minute ago
+	candidates := []struct {
+		until          int64
+		expectedResult []string
+	}{
+		{
+			until:          now.Add(-time.Minute * 3).Unix(),
+			expectedResult: []string{"tid2"},
+		},
+		{
+			until: now.Unix(),
+			expectedResult: []string{"tid2", "tid13", "tid14", "tid15", "tid16", "tid17", "tid18",
+				"tid7", "tid8", "tid9", "tid10", "tid11", "tid12"},
+		},
+	}
+	for i, candidate := range candidates {
+		tasks, err := s.taskRepo.SearchForUpcomingReminders(ctx, candidate.until)
+		s.NoErrorf(err, "candidate %d", i+1)
+		taskIDs := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			taskIDs = append(taskIDs, task.TaskID)
+		}
+		// the earliest first
+		s.Equalf(candidate.expectedResult[0], taskIDs[0], "candidate %d", i+1)
+		s.ElementsMatchf(candidate.expectedResult, taskIDs, "candidate %d", i+1)
+	}
+}
+
+func (s *RepoTaskTestSuite) TestDailyStats() {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	// stats documents outlive the test data, so only the difference is compared
+	statsOfToday := func() DailyStats {
+		stats, err := s.taskRepo.GetDailyStats(ctx, "5", now, now)
+		s.NoError(err)
+		if len(stats) == 0 {
+			return DailyStats{Date: StatsDate(now)}
+		}
+		return stats[0]
+	}
+	before := statsOfToday()
+	task, err := s.taskRepo.Create(ctx, Task{
+		Name:      "stats",
+		UserID:    "5",
+		UserEmail: "example5@tst.com",
+		Time:      now + 60,
+	})
+	s.NoError(err)
+	task.Completed = true
+	task, err = s.taskRepo.Update(ctx, task, task.UserID, task.TaskID)
+	s.NoError(err)
+	s.NotZero(task.CompletedAt)
+	after := statsOfToday()
+	s.Equal(before.Created+1, after.Created)
+	s.Equal(before.Completed+1, after.Completed)
+	// due time may fall on the next day right before midnight
+	if StatsDate(now+60) == StatsDate(now) {
+		s.Equal(before.Due+1, after.Due)
+		s.Equal(before.DueCompleted+1, after.DueCompleted)
+	}
+	err = s.taskRepo.Delete(ctx, task.UserID, task.TaskID)
+	s.NoError(err)
+	afterDelete := statsOfToday()
+	s.Equal(after.Completed, afterDelete.Completed)
+	s.Equal(after.Due-after.DueCompleted, afterDelete.Due-afterDelete.DueCompleted)
+}
+
+func (s *RepoTaskTestSuite) TestDailyStatsConcurrentUpdates() {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	task, err := s.taskRepo.Create(ctx, Task{
+		Name:      "stats",
+		UserID:    "5",
+		UserEmail: "example5@tst.com",
+	})
+	s.Require().NoError(err)
+	stats, err := s.taskRepo.GetDailyStats(ctx, "5", now, now)
+	s.Require().NoError(err)
+	s.Require().Len(stats, 1)
+	before := stats[0]
+	// every update completes the task, only the first one counts
+	task.Completed = true
+	errs := make(chan error)
+	for i := 0; i < 5; i++ {
+		go func() {
+			_, err := s.taskRepo.Update(ctx, task, task.UserID, task.TaskID)
+			errs <- err
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		s.NoError(<-errs)
+	}
+	stats, err = s.taskRepo.GetDailyStats(ctx, "5", now, now)
+	s.Require().NoError(err)
+	s.Equal(before.Completed+1, stats[0].Completed)
+	err = s.taskRepo.Delete(ctx, task.UserID, task.TaskID)
+	s.NoError(err)
+}
+
+func (s *RepoTaskTestSuite) TestChanges() {
+	ctx := context.Background()
+	start, err := s.taskRepo.GetLatestChange(ctx, "5")
+	s.NoError(err)
+	task, err := s.taskRepo.Create(ctx, Task{
+		Name:      "synced",
+		UserID:    "5",
+		UserEmail: "example5@tst.com",
+	})
+	s.NoError(err)
+	task.Name = "synced again"
+	_, err = s.taskRepo.Update(ctx, task, task.UserID, task.TaskID)
+	s.NoError(err)
+	other, err := s.taskRepo.Create(ctx, Task{
+		Name:      "deleted",
+		UserID:    "5",
+		UserEmail: "example5@tst.com",
+	})
+	s.NoError(err)
+	err = s.taskRepo.Delete(ctx, other.UserID, other.TaskID)
+	s.NoError(err)
+
+	// every task has a single entry with its latest state
+	changes, err := s.taskRepo.GetChanges(ctx, "5", start, 10)
+	s.NoError(err)
+	s.Len(changes, 2)
+	s.Equal(task.TaskID, changes[0].TaskID)
+	s.Equal("synced again", changes[0].Task.Name)
+	s.Equal(other.TaskID, changes[1].TaskID)
+	s.True(changes[1].Deleted)
+	s.Nil(changes[1].Task)
+	s.NotNil(changes[1].ExpireAt)
+	s.True(changes[0].UpdatedAt.Before(changes[1].UpdatedAt))
+
+	latest, err := s.taskRepo.GetLatestChange(ctx, "5")
+	s.NoError(err)
+	s.Equal(changes[1].Cursor(), latest)
+	changes, err = s.taskRepo.GetChanges(ctx, "5", latest, 10)
+	s.NoError(err)
+	s.Empty(changes)
+}
+
+func (s *RepoTaskTestSuite) TestWatchChanges() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	start, err := s.taskRepo.GetLatestChange(ctx, "5")
+	s.NoError(err)
+	task, err := s.taskRepo.Create(ctx, Task{
+		Name:      "watched",
+		UserID:    "5",
+		UserEmail: "example5@tst.com",
+	})
+	s.NoError(err)
+	errStop := errors.New("stop")
+	var watched []Change
+	err = s.taskRepo.WatchChanges(ctx, "5", start, func(change Change) error {
+		watched = append(watched, change)
+		return errStop
+	})
+	s.ErrorIs(err, errStop)
+	s.Len(watched, 1)
+	s.Equal(task.TaskID, watched[0].TaskID)
+	s.True(watched[0].Created)
+}
+
+func (s *RepoTaskTestSuite) TestWebhookFailures() {
+	ctx := context.Background()
+	webhookRepo := NewFSWebhook(s.client.Collection(CollectionUsers), s.client)
+	webhook, err := webhookRepo.Create(ctx, Webhook{
+		UserID: "5",
+		URL:    "https://example.com/hook",
+		Secret: "0123456789abcdef",
+	})
+	s.NoError(err)
+	webhook, err = webhookRepo.RecordResult(ctx, "5", webhook.WebhookID, false, 2)
+	s.NoError(err)
+	s.False(webhook.Disabled)
+	webhook, err = webhookRepo.RecordResult(ctx, "5", webhook.WebhookID, false, 2)
+	s.NoError(err)
+	s.True(webhook.Disabled)
+	s.Equal(int32(2), webhook.ConsecutiveFailures)
+
+	// enabling resets the failures and keeps the secret
+	webhook.Disabled = false
+	webhook, err = webhookRepo.Update(ctx, webhook)
+	s.NoError(err)
+	s.False(webhook.Disabled)
+	s.Zero(webhook.ConsecutiveFailures)
+	s.Equal("0123456789abcdef", webhook.Secret)
+
+	_, err = webhookRepo.Get(ctx, "5", "missing")
+	s.ErrorIs(err, ErrWebhookNotFound)
+}
+
+func (s *RepoTaskTestSuite) TestGetByCalDAVName() {
+	ctx := context.Background()
+	task, err := s.taskRepo.Create(ctx, Task{
+		Name:       "caldav",
+		UserID:     "5",
+		UserEmail:  "example5@tst.com",
+		CalDAVName: "ABC-123",
+	})
+	s.Require().NoError(err)
+	s.NotEqual("ABC-123", task.TaskID)
+	found, err := s.taskRepo.GetByCalDAVName(ctx, "5", "ABC-123")
+	s.NoError(err)
+	s.Equal(task.TaskID, found.TaskID)
+	// names are kept per user
+	_, err = s.taskRepo.GetByCalDAVName(ctx, "6", "ABC-123")
+	s.Equal(codes.NotFound, status.Code(err))
+	// updates through the API keep the name
+	task.CalDAVName = ""
+	task, err = s.taskRepo.Update(ctx, task, "5", task.TaskID)
+	s.NoError(err)
+	s.Equal("ABC-123", task.CalDAVName)
+	err = s.taskRepo.Delete(ctx, "5", task.TaskID)
+	s.NoError(err)
+}
+
+func (s *RepoTaskTestSuite) TestCalDAVPasswords() {
+	ctx := context.Background()
+	calendarRepo := NewFSCalendar(s.client.Collection(CalendarFeeds), s.client)
+	password, err := calendarRepo.CreatePassword(ctx, CalDAVPassword{
+		UserID:       "5",
+		UserEmail:    "example5@tst.com",
+		Name:         "phone",
+		PasswordHash: "hash5",
+	})
+	s.Require().NoError(err)
+	account, err := calendarRepo.GetByPassword(ctx, "hash5")
+	s.NoError(err)
+	s.Equal(password, account)
+	passwords, err := calendarRepo.ListPasswords(ctx, "5")
+	s.NoError(err)
+	s.Contains(passwords, password)
+
+	// only the owner revokes the password
+	err = calendarRepo.RevokePassword(ctx, "6", password.PasswordID)
+	s.ErrorIs(err, ErrPasswordNotFound)
+	err = calendarRepo.RevokePassword(ctx, "5", password.PasswordID)
+	s.NoError(err)
+	_, err = calendarRepo.GetByPassword(ctx, "hash5")
+	s.ErrorIs(err, ErrPasswordNotFound)
+}
+
+func (s *RepoTaskTestSuite) TestBackfillReminders() {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	_, err := s.client.Collection(CollectionMigrations).Doc(MigrationReminders).Delete(ctx)
+	s.NoError(err)
+	// written before the tasks had reminders
+	legacy := map[string]interface{}{
+		"taskID":       "legacy1",
+		"userID":       "5",
+		"email":        "example5@tst.com",
+		"name":         "legacy",
+		"time":         now + 3600,
+		"reminderSent": false,
+	}
+	_, err = s.client.Collection(CollectionUsers).Doc("5").Collection(CollectionTasks).Doc("legacy1").Set(ctx, legacy)
+	s.NoError(err)
+	_, err = s.client.Collection(TaskList).Doc("legacy1").Set(ctx, legacy)
+	s.NoError(err)
+	_, err = s.taskRepo.SetReminderSettings(ctx, ReminderSettings{UserID: "5", Reminders: []int64{7200, 600}})
+	s.NoError(err)
+
+	scheduled, err := s.taskRepo.BackfillReminders(ctx, now)
+	s.NoError(err)
+	s.Equal(1, scheduled)
+	task, err := s.taskRepo.Get(ctx, "5", "legacy1")
+	s.NoError(err)
+	s.Equal([]int64{7200, 600}, task.Reminders)
+	// the reminder two hours before is past already
+	s.Equal([]int64{7200}, task.RemindersSent)
+	s.Equal(now+3000, task.NextReminderAt)
+	upcoming, err := s.taskRepo.SearchForUpcomingReminders(ctx, now+3000)
+	s.NoError(err)
+	found := false
+	for _, t := range upcoming {
+		found = found || t.TaskID == "legacy1"
+	}
+	s.True(found)
+
+	// runs once
+	_, err = s.client.Collection(TaskList).Doc("legacy2").Set(ctx, legacy)
+	s.NoError(err)
+	scheduled, err = s.taskRepo.BackfillReminders(ctx, now)
+	s.NoError(err)
+	s.Equal(0, scheduled)
+}
+
+func (s *RepoTaskTestSuite) TestBackfillOverdue() {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	_, err := s.client.Collection(CollectionMigrations).Doc(MigrationOverdue).Delete(ctx)
+	s.NoError(err)
+	// written before the tasks had nextOverdueAt, due an hour ago
+	legacy := map[string]interface{}{
+		"taskID":    "legacy1",
+		"userID":    "5",
+		"email":     "example5@tst.com",
+		"name":      "legacy",
+		"time":      now - 3600,
+		"completed": false,
+	}
+	_, err = s.client.Collection(CollectionUsers).Doc("5").Collection(CollectionTasks).Doc("legacy1").Set(ctx, legacy)
+	s.NoError(err)
+	_, err = s.client.Collection(TaskList).Doc("legacy1").Set(ctx, legacy)
+	s.NoError(err)
+
+	_, err = s.taskRepo.SetEscalationPolicy(ctx, EscalationPolicy{UserID: "5", Intervals: []int64{1800, 7200}})
+	s.NoError(err)
+
+	_, err = s.taskRepo.BackfillOverdue(ctx, now)
+	s.NoError(err)
+	task, err := s.taskRepo.Get(ctx, "5", "legacy1")
+	s.NoError(err)
+	// the overdue reminder past already is skipped, the next one is scheduled
+	s.Equal(now+3600, task.NextOverdueAt)
+	s.Equal(int32(1), task.OverdueNotices)
+	overdue, err := s.taskRepo.SearchForOverdueTasks(ctx, now)
+	s.NoError(err)
+	found := false
+	for _, t := range overdue {
+		found = found || t.TaskID == "legacy1"
+	}
+	s.False(found)
+
+	// runs once
+	_, err = s.client.Collection(TaskList).Doc("legacy2").Set(ctx, legacy)
+	s.NoError(err)
+	backfilled, err := s.taskRepo.BackfillOverdue(ctx, now)
+	s.NoError(err)
+	s.Equal(0, backfilled)
+}
+
+func (s *RepoTaskTestSuite) TestRescheduleOverdue() {
+	ctx := context.Background()
+	task, err := s.taskRepo.Create(ctx, Task{
+		Name:      "overdue",
+		UserID:    "5",
+		UserEmail: "example5@tst.com",
+		Time:      time.Now().Add(-time.Hour * 2).Unix(),
+	})
+	s.Require().NoError(err)
+	// the zero policy was done with the task
+	done := map[string]interface{}{"nextOverdueAt": 0, "overdueNotices": 1}
+	_, err = s.client.Collection(CollectionUsers).Doc("5").Collection(CollectionTasks).Doc(task.TaskID).
+		Set(ctx, done, firestore.MergeAll)
+	s.Require().NoError(err)
+	_, err = s.client.Collection(TaskList).Doc(task.TaskID).Set(ctx, done, firestore.MergeAll)
+	s.Require().NoError(err)
+
+	_, err = s.taskRepo.SetEscalationPolicy(ctx, EscalationPolicy{UserID: "5", Intervals: []int64{3600, 10800}})
+	s.Require().NoError(err)
+
+	now := time.Now().Unix()
+	_, err = s.taskRepo.RescheduleOverdue(ctx, now)
+	s.NoError(err)
+	policy, err := s.taskRepo.GetEscalationPolicy(ctx, "5")
+	s.NoError(err)
+	s.True(policy.Rescheduled)
+	rescheduled, err := s.taskRepo.Get(ctx, "5", task.TaskID)
+	s.NoError(err)
+	// the overdue reminder sent already isn't sent again, the next one is due at its time
+	s.Equal(task.Time+10800, rescheduled.NextOverdueAt)
+	s.Equal(int32(1), rescheduled.OverdueNotices)
+	listed, err := s.client.Collection(TaskList).Doc(task.TaskID).Get(ctx)
+	s.Require().NoError(err)
+	s.Equal(task.Time+10800, listed.Data()["nextOverdueAt"])
+}
+
+func (s *RepoTaskTestSuite) TestOutbox() {
+	ctx := context.Background()
+	outbox := events.NewFSOutbox(s.client.Collection(events.CollectionOutbox), s.client)
+	task, err := s.taskRepo.Create(ctx, Task{
+		Name:      "published",
+		UserID:    "5",
+		UserEmail: "example5@tst.com",
+	})
+	s.NoError(err)
+	pending, err := outbox.Pending(ctx, 100)
+	s.NoError(err)
+	var created *events.Event
+	for i := range pending {
+		if pending[i].AggregateID == task.TaskID {
+			created = &pending[i]
+		}
+	}
+	s.Require().NotNil(created)
+	s.Equal(events.TaskCreated, created.Type)
+	data := events.TaskData{}
+	s.NoError(created.Decode(&data))
+	s.Equal(task.TaskID, data.TaskID)
+
+	claimed, err := outbox.Claim(ctx, created.ID, time.Minute)
+	s.NoError(err)
+	s.True(claimed)
+	// locked for other relays
+	claimed, err = outbox.Claim(ctx, created.ID, time.Minute)
+	s.NoError(err)
+	s.False(claimed)
+	s.NoError(outbox.MarkPublished(ctx, created.ID))
+	pending, err = outbox.Pending(ctx, 100)
+	s.NoError(err)
+	for _, event := range pending {
+		s.NotEqual(created.ID, event.ID)
+	}
+}
+
+func (s *RepoTaskTestSuite) TestOutboxRetry() {
+	ctx := context.Background()
+	outbox := events.NewFSOutbox(s.client.Collection(events.CollectionOutbox), s.client)
+	task, err := s.taskRepo.Create(ctx, Task{
+		Name:      "poisoned",
+		UserID:    "5",
+		UserEmail: "example5@tst.com",
+	})
+	s.NoError(err)
+	docs, err := s.client.Collection(events.CollectionOutbox).Where("aggregateID", "==", task.TaskID).
+		Documents(ctx).GetAll()
+	s.NoError(err)
+	s.Require().Len(docs, 1)
+	eventID := docs[0].Ref.ID
+
+	claimed, err := outbox.Claim(ctx, eventID, time.Minute)
+	s.NoError(err)
+	s.True(claimed)
+	dead, err := outbox.Retry(ctx, eventID, "failed", 2)
+	s.NoError(err)
+	s.False(dead)
+	// backing off, not pending nor claimable meanwhile
+	pending, err := outbox.Pending(ctx, 100)
+	s.NoError(err)
+	for _, event := range pending {
+		s.NotEqual(eventID, event.ID)
+	}
+	claimed, err = outbox.Claim(ctx, eventID, time.Minute)
+	s.NoError(err)
+	s.False(claimed)
+
+	dead, err = outbox.Retry(ctx, eventID, "failed again", 2)
+	s.NoError(err)
+	s.True(dead)
+	_, err = s.client.Collection(events.CollectionOutbox).Doc(eventID).Get(ctx)
+	s.Equal(codes.NotFound, status.Code(err))
+	doc, err := s.client.Collection(events.CollectionDeadLetters).Doc(eventID).Get(ctx)
+	s.NoError(err)
+	s.Equal("failed again", doc.Data()["lastError"])
+
+	// entries written without nextAttemptAt become pending after the backfill
+	legacy := s.client.Collection(events.CollectionOutbox).NewDoc()
+	_, err = legacy.Set(ctx, map[string]interface{}{
+		"id":          legacy.ID,
+		"type":        string(events.UserDeleted),
+		"aggregateID": "5",
+		"occurredAt":  firestore.ServerTimestamp,
+		"published":   false,
+	})
+	s.NoError(err)
+	backfilled, err := outbox.Backfill(ctx)
+	s.NoError(err)
+	s.GreaterOrEqual(backfilled, 1)
+	pending, err = outbox.Pending(ctx, 100)
+	s.NoError(err)
+	found := false
+	for _, event := range pending {
+		found = found || event.ID == legacy.ID
+	}
+	s.True(found)
+}
+
+func (s *RepoTaskTestSuite) TestIdempotency() {
+	ctx := context.Background()
+	idempotencyRepo := NewFSIdempotency(s.client.Collection(CollectionUsers), s.client)
+	record := IdempotencyRecord{Method: "/task.TaskService/CreateTask", RequestHash: "hash1"}
+	_, reserved, err := idempotencyRepo.Reserve(ctx, "5", "key1", record, time.Minute)
+	s.NoError(err)
+	s.True(reserved)
+	existing, reserved, err := idempotencyRepo.Reserve(ctx, "5", "key1", IdempotencyRecord{RequestHash: "hash2"},
+		time.Minute)
+	s.NoError(err)
+	s.False(reserved)
+	s.Equal("hash1", existing.RequestHash)
+	s.False(existing.Completed)
+
+	s.NoError(idempotencyRepo.Complete(ctx, "5", "key1", []byte("response")))
+	existing, _, err = idempotencyRepo.Reserve(ctx, "5", "key1", record, time.Minute)
+	s.NoError(err)
+	s.True(existing.Completed)
+	s.Equal([]byte("response"), existing.Response)
+
+	s.NoError(idempotencyRepo.Release(ctx, "5", "key1"))
+	_, reserved, err = idempotencyRepo.Reserve(ctx, "5", "key1", record, time.Minute)
+	s.NoError(err)
+	s.True(reserved)
+
+	// a retry takes over the reservation of a request that didn't finish within the lease
+	_, reserved, err = idempotencyRepo.Reserve(ctx, "5", "key2", record, time.Millisecond)
+	s.NoError(err)
+	s.True(reserved)
+	time.Sleep(time.Millisecond * 10)
+	_, reserved, err = idempotencyRepo.Reserve(ctx, "5", "key2", IdempotencyRecord{RequestHash: "hash2"},
+		time.Minute)
+	s.NoError(err)
+	s.False(reserved)
+	_, reserved, err = idempotencyRepo.Reserve(ctx, "5", "key2", record, time.Minute)
+	s.NoError(err)
+	s.True(reserved)
+	_, reserved, err = idempotencyRepo.Reserve(ctx, "5", "key2", record, time.Minute)
+	s.NoError(err)
+	s.False(reserved)
 }
 
 func TestTaskRepositoryTestSuite(t *testing.T) {
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+var (
+	ErrWebhookNotFound = apierror.New(codes.NotFound, "WEBHOOK_NOT_FOUND", "webhook not found")
+)
+
+type FSWebhookInterface interface {
+	Create(ctx context.Context, webhook Webhook) (Webhook, error)
+	Get(ctx context.Context, userID, webhookID string) (Webhook, error)
+	List(ctx context.Context, userID string) ([]Webhook, error)
+	Update(ctx context.Context, webhook Webhook) (Webhook, error)
+	Delete(ctx context.Context, userID, webhookID string) error
+	AddDelivery(ctx context.Context, userID string, delivery Delivery) error
+	ListDeliveries(ctx context.Context, userID, webhookID string, n int) ([]Delivery, error)
+	RecordResult(ctx context.Context, userID, webhookID string, success bool, maxFailures int32) (Webhook, error)
+}
+
+// FSWebhook stores webhooks in a sub collection of the user and their delivery log under each webhook
+type FSWebhook struct {
+	fs     *firestore.CollectionRef
+	client *firestore.Client
+}
+
+func NewFSWebhook(fs *firestore.CollectionRef, client *firestore.Client) *FSWebhook {
+	return &FSWebhook{
+		fs:     fs,
+		client: client,
+	}
+}
+
+func (f *FSWebhook) webhooks(userID string) *firestore.CollectionRef {
+	return f.fs.Doc(userID).Collection(CollectionWebhooks)
+}
+
+func (f *FSWebhook) Create(ctx context.Context, webhook Webhook) (Webhook, error) {
+	docRef := f.webhooks(webhook.UserID).NewDoc()
+	webhook.WebhookID = docRef.ID
+	webhook.CreatedAt = time.Now().Unix()
+	webhook.UpdatedAt = webhook.CreatedAt
+	_, err := docRef.Set(ctx, webhook)
+	if err != nil {
+		return Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (f *FSWebhook) Get(ctx context.Context, userID, webhookID string) (Webhook, error) {
+	doc, err := f.webhooks(userID).Doc(webhookID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return Webhook{}, ErrWebhookNotFound
+	}
+	if err != nil {
+		return Webhook{}, err
+	}
+	webhook := Webhook{}
+	err = doc.DataTo(&webhook)
+	if err != nil {
+		return Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (f *FSWebhook) List(ctx context.Context, userID string) ([]Webhook, error) {
+	docs := f.webhooks(userID).OrderBy("createdAt", firestore.Asc).Documents(ctx)
+	var webhooks []Webhook
+	for {
+		doc, err := docs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		webhook := Webhook{}
+		err = doc.DataTo(&webhook)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
+}
+
+// Update changes URL, events and the disabled flag of an existing webhook, the secret is kept.
+// Enabling a disabled webhook resets its failures
+func (f *FSWebhook) Update(ctx context.Context, webhook Webhook) (Webhook, error) {
+	docRef := f.webhooks(webhook.UserID).Doc(webhook.WebhookID)
+	var updated Webhook
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if status.Code(err) == codes.NotFound {
+			return ErrWebhookNotFound
+		}
+		if err != nil {
+			return err
+		}
+		updated = Webhook{}
+		err = doc.DataTo(&updated)
+		if err != nil {
+			return err
+		}
+		if updated.Disabled && !webhook.Disabled {
+			updated.ConsecutiveFailures = 0
+			updated.DisabledReason = ""
+		}
+		updated.URL = webhook.URL
+		updated.Events = webhook.Events
+		updated.Disabled = webhook.Disabled
+		updated.UpdatedAt = time.Now().Unix()
+		return tx.Set(docRef, updated)
+	})
+	if err != nil {
+		return Webhook{}, err
+	}
+	return updated, nil
+}
+
+// Delete removes the webhook, its delivery log is left to expire
+func (f *FSWebhook) Delete(ctx context.Context, userID, webhookID string) error {
+	_, err := f.webhooks(userID).Doc(webhookID).Delete(ctx)
+	return err
+}
+
+func (f *FSWebhook) AddDelivery(ctx context.Context, userID string, delivery Delivery) error {
+	docRef := f.webhooks(userID).Doc(delivery.WebhookID).Collection(CollectionDeliveries).NewDoc()
+	delivery.DeliveryID = docRef.ID
+	delivery.CreatedAt = time.Now().Unix()
+	expireAt := time.Now().Add(DeliveryTTL)
+	delivery.ExpireAt = &expireAt
+	_, err := docRef.Set(ctx, delivery)
+	return err
+}
+
+// ListDeliveries returns the last n deliveries of the webhook, newest first
+func (f *FSWebhook) ListDeliveries(ctx context.Context, userID, webhookID string, n int) ([]Delivery, error) {
+	docs := f.webhooks(userID).Doc(webhookID).Collection(CollectionDeliveries).
+		OrderBy("createdAt", firestore.Desc).
+		Limit(n).Documents(ctx)
+	var deliveries []Delivery
+	for {
+		doc, err := docs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		delivery := Delivery{}
+		err = doc.DataTo(&delivery)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+// RecordResult counts the consecutive failed deliveries of the webhook and disables it once
+// there are maxFailures of them, a successful delivery resets the count
+func (f *FSWebhook) RecordResult(ctx context.Context, userID, webhookID string, success bool,
+	maxFailures int32) (Webhook, error) {
+	docRef := f.webhooks(userID).Doc(webhookID)
+	var webhook Webhook
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if status.Code(err) == codes.NotFound {
+			return ErrWebhookNotFound
+		}
+		if err != nil {
+			return err
+		}
+		webhook = Webhook{}
+		err = doc.DataTo(&webhook)
+		if err != nil {
+			return err
+		}
+		if success {
+			webhook.ConsecutiveFailures = 0
+		} else {
+			webhook.ConsecutiveFailures++
+			if webhook.ConsecutiveFailures >= maxFailures && !webhook.Disabled {
+				webhook.Disabled = true
+				webhook.DisabledReason = "disabled after repeated failed deliveries"
+			}
+		}
+		webhook.UpdatedAt = time.Now().Unix()
+		return tx.Set(docRef, webhook)
+	})
+	if err != nil {
+		return Webhook{}, err
+	}
+	return webhook, nil
+}
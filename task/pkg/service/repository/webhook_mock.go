@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type FSWebhookMock struct {
+	mock.Mock
+}
+
+func NewMockWebhookRepo() *FSWebhookMock {
+	return &FSWebhookMock{}
+}
+
+func (m *FSWebhookMock) Create(ctx context.Context, webhook Webhook) (Webhook, error) {
+	args := m.Called(ctx, webhook)
+	return args.Get(0).(Webhook), args.Error(1)
+}
+
+func (m *FSWebhookMock) Get(ctx context.Context, userID, webhookID string) (Webhook, error) {
+	args := m.Called(ctx, userID, webhookID)
+	return args.Get(0).(Webhook), args.Error(1)
+}
+
+func (m *FSWebhookMock) List(ctx context.Context, userID string) ([]Webhook, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]Webhook), args.Error(1)
+}
+
+func (m *FSWebhookMock) Update(ctx context.Context, webhook Webhook) (Webhook, error) {
+	args := m.Called(ctx, webhook)
+	return args.Get(0).(Webhook), args.Error(1)
+}
+
+func (m *FSWebhookMock) Delete(ctx context.Context, userID, webhookID string) error {
+	args := m.Called(ctx, userID, webhookID)
+	return args.Error(0)
+}
+
+func (m *FSWebhookMock) AddDelivery(ctx context.Context, userID string, delivery Delivery) error {
+	args := m.Called(ctx, userID, delivery)
+	return args.Error(0)
+}
+
+func (m *FSWebhookMock) ListDeliveries(ctx context.Context, userID, webhookID string, n int) ([]Delivery, error) {
+	args := m.Called(ctx, userID, webhookID, n)
+	return args.Get(0).([]Delivery), args.Error(1)
+}
+
+func (m *FSWebhookMock) RecordResult(ctx context.Context, userID, webhookID string, success bool,
+	maxFailures int32) (Webhook, error) {
+	args := m.Called(ctx, userID, webhookID, success, maxFailures)
+	return args.Get(0).(Webhook), args.Error(1)
+}
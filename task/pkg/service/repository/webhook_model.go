@@ -0,0 +1,106 @@
+package repository
+
+import (
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	"time"
+)
+
+const (
+	CollectionWebhooks   = "webhooks"
+	CollectionDeliveries = "deliveries"
+	// DeliveryTTL is how long the delivery log is kept, old entries are removed by the Firestore TTL policy on expireAt
+	DeliveryTTL = time.Hour * 24 * 30
+)
+
+// Webhook is a subscription of the URL to events of user's tasks, kept under users/{uid}/webhooks/{id}.
+// Events hold names of the v1.WebhookEventType values, empty subscribes to all of them
+type Webhook struct {
+	WebhookID           string   `firestore:"webhookID"`
+	UserID              string   `firestore:"userID"`
+	URL                 string   `firestore:"url"`
+	Events              []string `firestore:"events"`
+	Secret              string   `firestore:"secret"`
+	Disabled            bool     `firestore:"disabled"`
+	DisabledReason      string   `firestore:"disabledReason"`
+	ConsecutiveFailures int32    `firestore:"consecutiveFailures"`
+	CreatedAt           int64    `firestore:"createdAt"`
+	UpdatedAt           int64    `firestore:"updatedAt"`
+}
+
+// Subscribed tells whether the webhook wants the event, disabled webhooks want none
+func (w Webhook) Subscribed(event v1.WebhookEventType) bool {
+	if w.Disabled {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// ToApi leaves out the secret, it is returned only when the webhook is created
+func (w Webhook) ToApi() *v1.Webhook {
+	events := make([]v1.WebhookEventType, 0, len(w.Events))
+	for _, e := range w.Events {
+		events = append(events, v1.WebhookEventType(v1.WebhookEventType_value[e]))
+	}
+	return &v1.Webhook{
+		WebhookId:           w.WebhookID,
+		Url:                 w.URL,
+		Events:              events,
+		Disabled:            w.Disabled,
+		DisabledReason:      w.DisabledReason,
+		ConsecutiveFailures: w.ConsecutiveFailures,
+		CreatedAt:           w.CreatedAt,
+	}
+}
+
+func WebhookFromMsg(msg *v1.Webhook) Webhook {
+	events := make([]string, 0, len(msg.Events))
+	for _, e := range msg.Events {
+		events = append(events, e.String())
+	}
+	return Webhook{
+		WebhookID: msg.WebhookId,
+		URL:       msg.Url,
+		Events:    events,
+		Secret:    msg.Secret,
+		Disabled:  msg.Disabled,
+	}
+}
+
+// Delivery is a single attempt to deliver an event, kept under users/{uid}/webhooks/{id}/deliveries.
+// Event holds the name of the v1.WebhookEventType value
+type Delivery struct {
+	DeliveryID string     `firestore:"deliveryID"`
+	WebhookID  string     `firestore:"webhookID"`
+	EventID    string     `firestore:"eventID"`
+	Event      string     `firestore:"event"`
+	Attempt    int32      `firestore:"attempt"`
+	StatusCode int32      `firestore:"statusCode"`
+	Error      string     `firestore:"error"`
+	Success    bool       `firestore:"success"`
+	DurationMs int64      `firestore:"durationMs"`
+	CreatedAt  int64      `firestore:"createdAt"`
+	ExpireAt   *time.Time `firestore:"expireAt,omitempty"`
+}
+
+func (d Delivery) ToApi() *v1.WebhookDelivery {
+	return &v1.WebhookDelivery{
+		DeliveryId: d.DeliveryID,
+		WebhookId:  d.WebhookID,
+		EventId:    d.EventID,
+		Event:      v1.WebhookEventType(v1.WebhookEventType_value[d.Event]),
+		Attempt:    d.Attempt,
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		Success:    d.Success,
+		DurationMs: d.DurationMs,
+		CreatedAt:  d.CreatedAt,
+	}
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"container/heap"
+	"context"
+	"github.com/jakubjano/todolist/task/pkg/events"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+	"time"
+)
+
+// SchedulerSettings control the reminder scheduler, it loads the reminders due within Horizon
+// from Firestore every ReconcileEvery. Horizon has to exceed ReconcileEvery, so no reminder falls in between
+type SchedulerSettings struct {
+	ReconcileEvery time.Duration
+	Horizon        time.Duration
+}
+
+// scheduledReminder is the task in the heap, due at the NextReminderAt of the task
+type scheduledReminder struct {
+	task  repository.Task
+	index int
+}
+
+// reminderHeap orders the scheduled reminders from the earliest
+type reminderHeap []*scheduledReminder
+
+func (h reminderHeap) Len() int { return len(h) }
+
+func (h reminderHeap) Less(i, j int) bool {
+	return h[i].task.NextReminderAt < h[j].task.NextReminderAt
+}
+
+func (h reminderHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *reminderHeap) Push(x interface{}) {
+	scheduled := x.(*scheduledReminder)
+	scheduled.index = len(*h)
+	*h = append(*h, scheduled)
+}
+
+func (h *reminderHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	scheduled := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return scheduled
+}
+
+// ReminderScheduler fires the reminders of tasks at their time instead of polling for them. It keeps
+// the reminders due within the horizon in a min-heap, waits for the earliest one and queues it
+// the way EnqueueReminders does. Task events reschedule the tasks right away, the periodic reconciliation
+// with Firestore catches up with the events this instance didn't get and with the reminders entering
+// the horizon. Every instance keeps the heap, only the one leading fires the reminders
+type ReminderScheduler struct {
+	reminder *Reminder
+	logger   *zap.Logger
+	settings SchedulerSettings
+	lead     func() bool
+
+	mu        sync.Mutex
+	reminders reminderHeap
+	byTask    map[string]*scheduledReminder
+	// wake interrupts the wait of Run when the earliest reminder changes
+	wake chan struct{}
+	// backfilled is set once the reminders of tasks written before the scheduler existed are scheduled
+	backfilled bool
+}
+
+// NewReminderScheduler returns the scheduler firing the reminders while lead returns true,
+// it refuses a Horizon that doesn't exceed ReconcileEvery
+func NewReminderScheduler(reminder *Reminder, logger *zap.Logger, settings SchedulerSettings,
+	lead func() bool) (*ReminderScheduler, error) {
+	if settings.ReconcileEvery <= 0 || settings.Horizon <= settings.ReconcileEvery {
+		return nil, ErrInvalidSchedulerSettings
+	}
+	return &ReminderScheduler{
+		reminder: reminder,
+		logger:   logger,
+		settings: settings,
+		lead:     lead,
+		byTask:   make(map[string]*scheduledReminder),
+		wake:     make(chan struct{}, 1),
+	}, nil
+}
+
+// Observe reschedules the tasks on their events. The heap is kept by every instance, so the scheduler
+// observes the events instead of subscribing, which would hand every event to a single instance
+func (s *ReminderScheduler) Observe(observer events.Observer) {
+	observer.Observe("reminder-scheduler", events.TaskCreated, s.HandleEvent)
+	observer.Observe("reminder-scheduler", events.TaskUpdated, s.HandleEvent)
+	observer.Observe("reminder-scheduler", events.TaskDeleted, s.HandleEvent)
+}
+
+// HandleEvent schedules the next reminder of the written task, deleted tasks are unscheduled.
+// The task is read again, the event carries the API view of it without the reminders sent already
+func (s *ReminderScheduler) HandleEvent(ctx context.Context, event events.Event) error {
+	data := events.TaskData{}
+	err := event.Decode(&data)
+	if err != nil {
+		s.logger.Error(err.Error(), zap.String("event_id", event.ID))
+		return nil
+	}
+	if event.Type == events.TaskDeleted {
+		s.Unschedule(data.TaskID)
+		return nil
+	}
+	task, err := s.reminder.taskRepo.Get(ctx, event.UserID, data.TaskID)
+	if status.Code(err) == codes.NotFound {
+		s.Unschedule(data.TaskID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.Schedule(task, time.Now())
+	return nil
+}
+
+// Schedule puts the task in the heap at its NextReminderAt, replacing the previous reminder of the task.
+// Tasks without a reminder or with one beyond the horizon are unscheduled, the reconciliation loads them later
+func (s *ReminderScheduler) Schedule(task repository.Task, now time.Time) {
+	if task.NextReminderAt <= 0 || task.NextReminderAt > now.Add(s.settings.Horizon).Unix() {
+		s.Unschedule(task.TaskID)
+		return
+	}
+	s.mu.Lock()
+	scheduled, ok := s.byTask[task.TaskID]
+	if ok {
+		scheduled.task = task
+		heap.Fix(&s.reminders, scheduled.index)
+	} else {
+		scheduled = &scheduledReminder{task: task}
+		heap.Push(&s.reminders, scheduled)
+		s.byTask[task.TaskID] = scheduled
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Unschedule removes the reminder of the task from the heap
+func (s *ReminderScheduler) Unschedule(taskID string) {
+	s.mu.Lock()
+	scheduled, ok := s.byTask[taskID]
+	if ok {
+		heap.Remove(&s.reminders, scheduled.index)
+		delete(s.byTask, taskID)
+	}
+	s.mu.Unlock()
+	if ok {
+		s.notify()
+	}
+}
+
+// Next returns the time of the earliest scheduled reminder, false when there is none
+func (s *ReminderScheduler) Next() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.reminders) == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(s.reminders[0].task.NextReminderAt, 0), true
+}
+
+// Reconcile replaces the reminders due within the horizon with the ones in Firestore
+func (s *ReminderScheduler) Reconcile(ctx context.Context, now time.Time) error {
+	until := now.Add(s.settings.Horizon).Unix()
+	tasks, err := s.reminder.taskRepo.SearchForUpcomingReminders(ctx, until)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.reminders = s.reminders[:0]
+	s.byTask = make(map[string]*scheduledReminder, len(tasks))
+	for _, task := range tasks {
+		scheduled := &scheduledReminder{task: task}
+		heap.Push(&s.reminders, scheduled)
+		s.byTask[task.TaskID] = scheduled
+	}
+	s.mu.Unlock()
+	s.notify()
+	return nil
+}
+
+// Fire queues the reminders due at now, schedules the next reminders of their tasks and sends the queued
+// reminders, the retried and deferred ones included. A reminder that fails to queue is left for the reconciliation
+func (s *ReminderScheduler) Fire(ctx context.Context, now time.Time) error {
+	var due []repository.Task
+	s.mu.Lock()
+	for len(s.reminders) > 0 && s.reminders[0].task.NextReminderAt <= now.Unix() {
+		scheduled := heap.Pop(&s.reminders).(*scheduledReminder)
+		delete(s.byTask, scheduled.task.TaskID)
+		due = append(due, scheduled.task)
+	}
+	s.mu.Unlock()
+	for _, task := range due {
+		log := s.logger.With(
+			zap.String("user_id", task.UserID),
+			zap.String("task_id", task.TaskID),
+		)
+		delivery, queued, err := s.reminder.reminderQueue.FireReminders(ctx, task.TaskID, now.Unix())
+		if err != nil {
+			log.Error(err.Error())
+			continue
+		}
+		if queued {
+			log.Info("reminder queued", zap.String("delivery_id", delivery.DeliveryID))
+		}
+		// the transaction marked the reminders due at now as sent the same way
+		task.FireReminders(now.Unix())
+		s.Schedule(task, now)
+	}
+	return s.reminder.SendReminders(ctx, now.Unix())
+}
+
+// Run reconciles and fires the reminders until the context is done. Instances that don't lead
+// only check again at the next reconciliation, when they may have become the leader
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	reconcile := time.NewTicker(s.settings.ReconcileEvery)
+	defer reconcile.Stop()
+	s.run(ctx, true)
+	for {
+		timer := time.NewTimer(s.wait(time.Now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-reconcile.C:
+			timer.Stop()
+			s.run(ctx, true)
+		case <-s.wake:
+			timer.Stop()
+		case <-timer.C:
+			s.run(ctx, false)
+		}
+	}
+}
+
+func (s *ReminderScheduler) run(ctx context.Context, reconcile bool) {
+	if !s.lead() {
+		return
+	}
+	now := time.Now()
+	if reconcile {
+		s.backfill(ctx, now)
+		err := s.Reconcile(ctx, now)
+		if err != nil {
+			s.logger.Error(err.Error())
+		}
+		rescheduled, err := s.reminder.taskRepo.RescheduleOverdue(ctx, now.Unix())
+		if err != nil {
+			s.logger.Error(err.Error())
+		}
+		if rescheduled > 0 {
+			s.logger.Info("Rescheduled overdue checks", zap.Int("tasks", rescheduled))
+		}
+		// overdue reminders are hours apart, the reconciliation is precise enough for them
+		err = s.reminder.EnqueueOverdue(ctx, now.Unix())
+		if err != nil {
+			s.logger.Error(err.Error())
+		}
+	}
+	err := s.Fire(ctx, now)
+	if err != nil {
+		s.logger.Error(err.Error())
+	}
+}
+
+// backfill schedules the reminders and overdue checks of old tasks before the first reconciliation
+// of the leader, a failed backfill is tried again at the next one
+func (s *ReminderScheduler) backfill(ctx context.Context, now time.Time) {
+	if s.backfilled {
+		return
+	}
+	scheduled, err := s.reminder.taskRepo.BackfillReminders(ctx, now.Unix())
+	if err != nil {
+		s.logger.Error(err.Error())
+		return
+	}
+	if scheduled > 0 {
+		s.logger.Info("Backfilled reminders", zap.Int("tasks", scheduled))
+	}
+	overdue, err := s.reminder.taskRepo.BackfillOverdue(ctx, now.Unix())
+	if err != nil {
+		s.logger.Error(err.Error())
+		return
+	}
+	if overdue > 0 {
+		s.logger.Info("Backfilled overdue checks", zap.Int("tasks", overdue))
+	}
+	s.backfilled = true
+}
+
+// wait returns how long Run waits for the earliest reminder
+func (s *ReminderScheduler) wait(now time.Time) time.Duration {
+	next, ok := s.Next()
+	if !ok || !s.lead() {
+		return s.settings.ReconcileEvery
+	}
+	if next.Before(now) {
+		return 0
+	}
+	return next.Sub(now)
+}
+
+func (s *ReminderScheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
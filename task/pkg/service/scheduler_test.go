@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"github.com/jakubjano/todolist/task/pkg/events"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"testing"
+	"time"
+)
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	scheduler     *ReminderScheduler
+	taskRepo      *repository.FSTaskMock
+	reminderQueue *repository.FSReminderQueueMock
+	now           time.Time
+}
+
+func (s *SchedulerTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.taskRepo = repository.NewMockRepo()
+	s.reminderQueue = repository.NewMockReminderQueue()
+	reminder := NewReminder(s.taskRepo, s.reminderQueue, logger, nil, nil, ReminderQueueSettings{
+		Attempts:  3,
+		Backoff:   time.Minute,
+		Lease:     time.Minute,
+		BatchSize: 10,
+	})
+	scheduler, err := NewReminderScheduler(reminder, logger, SchedulerSettings{
+		ReconcileEvery: time.Minute,
+		Horizon:        time.Minute * 10,
+	}, func() bool { return true })
+	s.Require().NoError(err)
+	s.scheduler = scheduler
+	s.now = time.Unix(1658098800, 0)
+}
+
+func (s *SchedulerTestSuite) task(taskID string, nextReminderAt int64) repository.Task {
+	return repository.Task{
+		TaskID:         taskID,
+		UserID:         "1",
+		UserEmail:      "example1@tst.com",
+		Name:           taskID,
+		Time:           nextReminderAt + 300,
+		Reminders:      []int64{300},
+		NextReminderAt: nextReminderAt,
+	}
+}
+
+func (s *SchedulerTestSuite) TestSettings() {
+	candidates := []SchedulerSettings{
+		{ReconcileEvery: time.Minute, Horizon: time.Minute},
+		{ReconcileEvery: time.Minute * 10, Horizon: time.Minute},
+		{Horizon: time.Minute},
+	}
+	for i, c := range candidates {
+		_, err := NewReminderScheduler(s.scheduler.reminder, zap.NewNop(), c, func() bool { return true })
+		s.ErrorIsf(err, ErrInvalidSchedulerSettings, "candidate %d", i+1)
+	}
+}
+
+func (s *SchedulerTestSuite) TestSchedule() {
+	now := s.now.Unix()
+	candidates := []struct {
+		schedule     []repository.Task
+		unschedule   []string
+		expectedNext int64
+		expectedLen  int
+	}{
+		{
+			schedule:     []repository.Task{s.task("tid1", now+300), s.task("tid2", now+60), s.task("tid3", now+120)},
+			expectedNext: now + 60,
+			expectedLen:  3,
+		},
+		// rescheduling replaces the reminder of the task
+		{
+			schedule:     []repository.Task{s.task("tid2", now+240)},
+			expectedNext: now + 120,
+			expectedLen:  3,
+		},
+		{
+			unschedule:   []string{"tid3"},
+			expectedNext: now + 240,
+			expectedLen:  2,
+		},
+		// reminders beyond the horizon are left for the reconciliation, tasks without one are unscheduled
+		{
+			schedule:     []repository.Task{s.task("tid4", now+3600), s.task("tid2", 0)},
+			expectedNext: now + 300,
+			expectedLen:  1,
+		},
+	}
+	for i, c := range candidates {
+		for _, task := range c.schedule {
+			s.scheduler.Schedule(task, s.now)
+		}
+		for _, taskID := range c.unschedule {
+			s.scheduler.Unschedule(taskID)
+		}
+		next, ok := s.scheduler.Next()
+		s.Truef(ok, "candidate %d", i+1)
+		s.Equalf(c.expectedNext, next.Unix(), "candidate %d", i+1)
+		s.Lenf(s.scheduler.byTask, c.expectedLen, "candidate %d", i+1)
+	}
+}
+
+func (s *SchedulerTestSuite) TestFire() {
+	ctx := context.Background()
+	now := s.now.Unix()
+	// reminded ten and five minutes before the due time
+	task := s.task("tid1", now)
+	task.Time = now + 600
+	task.Reminders = []int64{600, 300}
+	s.scheduler.Schedule(task, s.now)
+	s.scheduler.Schedule(s.task("tid2", now+3600), s.now)
+	s.scheduler.Schedule(s.task("tid3", now+60), s.now)
+	s.reminderQueue.On("FireReminders", ctx, "tid1", now).
+		Return(repository.NewReminderDelivery(task, 600, now), true, nil)
+	s.reminderQueue.On("Due", ctx, now, 10).Return([]repository.ReminderDelivery{}, nil)
+
+	err := s.scheduler.Fire(ctx, s.now)
+	s.NoError(err)
+	// only the due reminder fired, the queued reminders are sent right away
+	s.reminderQueue.AssertNumberOfCalls(s.T(), "FireReminders", 1)
+	s.reminderQueue.AssertCalled(s.T(), "Due", ctx, now, 10)
+	// the task waits for its next reminder
+	s.Equal(now+300, s.scheduler.byTask["tid1"].task.NextReminderAt)
+	s.Equal([]int64{600}, s.scheduler.byTask["tid1"].task.RemindersSent)
+	next, ok := s.scheduler.Next()
+	s.True(ok)
+	s.Equal(now+60, next.Unix())
+}
+
+func (s *SchedulerTestSuite) TestFireFailed() {
+	ctx := context.Background()
+	now := s.now.Unix()
+	s.scheduler.Schedule(s.task("tid1", now), s.now)
+	s.reminderQueue.On("FireReminders", ctx, "tid1", now).
+		Return(repository.ReminderDelivery{}, false, context.DeadlineExceeded)
+	s.reminderQueue.On("Due", ctx, now, 10).Return([]repository.ReminderDelivery{}, nil)
+
+	err := s.scheduler.Fire(ctx, s.now)
+	s.NoError(err)
+	// the reconciliation loads the reminder again
+	_, ok := s.scheduler.Next()
+	s.False(ok)
+}
+
+func (s *SchedulerTestSuite) TestReconcile() {
+	ctx := context.Background()
+	now := s.now.Unix()
+	// fired by another instance meanwhile
+	s.scheduler.Schedule(s.task("tid1", now+60), s.now)
+	s.taskRepo.On("SearchForUpcomingReminders", ctx, now+600).Return([]repository.Task{
+		s.task("tid2", now-30),
+		s.task("tid3", now+120),
+	}, nil)
+
+	err := s.scheduler.Reconcile(ctx, s.now)
+	s.NoError(err)
+	next, ok := s.scheduler.Next()
+	s.True(ok)
+	// late reminders are due right away
+	s.Equal(now-30, next.Unix())
+	s.Len(s.scheduler.byTask, 2)
+	s.NotContains(s.scheduler.byTask, "tid1")
+}
+
+func (s *SchedulerTestSuite) TestBackfill() {
+	ctx := context.Background()
+	now := s.now.Unix()
+	s.taskRepo.On("BackfillReminders", ctx, now).Return(0, context.DeadlineExceeded).Once()
+	s.taskRepo.On("BackfillReminders", ctx, now).Return(2, nil)
+	s.taskRepo.On("BackfillOverdue", ctx, now).Return(0, context.DeadlineExceeded).Once()
+	s.taskRepo.On("BackfillOverdue", ctx, now).Return(3, nil).Once()
+
+	// a failed backfill is tried again at the next reconciliation, a done one isn't
+	s.scheduler.backfill(ctx, s.now)
+	s.False(s.scheduler.backfilled)
+	s.scheduler.backfill(ctx, s.now)
+	s.False(s.scheduler.backfilled)
+	s.scheduler.backfill(ctx, s.now)
+	s.True(s.scheduler.backfilled)
+	s.scheduler.backfill(ctx, s.now)
+	s.taskRepo.AssertNumberOfCalls(s.T(), "BackfillReminders", 3)
+	s.taskRepo.AssertNumberOfCalls(s.T(), "BackfillOverdue", 2)
+}
+
+func (s *SchedulerTestSuite) TestHandleEvent() {
+	ctx := context.Background()
+	task := s.task("tid1", time.Now().Add(time.Minute).Unix())
+	task.Reminders = []int64{3600, 300}
+	task.RemindersSent = []int64{3600}
+	raw, err := protojson.Marshal(repository.ToApi(task))
+	s.Require().NoError(err)
+	updated, err := events.New(events.TaskUpdated, "1", "tid1", events.TaskData{TaskID: "tid1", Task: raw})
+	s.Require().NoError(err)
+	deleted, err := events.New(events.TaskDeleted, "1", "tid1", events.TaskData{TaskID: "tid1"})
+	s.Require().NoError(err)
+	s.taskRepo.On("Get", ctx, "1", "tid1").Return(task, nil)
+
+	err = s.scheduler.HandleEvent(ctx, updated)
+	s.NoError(err)
+	next, ok := s.scheduler.Next()
+	s.True(ok)
+	s.Equal(task.NextReminderAt, next.Unix())
+	// the stored task is scheduled, with the reminders sent already
+	s.Equal(task, s.scheduler.byTask["tid1"].task)
+
+	err = s.scheduler.HandleEvent(ctx, deleted)
+	s.NoError(err)
+	_, ok = s.scheduler.Next()
+	s.False(ok)
+
+	// a task deleted after the event is unscheduled too
+	s.scheduler.Schedule(s.task("tid2", time.Now().Add(time.Minute).Unix()), time.Now())
+	gone, err := events.New(events.TaskUpdated, "1", "tid2", events.TaskData{TaskID: "tid2"})
+	s.Require().NoError(err)
+	s.taskRepo.On("Get", ctx, "1", "tid2").Return(repository.Task{}, status.Error(codes.NotFound, "not found"))
+	err = s.scheduler.HandleEvent(ctx, gone)
+	s.NoError(err)
+	_, ok = s.scheduler.Next()
+	s.False(ok)
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
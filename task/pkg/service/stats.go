@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	day               = time.Hour * 24
+	defaultStatsRange = day * 30
+	maxStatsRange     = day * 366
+)
+
+// GetTaskStats reports task activity of the caller over the requested range
+// from the daily aggregates maintained by the task repository
+func (ts *TaskService) GetTaskStats(ctx context.Context, in *v1.GetTaskStatsRequest) (*v1.TaskStats, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.Int64("from", in.From),
+		zap.Int64("to", in.To),
+	)
+	now := time.Now()
+	to := in.To
+	if to == 0 {
+		to = now.Unix()
+	}
+	from := in.From
+	if from == 0 {
+		from = to - int64(defaultStatsRange.Seconds())
+	}
+	if from > to || to-from > int64(maxStatsRange.Seconds()) {
+		log.Error(ErrInvalidRange.Error())
+		return &v1.TaskStats{}, apierror.Status(ErrInvalidRange)
+	}
+	days, err := ts.taskRepo.GetDailyStats(ctx, userCtx.UserID, from, to)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.TaskStats{}, apierror.Status(err)
+	}
+	return buildTaskStats(days, time.Unix(from, 0), time.Unix(to, 0), in.Period, now), nil
+}
+
+// buildTaskStats groups the daily aggregates into buckets of the given period and computes streaks.
+// Tasks due on a day count as overdue once the whole day has passed without them being completed in time
+func buildTaskStats(days []repository.DailyStats, from, to time.Time, period v1.StatsPeriod,
+	now time.Time) *v1.TaskStats {
+	byDate := make(map[string]repository.DailyStats, len(days))
+	for _, d := range days {
+		byDate[d.Date] = d
+	}
+	stats := &v1.TaskStats{}
+	var bucket *v1.TaskStatsBucket
+	var leadTimeSum int64
+	streak := int32(0)
+	first := truncateDay(from)
+	last := truncateDay(to)
+	today := truncateDay(now)
+	for date := first; !date.After(last); date = date.Add(day) {
+		start := date
+		if period == v1.StatsPeriod_WEEK {
+			start = weekStart(date)
+		}
+		if bucket == nil || bucket.Start != start.Unix() {
+			bucket = &v1.TaskStatsBucket{Start: start.Unix()}
+			stats.Buckets = append(stats.Buckets, bucket)
+		}
+		d := byDate[repository.StatsDate(date.Unix())]
+		overdue := int32(0)
+		if !date.Add(day).After(now) {
+			overdue = int32(d.Due - d.DueCompleted)
+		}
+		bucket.Created += int32(d.Created)
+		bucket.Completed += int32(d.Completed)
+		bucket.Overdue += overdue
+		stats.Created += int32(d.Created)
+		stats.Completed += int32(d.Completed)
+		stats.Overdue += overdue
+		leadTimeSum += d.LeadTimeSum
+
+		if d.Completed > 0 {
+			streak++
+			if streak > stats.LongestStreak {
+				stats.LongestStreak = streak
+			}
+		} else if !date.Equal(today) {
+			// today without completed task yet does not break the streak
+			streak = 0
+		}
+	}
+	stats.CurrentStreak = streak
+	if stats.Completed > 0 {
+		stats.AverageLeadTime = leadTimeSum / int64(stats.Completed)
+	}
+	return stats
+}
+
+func truncateDay(t time.Time) time.Time {
+	return t.UTC().Truncate(day)
+}
+
+// weekStart returns monday of the week the day falls into
+func weekStart(date time.Time) time.Time {
+	// shift weekdays so that monday is 0 and sunday 6
+	return date.Add(-day * time.Duration((int(date.Weekday())+6)%7))
+}
@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidKey       = errors.New("invalid blob key")
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+)
+
+// BlobStore stores exported files and hands out time-limited links to download them
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	SignedURL(ctx context.Context, key string, expires time.Time) (string, error)
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"cloud.google.com/go/storage"
+	"context"
+	"net/http"
+	"time"
+)
+
+// GCSStore keeps blobs in a Cloud Storage bucket, links are V4 signed URLs
+// signed with the service account the client was created with
+type GCSStore struct {
+	bucket *storage.BucketHandle
+}
+
+func NewGCSStore(client *storage.Client, bucket string) *GCSStore {
+	return &GCSStore{
+		bucket: client.Bucket(bucket),
+	}
+}
+
+func (g *GCSStore) Put(ctx context.Context, key, contentType string, data []byte) error {
+	w := g.bucket.Object(key).NewWriter(ctx)
+	w.ContentType = contentType
+	_, err := w.Write(data)
+	if err != nil {
+		// closing would commit the partially written object
+		w.CloseWithError(err)
+		return err
+	}
+	return w.Close()
+}
+
+func (g *GCSStore) SignedURL(ctx context.Context, key string, expires time.Time) (string, error) {
+	return g.bucket.SignedURL(key, &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  http.MethodGet,
+		Expires: expires,
+	})
+}
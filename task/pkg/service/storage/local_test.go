@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type LocalStoreTestSuite struct {
+	suite.Suite
+	store *LocalStore
+}
+
+func (s *LocalStoreTestSuite) SetupTest() {
+	s.store = NewLocalStore(s.T().TempDir(), "http://localhost/task/export/download", []byte("secret"))
+}
+
+func (s *LocalStoreTestSuite) download(link string) *http.Response {
+	rec := httptest.NewRecorder()
+	s.store.Download(rec, httptest.NewRequest(http.MethodGet, link, nil))
+	return rec.Result()
+}
+
+func (s *LocalStoreTestSuite) TestPutAndDownload() {
+	ctx := context.Background()
+	err := s.store.Put(ctx, "exports/1/job1.csv", "text/csv", []byte("task_id\n"))
+	s.NoError(err)
+
+	link, err := s.store.SignedURL(ctx, "exports/1/job1.csv", time.Now().Add(time.Minute))
+	s.NoError(err)
+	resp := s.download(link)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	s.Equal("task_id\n", string(body))
+
+	// expired link
+	link, err = s.store.SignedURL(ctx, "exports/1/job1.csv", time.Now().Add(-time.Minute))
+	s.NoError(err)
+	s.Equal(http.StatusForbidden, s.download(link).StatusCode)
+
+	// link tampered to reference another key
+	link, err = s.store.SignedURL(ctx, "exports/1/job1.csv", time.Now().Add(time.Minute))
+	s.NoError(err)
+	u, _ := url.Parse(link)
+	query := u.Query()
+	query.Set("key", "exports/2/job2.csv")
+	u.RawQuery = query.Encode()
+	s.Equal(http.StatusForbidden, s.download(u.String()).StatusCode)
+}
+
+func (s *LocalStoreTestSuite) TestInvalidKey() {
+	ctx := context.Background()
+	for _, key := range []string{"", "../outside.json", "exports/../../outside.json", "/absolute.json"} {
+		err := s.store.Put(ctx, key, "application/json", []byte("{}"))
+		s.ErrorIsf(err, ErrInvalidKey, "key %q", key)
+	}
+}
+
+func TestLocalStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(LocalStoreTestSuite))
+}
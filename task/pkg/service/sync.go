@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSyncPageSize = 100
+	maxSyncPageSize     = 500
+	syncTokenVersion    = "1"
+)
+
+// SyncTasks returns the changes of the caller's tasks after the position in the change log the token
+// points to. Clients without a token or with one older than the tombstones get all their tasks instead
+func (ts *TaskService) SyncTasks(ctx context.Context, in *v1.SyncTasksRequest) (*v1.SyncTasksResponse, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	pageSize := int(in.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultSyncPageSize
+	}
+	if pageSize > maxSyncPageSize {
+		pageSize = maxSyncPageSize
+	}
+	now := time.Now()
+	if in.SyncToken != "" {
+		cursor, issuedAt, err := decodeSyncToken(in.SyncToken)
+		if err != nil {
+			log.Error(err.Error())
+			return &v1.SyncTasksResponse{}, apierror.Status(apierror.Field("sync_token", ErrInvalidSyncToken))
+		}
+		// deletions after this time may have been forgotten already
+		since := cursor.UpdatedAt
+		if since.IsZero() {
+			since = issuedAt
+		}
+		if now.Sub(since) < repository.TombstoneTTL {
+			return ts.syncChanges(ctx, log, userCtx.UserID, cursor, pageSize, now)
+		}
+		log.Info("Sync token expired", zap.Time("since", since))
+	}
+	return ts.syncAll(ctx, log, userCtx.UserID, now)
+}
+
+func (ts *TaskService) syncChanges(ctx context.Context, log *zap.Logger, userID string, cursor repository.SyncCursor,
+	pageSize int, now time.Time) (*v1.SyncTasksResponse, error) {
+	// one more change tells whether there are more pages
+	changes, err := ts.taskRepo.GetChanges(ctx, userID, cursor, pageSize+1)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.SyncTasksResponse{}, apierror.Status(err)
+	}
+	resp := &v1.SyncTasksResponse{}
+	if len(changes) > pageSize {
+		resp.HasMore = true
+		changes = changes[:pageSize]
+	}
+	for _, change := range changes {
+		if change.Deleted {
+			resp.Tombstones = append(resp.Tombstones, &v1.Tombstone{
+				TaskId:    change.TaskID,
+				DeletedAt: change.UpdatedAt.Unix(),
+			})
+		} else if change.Task != nil {
+			resp.Tasks = append(resp.Tasks, repository.ToApi(*change.Task))
+		}
+		cursor = change.Cursor()
+	}
+	resp.SyncToken = encodeSyncToken(cursor, now)
+	log.Info("Synced changes", zap.Int("change_count", len(changes)), zap.Bool("has_more", resp.HasMore))
+	return resp, nil
+}
+
+// syncAll returns all tasks with the token of the last change read before them, changes written
+// in the meantime are returned once more by the next sync
+func (ts *TaskService) syncAll(ctx context.Context, log *zap.Logger, userID string,
+	now time.Time) (*v1.SyncTasksResponse, error) {
+	cursor, err := ts.taskRepo.GetLatestChange(ctx, userID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.SyncTasksResponse{}, apierror.Status(err)
+	}
+	tasks, err := ts.taskRepo.GetAll(ctx, userID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.SyncTasksResponse{}, apierror.Status(err)
+	}
+	log.Info("Synced all tasks", zap.Int("task_count", len(tasks)))
+	return &v1.SyncTasksResponse{
+		Tasks:     repository.SliceToApi(tasks).Tasks,
+		SyncToken: encodeSyncToken(cursor, now),
+		FullSync:  true,
+	}, nil
+}
+
+// encodeSyncToken keeps the cursor together with the time the token was issued,
+// which tells the age of tokens issued before the first change
+func encodeSyncToken(cursor repository.SyncCursor, issuedAt time.Time) string {
+	updatedAt := int64(0)
+	if !cursor.UpdatedAt.IsZero() {
+		updatedAt = cursor.UpdatedAt.UnixNano()
+	}
+	raw := fmt.Sprintf("%s:%d:%d:%s", syncTokenVersion, issuedAt.Unix(), updatedAt, cursor.TaskID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSyncToken(token string) (repository.SyncCursor, time.Time, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return repository.SyncCursor{}, time.Time{}, err
+	}
+	parts := strings.SplitN(string(raw), ":", 4)
+	if len(parts) != 4 || parts[0] != syncTokenVersion {
+		return repository.SyncCursor{}, time.Time{}, ErrInvalidSyncToken
+	}
+	issuedAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return repository.SyncCursor{}, time.Time{}, err
+	}
+	updatedAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return repository.SyncCursor{}, time.Time{}, err
+	}
+	cursor := repository.SyncCursor{TaskID: parts[3]}
+	if updatedAt != 0 {
+		cursor.UpdatedAt = time.Unix(0, updatedAt).UTC()
+	}
+	return cursor, time.Unix(issuedAt, 0), nil
+}
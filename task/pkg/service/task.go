@@ -3,25 +3,47 @@ package service
 import (
 	"context"
 	"fmt"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
 	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
 	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/ical"
+	"github.com/jakubjano/todolist/task/pkg/service/importer"
 	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/jakubjano/todolist/task/pkg/service/textformat"
 	"go.uber.org/zap"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
-	"net/http"
 )
 
 type TaskService struct {
 	v1.UnimplementedTaskServiceServer
 	taskRepo repository.FSTaskInterface
-	logger   *zap.Logger
+	exporter *Exporter
+	calendar *Calendar
+	webhooks *Webhooks
+	// notifications keep the channels reminders are sent through
+	notifications *Notifications
+	// digests keep the digest schedules
+	digests *Digests
+	// actions email the links confirming escalation emails
+	actions *ActionLinks
+	// importers parse the documents of ImportTasks, more formats can be registered on it
+	importers *importer.Registry
+	logger    *zap.Logger
 }
 
-func NewTaskService(taskRepo repository.FSTaskInterface, logger *zap.Logger) *TaskService {
+func NewTaskService(taskRepo repository.FSTaskInterface, exporter *Exporter, calendar *Calendar,
+	webhooks *Webhooks, notifications *Notifications, digests *Digests, actions *ActionLinks,
+	logger *zap.Logger) *TaskService {
 	return &TaskService{
-		taskRepo: taskRepo,
-		logger:   logger,
+		taskRepo:      taskRepo,
+		exporter:      exporter,
+		calendar:      calendar,
+		webhooks:      webhooks,
+		notifications: notifications,
+		digests:       digests,
+		actions:       actions,
+		importers:     importer.NewRegistry(),
+		logger:        logger,
 	}
 }
 
@@ -33,10 +55,15 @@ func (ts *TaskService) CreateTask(ctx context.Context, in *v1.Task) (*v1.Task, e
 	)
 	in.UserId = userCtx.UserID
 	in.UserEmail = userCtx.Email
+	err := validateTask(in)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.Task{}, apierror.Status(err)
+	}
 	task, err := ts.taskRepo.Create(ctx, repository.TaskFromMsg(in))
 	if err != nil {
 		log.Error(err.Error(), zap.String("task_id", task.TaskID))
-		return &v1.Task{}, status.Error(http.StatusInternalServerError, err.Error())
+		return &v1.Task{}, apierror.Status(err)
 	}
 	log.Info("Created task ", zap.String("task_id", task.TaskID))
 	return repository.ToApi(task), nil
@@ -52,7 +79,7 @@ func (ts *TaskService) GetTask(ctx context.Context, in *v1.GetTaskRequest) (*v1.
 	task, err := ts.taskRepo.Get(ctx, userCtx.UserID, in.TaskId)
 	if err != nil {
 		log.Error(err.Error())
-		return &v1.Task{}, status.Error(http.StatusBadRequest, err.Error())
+		return &v1.Task{}, apierror.Status(err)
 	}
 	return repository.ToApi(task), nil
 }
@@ -64,11 +91,18 @@ func (ts *TaskService) UpdateTask(ctx context.Context, in *v1.Task) (*v1.Task, e
 		zap.String("caller_id", userCtx.UserID),
 		zap.String("task_id", in.TaskId),
 	)
+	in.UserId = userCtx.UserID
+	in.UserEmail = userCtx.Email
+	err := validateTask(in)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.Task{}, apierror.Status(err)
+	}
 	task, err := ts.taskRepo.Update(ctx, repository.TaskFromMsg(in), userCtx.UserID, in.TaskId)
 	log.Info("Updated task ")
 	if err != nil {
 		log.Error(err.Error())
-		return &v1.Task{}, status.Error(http.StatusInternalServerError, err.Error())
+		return &v1.Task{}, apierror.Status(err)
 	}
 	return repository.ToApi(task), nil
 }
@@ -84,7 +118,7 @@ func (ts *TaskService) DeleteTask(ctx context.Context, in *v1.DeleteTaskRequest)
 	log.Info("Deleted task ")
 	if err != nil {
 		log.Error(err.Error())
-		return &emptypb.Empty{}, status.Error(http.StatusInternalServerError, err.Error())
+		return &emptypb.Empty{}, apierror.Status(err)
 	}
 	return &emptypb.Empty{}, nil
 }
@@ -98,7 +132,7 @@ func (ts *TaskService) GetLastN(ctx context.Context, in *v1.GetLastNRequest) (*v
 	tasks, err := ts.taskRepo.GetLastN(ctx, userCtx.UserID, in.N)
 	if err != nil {
 		log.Error(err.Error())
-		return &v1.TaskList{Tasks: nil}, err
+		return &v1.TaskList{Tasks: nil}, apierror.Status(err)
 	}
 	return repository.SliceToApi(tasks), nil
 }
@@ -112,8 +146,141 @@ func (ts *TaskService) GetExpired(ctx context.Context, in *v1.GetExpiredRequest)
 	tasks, err := ts.taskRepo.GetExpired(ctx, userCtx.UserID)
 	if err != nil {
 		log.Error(err.Error())
-		return &v1.TaskList{Tasks: nil}, err
+		return &v1.TaskList{Tasks: nil}, apierror.Status(err)
 	}
 	fmt.Println(repository.SliceToApi(tasks))
 	return repository.SliceToApi(tasks), nil
 }
+
+// AdminListTasks lists tasks across all users, optionally filtered by the owner's ID or email
+func (ts *TaskService) AdminListTasks(ctx context.Context, in *v1.AdminListTasksRequest) (*v1.TaskList, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("caller_role", userCtx.Role),
+		zap.String("filter_user_id", in.UserId),
+		zap.String("filter_user_email", in.UserEmail),
+	)
+	if userCtx.Role != middleware.ContextAdmin {
+		log.Error(ErrUnauthorized.Error())
+		return &v1.TaskList{Tasks: nil}, apierror.Status(ErrUnauthorized)
+	}
+	log.Info("Admin authorized")
+	tasks, err := ts.taskRepo.List(ctx, repository.TaskFilter{
+		UserID:    in.UserId,
+		UserEmail: in.UserEmail,
+		N:         in.N,
+	})
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.TaskList{Tasks: nil}, apierror.Status(err)
+	}
+	log.Info("Admin listed tasks", zap.Int("count", len(tasks)))
+	return repository.SliceToApi(tasks), nil
+}
+
+func (ts *TaskService) AdminGetTask(ctx context.Context, in *v1.AdminTaskRequest) (*v1.Task, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("caller_role", userCtx.Role),
+		zap.String("user_id", in.UserId),
+		zap.String("task_id", in.TaskId),
+	)
+	if userCtx.Role != middleware.ContextAdmin {
+		log.Error(ErrUnauthorized.Error())
+		return &v1.Task{}, apierror.Status(ErrUnauthorized)
+	}
+	log.Info("Admin authorized")
+	task, err := ts.taskRepo.Get(ctx, in.UserId, in.TaskId)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.Task{}, apierror.Status(err)
+	}
+	log.Info("Admin got task")
+	return repository.ToApi(task), nil
+}
+
+// AdminUpdateTask updates an existing task of the user given by in.UserId,
+// the owner's email is kept from the stored task
+func (ts *TaskService) AdminUpdateTask(ctx context.Context, in *v1.Task) (*v1.Task, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("caller_role", userCtx.Role),
+		zap.String("user_id", in.UserId),
+		zap.String("task_id", in.TaskId),
+	)
+	if userCtx.Role != middleware.ContextAdmin {
+		log.Error(ErrUnauthorized.Error())
+		return &v1.Task{}, apierror.Status(ErrUnauthorized)
+	}
+	log.Info("Admin authorized")
+	err := validateTask(in)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.Task{}, apierror.Status(err)
+	}
+	// unlike UpdateTask, admin must not create tasks on behalf of users by a mistyped ID
+	existing, err := ts.taskRepo.Get(ctx, in.UserId, in.TaskId)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.Task{}, apierror.Status(err)
+	}
+	in.UserEmail = existing.UserEmail
+	task, err := ts.taskRepo.Update(ctx, repository.TaskFromMsg(in), in.UserId, in.TaskId)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.Task{}, apierror.Status(err)
+	}
+	log.Info("Admin updated task")
+	return repository.ToApi(task), nil
+}
+
+func (ts *TaskService) AdminDeleteTask(ctx context.Context, in *v1.AdminTaskRequest) (*emptypb.Empty, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("caller_role", userCtx.Role),
+		zap.String("user_id", in.UserId),
+		zap.String("task_id", in.TaskId),
+	)
+	if userCtx.Role != middleware.ContextAdmin {
+		log.Error(ErrUnauthorized.Error())
+		return &emptypb.Empty{}, apierror.Status(ErrUnauthorized)
+	}
+	log.Info("Admin authorized")
+	err := ts.taskRepo.Delete(ctx, in.UserId, in.TaskId)
+	if err != nil {
+		log.Error(err.Error())
+		return &emptypb.Empty{}, apierror.Status(err)
+	}
+	log.Info("Admin deleted task")
+	return &emptypb.Empty{}, nil
+}
+
+// validateTask checks the optional fields with a format, tasks without recurrence or priority are valid
+func validateTask(in *v1.Task) error {
+	return validateTaskFormats(in.Priority, in.Recurrence)
+}
+
+func validateTaskFormats(priority, recurrence string) error {
+	if priority != "" {
+		err := textformat.ValidatePriority(priority)
+		if err != nil {
+			return apierror.Field("priority", err)
+		}
+	}
+	if recurrence == "" {
+		return nil
+	}
+	err := ical.ValidateRRule(recurrence)
+	if err != nil {
+		return apierror.Field("recurrence", err)
+	}
+	return nil
+}
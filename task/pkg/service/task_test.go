@@ -2,14 +2,18 @@ package service
 
 import (
 	"context"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
 	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
 	middleware "github.com/jakubjano/todolist/task/internal/auth"
 	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"testing"
+	"time"
 )
 
 type ServiceTaskTestSuite struct {
@@ -21,7 +25,7 @@ type ServiceTaskTestSuite struct {
 func (s *ServiceTaskTestSuite) SetupSuite() {
 	logger, _ := zap.NewProduction()
 	taskRepo := repository.NewMockRepo()
-	ts := NewTaskService(taskRepo, logger)
+	ts := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil, logger)
 	s.mockRepo = taskRepo
 	s.ts = ts
 }
@@ -53,14 +57,13 @@ func (s *ServiceTaskTestSuite) TestCreateTask() {
 				UserEmail:   "example1@tst.com",
 			},
 			mockReturn: repository.Task{
-				CreatedAt:    1,
-				Name:         "task1",
-				Description:  "task1 desc",
-				UserID:       "1",
-				UserEmail:    "example1@tst.com",
-				Time:         5,
-				TaskID:       "tid1",
-				ReminderSent: false,
+				CreatedAt:   1,
+				Name:        "task1",
+				Description: "task1 desc",
+				UserID:      "1",
+				UserEmail:   "example1@tst.com",
+				Time:        5,
+				TaskID:      "tid1",
 			},
 			expectedResult: &v1.Task{
 				TaskId:      "tid1",
@@ -162,14 +165,13 @@ func (s *ServiceTaskTestSuite) TestGetTask() {
 	for i, candidate := range candidates {
 		userCtx := candidate.ctx.Value(middleware.ContextUser).(*middleware.UserContext)
 		s.mockRepo.On("Get", candidate.ctx, userCtx.UserID, candidate.in.TaskId).Return(repository.Task{
-			CreatedAt:    candidate.expectedResult.CreatedAt,
-			Name:         candidate.expectedResult.Name,
-			Description:  candidate.expectedResult.Description,
-			UserID:       candidate.expectedResult.UserId,
-			UserEmail:    candidate.expectedResult.UserEmail,
-			Time:         candidate.expectedResult.Time,
-			TaskID:       candidate.expectedResult.TaskId,
-			ReminderSent: false,
+			CreatedAt:   candidate.expectedResult.CreatedAt,
+			Name:        candidate.expectedResult.Name,
+			Description: candidate.expectedResult.Description,
+			UserID:      candidate.expectedResult.UserId,
+			UserEmail:   candidate.expectedResult.UserEmail,
+			Time:        candidate.expectedResult.Time,
+			TaskID:      candidate.expectedResult.TaskId,
 		}, candidate.expectedError)
 		task, err := s.ts.GetTask(candidate.ctx, candidate.in)
 		// check if method was called correctly
@@ -247,22 +249,51 @@ func (s *ServiceTaskTestSuite) TestUpdateTask() {
 			expectedError: nil,
 			expectedCode:  codes.OK,
 		},
+		// the owner of the task is the caller, whatever the input says
+		{
+			ctx: context.WithValue(ctx, middleware.ContextUser, &middleware.UserContext{
+				UserID: "1",
+				Email:  "example1@tst.com",
+				Role:   "user",
+			}),
+			in: &v1.Task{
+				TaskId:      "tid2",
+				CreatedAt:   1,
+				Name:        "updated name",
+				Description: "updated desc",
+				Time:        21,
+				UserId:      "2",
+				UserEmail:   "example2@tst.com",
+			},
+			expectedResult: &v1.Task{
+				TaskId:      "tid2",
+				CreatedAt:   1,
+				Name:        "updated name",
+				Description: "updated desc",
+				Time:        21,
+				UserId:      "1",
+				UserEmail:   "example1@tst.com",
+			},
+			expectedError: nil,
+			expectedCode:  codes.OK,
+		},
 	}
 	for i, candidate := range candidates {
-		s.mockRepo.On("Update", candidate.ctx,
-			repository.TaskFromMsg(candidate.in), candidate.in.UserId, candidate.in.TaskId).Return(repository.Task{
-			CreatedAt:    candidate.expectedResult.CreatedAt,
-			Name:         candidate.expectedResult.Name,
-			Description:  candidate.expectedResult.Description,
-			UserID:       candidate.expectedResult.UserId,
-			UserEmail:    candidate.expectedResult.UserEmail,
-			Time:         candidate.expectedResult.Time,
-			TaskID:       candidate.expectedResult.TaskId,
-			ReminderSent: false,
+		userCtx := candidate.ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+		expected := repository.TaskFromMsg(candidate.in)
+		expected.UserID = userCtx.UserID
+		expected.UserEmail = userCtx.Email
+		s.mockRepo.On("Update", candidate.ctx, expected, userCtx.UserID, candidate.in.TaskId).Return(repository.Task{
+			CreatedAt:   candidate.expectedResult.CreatedAt,
+			Name:        candidate.expectedResult.Name,
+			Description: candidate.expectedResult.Description,
+			UserID:      candidate.expectedResult.UserId,
+			UserEmail:   candidate.expectedResult.UserEmail,
+			Time:        candidate.expectedResult.Time,
+			TaskID:      candidate.expectedResult.TaskId,
 		}, candidate.expectedError)
 		task, err := s.ts.UpdateTask(candidate.ctx, candidate.in)
-		s.mockRepo.AssertCalled(s.T(), "Update", candidate.ctx, repository.TaskFromMsg(candidate.in),
-			candidate.in.UserId, candidate.in.TaskId)
+		s.mockRepo.AssertCalled(s.T(), "Update", candidate.ctx, expected, userCtx.UserID, candidate.in.TaskId)
 		s.Equalf(candidate.expectedResult, task, "candidate %d", i+1)
 		s.Equalf(candidate.expectedCode, status.Code(err), "candidate %:", i+1)
 	}
@@ -330,24 +361,22 @@ func (s *ServiceTaskTestSuite) TestGetLastNTasks() {
 			in: &v1.GetLastNRequest{N: 2},
 			mockReturn: []repository.Task{
 				{
-					CreatedAt:    2,
-					Name:         "task2",
-					Description:  "task2 desc",
-					UserID:       "1",
-					UserEmail:    "example1@tst.com",
-					Time:         6,
-					TaskID:       "tid2",
-					ReminderSent: false,
+					CreatedAt:   2,
+					Name:        "task2",
+					Description: "task2 desc",
+					UserID:      "1",
+					UserEmail:   "example1@tst.com",
+					Time:        6,
+					TaskID:      "tid2",
 				},
 				{
-					CreatedAt:    1,
-					Name:         "task1",
-					Description:  "task1 desc",
-					UserID:       "1",
-					UserEmail:    "example1@tst.com",
-					Time:         5,
-					TaskID:       "tid1",
-					ReminderSent: false,
+					CreatedAt:   1,
+					Name:        "task1",
+					Description: "task1 desc",
+					UserID:      "1",
+					UserEmail:   "example1@tst.com",
+					Time:        5,
+					TaskID:      "tid1",
 				},
 			},
 			expectedResult: &v1.TaskList{
@@ -375,6 +404,18 @@ func (s *ServiceTaskTestSuite) TestGetLastNTasks() {
 			expectedError: nil,
 			expectedCode:  codes.OK,
 		},
+		// repository error is reported with a proper status code
+		{
+			ctx: context.WithValue(ctx, middleware.ContextUser, &middleware.UserContext{
+				UserID: "2",
+				Email:  "example2@tst.com",
+				Role:   "user",
+			}),
+			in:             &v1.GetLastNRequest{N: 2},
+			expectedResult: &v1.TaskList{Tasks: nil},
+			expectedError:  context.DeadlineExceeded,
+			expectedCode:   codes.DeadlineExceeded,
+		},
 	}
 	for i, candidate := range candidates {
 		userCtx := candidate.ctx.Value(middleware.ContextUser).(*middleware.UserContext)
@@ -407,24 +448,22 @@ func (s *ServiceTaskTestSuite) TestGetExpiredTasks() {
 			in: &v1.GetExpiredRequest{},
 			mockReturn: []repository.Task{
 				{
-					CreatedAt:    1,
-					Name:         "task1",
-					Description:  "task1 desc",
-					UserID:       "1",
-					UserEmail:    "example1@tst.com",
-					Time:         2,
-					TaskID:       "tid1",
-					ReminderSent: false,
+					CreatedAt:   1,
+					Name:        "task1",
+					Description: "task1 desc",
+					UserID:      "1",
+					UserEmail:   "example1@tst.com",
+					Time:        2,
+					TaskID:      "tid1",
 				},
 				{
-					CreatedAt:    1,
-					Name:         "task2",
-					Description:  "task2 desc",
-					UserID:       "1",
-					UserEmail:    "example1@tst.com",
-					Time:         3,
-					TaskID:       "tid2",
-					ReminderSent: false,
+					CreatedAt:   1,
+					Name:        "task2",
+					Description: "task2 desc",
+					UserID:      "1",
+					UserEmail:   "example1@tst.com",
+					Time:        3,
+					TaskID:      "tid2",
 				},
 			},
 			expectedResult: &v1.TaskList{
@@ -452,6 +491,18 @@ func (s *ServiceTaskTestSuite) TestGetExpiredTasks() {
 			expectedError: nil,
 			expectedCode:  codes.OK,
 		},
+		// repository error is reported with a proper status code
+		{
+			ctx: context.WithValue(ctx, middleware.ContextUser, &middleware.UserContext{
+				UserID: "2",
+				Email:  "example2@tst.com",
+				Role:   "user",
+			}),
+			in:             &v1.GetExpiredRequest{},
+			expectedResult: &v1.TaskList{Tasks: nil},
+			expectedError:  context.DeadlineExceeded,
+			expectedCode:   codes.DeadlineExceeded,
+		},
 	}
 	for i, candidate := range candidates {
 		userCtx := candidate.ctx.Value(middleware.ContextUser).(*middleware.UserContext)
@@ -464,6 +515,347 @@ func (s *ServiceTaskTestSuite) TestGetExpiredTasks() {
 	}
 }
 
+func (s *ServiceTaskTestSuite) TestAdminListTasks() {
+	ctx := context.Background()
+	candidates := []struct {
+		ctx            context.Context
+		in             *v1.AdminListTasksRequest
+		mockReturn     []repository.Task
+		expectedResult *v1.TaskList
+		expectedError  error
+	}{
+		// admin, filter by user email
+		{
+			ctx: context.WithValue(ctx, middleware.ContextUser, &middleware.UserContext{
+				UserID: "idadmin",
+				Email:  "admin@tst.com",
+				Role:   middleware.ContextAdmin,
+			}),
+			in: &v1.AdminListTasksRequest{UserEmail: "example2@tst.com"},
+			mockReturn: []repository.Task{
+				{
+					CreatedAt:   1,
+					Name:        "task1",
+					Description: "task1 desc",
+					UserID:      "2",
+					UserEmail:   "example2@tst.com",
+					Time:        2,
+					TaskID:      "tid1",
+				},
+			},
+			expectedResult: &v1.TaskList{
+				Tasks: []*v1.Task{
+					{
+						TaskId:      "tid1",
+						CreatedAt:   1,
+						Name:        "task1",
+						Description: "task1 desc",
+						Time:        2,
+						UserId:      "2",
+						UserEmail:   "example2@tst.com",
+					},
+				},
+			},
+			expectedError: nil,
+		},
+		// user role is not allowed to list tasks of others
+		{
+			ctx: context.WithValue(ctx, middleware.ContextUser, &middleware.UserContext{
+				UserID: "1",
+				Email:  "example1@tst.com",
+				Role:   middleware.ContextUser,
+			}),
+			in:             &v1.AdminListTasksRequest{UserId: "2"},
+			mockReturn:     []repository.Task{},
+			expectedResult: &v1.TaskList{Tasks: nil},
+			expectedError:  apierror.Status(ErrUnauthorized),
+		},
+	}
+	for i, candidate := range candidates {
+		filter := repository.TaskFilter{
+			UserID:    candidate.in.UserId,
+			UserEmail: candidate.in.UserEmail,
+			N:         candidate.in.N,
+		}
+		s.mockRepo.On("List", candidate.ctx, filter).Return(candidate.mockReturn, nil)
+		tasks, err := s.ts.AdminListTasks(candidate.ctx, candidate.in)
+		s.Equalf(candidate.expectedResult, tasks, "candidate %d", i+1)
+		s.Equalf(candidate.expectedError, err, "candidate %d", i+1)
+	}
+	s.mockRepo.AssertNumberOfCalls(s.T(), "List", 1)
+}
+
+func (s *ServiceTaskTestSuite) TestAdminDeleteTask() {
+	ctx := context.Background()
+	candidates := []struct {
+		ctx           context.Context
+		in            *v1.AdminTaskRequest
+		expectedError error
+	}{
+		// admin deleting task of another user
+		{
+			ctx: context.WithValue(ctx, middleware.ContextUser, &middleware.UserContext{
+				UserID: "idadmin",
+				Email:  "admin@tst.com",
+				Role:   middleware.ContextAdmin,
+			}),
+			in:            &v1.AdminTaskRequest{UserId: "2", TaskId: "tid4"},
+			expectedError: nil,
+		},
+		// user role trying to delete task of another user
+		{
+			ctx: context.WithValue(ctx, middleware.ContextUser, &middleware.UserContext{
+				UserID: "1",
+				Email:  "example1@tst.com",
+				Role:   middleware.ContextUser,
+			}),
+			in:            &v1.AdminTaskRequest{UserId: "3", TaskId: "tid5"},
+			expectedError: apierror.Status(ErrUnauthorized),
+		},
+	}
+	for i, candidate := range candidates {
+		s.mockRepo.On("Delete", candidate.ctx, candidate.in.UserId, candidate.in.TaskId).Return(nil)
+		_, err := s.ts.AdminDeleteTask(candidate.ctx, candidate.in)
+		s.Equalf(candidate.expectedError, err, "candidate %d", i+1)
+	}
+	s.mockRepo.AssertCalled(s.T(), "Delete", candidates[0].ctx, "2", "tid4")
+	s.mockRepo.AssertNotCalled(s.T(), "Delete", candidates[1].ctx, "3", "tid5")
+}
+
+func (s *ServiceTaskTestSuite) TestGetTaskStats() {
+	ctx := context.Background()
+	candidates := []struct {
+		ctx            context.Context
+		in             *v1.GetTaskStatsRequest
+		mockReturn     []repository.DailyStats
+		expectedResult *v1.TaskStats
+		expectedError  error
+	}{
+		// weekly buckets, monday 2022-07-18 to wednesday 2022-07-27
+		{
+			ctx: context.WithValue(ctx, middleware.ContextUser, &middleware.UserContext{
+				UserID: "1",
+				Email:  "example1@tst.com",
+				Role:   middleware.ContextUser,
+			}),
+			in: &v1.GetTaskStatsRequest{From: 1658102400, To: 1658923200, Period: v1.StatsPeriod_WEEK},
+			mockReturn: []repository.DailyStats{
+				{Date: "2022-07-18", Created: 2, Completed: 1, Due: 1, DueCompleted: 1, LeadTimeSum: 100},
+				{Date: "2022-07-19", Completed: 1, Due: 2, LeadTimeSum: 300},
+				{Date: "2022-07-25", Created: 1, Completed: 1, LeadTimeSum: 200},
+				{Date: "2022-07-26", Completed: 1, LeadTimeSum: 400},
+			},
+			expectedResult: &v1.TaskStats{
+				Buckets: []*v1.TaskStatsBucket{
+					{Start: 1658102400, Created: 2, Completed: 2, Overdue: 2},
+					{Start: 1658707200, Created: 1, Completed: 2, Overdue: 0},
+				},
+				Created:         3,
+				Completed:       4,
+				Overdue:         2,
+				AverageLeadTime: 250,
+				CurrentStreak:   0,
+				LongestStreak:   2,
+			},
+			expectedError: nil,
+		},
+		// range ends before it starts
+		{
+			ctx: context.WithValue(ctx, middleware.ContextUser, &middleware.UserContext{
+				UserID: "1",
+				Email:  "example1@tst.com",
+				Role:   middleware.ContextUser,
+			}),
+			in:             &v1.GetTaskStatsRequest{From: 1658923200, To: 1658102400},
+			mockReturn:     []repository.DailyStats{},
+			expectedResult: &v1.TaskStats{},
+			expectedError:  apierror.Status(ErrInvalidRange),
+		},
+	}
+	for i, candidate := range candidates {
+		userCtx := candidate.ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+		s.mockRepo.On("GetDailyStats", candidate.ctx, userCtx.UserID, candidate.in.From, candidate.in.To).
+			Return(candidate.mockReturn, nil)
+		stats, err := s.ts.GetTaskStats(candidate.ctx, candidate.in)
+		s.Equalf(candidate.expectedResult, stats, "candidate %d", i+1)
+		s.Equalf(candidate.expectedError, err, "candidate %d", i+1)
+	}
+}
+
+func (s *ServiceTaskTestSuite) TestImportTasks() {
+	ctx := context.WithValue(context.Background(), middleware.ContextUser, &middleware.UserContext{
+		UserID: "7",
+		Email:  "example7@tst.com",
+		Role:   middleware.ContextUser,
+	})
+	task := repository.Task{
+		Name:      "buy milk @shop",
+		UserID:    "7",
+		UserEmail: "example7@tst.com",
+		Time:      1658188800,
+		Priority:  "A",
+	}
+	created := task
+	created.TaskID = "tid7"
+	s.mockRepo.On("Create", ctx, task).Return(created, nil)
+
+	resp, err := s.ts.ImportTasks(ctx, &v1.ImportTasksRequest{
+		Format:  v1.TextFormat_MARKDOWN,
+		Content: "# Shopping\n- [ ] (A) buy milk @shop due:2022-07-19\n- [ ] due:2022-07-19\n",
+	})
+	s.NoError(err)
+	s.Equal(&v1.ImportTasksResponse{
+		Tasks:  []*v1.Task{repository.ToApi(created)},
+		Errors: []*v1.ImportError{{Line: 3, Message: "missing task text"}},
+	}, resp)
+
+	// dry run reports the task without creating it
+	resp, err = s.ts.ImportTasks(ctx, &v1.ImportTasksRequest{
+		Format:  v1.TextFormat_TRELLO_JSON,
+		Content: `{"lists": [{"id": "l1", "name": "Backlog"}], "cards": [{"id": "c1", "name": "dry", "idList": "l1"}]}`,
+		DryRun:  true,
+	})
+	s.NoError(err)
+	s.Equal(&v1.ImportTasksResponse{
+		Tasks:  []*v1.Task{{Name: "dry +Backlog", UserId: "7", UserEmail: "example7@tst.com"}},
+		DryRun: true,
+	}, resp)
+	s.mockRepo.AssertNotCalled(s.T(), "Create", ctx, repository.Task{
+		Name:      "dry +Backlog",
+		UserID:    "7",
+		UserEmail: "example7@tst.com",
+	})
+}
+
+func (s *ServiceTaskTestSuite) TestReminderSettings() {
+	ctx := context.WithValue(context.Background(), middleware.ContextUser, &middleware.UserContext{
+		UserID: "8",
+		Email:  "example8@tst.com",
+		Role:   "user",
+	})
+	s.mockRepo.On("GetReminderSettings", mock.Anything, "8").Return(repository.ReminderSettings{
+		UserID:    "8",
+		Reminders: repository.DefaultReminders,
+	}, nil)
+	settings, err := s.ts.GetReminderSettings(ctx, nil)
+	s.NoError(err)
+	s.Equal([]int64{300}, settings.Reminders)
+
+	s.mockRepo.On("SetReminderSettings", mock.Anything, repository.ReminderSettings{
+		UserID:    "8",
+		Reminders: []int64{0, 86400},
+	}).Return(repository.ReminderSettings{UserID: "8", Reminders: []int64{86400, 0}}, nil)
+	settings, err = s.ts.UpdateReminderSettings(ctx, &v1.ReminderSettings{Reminders: []int64{0, 86400}})
+	s.NoError(err)
+	s.Equal([]int64{86400, 0}, settings.Reminders)
+}
+
+func (s *ServiceTaskTestSuite) TestSyncTasks() {
+	ctx := context.WithValue(context.Background(), middleware.ContextUser, &middleware.UserContext{
+		UserID: "8",
+		Email:  "example8@tst.com",
+		Role:   middleware.ContextUser,
+	})
+	now := time.Now().UTC()
+	task := repository.Task{TaskID: "tid8", Name: "synced", UserID: "8", CreatedAt: 1, UpdatedAt: 2}
+	latest := repository.SyncCursor{UpdatedAt: now.Add(-time.Hour), TaskID: "tid8"}
+	s.mockRepo.On("GetLatestChange", ctx, "8").Return(latest, nil)
+	s.mockRepo.On("GetAll", ctx, "8").Return([]repository.Task{task}, nil)
+
+	// first sync downloads everything
+	resp, err := s.ts.SyncTasks(ctx, &v1.SyncTasksRequest{})
+	s.NoError(err)
+	s.True(resp.FullSync)
+	s.Equal([]*v1.Task{repository.ToApi(task)}, resp.Tasks)
+	cursor, _, err := decodeSyncToken(resp.SyncToken)
+	s.NoError(err)
+	s.Equal(latest, cursor)
+
+	// delta is paged, the token points to the last returned change
+	deleted := repository.Change{TaskID: "tid9", Deleted: true, UpdatedAt: now.Add(-time.Minute)}
+	s.mockRepo.On("GetChanges", ctx, "8", latest, 3).Return([]repository.Change{
+		{TaskID: "tid8", Task: &task, UpdatedAt: now.Add(-time.Minute * 2)},
+		deleted,
+		{TaskID: "tid10", Task: &repository.Task{TaskID: "tid10"}, UpdatedAt: now},
+	}, nil)
+	resp, err = s.ts.SyncTasks(ctx, &v1.SyncTasksRequest{SyncToken: resp.SyncToken, PageSize: 2})
+	s.NoError(err)
+	s.False(resp.FullSync)
+	s.True(resp.HasMore)
+	s.Equal([]*v1.Task{repository.ToApi(task)}, resp.Tasks)
+	s.Equal([]*v1.Tombstone{{TaskId: "tid9", DeletedAt: deleted.UpdatedAt.Unix()}}, resp.Tombstones)
+	cursor, _, err = decodeSyncToken(resp.SyncToken)
+	s.NoError(err)
+	s.Equal(deleted.Cursor(), cursor)
+
+	// tombstones of deletions after an old token may be gone
+	expired := encodeSyncToken(repository.SyncCursor{UpdatedAt: now.Add(-repository.TombstoneTTL - time.Hour)}, now)
+	resp, err = s.ts.SyncTasks(ctx, &v1.SyncTasksRequest{SyncToken: expired})
+	s.NoError(err)
+	s.True(resp.FullSync)
+
+	_, err = s.ts.SyncTasks(ctx, &v1.SyncTasksRequest{SyncToken: "garbage"})
+	s.Equal(apierror.Status(apierror.Field("sync_token", ErrInvalidSyncToken)), err)
+}
+
+// watchStream collects the events sent to the client
+type watchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events []*v1.TaskEvent
+}
+
+func (w *watchStream) Context() context.Context {
+	return w.ctx
+}
+
+func (w *watchStream) Send(event *v1.TaskEvent) error {
+	w.events = append(w.events, event)
+	return nil
+}
+
+func (s *ServiceTaskTestSuite) TestWatchTasks() {
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), middleware.ContextUser,
+		&middleware.UserContext{
+			UserID: "9",
+			Email:  "example9@tst.com",
+			Role:   middleware.ContextUser,
+		}))
+	now := time.Now().UTC()
+	latest := repository.SyncCursor{UpdatedAt: now.Add(-time.Hour), TaskID: "tid9"}
+	task := repository.Task{TaskID: "tid10", Name: "watched", UserID: "9"}
+	changes := []repository.Change{
+		{TaskID: "tid10", Task: &task, Created: true, UpdatedAt: now},
+		{TaskID: "tid9", Deleted: true, UpdatedAt: now.Add(time.Second)},
+	}
+	s.mockRepo.On("GetLatestChange", ctx, "9").Return(latest, nil)
+	s.mockRepo.On("WatchChanges", ctx, "9", latest, mock.Anything).Run(func(args mock.Arguments) {
+		handle := args.Get(3).(func(repository.Change) error)
+		for _, change := range changes {
+			s.NoError(handle(change))
+		}
+		// client goes away
+		cancel()
+	}).Return(context.Canceled)
+
+	stream := &watchStream{ctx: ctx}
+	err := s.ts.WatchTasks(&v1.WatchTasksRequest{}, stream)
+	s.NoError(err)
+	s.Len(stream.events, 2)
+	s.Equal(v1.TaskEventType_CREATED, stream.events[0].Type)
+	s.Equal(repository.ToApi(task), stream.events[0].Task)
+	s.Equal(v1.TaskEventType_DELETED, stream.events[1].Type)
+	s.Equal("tid9", stream.events[1].TaskId)
+	s.Nil(stream.events[1].Task)
+	cursor, _, err := decodeSyncToken(stream.events[1].SyncToken)
+	s.NoError(err)
+	s.Equal(changes[1].Cursor(), cursor)
+
+	expired := encodeSyncToken(repository.SyncCursor{UpdatedAt: now.Add(-repository.TombstoneTTL - time.Hour)}, now)
+	err = s.ts.WatchTasks(&v1.WatchTasksRequest{SyncToken: expired}, &watchStream{ctx: ctx})
+	s.Equal(apierror.Status(ErrSyncTokenExpired), err)
+}
+
 func TestServiceTaskTestSuite(t *testing.T) {
 	suite.Run(t, new(ServiceTaskTestSuite))
 }
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	v2 "github.com/jakubjano/todolist/apis/go-sdk/task/v2"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTaskPageSize = 50
+	pageTokenVersion    = "1"
+)
+
+// TaskServiceV2 serves the resource oriented v2 API, sharing the repositories and the logger with TaskService
+type TaskServiceV2 struct {
+	v2.UnimplementedTaskServiceServer
+	ts *TaskService
+}
+
+func NewTaskServiceV2(ts *TaskService) *TaskServiceV2 {
+	return &TaskServiceV2{
+		ts: ts,
+	}
+}
+
+func (s *TaskServiceV2) ListTasks(ctx context.Context, in *v2.ListTasksRequest) (*v2.ListTasksResponse, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := s.ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("parent", in.Parent),
+	)
+	userID, err := parseUserName(in.Parent)
+	if err == nil {
+		err = authorizeOwner(userCtx, userID)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.ListTasksResponse{}, apierror.Status(err)
+	}
+	var after repository.PageCursor
+	if in.PageToken != "" {
+		after, err = decodePageToken(in.PageToken)
+		if err != nil {
+			log.Error(err.Error())
+			return &v2.ListTasksResponse{}, apierror.Status(apierror.Field("page_token", ErrInvalidPageToken))
+		}
+	}
+	pageSize := int(in.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultTaskPageSize
+	}
+	if pageSize > repository.MaxListSize {
+		pageSize = repository.MaxListSize
+	}
+	// one more task tells whether there is a next page
+	tasks, err := s.ts.taskRepo.GetPage(ctx, userID, after, pageSize+1)
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.ListTasksResponse{}, apierror.Status(err)
+	}
+	resp := &v2.ListTasksResponse{}
+	if len(tasks) > pageSize {
+		tasks = tasks[:pageSize]
+		resp.NextPageToken = encodePageToken(tasks[pageSize-1].PageCursor())
+	}
+	for _, task := range tasks {
+		resp.Tasks = append(resp.Tasks, taskToV2(task))
+	}
+	return resp, nil
+}
+
+func (s *TaskServiceV2) GetTask(ctx context.Context, in *v2.GetTaskRequest) (*v2.Task, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := s.ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("name", in.Name),
+	)
+	task, err := s.get(ctx, userCtx, in.Name)
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.Task{}, apierror.Status(err)
+	}
+	return taskToV2(task), nil
+}
+
+// CreateTask creates tasks of the caller only, even admins can't create tasks on behalf of other users
+func (s *TaskServiceV2) CreateTask(ctx context.Context, in *v2.CreateTaskRequest) (*v2.Task, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := s.ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("parent", in.Parent),
+	)
+	userID, err := parseUserName(in.Parent)
+	if err == nil && userID != userCtx.UserID {
+		err = ErrUnauthorized
+	}
+	if err == nil {
+		err = validateTaskFormats(in.Task.Priority, in.Task.Recurrence)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.Task{}, apierror.Status(err)
+	}
+	task := repository.Task{
+		UserID:    userCtx.UserID,
+		UserEmail: userCtx.Email,
+	}
+	applyTaskV2(&task, in.Task, nil)
+	err = validateDisplayName(task)
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.Task{}, apierror.Status(err)
+	}
+	// the repository gives tasks created without reminders the defaults of the user
+	if len(task.Reminders) == 0 {
+		task.Reminders = nil
+	}
+	task, err = s.ts.taskRepo.Create(ctx, task)
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.Task{}, apierror.Status(err)
+	}
+	log.Info("Created task", zap.String("task_id", task.TaskID))
+	return taskToV2(task), nil
+}
+
+func (s *TaskServiceV2) UpdateTask(ctx context.Context, in *v2.UpdateTaskRequest) (*v2.Task, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := s.ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("name", in.Task.Name),
+	)
+	err := validateTaskMask(in.UpdateMask)
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.Task{}, apierror.Status(err)
+	}
+	task, err := s.get(ctx, userCtx, in.Task.Name)
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.Task{}, apierror.Status(err)
+	}
+	applyTaskV2(&task, in.Task, in.UpdateMask)
+	err = validateDisplayName(task)
+	if err == nil {
+		err = validateTaskFormats(task.Priority, task.Recurrence)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.Task{}, apierror.Status(err)
+	}
+	task, err = s.ts.taskRepo.Update(ctx, task, task.UserID, task.TaskID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.Task{}, apierror.Status(err)
+	}
+	log.Info("Updated task")
+	return taskToV2(task), nil
+}
+
+func (s *TaskServiceV2) DeleteTask(ctx context.Context, in *v2.DeleteTaskRequest) (*emptypb.Empty, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := s.ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("name", in.Name),
+	)
+	// unlike v1, deleting a missing task is an error
+	task, err := s.get(ctx, userCtx, in.Name)
+	if err == nil {
+		err = s.ts.taskRepo.Delete(ctx, task.UserID, task.TaskID)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return &emptypb.Empty{}, apierror.Status(err)
+	}
+	log.Info("Deleted task")
+	return &emptypb.Empty{}, nil
+}
+
+func (s *TaskServiceV2) get(ctx context.Context, userCtx *middleware.UserContext, name string) (repository.Task, error) {
+	userID, taskID, err := parseTaskName(name)
+	if err != nil {
+		return repository.Task{}, err
+	}
+	err = authorizeOwner(userCtx, userID)
+	if err != nil {
+		return repository.Task{}, err
+	}
+	return s.ts.taskRepo.Get(ctx, userID, taskID)
+}
+
+// authorizeOwner lets users reach their own resources and admins the resources of everyone
+func authorizeOwner(userCtx *middleware.UserContext, userID string) error {
+	if userCtx.Role == middleware.ContextAdmin || userCtx.UserID == userID {
+		return nil
+	}
+	return ErrUnauthorized
+}
+
+func userName(userID string) string {
+	return "users/" + userID
+}
+
+func taskName(userID, taskID string) string {
+	return userName(userID) + "/tasks/" + taskID
+}
+
+// parseUserName returns the user ID of users/{user}
+func parseUserName(name string) (string, error) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 2 || parts[0] != "users" || parts[1] == "" {
+		return "", apierror.Field("name", ErrInvalidName)
+	}
+	return parts[1], nil
+}
+
+// parseTaskName returns the user and task ID of users/{user}/tasks/{task}
+func parseTaskName(name string) (string, string, error) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 4 || parts[0] != "users" || parts[1] == "" || parts[2] != "tasks" || parts[3] == "" {
+		return "", "", apierror.Field("name", ErrInvalidName)
+	}
+	return parts[1], parts[3], nil
+}
+
+func taskToV2(task repository.Task) *v2.Task {
+	return &v2.Task{
+		Name:             taskName(task.UserID, task.TaskID),
+		DisplayName:      task.Name,
+		Description:      task.Description,
+		DueTime:          unixToTimestamp(task.Time),
+		Completed:        task.Completed,
+		Recurrence:       task.Recurrence,
+		Priority:         task.Priority,
+		OwnerEmail:       task.UserEmail,
+		CompleteTime:     unixToTimestamp(task.CompletedAt),
+		CreateTime:       unixToTimestamp(task.CreatedAt),
+		UpdateTime:       unixToTimestamp(task.UpdatedAt),
+		Reminders:        offsetsToDurations(task.Reminders),
+		NextReminderTime: unixToTimestamp(task.NextReminderAt),
+		StopNagging:      task.StopNagging,
+	}
+}
+
+// taskMaskPaths are the mutable fields of v2 tasks, output only fields are left out
+var taskMaskPaths = map[string]bool{
+	"display_name": true,
+	"description":  true,
+	"due_time":     true,
+	"completed":    true,
+	"recurrence":   true,
+	"priority":     true,
+	"reminders":    true,
+	"stop_nagging": true,
+}
+
+func validateTaskMask(mask *fieldmaskpb.FieldMask) error {
+	for _, path := range mask.GetPaths() {
+		if !taskMaskPaths[path] {
+			return apierror.Field("update_mask", fmt.Errorf("%w: %s", ErrInvalidUpdateMask, path))
+		}
+	}
+	return nil
+}
+
+// validateDisplayName checks the task after the request was applied, so a display name left out of the mask passes
+func validateDisplayName(task repository.Task) error {
+	if task.Name == "" {
+		return apierror.Field("task.display_name", ErrDisplayNameRequired)
+	}
+	return nil
+}
+
+// applyTaskV2 copies the fields of the mask from in to task, all mutable fields without a mask
+func applyTaskV2(task *repository.Task, in *v2.Task, mask *fieldmaskpb.FieldMask) {
+	paths := mask.GetPaths()
+	if len(paths) == 0 {
+		for path := range taskMaskPaths {
+			paths = append(paths, path)
+		}
+	}
+	for _, path := range paths {
+		switch path {
+		case "display_name":
+			task.Name = in.DisplayName
+		case "description":
+			task.Description = in.Description
+		case "due_time":
+			task.Time = timestampToUnix(in.DueTime)
+		case "completed":
+			task.Completed = in.Completed
+		case "recurrence":
+			task.Recurrence = in.Recurrence
+		case "priority":
+			task.Priority = in.Priority
+		case "reminders":
+			task.Reminders = durationsToOffsets(in.Reminders)
+		case "stop_nagging":
+			task.StopNagging = in.StopNagging
+		}
+	}
+}
+
+// unixToTimestamp leaves unset times, stored as 0, unset
+func unixToTimestamp(unix int64) *timestamppb.Timestamp {
+	if unix == 0 {
+		return nil
+	}
+	return timestamppb.New(time.Unix(unix, 0))
+}
+
+func timestampToUnix(ts *timestamppb.Timestamp) int64 {
+	if ts == nil {
+		return 0
+	}
+	return ts.AsTime().Unix()
+}
+
+// durationsToOffsets never returns nil, an empty list clears the reminders of the task
+func durationsToOffsets(durations []*durationpb.Duration) []int64 {
+	offsets := make([]int64, 0, len(durations))
+	for _, d := range durations {
+		offsets = append(offsets, int64(d.AsDuration().Seconds()))
+	}
+	return offsets
+}
+
+func offsetsToDurations(offsets []int64) []*durationpb.Duration {
+	var durations []*durationpb.Duration
+	for _, offset := range offsets {
+		durations = append(durations, durationpb.New(time.Duration(offset)*time.Second))
+	}
+	return durations
+}
+
+func encodePageToken(cursor repository.PageCursor) string {
+	raw := fmt.Sprintf("%s:%d:%s", pageTokenVersion, cursor.CreatedAt, cursor.TaskID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePageToken(token string) (repository.PageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return repository.PageCursor{}, err
+	}
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 || parts[0] != pageTokenVersion || parts[2] == "" {
+		return repository.PageCursor{}, ErrInvalidPageToken
+	}
+	createdAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return repository.PageCursor{}, err
+	}
+	return repository.PageCursor{CreatedAt: createdAt, TaskID: parts[2]}, nil
+}
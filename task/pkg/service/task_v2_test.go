@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	v2 "github.com/jakubjano/todolist/apis/go-sdk/task/v2"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"testing"
+	"time"
+)
+
+type TaskServiceV2TestSuite struct {
+	suite.Suite
+	s        *TaskServiceV2
+	mockRepo *repository.FSTaskMock
+	ctx      context.Context
+}
+
+func (s *TaskServiceV2TestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.mockRepo = repository.NewMockRepo()
+	s.s = NewTaskServiceV2(NewTaskService(s.mockRepo, nil, nil, nil, nil, nil, nil, logger))
+	s.ctx = context.WithValue(context.Background(), middleware.ContextUser, &middleware.UserContext{
+		UserID: "1",
+		Email:  "example1@tst.com",
+		Role:   middleware.ContextUser,
+	})
+}
+
+func (s *TaskServiceV2TestSuite) TestGetTask() {
+	admin := context.WithValue(context.Background(), middleware.ContextUser, &middleware.UserContext{
+		UserID: "9",
+		Email:  "admin@tst.com",
+		Role:   middleware.ContextAdmin,
+	})
+	s.mockRepo.On("Get", mock.Anything, "1", "tid1").Return(repository.Task{
+		TaskID:    "tid1",
+		UserID:    "1",
+		UserEmail: "example1@tst.com",
+		Name:      "task1",
+		Time:      1658102400,
+		CreatedAt: 1658016000,
+	}, nil)
+	s.mockRepo.On("Get", mock.Anything, "1", "tid2").Return(repository.Task{}, status.Error(codes.NotFound, ""))
+	candidates := []struct {
+		ctx          context.Context
+		name         string
+		expectedCode codes.Code
+	}{
+		{ctx: s.ctx, name: "users/1/tasks/tid1", expectedCode: codes.OK},
+		// admins read the tasks of everyone
+		{ctx: admin, name: "users/1/tasks/tid1", expectedCode: codes.OK},
+		{ctx: s.ctx, name: "users/1/tasks/tid2", expectedCode: codes.NotFound},
+		{ctx: s.ctx, name: "users/2/tasks/tid1", expectedCode: codes.PermissionDenied},
+		{ctx: s.ctx, name: "users/1/task/tid1", expectedCode: codes.InvalidArgument},
+	}
+	for i, c := range candidates {
+		task, err := s.s.GetTask(c.ctx, &v2.GetTaskRequest{Name: c.name})
+		s.Equalf(c.expectedCode, status.Code(err), "candidate %d", i+1)
+		if err == nil {
+			s.Equal("users/1/tasks/tid1", task.Name)
+			s.Equal("task1", task.DisplayName)
+			s.Equal(int64(1658102400), task.DueTime.Seconds)
+			s.Equal(int64(1658016000), task.CreateTime.Seconds)
+			s.Nil(task.CompleteTime)
+		}
+	}
+}
+
+func (s *TaskServiceV2TestSuite) TestCreateTask() {
+	var created repository.Task
+	s.mockRepo.On("Create", mock.Anything, mock.Anything).Return(repository.Task{
+		TaskID:    "tid3",
+		UserID:    "1",
+		UserEmail: "example1@tst.com",
+		Name:      "task3",
+	}, nil).Run(func(args mock.Arguments) {
+		created = args.Get(1).(repository.Task)
+	})
+	task, err := s.s.CreateTask(s.ctx, &v2.CreateTaskRequest{
+		Parent: "users/1",
+		Task:   &v2.Task{DisplayName: "task3", DueTime: timestamppb.New(time.Unix(1658102400, 0))},
+	})
+	s.Require().NoError(err)
+	s.Equal("users/1/tasks/tid3", task.Name)
+	s.Equal(repository.Task{
+		UserID:    "1",
+		UserEmail: "example1@tst.com",
+		Name:      "task3",
+		Time:      1658102400,
+	}, created)
+
+	_, err = s.s.CreateTask(s.ctx, &v2.CreateTaskRequest{Parent: "users/2", Task: &v2.Task{DisplayName: "task4"}})
+	s.Equal(codes.PermissionDenied, status.Code(err))
+	_, err = s.s.CreateTask(s.ctx, &v2.CreateTaskRequest{Parent: "users/1", Task: &v2.Task{Priority: "AB"}})
+	s.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = s.s.CreateTask(s.ctx, &v2.CreateTaskRequest{Parent: "users/1", Task: &v2.Task{Description: "no name"}})
+	s.Equal(codes.InvalidArgument, status.Code(err))
+	s.mockRepo.AssertNumberOfCalls(s.T(), "Create", 1)
+}
+
+func (s *TaskServiceV2TestSuite) TestUpdateTask() {
+	existing := repository.Task{
+		TaskID:        "tid5",
+		UserID:        "1",
+		UserEmail:     "example1@tst.com",
+		Name:          "task5",
+		Description:   "desc5",
+		Time:          1658102400,
+		Reminders:     []int64{3600},
+		RemindersSent: []int64{3600},
+	}
+	s.mockRepo.On("Get", mock.Anything, "1", "tid5").Return(existing, nil)
+	var updated repository.Task
+	s.mockRepo.On("Update", mock.Anything, mock.Anything, "1", "tid5").Return(repository.Task{}, nil).
+		Run(func(args mock.Arguments) {
+			updated = args.Get(1).(repository.Task)
+		})
+	candidates := []struct {
+		in           *v2.UpdateTaskRequest
+		expectedCode codes.Code
+		expected     repository.Task
+	}{
+		// only the fields of the mask change
+		{
+			in: &v2.UpdateTaskRequest{
+				Task:       &v2.Task{Name: "users/1/tasks/tid5", DisplayName: "renamed", Description: "ignored"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"display_name"}},
+			},
+			expected: repository.Task{
+				TaskID: "tid5", UserID: "1", UserEmail: "example1@tst.com", Name: "renamed",
+				Description: "desc5", Time: 1658102400, Reminders: []int64{3600}, RemindersSent: []int64{3600},
+			},
+		},
+		// without a mask all mutable fields are replaced, the reminders too
+		{
+			in: &v2.UpdateTaskRequest{
+				Task: &v2.Task{
+					Name:        "users/1/tasks/tid5",
+					DisplayName: "task5",
+					DueTime:     timestamppb.New(time.Unix(1658188800, 0)),
+				},
+			},
+			expected: repository.Task{
+				TaskID: "tid5", UserID: "1", UserEmail: "example1@tst.com", Name: "task5", Time: 1658188800,
+				Reminders: []int64{}, RemindersSent: []int64{3600},
+			},
+		},
+		{
+			in: &v2.UpdateTaskRequest{
+				Task: &v2.Task{
+					Name:      "users/1/tasks/tid5",
+					Reminders: []*durationpb.Duration{durationpb.New(time.Hour), durationpb.New(0)},
+				},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"reminders"}},
+			},
+			expected: repository.Task{
+				TaskID: "tid5", UserID: "1", UserEmail: "example1@tst.com", Name: "task5",
+				Description: "desc5", Time: 1658102400, Reminders: []int64{3600, 0}, RemindersSent: []int64{3600},
+			},
+		},
+		{
+			in: &v2.UpdateTaskRequest{
+				Task:       &v2.Task{Name: "users/1/tasks/tid5", StopNagging: true},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"stop_nagging"}},
+			},
+			expected: repository.Task{
+				TaskID: "tid5", UserID: "1", UserEmail: "example1@tst.com", Name: "task5", Description: "desc5",
+				Time: 1658102400, Reminders: []int64{3600}, RemindersSent: []int64{3600}, StopNagging: true,
+			},
+		},
+		// the display name is required only when the mask has it
+		{
+			in: &v2.UpdateTaskRequest{
+				Task:       &v2.Task{Name: "users/1/tasks/tid5", Description: "patched"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"description"}},
+			},
+			expected: repository.Task{
+				TaskID: "tid5", UserID: "1", UserEmail: "example1@tst.com", Name: "task5",
+				Description: "patched", Time: 1658102400, Reminders: []int64{3600}, RemindersSent: []int64{3600},
+			},
+		},
+		{
+			in: &v2.UpdateTaskRequest{
+				Task:       &v2.Task{Name: "users/1/tasks/tid5", Description: "patched"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"display_name", "description"}},
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			in: &v2.UpdateTaskRequest{
+				Task:       &v2.Task{Name: "users/1/tasks/tid5"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"owner_email"}},
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+	for i, c := range candidates {
+		// the request passes the validation of the message, the service checks the rest
+		s.NoErrorf(c.in.Validate(), "candidate %d", i+1)
+		updated = repository.Task{}
+		_, err := s.s.UpdateTask(s.ctx, c.in)
+		s.Equalf(c.expectedCode, status.Code(err), "candidate %d", i+1)
+		s.Equalf(c.expected, updated, "candidate %d", i+1)
+	}
+}
+
+func (s *TaskServiceV2TestSuite) TestListTasks() {
+	page := []repository.Task{
+		{TaskID: "tid8", UserID: "1", CreatedAt: 30},
+		{TaskID: "tid7", UserID: "1", CreatedAt: 20},
+		{TaskID: "tid6", UserID: "1", CreatedAt: 10},
+	}
+	s.mockRepo.On("GetPage", mock.Anything, "1", repository.PageCursor{}, 3).Return(page, nil)
+	resp, err := s.s.ListTasks(s.ctx, &v2.ListTasksRequest{Parent: "users/1", PageSize: 2})
+	s.Require().NoError(err)
+	s.Len(resp.Tasks, 2)
+	s.Equal("users/1/tasks/tid7", resp.Tasks[1].Name)
+
+	cursor, err := decodePageToken(resp.NextPageToken)
+	s.Require().NoError(err)
+	s.Equal(repository.PageCursor{CreatedAt: 20, TaskID: "tid7"}, cursor)
+	s.mockRepo.On("GetPage", mock.Anything, "1", cursor, 3).Return(page[2:], nil)
+	resp, err = s.s.ListTasks(s.ctx, &v2.ListTasksRequest{Parent: "users/1", PageSize: 2, PageToken: resp.NextPageToken})
+	s.Require().NoError(err)
+	s.Len(resp.Tasks, 1)
+	s.Empty(resp.NextPageToken)
+
+	_, err = s.s.ListTasks(s.ctx, &v2.ListTasksRequest{Parent: "users/1", PageToken: "garbage"})
+	s.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (s *TaskServiceV2TestSuite) TestDeleteTask() {
+	s.mockRepo.On("Get", mock.Anything, "1", "tid9").Return(repository.Task{TaskID: "tid9", UserID: "1"}, nil)
+	s.mockRepo.On("Get", mock.Anything, "1", "tid10").Return(repository.Task{}, status.Error(codes.NotFound, ""))
+	s.mockRepo.On("Delete", mock.Anything, "1", "tid9").Return(nil)
+	_, err := s.s.DeleteTask(s.ctx, &v2.DeleteTaskRequest{Name: "users/1/tasks/tid9"})
+	s.NoError(err)
+	_, err = s.s.DeleteTask(s.ctx, &v2.DeleteTaskRequest{Name: "users/1/tasks/tid10"})
+	s.Equal(codes.NotFound, status.Code(err))
+	s.mockRepo.AssertNumberOfCalls(s.T(), "Delete", 1)
+}
+
+func TestTaskServiceV2TestSuite(t *testing.T) {
+	suite.Run(t, new(TaskServiceV2TestSuite))
+}
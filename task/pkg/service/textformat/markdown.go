@@ -0,0 +1,94 @@
+package textformat
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	checklistItem = regexp.MustCompile(`^(\s*)[-*+] \[(.)\](?:\s+(.*))?$`)
+	markdownLine  = regexp.MustCompile(`\r?\n`)
+)
+
+// ParseMarkdown reads the task items of GitHub-style checklists:
+//
+//	## Sprint
+//	- [ ] (A) review PR +todolist due:2022-07-20
+//	  indented lines below the item make up the description
+//	- [x] call mom @phone
+//
+// Headings, prose and other list items are ignored, a blank line ends the description.
+// Items with a checkbox other than "[ ]" or "[x]" are reported and left out
+func ParseMarkdown(r io.Reader) ([]repository.Task, []LineError, error) {
+	var tasks []repository.Task
+	var lineErrors []LineError
+	// current is the item the following indented lines describe
+	var current *repository.Task
+	indent := 0
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		m := checklistItem.FindStringSubmatch(text)
+		if m == nil {
+			if current != nil && text != "" && leadingSpace(text) > indent {
+				if current.Description != "" {
+					current.Description += "\n"
+				}
+				current.Description += strings.TrimSpace(text)
+				continue
+			}
+			current = nil
+			continue
+		}
+		current = nil
+		task := repository.Task{}
+		switch m[2] {
+		case " ":
+		case "x", "X":
+			task.Completed = true
+		default:
+			lineErrors = append(lineErrors, LineError{Line: line, Err: fmt.Errorf("invalid checkbox [%s]", m[2])})
+			continue
+		}
+		err := parseBody(m[3], &task)
+		if err != nil {
+			lineErrors = append(lineErrors, LineError{Line: line, Err: err})
+			continue
+		}
+		tasks = append(tasks, task)
+		current = &tasks[len(tasks)-1]
+		indent = len(m[1])
+	}
+	return tasks, lineErrors, scanner.Err()
+}
+
+func leadingSpace(text string) int {
+	return len(text) - len(strings.TrimLeft(text, " \t"))
+}
+
+// FormatMarkdown writes the tasks as a checklist, the opposite of ParseMarkdown
+func FormatMarkdown(w io.Writer, tasks []repository.Task) error {
+	for _, task := range tasks {
+		item := "- [ ] "
+		if task.Completed {
+			item = "- [x] "
+		} else if task.Priority != "" {
+			item += "(" + task.Priority + ") "
+		}
+		item += formatBody(task) + "\n"
+		for _, line := range markdownLine.Split(strings.TrimSpace(task.Description), -1) {
+			if strings.TrimSpace(line) != "" {
+				item += "  " + strings.TrimSpace(line) + "\n"
+			}
+		}
+		_, err := io.WriteString(w, item)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
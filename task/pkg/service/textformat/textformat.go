@@ -0,0 +1,146 @@
+// Package textformat converts tasks from and to plain text formats people keep their lists in,
+// todo.txt (http://todotxt.org) and GitHub-style Markdown checklists.
+//
+// Both formats share the task body syntax of todo.txt: an optional "(A)" priority, the task text
+// with +project and @context tags and a "due:" tag holding a date or an RFC 3339 time.
+// Projects and contexts stay a part of the task name, so they survive the round trip unchanged
+package textformat
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	tagDue     = "due:"
+	// completed tasks keep their priority in a tag, since "x (A)" would not parse as completed
+	tagPriority = "pri:"
+)
+
+var (
+	ErrInvalidPriority = errors.New("priority must be a single letter A-Z")
+	ErrMissingText     = errors.New("missing task text")
+
+	priorityPrefix = regexp.MustCompile(`^\(([A-Z])\)\s+`)
+	datePrefix     = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\s+`)
+)
+
+// LineError reports a line of the document that could not be parsed
+type LineError struct {
+	// Line is 1-based
+	Line int
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e LineError) Unwrap() error {
+	return e.Err
+}
+
+// ValidatePriority accepts a single upper case letter
+func ValidatePriority(priority string) error {
+	if len(priority) != 1 || priority[0] < 'A' || priority[0] > 'Z' {
+		return ErrInvalidPriority
+	}
+	return nil
+}
+
+// Projects returns the +project tags of the task text
+func Projects(text string) []string {
+	return tags(text, '+')
+}
+
+// Contexts returns the @context tags of the task text
+func Contexts(text string) []string {
+	return tags(text, '@')
+}
+
+func tags(text string, prefix byte) []string {
+	var found []string
+	for _, word := range strings.Fields(text) {
+		if len(word) > 1 && word[0] == prefix {
+			found = append(found, word[1:])
+		}
+	}
+	return found
+}
+
+// parseBody fills priority, due time and name of the task from the body following the
+// completion mark and dates
+func parseBody(body string, task *repository.Task) error {
+	if m := priorityPrefix.FindStringSubmatch(body); m != nil {
+		task.Priority = m[1]
+		body = body[len(m[0]):]
+	}
+	var words []string
+	for _, word := range strings.Fields(body) {
+		switch {
+		case strings.HasPrefix(word, tagDue) && len(word) > len(tagDue):
+			due, err := parseDue(word[len(tagDue):])
+			if err != nil {
+				return err
+			}
+			task.Time = due.Unix()
+		case strings.HasPrefix(word, tagPriority) && len(word) > len(tagPriority):
+			err := ValidatePriority(word[len(tagPriority):])
+			if err != nil {
+				return err
+			}
+			task.Priority = word[len(tagPriority):]
+		default:
+			words = append(words, word)
+		}
+	}
+	task.Name = strings.Join(words, " ")
+	if task.Name == "" {
+		return ErrMissingText
+	}
+	return nil
+}
+
+// parseDue accepts a date, taken as midnight UTC, or an RFC 3339 time
+func parseDue(value string) (time.Time, error) {
+	due, err := time.Parse(dateLayout, value)
+	if err == nil {
+		return due, nil
+	}
+	due, err = time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid due %q, expected YYYY-MM-DD or RFC 3339 time", value)
+	}
+	return due, nil
+}
+
+// formatBody writes the task name followed by the priority tag of completed tasks and the due tag.
+// Open tasks get the priority prefix from the caller
+func formatBody(task repository.Task) string {
+	body := strings.Join(strings.Fields(task.Name), " ")
+	if task.Completed && task.Priority != "" {
+		body += " " + tagPriority + task.Priority
+	}
+	if task.Time > 0 {
+		body += " " + tagDue + formatDue(task.Time)
+	}
+	return body
+}
+
+// formatDue writes the date only when the task is due at midnight UTC
+func formatDue(unix int64) string {
+	due := time.Unix(unix, 0).UTC()
+	if due.Equal(due.Truncate(24 * time.Hour)) {
+		return due.Format(dateLayout)
+	}
+	return due.Format(time.RFC3339)
+}
+
+func formatDate(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(dateLayout)
+}
@@ -0,0 +1,125 @@
+package textformat
+
+import (
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type TextFormatTestSuite struct {
+	suite.Suite
+}
+
+func (s *TextFormatTestSuite) TestParseTodoTxt() {
+	doc := "x 2022-07-18 2022-07-17 call mom +family @phone due:2022-07-19 pri:A\n" +
+		"\n" +
+		"(B) 2022-07-17 review PR +todolist due:2022-07-20T15:00:00Z\n" +
+		"buy milk\n" +
+		"(C) due:tomorrow\n" +
+		"2022-07-17 due:2022-07-19\n"
+	tasks, lineErrors, err := ParseTodoTxt(strings.NewReader(doc))
+	s.NoError(err)
+	s.Equal([]repository.Task{
+		{
+			Name:        "call mom +family @phone",
+			Time:        1658188800,
+			CreatedAt:   1658016000,
+			Completed:   true,
+			CompletedAt: 1658102400,
+			Priority:    "A",
+		},
+		{
+			Name:      "review PR +todolist",
+			Time:      1658329200,
+			CreatedAt: 1658016000,
+			Priority:  "B",
+		},
+		{
+			Name: "buy milk",
+		},
+	}, tasks)
+	s.Len(lineErrors, 2)
+	s.Equal(5, lineErrors[0].Line)
+	s.Equal(6, lineErrors[1].Line)
+	s.ErrorIs(lineErrors[1], ErrMissingText)
+	s.Equal([]string{"family"}, Projects(tasks[0].Name))
+	s.Equal([]string{"phone"}, Contexts(tasks[0].Name))
+}
+
+func (s *TextFormatTestSuite) TestTodoTxtRoundTrip() {
+	doc := "x 2022-07-18 2022-07-17 call mom +family @phone pri:A due:2022-07-19\n" +
+		"(B) 2022-07-17 review PR +todolist due:2022-07-20T15:00:00Z\n" +
+		"buy milk\n"
+	tasks, lineErrors, err := ParseTodoTxt(strings.NewReader(doc))
+	s.NoError(err)
+	s.Empty(lineErrors)
+	b := &strings.Builder{}
+	s.NoError(FormatTodoTxt(b, tasks))
+	s.Equal(doc, b.String())
+}
+
+func (s *TextFormatTestSuite) TestParseMarkdown() {
+	doc := "# Groceries\n" +
+		"\n" +
+		"- [ ] (A) buy milk due:2022-07-19\n" +
+		"  two bottles\n" +
+		"  semi-skimmed\n" +
+		"- [X] call mom @phone\n" +
+		"\n" +
+		"  not a description\n" +
+		"- plain item\n" +
+		"- [?] unknown\n" +
+		"  * [ ] nested +todolist\n"
+	tasks, lineErrors, err := ParseMarkdown(strings.NewReader(doc))
+	s.NoError(err)
+	s.Equal([]repository.Task{
+		{
+			Name:        "buy milk",
+			Description: "two bottles\nsemi-skimmed",
+			Time:        1658188800,
+			Priority:    "A",
+		},
+		{
+			Name:      "call mom @phone",
+			Completed: true,
+		},
+		{
+			Name: "nested +todolist",
+		},
+	}, tasks)
+	s.Equal([]LineError{{Line: 10, Err: lineErrors[0].Err}}, lineErrors)
+}
+
+func (s *TextFormatTestSuite) TestMarkdownRoundTrip() {
+	doc := "- [ ] (A) buy milk due:2022-07-19\n" +
+		"  two bottles\n" +
+		"- [x] call mom @phone pri:B\n"
+	tasks, lineErrors, err := ParseMarkdown(strings.NewReader(doc))
+	s.NoError(err)
+	s.Empty(lineErrors)
+	b := &strings.Builder{}
+	s.NoError(FormatMarkdown(b, tasks))
+	s.Equal(doc, b.String())
+}
+
+func (s *TextFormatTestSuite) TestValidatePriority() {
+	candidates := []struct {
+		priority string
+		valid    bool
+	}{
+		{priority: "A", valid: true},
+		{priority: "Z", valid: true},
+		{priority: "a", valid: false},
+		{priority: "AB", valid: false},
+		{priority: "", valid: false},
+	}
+	for _, c := range candidates {
+		err := ValidatePriority(c.priority)
+		s.Equal(c.valid, err == nil, c.priority)
+	}
+}
+
+func TestTextFormatTestSuite(t *testing.T) {
+	suite.Run(t, new(TextFormatTestSuite))
+}
@@ -0,0 +1,104 @@
+package textformat
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"io"
+	"strings"
+	"time"
+)
+
+// ParseTodoTxt reads one task per line in the todo.txt format:
+//
+//	x 2022-07-18 2022-07-17 call mom +family @phone due:2022-07-19 pri:A
+//	(B) 2022-07-17 review PR +todolist due:2022-07-20T15:00:00Z
+//
+// Blank lines are skipped, lines that can't be parsed are reported and left out.
+// Task descriptions have no place in todo.txt
+func ParseTodoTxt(r io.Reader) ([]repository.Task, []LineError, error) {
+	var tasks []repository.Task
+	var lineErrors []LineError
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		task, err := parseTodoTxtLine(text)
+		if err != nil {
+			lineErrors = append(lineErrors, LineError{Line: line, Err: err})
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, lineErrors, scanner.Err()
+}
+
+func parseTodoTxtLine(text string) (repository.Task, error) {
+	task := repository.Task{}
+	if strings.HasPrefix(text, "x ") {
+		task.Completed = true
+		text = strings.TrimSpace(text[2:])
+		// completion date is followed by creation date
+		date, rest, err := cutDate(text)
+		if err != nil {
+			return task, err
+		}
+		if !date.IsZero() {
+			task.CompletedAt = date.Unix()
+			text = rest
+		}
+	} else if m := priorityPrefix.FindStringSubmatch(text); m != nil {
+		task.Priority = m[1]
+		text = text[len(m[0]):]
+	}
+	date, rest, err := cutDate(text)
+	if err != nil {
+		return task, err
+	}
+	if !date.IsZero() {
+		task.CreatedAt = date.Unix()
+		text = rest
+	}
+	err = parseBody(text, &task)
+	return task, err
+}
+
+// cutDate splits the leading date off the text, zero time is returned when the text doesn't start with one
+func cutDate(text string) (time.Time, string, error) {
+	m := datePrefix.FindStringSubmatch(text)
+	if m == nil {
+		return time.Time{}, text, nil
+	}
+	date, err := time.Parse(dateLayout, m[1])
+	if err != nil {
+		return time.Time{}, text, fmt.Errorf("invalid date %q", m[1])
+	}
+	return date, text[len(m[0]):], nil
+}
+
+// FormatTodoTxt writes the tasks one per line, the opposite of ParseTodoTxt
+func FormatTodoTxt(w io.Writer, tasks []repository.Task) error {
+	for _, task := range tasks {
+		var parts []string
+		if task.Completed {
+			parts = append(parts, "x")
+			// completion date is only allowed together with creation date
+			if task.CompletedAt > 0 && task.CreatedAt > 0 {
+				parts = append(parts, formatDate(task.CompletedAt))
+			}
+		} else if task.Priority != "" {
+			parts = append(parts, "("+task.Priority+")")
+		}
+		if task.CreatedAt > 0 {
+			parts = append(parts, formatDate(task.CreatedAt))
+		}
+		parts = append(parts, formatBody(task))
+		_, err := io.WriteString(w, strings.Join(parts, " ")+"\n")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"time"
+)
+
+// WatchTasks streams the changes of the caller's tasks from the change log until the client goes away.
+// Streams resumed with a token older than the tombstones are refused, the client has to sync all tasks first
+func (ts *TaskService) WatchTasks(in *v1.WatchTasksRequest, stream v1.TaskService_WatchTasksServer) error {
+	ctx := stream.Context()
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	var cursor repository.SyncCursor
+	var err error
+	if in.SyncToken != "" {
+		var issuedAt time.Time
+		cursor, issuedAt, err = decodeSyncToken(in.SyncToken)
+		if err != nil {
+			log.Error(err.Error())
+			return apierror.Status(apierror.Field("sync_token", ErrInvalidSyncToken))
+		}
+		since := cursor.UpdatedAt
+		if since.IsZero() {
+			since = issuedAt
+		}
+		if time.Since(since) >= repository.TombstoneTTL {
+			log.Error(ErrSyncTokenExpired.Error(), zap.Time("since", since))
+			return apierror.Status(ErrSyncTokenExpired)
+		}
+	} else {
+		// without a token only the changes from now on are streamed
+		cursor, err = ts.taskRepo.GetLatestChange(ctx, userCtx.UserID)
+		if err != nil {
+			log.Error(err.Error())
+			return apierror.Status(err)
+		}
+	}
+	log.Info("Watching tasks")
+	err = ts.taskRepo.WatchChanges(ctx, userCtx.UserID, cursor, func(change repository.Change) error {
+		return stream.Send(changeToEvent(change))
+	})
+	if err == nil || ctx.Err() != nil {
+		log.Info("Stopped watching tasks")
+		return nil
+	}
+	log.Error(err.Error())
+	return apierror.Status(err)
+}
+
+func changeToEvent(change repository.Change) *v1.TaskEvent {
+	event := &v1.TaskEvent{
+		Type:      v1.TaskEventType_UPDATED,
+		TaskId:    change.TaskID,
+		Time:      change.UpdatedAt.Unix(),
+		SyncToken: encodeSyncToken(change.Cursor(), time.Now()),
+	}
+	switch {
+	case change.Deleted:
+		event.Type = v1.TaskEventType_DELETED
+	case change.Created:
+		event.Type = v1.TaskEventType_CREATED
+	}
+	if change.Task != nil {
+		event.Task = repository.ToApi(*change.Task)
+	}
+	return event
+}
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/internal/netguard"
+	"github.com/jakubjano/todolist/task/pkg/events"
+	"github.com/jakubjano/todolist/task/pkg/service/notify"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// HeaderWebhookSignature carries "sha256=" and the hex HMAC-SHA256 of "{timestamp}.{body}" keyed by the secret
+	HeaderWebhookSignature = notify.HeaderSignature
+	HeaderWebhookTimestamp = notify.HeaderTimestamp
+	HeaderWebhookEvent     = notify.HeaderEvent
+	HeaderWebhookDelivery  = notify.HeaderDelivery
+
+	maxWebhooks             = 10
+	minWebhookSecretLength  = 16
+	defaultDeliveryPageSize = 50
+	maxDeliveryPageSize     = 500
+)
+
+// WebhookSettings control the delivery of events, an event is sent at most Attempts times
+// with the delay doubling from Backoff. Webhooks are disabled after MaxFailures events in a row
+// failed all attempts
+type WebhookSettings struct {
+	Attempts    int
+	Backoff     time.Duration
+	Timeout     time.Duration
+	MaxFailures int32
+}
+
+// WebhookPayload is the JSON body of a delivery. ID is the same for every attempt and instance
+// delivering the event, so receivers can drop duplicates. Task is left out for deletions
+type WebhookPayload struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt int64           `json:"created_at"`
+	UserID    string          `json:"user_id"`
+	TaskID    string          `json:"task_id"`
+	Task      json.RawMessage `json:"task,omitempty"`
+}
+
+// Webhooks delivers the task events to the webhooks subscribed by the owners of the tasks
+type Webhooks struct {
+	webhookRepo repository.FSWebhookInterface
+	client      *http.Client
+	logger      *zap.Logger
+	settings    WebhookSettings
+}
+
+func NewWebhooks(webhookRepo repository.FSWebhookInterface, logger *zap.Logger, settings WebhookSettings) *Webhooks {
+	return &Webhooks{
+		webhookRepo: webhookRepo,
+		client:      netguard.NewClient(settings.Timeout),
+		logger:      logger,
+		settings:    settings,
+	}
+}
+
+// Subscribe registers the webhooks for the task events
+func (w *Webhooks) Subscribe(subscriber events.Subscriber) {
+	subscriber.Subscribe("webhooks", events.TaskCreated, w.HandleEvent)
+	subscriber.Subscribe("webhooks", events.TaskUpdated, w.HandleEvent)
+	subscriber.Subscribe("webhooks", events.TaskDeleted, w.HandleEvent)
+}
+
+// HandleEvent starts the delivery of the task event to the subscribed webhooks of its user.
+// Deliveries run detached, their failures are recorded in the delivery log instead
+func (w *Webhooks) HandleEvent(ctx context.Context, event events.Event) error {
+	log := w.logger.With(
+		zap.String("user_id", event.UserID),
+		zap.String("task_id", event.AggregateID),
+		zap.String("event_id", event.ID),
+	)
+	data := events.TaskData{}
+	err := event.Decode(&data)
+	if err != nil {
+		log.Error(err.Error())
+		return nil
+	}
+	webhooks, err := w.webhookRepo.List(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+	for _, webhookEvent := range webhookEvents(event.Type, data) {
+		var payload []byte
+		for _, webhook := range webhooks {
+			if !webhook.Subscribed(webhookEvent) {
+				continue
+			}
+			if payload == nil {
+				payload, err = webhookPayload(event, webhookEvent, data)
+				if err != nil {
+					log.Error(err.Error())
+					return nil
+				}
+			}
+			go w.Deliver(context.Background(), webhook, webhookEvent, webhookEventID(event, webhookEvent), payload)
+		}
+	}
+	return nil
+}
+
+// Deliver sends the payload until the receiver accepts it, records every attempt in the delivery log
+// and the result on the webhook
+func (w *Webhooks) Deliver(ctx context.Context, webhook repository.Webhook, event v1.WebhookEventType,
+	eventID string, payload []byte) {
+	log := w.logger.With(
+		zap.String("user_id", webhook.UserID),
+		zap.String("webhook_id", webhook.WebhookID),
+		zap.String("event_id", eventID),
+		zap.String("event", event.String()),
+	)
+	success := false
+	for attempt := 1; attempt <= w.settings.Attempts; attempt++ {
+		start := time.Now()
+		statusCode, err := w.send(ctx, webhook, event, eventID, payload)
+		delivery := repository.Delivery{
+			WebhookID:  webhook.WebhookID,
+			EventID:    eventID,
+			Event:      event.String(),
+			Attempt:    int32(attempt),
+			StatusCode: int32(statusCode),
+			Success:    err == nil,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		logErr := w.webhookRepo.AddDelivery(ctx, webhook.UserID, delivery)
+		if logErr != nil {
+			log.Error(logErr.Error())
+		}
+		if err == nil {
+			success = true
+			break
+		}
+		log.Info("Webhook delivery failed", zap.Int("attempt", attempt), zap.Error(err))
+		if !retryable(statusCode) || attempt == w.settings.Attempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.settings.Backoff << (attempt - 1)):
+		}
+	}
+	updated, err := w.webhookRepo.RecordResult(ctx, webhook.UserID, webhook.WebhookID, success, w.settings.MaxFailures)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+	if !webhook.Disabled && updated.Disabled {
+		log.Info("Webhook disabled", zap.Int32("consecutive_failures", updated.ConsecutiveFailures))
+	}
+}
+
+// send returns the status code of the response, 0 when there was none,
+// and an error unless the receiver responded with 2xx
+func (w *Webhooks) send(ctx context.Context, webhook repository.Webhook, event v1.WebhookEventType,
+	eventID string, payload []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todolist-webhooks")
+	req.Header.Set(HeaderWebhookEvent, event.String())
+	req.Header.Set(HeaderWebhookDelivery, eventID)
+	req.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderWebhookSignature, SignWebhook(webhook.Secret, timestamp, payload))
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// drain the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhook returns the value of the signature header of the payload sent at the timestamp
+func SignWebhook(secret string, timestamp int64, payload []byte) string {
+	return notify.Sign(secret, timestamp, payload)
+}
+
+// retryable tells whether another attempt may succeed, other client errors won't change by retrying
+func retryable(statusCode int) bool {
+	switch {
+	case statusCode == 0, statusCode >= 500:
+		return true
+	case statusCode == http.StatusRequestTimeout, statusCode == http.StatusTooManyRequests:
+		return true
+	default:
+		return false
+	}
+}
+
+// webhookEvents maps the task event onto webhook events, completing a task is an update too
+func webhookEvents(eventType events.Type, data events.TaskData) []v1.WebhookEventType {
+	var webhookEvents []v1.WebhookEventType
+	switch eventType {
+	case events.TaskCreated:
+		webhookEvents = append(webhookEvents, v1.WebhookEventType_TASK_CREATED)
+	case events.TaskUpdated:
+		webhookEvents = append(webhookEvents, v1.WebhookEventType_TASK_UPDATED)
+	case events.TaskDeleted:
+		return []v1.WebhookEventType{v1.WebhookEventType_TASK_DELETED}
+	}
+	if data.Completion {
+		webhookEvents = append(webhookEvents, v1.WebhookEventType_TASK_COMPLETED)
+	}
+	return webhookEvents
+}
+
+// webhookEventID derives the ID from the domain event, so it doesn't change when the event is redelivered
+func webhookEventID(event events.Event, webhookEvent v1.WebhookEventType) string {
+	sum := sha256.Sum256([]byte(event.ID + ":" + webhookEvent.String()))
+	return hex.EncodeToString(sum[:16])
+}
+
+func webhookPayload(event events.Event, webhookEvent v1.WebhookEventType, data events.TaskData) ([]byte, error) {
+	return json.Marshal(WebhookPayload{
+		ID:        webhookEventID(event, webhookEvent),
+		Type:      webhookEvent.String(),
+		CreatedAt: event.OccurredAt.Unix(),
+		UserID:    event.UserID,
+		TaskID:    data.TaskID,
+		Task:      data.Task,
+	})
+}
+
+// validateWebhook rejects URLs pointing at the internal network early, the deliveries
+// check the resolved addresses again
+func validateWebhook(in *v1.Webhook) error {
+	u, err := url.Parse(in.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return apierror.Field("url", ErrInvalidWebhookURL)
+	}
+	if netguard.CheckURL(in.Url) != nil {
+		return apierror.Field("url", ErrForbiddenWebhookURL)
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func (ts *TaskService) CreateWebhook(ctx context.Context, in *v1.Webhook) (*v1.Webhook, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	err := validateWebhook(in)
+	if err == nil && in.Secret != "" && len(in.Secret) < minWebhookSecretLength {
+		err = apierror.Field("secret", ErrWeakWebhookSecret)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.Webhook{}, apierror.Status(err)
+	}
+	webhooks, err := ts.webhooks.webhookRepo.List(ctx, userCtx.UserID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.Webhook{}, apierror.Status(err)
+	}
+	if len(webhooks) >= maxWebhooks {
+		log.Error(ErrTooManyWebhooks.Error())
+		return &v1.Webhook{}, apierror.Status(ErrTooManyWebhooks)
+	}
+	webhook := repository.WebhookFromMsg(in)
+	webhook.UserID = userCtx.UserID
+	webhook.Disabled = false
+	if webhook.Secret == "" {
+		webhook.Secret, err = newWebhookSecret()
+		if err != nil {
+			log.Error(err.Error())
+			return &v1.Webhook{}, apierror.Status(err)
+		}
+	}
+	webhook, err = ts.webhooks.webhookRepo.Create(ctx, webhook)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.Webhook{}, apierror.Status(err)
+	}
+	log.Info("Created webhook", zap.String("webhook_id", webhook.WebhookID))
+	resp := webhook.ToApi()
+	resp.Secret = webhook.Secret
+	return resp, nil
+}
+
+func (ts *TaskService) GetWebhook(ctx context.Context, in *v1.WebhookRequest) (*v1.Webhook, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("webhook_id", in.WebhookId),
+	)
+	webhook, err := ts.webhooks.webhookRepo.Get(ctx, userCtx.UserID, in.WebhookId)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.Webhook{}, apierror.Status(err)
+	}
+	return webhook.ToApi(), nil
+}
+
+func (ts *TaskService) ListWebhooks(ctx context.Context, _ *emptypb.Empty) (*v1.WebhookList, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+	)
+	webhooks, err := ts.webhooks.webhookRepo.List(ctx, userCtx.UserID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.WebhookList{}, apierror.Status(err)
+	}
+	resp := &v1.WebhookList{}
+	for _, webhook := range webhooks {
+		resp.Webhooks = append(resp.Webhooks, webhook.ToApi())
+	}
+	return resp, nil
+}
+
+func (ts *TaskService) UpdateWebhook(ctx context.Context, in *v1.Webhook) (*v1.Webhook, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("webhook_id", in.WebhookId),
+	)
+	err := validateWebhook(in)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.Webhook{}, apierror.Status(err)
+	}
+	webhook := repository.WebhookFromMsg(in)
+	webhook.UserID = userCtx.UserID
+	webhook, err = ts.webhooks.webhookRepo.Update(ctx, webhook)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.Webhook{}, apierror.Status(err)
+	}
+	log.Info("Updated webhook", zap.Bool("disabled", webhook.Disabled))
+	return webhook.ToApi(), nil
+}
+
+func (ts *TaskService) DeleteWebhook(ctx context.Context, in *v1.WebhookRequest) (*emptypb.Empty, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("webhook_id", in.WebhookId),
+	)
+	_, err := ts.webhooks.webhookRepo.Get(ctx, userCtx.UserID, in.WebhookId)
+	if err == nil {
+		err = ts.webhooks.webhookRepo.Delete(ctx, userCtx.UserID, in.WebhookId)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return &emptypb.Empty{}, apierror.Status(err)
+	}
+	log.Info("Deleted webhook")
+	return &emptypb.Empty{}, nil
+}
+
+func (ts *TaskService) ListWebhookDeliveries(ctx context.Context,
+	in *v1.ListWebhookDeliveriesRequest) (*v1.WebhookDeliveryList, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := ts.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("webhook_id", in.WebhookId),
+	)
+	n := int(in.N)
+	if n <= 0 {
+		n = defaultDeliveryPageSize
+	}
+	if n > maxDeliveryPageSize {
+		n = maxDeliveryPageSize
+	}
+	_, err := ts.webhooks.webhookRepo.Get(ctx, userCtx.UserID, in.WebhookId)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.WebhookDeliveryList{}, apierror.Status(err)
+	}
+	deliveries, err := ts.webhooks.webhookRepo.ListDeliveries(ctx, userCtx.UserID, in.WebhookId, n)
+	if err != nil {
+		log.Error(err.Error())
+		return &v1.WebhookDeliveryList{}, apierror.Status(err)
+	}
+	resp := &v1.WebhookDeliveryList{}
+	for _, delivery := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, delivery.ToApi())
+	}
+	return resp, nil
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	v1 "github.com/jakubjano/todolist/apis/go-sdk/task/v1"
+	middleware "github.com/jakubjano/todolist/task/internal/auth"
+	"github.com/jakubjano/todolist/task/pkg/events"
+	"github.com/jakubjano/todolist/task/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type WebhooksTestSuite struct {
+	suite.Suite
+	webhooks    *Webhooks
+	ts          *TaskService
+	webhookRepo *repository.FSWebhookMock
+}
+
+func (s *WebhooksTestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.webhookRepo = repository.NewMockWebhookRepo()
+	s.webhooks = NewWebhooks(s.webhookRepo, logger, WebhookSettings{
+		Attempts:    3,
+		Backoff:     time.Millisecond,
+		Timeout:     time.Second,
+		MaxFailures: 2,
+	})
+	// the test servers listen on loopback, which the webhooks don't connect to
+	s.webhooks.client = &http.Client{Timeout: time.Second}
+	s.ts = NewTaskService(repository.NewMockRepo(), nil, nil, s.webhooks, nil, nil, nil, logger)
+}
+
+func (s *WebhooksTestSuite) TestDeliverSigned() {
+	ctx := context.Background()
+	event := events.Event{
+		ID:          "ev1",
+		Type:        events.TaskUpdated,
+		UserID:      "1",
+		AggregateID: "tid1",
+		OccurredAt:  time.Unix(1658102400, 0),
+	}
+	data := events.TaskData{
+		TaskID:     "tid1",
+		Task:       []byte(`{"taskId":"tid1","completed":true}`),
+		Completion: true,
+	}
+	payload, err := webhookPayload(event, v1.WebhookEventType_TASK_COMPLETED, data)
+	s.Require().NoError(err)
+
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+	webhook := repository.Webhook{WebhookID: "wh1", UserID: "1", URL: server.URL, Secret: "0123456789abcdef"}
+	s.webhookRepo.On("AddDelivery", ctx, "1", mock.Anything).Return(nil)
+	s.webhookRepo.On("RecordResult", ctx, "1", "wh1", true, int32(2)).Return(webhook, nil)
+
+	eventID := webhookEventID(event, v1.WebhookEventType_TASK_COMPLETED)
+	s.webhooks.Deliver(ctx, webhook, v1.WebhookEventType_TASK_COMPLETED, eventID, payload)
+	s.Require().NotNil(received)
+	s.Equal(payload, body)
+	timestamp, err := strconv.ParseInt(received.Header.Get(HeaderWebhookTimestamp), 10, 64)
+	s.Require().NoError(err)
+	s.Equal(SignWebhook(webhook.Secret, timestamp, body), received.Header.Get(HeaderWebhookSignature))
+	s.Equal("TASK_COMPLETED", received.Header.Get(HeaderWebhookEvent))
+
+	decoded := WebhookPayload{}
+	s.Require().NoError(json.Unmarshal(body, &decoded))
+	s.Equal(eventID, received.Header.Get(HeaderWebhookDelivery))
+	s.Equal(eventID, decoded.ID)
+	s.Equal(int64(1658102400), decoded.CreatedAt)
+	s.Equal("tid1", decoded.TaskID)
+	s.Contains(string(decoded.Task), `"completed":true`)
+	s.webhookRepo.AssertCalled(s.T(), "AddDelivery", ctx, "1", mock.MatchedBy(func(d repository.Delivery) bool {
+		return d.Success && d.Attempt == 1 && d.StatusCode == http.StatusOK
+	}))
+}
+
+func (s *WebhooksTestSuite) TestDeliverRetries() {
+	ctx := context.Background()
+	candidates := []struct {
+		status           int
+		expectedAttempts int32
+	}{
+		// server errors are retried until the attempts run out
+		{status: http.StatusInternalServerError, expectedAttempts: 3},
+		{status: http.StatusTooManyRequests, expectedAttempts: 3},
+		// other client errors are not
+		{status: http.StatusGone, expectedAttempts: 1},
+	}
+	for _, c := range candidates {
+		s.SetupTest()
+		var attempts int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			w.WriteHeader(c.status)
+		}))
+		webhook := repository.Webhook{WebhookID: "wh2", UserID: "2", URL: server.URL, Secret: "0123456789abcdef"}
+		s.webhookRepo.On("AddDelivery", ctx, "2", mock.Anything).Return(nil)
+		s.webhookRepo.On("RecordResult", ctx, "2", "wh2", false, int32(2)).
+			Return(repository.Webhook{ConsecutiveFailures: 1}, nil)
+
+		s.webhooks.Deliver(ctx, webhook, v1.WebhookEventType_TASK_DELETED, "ev2", []byte("{}"))
+		server.Close()
+		s.Equal(c.expectedAttempts, attempts)
+		s.webhookRepo.AssertNumberOfCalls(s.T(), "AddDelivery", int(c.expectedAttempts))
+		s.webhookRepo.AssertCalled(s.T(), "RecordResult", ctx, "2", "wh2", false, int32(2))
+	}
+}
+
+func (s *WebhooksTestSuite) TestHandleEvent() {
+	ctx := context.Background()
+	delivered := make(chan string, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- r.URL.Path + " " + r.Header.Get(HeaderWebhookEvent)
+	}))
+	defer server.Close()
+	s.webhookRepo.On("List", ctx, "3").Return([]repository.Webhook{
+		{WebhookID: "all", UserID: "3", URL: server.URL + "/all"},
+		{WebhookID: "completed", UserID: "3", URL: server.URL + "/completed", Events: []string{"TASK_COMPLETED"}},
+		{WebhookID: "created", UserID: "3", URL: server.URL + "/created", Events: []string{"TASK_CREATED"}},
+		{WebhookID: "disabled", UserID: "3", URL: server.URL + "/disabled", Disabled: true},
+	}, nil)
+	s.webhookRepo.On("AddDelivery", mock.Anything, "3", mock.Anything).Return(nil)
+	s.webhookRepo.On("RecordResult", mock.Anything, "3", mock.Anything, true, int32(2)).
+		Return(repository.Webhook{}, nil)
+
+	event, err := events.New(events.TaskUpdated, "3", "tid3", events.TaskData{
+		TaskID:     "tid3",
+		Task:       []byte(`{"taskId":"tid3","completed":true}`),
+		Completion: true,
+	})
+	s.Require().NoError(err)
+	err = s.webhooks.HandleEvent(ctx, event)
+	s.NoError(err)
+	var got []string
+	for i := 0; i < 3; i++ {
+		select {
+		case d := <-delivered:
+			got = append(got, d)
+		case <-time.After(5 * time.Second):
+			s.FailNow("missing delivery")
+		}
+	}
+	s.ElementsMatch([]string{
+		"/all TASK_UPDATED",
+		"/all TASK_COMPLETED",
+		"/completed TASK_COMPLETED",
+	}, got)
+}
+
+func (s *WebhooksTestSuite) TestWebhookEvents() {
+	candidates := []struct {
+		eventType events.Type
+		data      events.TaskData
+		expected  []v1.WebhookEventType
+	}{
+		{
+			eventType: events.TaskCreated,
+			expected:  []v1.WebhookEventType{v1.WebhookEventType_TASK_CREATED},
+		},
+		{
+			eventType: events.TaskUpdated,
+			expected:  []v1.WebhookEventType{v1.WebhookEventType_TASK_UPDATED},
+		},
+		{
+			eventType: events.TaskUpdated,
+			data:      events.TaskData{Completion: true},
+			expected:  []v1.WebhookEventType{v1.WebhookEventType_TASK_UPDATED, v1.WebhookEventType_TASK_COMPLETED},
+		},
+		{
+			eventType: events.TaskDeleted,
+			expected:  []v1.WebhookEventType{v1.WebhookEventType_TASK_DELETED},
+		},
+	}
+	for _, c := range candidates {
+		s.Equal(c.expected, webhookEvents(c.eventType, c.data))
+	}
+}
+
+func (s *WebhooksTestSuite) TestCreateWebhook() {
+	ctx := context.WithValue(context.Background(), middleware.ContextUser, &middleware.UserContext{
+		UserID: "4",
+		Email:  "example4@tst.com",
+		Role:   "user",
+	})
+	candidates := []struct {
+		in           *v1.Webhook
+		expectedCode codes.Code
+	}{
+		{in: &v1.Webhook{Url: "ftp://example.com/hook"}, expectedCode: codes.InvalidArgument},
+		{in: &v1.Webhook{Url: "/hook"}, expectedCode: codes.InvalidArgument},
+		{in: &v1.Webhook{Url: "http://169.254.169.254/computeMetadata/v1/"}, expectedCode: codes.InvalidArgument},
+		{in: &v1.Webhook{Url: "http://metadata.google.internal/"}, expectedCode: codes.InvalidArgument},
+		{in: &v1.Webhook{Url: "https://example.com/hook", Secret: "short"}, expectedCode: codes.InvalidArgument},
+		{in: &v1.Webhook{Url: "https://example.com/hook"}, expectedCode: codes.OK},
+	}
+	s.webhookRepo.On("List", ctx, "4").Return([]repository.Webhook{}, nil)
+	var created repository.Webhook
+	s.webhookRepo.On("Create", ctx, mock.Anything).
+		Return(repository.Webhook{WebhookID: "wh4", Secret: "generated"}, nil).
+		Run(func(args mock.Arguments) {
+			created = args.Get(1).(repository.Webhook)
+		})
+	for _, c := range candidates {
+		resp, err := s.ts.CreateWebhook(ctx, c.in)
+		s.Equal(c.expectedCode, status.Code(err))
+		if err == nil {
+			s.Equal("wh4", resp.WebhookId)
+			s.Equal("4", created.UserID)
+			s.Len(created.Secret, 64)
+			// the secret is returned only on create
+			s.Equal("generated", resp.Secret)
+		}
+	}
+}
+
+func TestWebhooksTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhooksTestSuite))
+}
@@ -8,7 +8,10 @@ import (
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
 	v1 "github.com/jakubjano/todolist/apis/go-sdk/user/v1"
+	v2 "github.com/jakubjano/todolist/apis/go-sdk/user/v2"
+	"github.com/jakubjano/todolist/apis/go-sdk/validation"
 	"github.com/jakubjano/todolist/user/internal/auth"
 	"github.com/jakubjano/todolist/user/pkg/service"
 	"github.com/jakubjano/todolist/user/pkg/service/repository"
@@ -63,7 +66,7 @@ func main() {
 		panic(err)
 	}
 
-	userRepo := repository.NewFSUser(client.Collection(repository.CollectionUsers))
+	userRepo := repository.NewFSUser(client.Collection(repository.CollectionUsers), client)
 	userService := service.NewUserService(authClient, userRepo, logger)
 	tokenClient := auth.NewTokenClient(authClient, logger)
 
@@ -77,9 +80,11 @@ func main() {
 		grpc_middleware.WithUnaryServerChain(
 			grpc_recovery.UnaryServerInterceptor(),
 			tokenClient.CustomUnaryInterceptor(),
+			validation.UnaryInterceptor(),
 		),
 	)
 	v1.RegisterUserServiceServer(s, userService)
+	v2.RegisterUserServiceServer(s, service.NewUserServiceV2(userService))
 	reflection.Register(s)
 
 	go func() {
@@ -101,11 +106,15 @@ func main() {
 
 	// Register gRPC server endpoint
 	// Note: Make sure the gRPC server is running properly and accessible
-	mux := runtime.NewServeMux()
+	mux := runtime.NewServeMux(runtime.WithErrorHandler(apierror.GatewayErrorHandler))
 	err = v1.RegisterUserServiceHandler(context.Background(), mux, conn)
 	if err != nil {
 		panic(err)
 	}
+	err = v2.RegisterUserServiceHandler(context.Background(), mux, conn)
+	if err != nil {
+		panic(err)
+	}
 
 	// Start HTTP server (and proxy calls to gRPC server endpoint)
 	fmt.Printf("starting http server at '%s'\n", viper.GetString("gateway.port"))
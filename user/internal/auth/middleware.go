@@ -4,13 +4,17 @@ import (
 	"context"
 	"firebase.google.com/go/auth"
 	grpcAuth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 const (
 	ContextUser  = "user"
 	ContextAdmin = "admin"
+	// ReasonInvalidToken is reported when the ID token can't be verified
+	ReasonInvalidToken = "INVALID_TOKEN"
 )
 
 type TokenClient struct {
@@ -50,14 +54,14 @@ func (t *TokenClient) AuthFunc(ctx context.Context) (context.Context, error) {
 	jwt, err := grpcAuth.AuthFromMD(ctx, "bearer")
 	if err != nil {
 		t.logger.Error(err.Error())
-		return nil, err
+		return nil, apierror.Status(err)
 	}
 	// VerifyIDToken searches for projectID in key automatically when client was initialized with service account
 	// credentials
 	token, err := t.authClient.VerifyIDToken(ctx, jwt)
 	if err != nil {
 		t.logger.Error(err.Error())
-		return nil, err
+		return nil, apierror.Status(apierror.Wrap(codes.Unauthenticated, ReasonInvalidToken, err))
 	}
 	data := token.Claims
 	ctxUser := &UserContext{
@@ -14,6 +14,11 @@ func NewFBClientMock() *FBClientMock {
 	return &FBClientMock{}
 }
 
+func (m *FBClientMock) GetUser(ctx context.Context, uid string) (*auth.UserRecord, error) {
+	args := m.Called(ctx, uid)
+	return args.Get(0).(*auth.UserRecord), args.Error(1)
+}
+
 func (m *FBClientMock) GetUserByEmail(ctx context.Context, email string) (*auth.UserRecord, error) {
 	args := m.Called(ctx, email)
 	return args.Get(0).(*auth.UserRecord), args.Error(1)
@@ -1,7 +1,15 @@
 package service
 
-import "errors"
+import (
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	"google.golang.org/grpc/codes"
+)
+
+const ReasonUserNotFound = "USER_NOT_FOUND"
 
 var (
-	ErrUnauthorized = errors.New("unauthorized entry")
+	ErrUnauthorized      = apierror.New(codes.PermissionDenied, "PERMISSION_DENIED", "unauthorized entry")
+	ErrInvalidName       = apierror.New(codes.InvalidArgument, "INVALID_RESOURCE_NAME", "invalid resource name")
+	ErrInvalidUpdateMask = apierror.New(codes.InvalidArgument, "INVALID_UPDATE_MASK",
+		"update mask contains a field that can't be updated")
 )
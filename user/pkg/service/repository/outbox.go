@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"encoding/json"
+)
+
+const (
+	CollectionOutbox = "outbox"
+	EventUserDeleted = "UserDeleted"
+)
+
+// OutboxEvent redefines the outbox entry of the task microservice to maintain independence on it,
+// see task/pkg/events. Events written here are published by the relay of the task service
+type OutboxEvent struct {
+	ID          string `firestore:"id"`
+	Type        string `firestore:"type"`
+	UserID      string `firestore:"userID"`
+	AggregateID string `firestore:"aggregateID"`
+	// OccurredAt and NextAttemptAt are set by the server on commit
+	OccurredAt    interface{} `firestore:"occurredAt"`
+	NextAttemptAt interface{} `firestore:"nextAttemptAt"`
+	Data          []byte      `firestore:"data"`
+	Published     bool        `firestore:"published"`
+}
+
+type userDeletedData struct {
+	UserID string `json:"user_id"`
+}
+
+// recordEvent adds the event with the JSON data into the batch
+func (a *FSUser) recordEvent(batch *firestore.WriteBatch, eventType, userID string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	docRef := a.client.Collection(CollectionOutbox).NewDoc()
+	batch.Set(docRef, OutboxEvent{
+		ID:            docRef.ID,
+		Type:          eventType,
+		UserID:        userID,
+		AggregateID:   userID,
+		OccurredAt:    firestore.ServerTimestamp,
+		NextAttemptAt: firestore.ServerTimestamp,
+		Data:          raw,
+	})
+	return nil
+}
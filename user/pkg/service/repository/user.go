@@ -15,12 +15,14 @@ type FSUserInterface interface {
 }
 
 type FSUser struct {
-	fs *firestore.CollectionRef
+	fs     *firestore.CollectionRef
+	client *firestore.Client
 }
 
-func NewFSUser(fs *firestore.CollectionRef) *FSUser {
+func NewFSUser(fs *firestore.CollectionRef, client *firestore.Client) *FSUser {
 	return &FSUser{
-		fs: fs,
+		fs:     fs,
+		client: client,
 	}
 }
 
@@ -45,8 +47,15 @@ func (a *FSUser) Update(ctx context.Context, userID string, user User) (User, er
 	return user, nil
 }
 
+// Delete removes the user together with recording UserDeleted, the task service removes their tasks on it
 func (a *FSUser) Delete(ctx context.Context, userID string) error {
-	_, err := a.fs.Doc(userID).Delete(ctx)
+	batch := a.client.Batch()
+	batch.Delete(a.fs.Doc(userID))
+	err := a.recordEvent(batch, EventUserDeleted, userID, userDeletedData{UserID: userID})
+	if err != nil {
+		return err
+	}
+	_, err = batch.Commit(ctx)
 	if err != nil {
 		return err
 	}
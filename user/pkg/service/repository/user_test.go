@@ -28,7 +28,7 @@ func (s *RepoUserTestSuite) SetupSuite() {
 	if err != nil {
 		panic(err)
 	}
-	userRepo := NewFSUser(client.Collection("users"))
+	userRepo := NewFSUser(client.Collection("users"), client)
 	s.userRepo = userRepo
 	s.client = client
 }
@@ -235,7 +235,13 @@ func (s *RepoUserTestSuite) TestDeleteUser() {
 		s.Equal(candidate.ExpectedError, err)
 		_, err = s.userRepo.Get(ctx, candidate.UserID)
 		s.Equal(codes.NotFound, status.Code(err))
-
+		// deletion is announced to the other services
+		docs, err := s.client.Collection(CollectionOutbox).
+			Where("aggregateID", "==", candidate.UserID).
+			Where("type", "==", EventUserDeleted).
+			Documents(ctx).GetAll()
+		s.NoError(err)
+		s.NotEmpty(docs)
 	}
 }
 
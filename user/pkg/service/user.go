@@ -3,16 +3,17 @@ package service
 import (
 	"context"
 	"firebase.google.com/go/auth"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
 	v1 "github.com/jakubjano/todolist/apis/go-sdk/user/v1"
 	middleware "github.com/jakubjano/todolist/user/internal/auth"
 	"github.com/jakubjano/todolist/user/pkg/service/repository"
 	"go.uber.org/zap"
-	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/types/known/emptypb"
-	"net/http"
 )
 
 type AuthClientInterface interface {
+	GetUser(ctx context.Context, uid string) (*auth.UserRecord, error)
 	GetUserByEmail(ctx context.Context, email string) (*auth.UserRecord, error)
 	DeleteUser(ctx context.Context, uid string) error
 }
@@ -46,18 +47,18 @@ func (s *UserService) UpdateUser(ctx context.Context, in *v1.User) (*v1.User, er
 	case middleware.ContextUser:
 		if userCtx.Email != in.Email {
 			log.Error(ErrUnauthorized.Error())
-			return &v1.User{}, status.Error(http.StatusUnauthorized, ErrUnauthorized.Error())
+			return &v1.User{}, apierror.Status(ErrUnauthorized)
 		}
 	}
 	fbUser, err := s.authClient.GetUserByEmail(ctx, in.Email)
 	if err != nil {
 		log.Error(err.Error())
-		return &v1.User{}, err //status.Error(http.StatusBadRequest, err.Error())
+		return &v1.User{}, apierror.Status(userError(err))
 	}
 	user, err := s.userRepo.Update(ctx, fbUser.UID, repository.UserFromMsg(in))
 	if err != nil {
 		log.Error(err.Error())
-		return &v1.User{}, status.Error(http.StatusInternalServerError, err.Error())
+		return &v1.User{}, apierror.Status(err)
 	}
 	log.Info("update", zap.String("updated_user", user.Email), zap.String("updated_by", userCtx.Email))
 	return user.ToApi(), nil
@@ -77,13 +78,13 @@ func (s *UserService) GetUser(ctx context.Context, in *v1.GetUserRequest) (*v1.U
 	case middleware.ContextUser:
 		if userCtx.UserID != in.UserId {
 			log.Error(ErrUnauthorized.Error())
-			return &v1.User{}, status.Error(http.StatusUnauthorized, ErrUnauthorized.Error())
+			return &v1.User{}, apierror.Status(ErrUnauthorized)
 		}
 	}
 	user, err := s.userRepo.Get(ctx, in.UserId)
 	if err != nil {
 		log.Error(err.Error())
-		return &v1.User{}, status.Error(http.StatusBadRequest, err.Error())
+		return &v1.User{}, apierror.Status(err)
 	}
 	return user.ToApi(), nil
 }
@@ -97,20 +98,28 @@ func (s *UserService) DeleteUser(ctx context.Context, in *v1.DeleteUserRequest)
 		zap.String("delete_user_id", in.UserId),
 	)
 	if userCtx.Role != middleware.ContextAdmin {
-		return &emptypb.Empty{}, status.Error(http.StatusUnauthorized, ErrUnauthorized.Error())
+		return &emptypb.Empty{}, apierror.Status(ErrUnauthorized)
 	}
 	log.Info("Admin authorized")
 	err := s.authClient.DeleteUser(ctx, in.UserId)
 	if err != nil {
 		log.Error(err.Error())
-		return &emptypb.Empty{}, status.Error(http.StatusBadRequest, err.Error())
+		return &emptypb.Empty{}, apierror.Status(userError(err))
 	}
 	log.Info("deleted user from FB")
 	err = s.userRepo.Delete(ctx, in.UserId)
 	if err != nil {
 		log.Error(err.Error())
-		return &emptypb.Empty{}, status.Error(http.StatusInternalServerError, err.Error())
+		return &emptypb.Empty{}, apierror.Status(err)
 	}
 	log.Info("deleted user from FS")
 	return &emptypb.Empty{}, nil
 }
+
+// userError reports the users missing in Firebase Auth as not found
+func userError(err error) error {
+	if auth.IsUserNotFound(err) {
+		return apierror.Wrap(codes.NotFound, ReasonUserNotFound, err)
+	}
+	return err
+}
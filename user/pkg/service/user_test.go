@@ -2,14 +2,15 @@ package service
 
 import (
 	"context"
+	"errors"
 	"firebase.google.com/go/auth"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
 	v1 "github.com/jakubjano/todolist/apis/go-sdk/user/v1"
 	middleware "github.com/jakubjano/todolist/user/internal/auth"
 	"github.com/jakubjano/todolist/user/pkg/service/repository"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
-	"google.golang.org/grpc/status"
-	"net/http"
+	"google.golang.org/grpc/codes"
 	"testing"
 )
 
@@ -67,7 +68,7 @@ func (s *ServiceUserTestSuite) TestGetUser() {
 			}),
 			in:             &v1.GetUserRequest{UserId: "idnot2"},
 			ExpectedResult: &v1.User{},
-			ExpectedError:  status.Error(http.StatusUnauthorized, "unauthorized entry"),
+			ExpectedError:  apierror.Status(ErrUnauthorized),
 		},
 
 		//admin role authorized invalid input
@@ -194,8 +195,8 @@ func (s *ServiceUserTestSuite) TestUpdateUser() {
 				UserId:    "",
 			},
 			ExpectedResult: &v1.User{},
-			ExpectedError: status.Error(http.StatusBadRequest,
-				"malformed email string: @@bad_email"),
+			ExpectedError: apierror.Status(apierror.Wrap(codes.InvalidArgument, apierror.ReasonInvalidArgument,
+				errors.New("malformed email string: @@bad_email"))),
 		},
 
 		// user role unauthorized valid input
@@ -214,7 +215,7 @@ func (s *ServiceUserTestSuite) TestUpdateUser() {
 				UserId:    "id33",
 			},
 			ExpectedResult: &v1.User{},
-			ExpectedError:  status.Error(http.StatusUnauthorized, "unauthorized entry"),
+			ExpectedError:  apierror.Status(ErrUnauthorized),
 		},
 	}
 
@@ -258,7 +259,7 @@ func (s *ServiceUserTestSuite) TestDeleteUser() {
 				Role:   middleware.ContextUser,
 			}),
 			in:            &v1.DeleteUserRequest{UserId: "id1"},
-			ExpectedError: status.Error(http.StatusUnauthorized, ErrUnauthorized.Error()),
+			ExpectedError: apierror.Status(ErrUnauthorized),
 		},
 
 		// admin trying to delete valid input
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"firebase.google.com/go/auth"
+	"fmt"
+	"github.com/jakubjano/todolist/apis/go-sdk/apierror"
+	v2 "github.com/jakubjano/todolist/apis/go-sdk/user/v2"
+	middleware "github.com/jakubjano/todolist/user/internal/auth"
+	"github.com/jakubjano/todolist/user/pkg/service/repository"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"strings"
+)
+
+// UserServiceV2 serves the resource oriented v2 API, sharing the clients and the logger with UserService
+type UserServiceV2 struct {
+	v2.UnimplementedUserServiceServer
+	us *UserService
+}
+
+func NewUserServiceV2(us *UserService) *UserServiceV2 {
+	return &UserServiceV2{
+		us: us,
+	}
+}
+
+func (s *UserServiceV2) GetUser(ctx context.Context, in *v2.GetUserRequest) (*v2.User, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := s.us.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("caller_role", userCtx.Role),
+		zap.String("name", in.Name),
+	)
+	userID, err := parseUserName(in.Name)
+	if err == nil {
+		err = authorizeOwner(userCtx, userID)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.User{}, apierror.Status(err)
+	}
+	user, err := s.us.userRepo.Get(ctx, userID)
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.User{}, apierror.Status(err)
+	}
+	return userToV2(user), nil
+}
+
+// UpdateUser creates the profile of users who haven't got one yet, the email is taken from Firebase Auth
+func (s *UserServiceV2) UpdateUser(ctx context.Context, in *v2.UpdateUserRequest) (*v2.User, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := s.us.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("caller_role", userCtx.Role),
+		zap.String("name", in.User.Name),
+	)
+	userID, err := parseUserName(in.User.Name)
+	if err == nil {
+		err = authorizeOwner(userCtx, userID)
+	}
+	if err == nil {
+		err = validateUserMask(in.UpdateMask)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.User{}, apierror.Status(err)
+	}
+	user, err := s.us.userRepo.Get(ctx, userID)
+	if status.Code(err) == codes.NotFound {
+		var fbUser *auth.UserRecord
+		fbUser, err = s.us.authClient.GetUser(ctx, userID)
+		if err == nil {
+			user = repository.User{UserID: userID, Email: fbUser.Email}
+		}
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.User{}, apierror.Status(userError(err))
+	}
+	applyUserV2(&user, in.User, in.UpdateMask)
+	user, err = s.us.userRepo.Update(ctx, userID, user)
+	if err != nil {
+		log.Error(err.Error())
+		return &v2.User{}, apierror.Status(err)
+	}
+	log.Info("update", zap.String("updated_user", user.Email), zap.String("updated_by", userCtx.Email))
+	return userToV2(user), nil
+}
+
+func (s *UserServiceV2) DeleteUser(ctx context.Context, in *v2.DeleteUserRequest) (*emptypb.Empty, error) {
+	userCtx := ctx.Value(middleware.ContextUser).(*middleware.UserContext)
+	log := s.us.logger.With(
+		zap.String("caller_email", userCtx.Email),
+		zap.String("caller_id", userCtx.UserID),
+		zap.String("caller_role", userCtx.Role),
+		zap.String("name", in.Name),
+	)
+	userID, err := parseUserName(in.Name)
+	if err == nil && userCtx.Role != middleware.ContextAdmin {
+		err = ErrUnauthorized
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return &emptypb.Empty{}, apierror.Status(err)
+	}
+	log.Info("Admin authorized")
+	err = s.us.authClient.DeleteUser(ctx, userID)
+	if err != nil {
+		log.Error(err.Error())
+		return &emptypb.Empty{}, apierror.Status(userError(err))
+	}
+	log.Info("deleted user from FB")
+	err = s.us.userRepo.Delete(ctx, userID)
+	if err != nil {
+		log.Error(err.Error())
+		return &emptypb.Empty{}, apierror.Status(err)
+	}
+	log.Info("deleted user from FS")
+	return &emptypb.Empty{}, nil
+}
+
+// authorizeOwner lets users reach their own profile and admins the profiles of everyone
+func authorizeOwner(userCtx *middleware.UserContext, userID string) error {
+	if userCtx.Role == middleware.ContextAdmin || userCtx.UserID == userID {
+		return nil
+	}
+	return ErrUnauthorized
+}
+
+// parseUserName returns the user ID of users/{user}
+func parseUserName(name string) (string, error) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 2 || parts[0] != "users" || parts[1] == "" {
+		return "", apierror.Field("name", ErrInvalidName)
+	}
+	return parts[1], nil
+}
+
+func userToV2(user repository.User) *v2.User {
+	return &v2.User{
+		Name:        "users/" + user.UserID,
+		GivenName:   user.FirstName,
+		FamilyName:  user.LastName,
+		PhoneNumber: user.Phone,
+		Address:     user.Address,
+		Email:       user.Email,
+	}
+}
+
+// userMaskPaths are the mutable fields of v2 users
+var userMaskPaths = map[string]bool{
+	"given_name":   true,
+	"family_name":  true,
+	"phone_number": true,
+	"address":      true,
+}
+
+func validateUserMask(mask *fieldmaskpb.FieldMask) error {
+	for _, path := range mask.GetPaths() {
+		if !userMaskPaths[path] {
+			return apierror.Field("update_mask", fmt.Errorf("%w: %s", ErrInvalidUpdateMask, path))
+		}
+	}
+	return nil
+}
+
+// applyUserV2 copies the fields of the mask from in to user, all mutable fields without a mask
+func applyUserV2(user *repository.User, in *v2.User, mask *fieldmaskpb.FieldMask) {
+	paths := mask.GetPaths()
+	if len(paths) == 0 {
+		for path := range userMaskPaths {
+			paths = append(paths, path)
+		}
+	}
+	for _, path := range paths {
+		switch path {
+		case "given_name":
+			user.FirstName = in.GivenName
+		case "family_name":
+			user.LastName = in.FamilyName
+		case "phone_number":
+			user.Phone = in.PhoneNumber
+		case "address":
+			user.Address = in.Address
+		}
+	}
+}
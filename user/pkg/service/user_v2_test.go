@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"firebase.google.com/go/auth"
+	v2 "github.com/jakubjano/todolist/apis/go-sdk/user/v2"
+	middleware "github.com/jakubjano/todolist/user/internal/auth"
+	"github.com/jakubjano/todolist/user/pkg/service/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"testing"
+)
+
+type UserServiceV2TestSuite struct {
+	suite.Suite
+	s          *UserServiceV2
+	mockRepo   *repository.FSUserMock
+	mockClient *FBClientMock
+	ctx        context.Context
+	admin      context.Context
+}
+
+func (s *UserServiceV2TestSuite) SetupTest() {
+	logger, _ := zap.NewProduction()
+	s.mockRepo = repository.NewMockRepo()
+	s.mockClient = NewFBClientMock()
+	s.s = NewUserServiceV2(NewUserService(s.mockClient, s.mockRepo, logger))
+	s.ctx = context.WithValue(context.Background(), middleware.ContextUser, &middleware.UserContext{
+		UserID: "id1",
+		Email:  "test@example.com",
+		Role:   middleware.ContextUser,
+	})
+	s.admin = context.WithValue(context.Background(), middleware.ContextUser, &middleware.UserContext{
+		UserID: "idadmin",
+		Email:  "admin@example.com",
+		Role:   middleware.ContextAdmin,
+	})
+}
+
+func (s *UserServiceV2TestSuite) TestGetUser() {
+	s.mockRepo.On("Get", mock.Anything, "id1").Return(repository.User{
+		UserID:    "id1",
+		Email:     "test@example.com",
+		FirstName: "first",
+		LastName:  "last",
+	}, nil)
+	candidates := []struct {
+		ctx          context.Context
+		name         string
+		expectedCode codes.Code
+	}{
+		{ctx: s.ctx, name: "users/id1", expectedCode: codes.OK},
+		{ctx: s.admin, name: "users/id1", expectedCode: codes.OK},
+		{ctx: s.ctx, name: "users/id2", expectedCode: codes.PermissionDenied},
+		{ctx: s.ctx, name: "user/id1", expectedCode: codes.InvalidArgument},
+	}
+	for i, c := range candidates {
+		user, err := s.s.GetUser(c.ctx, &v2.GetUserRequest{Name: c.name})
+		s.Equalf(c.expectedCode, status.Code(err), "candidate %d", i+1)
+		if err == nil {
+			s.Equal(&v2.User{
+				Name:       "users/id1",
+				GivenName:  "first",
+				FamilyName: "last",
+				Email:      "test@example.com",
+			}, user)
+		}
+	}
+}
+
+func (s *UserServiceV2TestSuite) TestUpdateUser() {
+	s.mockRepo.On("Get", mock.Anything, "id1").Return(repository.User{
+		UserID:    "id1",
+		Email:     "test@example.com",
+		FirstName: "first",
+		Phone:     "09500600",
+	}, nil)
+	// users without a profile get one on their first update
+	s.mockRepo.On("Get", mock.Anything, "id3").Return(repository.User{}, status.Error(codes.NotFound, ""))
+	s.mockClient.On("GetUser", mock.Anything, "id3").Return(&auth.UserRecord{
+		UserInfo: &auth.UserInfo{UID: "id3", Email: "test3@example.com"},
+	}, nil)
+	var updated repository.User
+	s.mockRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(repository.User{}, nil).
+		Run(func(args mock.Arguments) {
+			updated = args.Get(2).(repository.User)
+		})
+	candidates := []struct {
+		ctx          context.Context
+		in           *v2.UpdateUserRequest
+		expectedCode codes.Code
+		expected     repository.User
+	}{
+		// only the fields of the mask change
+		{
+			ctx: s.ctx,
+			in: &v2.UpdateUserRequest{
+				User:       &v2.User{Name: "users/id1", GivenName: "renamed", Address: "ignored"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"given_name"}},
+			},
+			expected: repository.User{UserID: "id1", Email: "test@example.com", FirstName: "renamed", Phone: "09500600"},
+		},
+		{
+			ctx: s.admin,
+			in: &v2.UpdateUserRequest{
+				User: &v2.User{Name: "users/id3", GivenName: "third", Email: "ignored@example.com"},
+			},
+			expected: repository.User{UserID: "id3", Email: "test3@example.com", FirstName: "third"},
+		},
+		{
+			ctx:          s.ctx,
+			in:           &v2.UpdateUserRequest{User: &v2.User{Name: "users/id3"}},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			ctx: s.ctx,
+			in: &v2.UpdateUserRequest{
+				User:       &v2.User{Name: "users/id1"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"email"}},
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+	for i, c := range candidates {
+		updated = repository.User{}
+		_, err := s.s.UpdateUser(c.ctx, c.in)
+		s.Equalf(c.expectedCode, status.Code(err), "candidate %d", i+1)
+		s.Equalf(c.expected, updated, "candidate %d", i+1)
+	}
+}
+
+func (s *UserServiceV2TestSuite) TestDeleteUser() {
+	s.mockClient.On("DeleteUser", mock.Anything, "id1").Return(nil)
+	s.mockRepo.On("Delete", mock.Anything, "id1").Return(nil)
+	_, err := s.s.DeleteUser(s.ctx, &v2.DeleteUserRequest{Name: "users/id1"})
+	s.Equal(codes.PermissionDenied, status.Code(err))
+	_, err = s.s.DeleteUser(s.admin, &v2.DeleteUserRequest{Name: "users/id1"})
+	s.NoError(err)
+	s.mockRepo.AssertNumberOfCalls(s.T(), "Delete", 1)
+}
+
+func TestUserServiceV2TestSuite(t *testing.T) {
+	suite.Run(t, new(UserServiceV2TestSuite))
+}